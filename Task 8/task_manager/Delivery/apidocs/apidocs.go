@@ -0,0 +1,33 @@
+// Package apidocs serves the OpenAPI description of the API and a Swagger UI page for
+// browsing it. The document is maintained by hand next to the routes; a test in the
+// routers package fails when the two disagree.
+package apidocs
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+//go:embed swagger_ui.html
+var swaggerUI []byte
+
+// Spec returns the OpenAPI 3 document.
+func Spec() []byte {
+	return spec
+}
+
+// ServeSpec responds with the OpenAPI document.
+func ServeSpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+}
+
+// ServeSwaggerUI responds with a Swagger UI page that loads the document from
+// /openapi.json. The UI's scripts come from a CDN.
+func ServeSwaggerUI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", swaggerUI)
+}
@@ -2,75 +2,230 @@ package controllers
 
 import (
 	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	infrastructure "A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
 	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"bytes"
 	"errors"
-	"log"
+	"fmt"
+	"mime"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func sendErrorResponse(c *gin.Context, statusCode int, message string) {
-	c.JSON(statusCode, gin.H{
-		"message": message,
-	})
+// sendErrorResponse reports a problem detected by the controller itself, such as a
+// malformed query parameter.
+func sendErrorResponse(c *gin.Context, statusCode int, code infrastructure.ErrorCode, detail string) {
+	infrastructure.AbortWithProblem(c, statusCode, code, detail)
+}
+
+// sendBindingErrorResponse reports a request body that could not be decoded or failed
+// its binding rules.
+func sendBindingErrorResponse(c *gin.Context, err error) {
+	infrastructure.AbortWithBindingError(c, err)
+}
+
+// sendDomainErrorResponse reports an error returned by a use case. The mapping from
+// domain errors to status codes lives in the infrastructure package.
+func sendDomainErrorResponse(c *gin.Context, err error) {
+	infrastructure.AbortWithError(c, err)
 }
 
 func sendInternalErrorResponse(c *gin.Context, err error) {
-	log.Println(err)
-	sendErrorResponse(c, http.StatusInternalServerError, "An unexpected error occurred")
+	infrastructure.AbortWithInternalError(c, err)
+}
+
+// dueDateIn resolves a due date from a request body, placing plain dates in the
+// caller's time zone.
+func dueDateIn(c *gin.Context, dueDate *domain.DueDate) time.Time {
+	location, _ := domain.LocationFromContext(c.Request.Context())
+	return dueDate.In(location)
+}
+
+// optionalDueDateIn is dueDateIn for fields that may be left out.
+func optionalDueDateIn(c *gin.Context, dueDate *domain.DueDate) *time.Time {
+	if dueDate == nil {
+		return nil
+	}
+	resolved := dueDateIn(c, dueDate)
+	return &resolved
 }
 
 // User DTO
 type UserRegisterLogin struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	// Organization is the slug of the organization to log in to; empty means the
+	// default organization. It is ignored on registration.
+	Organization string `json:"organization,omitempty"`
+	// Invitation is an invitation token; registering with one joins the organization
+	// that issued it instead of the default organization.
+	Invitation string `json:"invitation,omitempty"`
+}
+
+// SetTimeZoneRequest sets the user's IANA time zone; an empty zone means UTC.
+type SetTimeZoneRequest struct {
+	TimeZone string `json:"time_zone"`
+}
+
+// Two-factor DTOs
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// Role DTOs
+type DefineRoleRequest struct {
+	Permissions []domain.Permission `json:"permissions" binding:"required"`
+}
+
+type AssignRoleRequest struct {
+	Role domain.UserRole `json:"role" binding:"required"`
+}
+
+// Organization DTOs
+type CreateOrganizationRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Slug     string `json:"slug" binding:"required"`
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type CreateInvitationRequest struct {
+	Role domain.UserRole `json:"role" binding:"required"`
+}
+
+// Service account DTOs
+type CreateServiceAccountRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string              `json:"name" binding:"required"`
+	Scopes    []domain.Permission `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time          `json:"expires_at,omitempty"`
+}
+
+// Project DTOs
+type CreateProjectRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+type SetProjectMemberRequest struct {
+	Role domain.ProjectRole `json:"role" binding:"required"`
 }
 
 // Task DTOs
 type CreateTaskRequest struct {
 	Title       string            `json:"title" binding:"required"`
 	Description string            `json:"description"`
-	DueDate     time.Time         `json:"duedate" binding:"required" time_format:"2006-01-02"` // Example date format
+	DueDate     *domain.DueDate   `json:"duedate" binding:"required"` // YYYY-MM-DD or RFC 3339
+	Status      domain.TaskStatus `json:"status" binding:"required"`
+}
+
+type SetTagsRequest struct {
+	Tags []string `json:"tags" binding:"required"`
+}
+
+type AddDependencyRequest struct {
+	BlockedBy string `json:"blocked_by" binding:"required"`
+}
+
+// ReplaceTaskRequest is the body of PUT, which replaces every editable field. Optional
+// fields that are left out are cleared.
+type ReplaceTaskRequest struct {
+	Title       string            `json:"title" binding:"required"`
+	Description string            `json:"description"`
+	DueDate     *domain.DueDate   `json:"duedate" binding:"required"`
 	Status      domain.TaskStatus `json:"status" binding:"required"`
+	Tags        []string          `json:"tags"`
 }
 
+// UpdateTaskRequest is the partial update applied by bulk requests. Fields cannot be
+// cleared with it; PATCH can.
 type UpdateTaskRequest struct {
 	Title       *string            `json:"title,omitempty"` // Pointers for optional fields
 	Description *string            `json:"description,omitempty"`
-	DueDate     *time.Time         `json:"duedate,omitempty" time_format:"2006-01-02"`
+	DueDate     *domain.DueDate    `json:"duedate,omitempty"`
 	Status      *domain.TaskStatus `json:"status,omitempty"`
 }
 
+// BulkTaskRequest carries either a list of operations or a filter with an update, not both.
+type BulkTaskRequest struct {
+	Atomic     bool                   `json:"atomic"`
+	Operations []BulkTaskOperation    `json:"operations"`
+	Filter     *BulkTaskFilterRequest `json:"filter"`
+	Update     *UpdateTaskRequest     `json:"update"`
+}
+
+// bulkRollbackProblem reports an atomic bulk request that was rolled back, together
+// with the operation that caused it and the outcome of each operation.
+type bulkRollbackProblem struct {
+	infrastructure.Problem
+	Committed       bool                       `json:"committed"`
+	FailedOperation bulkFailure                `json:"failed_operation"`
+	Results         []*usecases.BulkItemResult `json:"results"`
+}
+
+// bulkFailure describes the operation that made an atomic bulk request fail.
+type bulkFailure struct {
+	Index int                      `json:"index"`
+	Code  infrastructure.ErrorCode `json:"code"`
+}
+
+type BulkTaskOperation struct {
+	Action      usecases.BulkAction `json:"action" binding:"required"`
+	ID          string              `json:"id"`
+	Title       *string             `json:"title,omitempty"`
+	Description *string             `json:"description,omitempty"`
+	DueDate     *domain.DueDate     `json:"duedate,omitempty"`
+	Status      *domain.TaskStatus  `json:"status,omitempty"`
+}
+
+type BulkTaskFilterRequest struct {
+	Status    []domain.TaskStatus `json:"status"`
+	ProjectID string              `json:"project_id"`
+	DueBefore *domain.DueDate     `json:"due_before"`
+	DueAfter  *domain.DueDate     `json:"due_after"`
+}
+
 // --- UserController ---
 
 type UserController struct {
-	uc *usecases.UserUseCase
+	uc   *usecases.UserUseCase
+	orgs *usecases.OrganizationUseCase
 }
 
-func NewUserController(userUC *usecases.UserUseCase) *UserController {
+func NewUserController(userUC *usecases.UserUseCase, organizationUC *usecases.OrganizationUseCase) *UserController {
 	return &UserController{
-		uc: userUC,
+		uc:   userUC,
+		orgs: organizationUC,
 	}
 }
 
 func (controller *UserController) RegisterUser(c *gin.Context) {
 	var userRegister UserRegisterLogin
 	if err := c.ShouldBindJSON(&userRegister); err != nil {
-		sendErrorResponse(c, http.StatusBadRequest, err.Error())
+		sendBindingErrorResponse(c, err)
 		return
 	}
-	registeredUser, err := controller.uc.RegisterUser(c.Request.Context(), userRegister.Username, userRegister.Password)
+	var registeredUser *domain.User
+	var err error
+	if userRegister.Invitation != "" {
+		registeredUser, err = controller.orgs.RegisterWithInvitation(c.Request.Context(), userRegister.Invitation, userRegister.Username, userRegister.Password)
+	} else {
+		registeredUser, err = controller.uc.RegisterUser(c.Request.Context(), userRegister.Username, userRegister.Password)
+	}
 	if err != nil {
-		if errors.Is(err, domain.ErrUsernameTaken) {
-			sendErrorResponse(c, http.StatusConflict, err.Error())
-			return
-		} else if errors.Is(err, domain.ErrValidationFailed) {
-			sendErrorResponse(c, http.StatusBadRequest, err.Error())
-			return
-		}
-		sendInternalErrorResponse(c, err)
+		sendDomainErrorResponse(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{
@@ -83,16 +238,42 @@ func (controller *UserController) RegisterUser(c *gin.Context) {
 func (controller *UserController) Login(c *gin.Context) {
 	var userRegister UserRegisterLogin
 	if err := c.ShouldBindJSON(&userRegister); err != nil {
-		sendErrorResponse(c, http.StatusBadRequest, err.Error())
+		sendBindingErrorResponse(c, err)
 		return
 	}
-	token, err := controller.uc.Login(c.Request.Context(), userRegister.Username, userRegister.Password)
+	result, err := controller.uc.Login(c.Request.Context(), userRegister.Organization, userRegister.Username, userRegister.Password)
 	if err != nil {
-		if errors.Is(err, domain.ErrInvalidCredentials) {
-			sendErrorResponse(c, http.StatusUnauthorized, err.Error())
-			return
-		}
-		sendInternalErrorResponse(c, err)
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	if result.TwoFactorRequired {
+		c.JSON(http.StatusOK, gin.H{
+			"two_factor_required": true,
+			"challenge_token":     result.ChallengeToken,
+		})
+		return
+	}
+	if result.EnrollmentRequired {
+		c.JSON(http.StatusOK, gin.H{
+			"two_factor_enrollment_required": true,
+			"challenge_token":                result.ChallengeToken,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"token": result.Token,
+	})
+}
+
+func (controller *UserController) VerifyTwoFactorLogin(c *gin.Context) {
+	var req TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	token, err := controller.uc.VerifyTwoFactorLogin(c.Request.Context(), req.ChallengeToken, req.Code)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
@@ -100,113 +281,955 @@ func (controller *UserController) Login(c *gin.Context) {
 	})
 }
 
-// --- TaskController ---
+func (controller *UserController) BeginTwoFactorEnrollment(c *gin.Context) {
+	enrollment, err := controller.uc.BeginTwoFactorEnrollment(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"secret":           enrollment.Secret,
+		"provisioning_uri": enrollment.ProvisioningURI,
+	})
+}
 
-type TaskController struct {
-	uc *usecases.TaskUseCase
+func (controller *UserController) ConfirmTwoFactorEnrollment(c *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	recoveryCodes, err := controller.uc.ConfirmTwoFactorEnrollment(c.Request.Context(), c.GetString("userID"), req.Code)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"two_factor_enabled": true,
+		"recovery_codes":     recoveryCodes,
+	})
 }
 
-func NewTaskController(taskUC *usecases.TaskUseCase) *TaskController {
-	return &TaskController{
-		uc: taskUC,
+func (controller *UserController) DisableTwoFactor(c *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	if err := controller.uc.DisableTwoFactor(c.Request.Context(), c.GetString("userID"), req.Code); err != nil {
+		sendDomainErrorResponse(c, err)
+		return
 	}
+	c.Status(http.StatusNoContent)
 }
 
-func (controller *TaskController) CreateTask(c *gin.Context) {
-	var req CreateTaskRequest
+func (controller *UserController) SetTimeZone(c *gin.Context) {
+	var req SetTimeZoneRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		sendErrorResponse(c, http.StatusBadRequest, err.Error())
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	if err := controller.uc.SetTimeZone(c.Request.Context(), c.GetString("userID"), req.TimeZone); err != nil {
+		sendDomainErrorResponse(c, err)
 		return
 	}
+	c.Status(http.StatusNoContent)
+}
 
-	createdTask, err := controller.uc.CreateTask(c.Request.Context(), req.Title, req.Description, req.DueDate, req.Status)
+func (controller *UserController) AssignRole(c *gin.Context) {
+	var req AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	updatedUser, err := controller.uc.AssignRole(c.Request.Context(), c.Param("id"), req.Role)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"id":       updatedUser.Id,
+		"username": updatedUser.Username,
+		"role":     updatedUser.Role,
+	})
+}
+
+// --- OrganizationController ---
+
+type OrganizationController struct {
+	uc *usecases.OrganizationUseCase
+}
+
+func NewOrganizationController(organizationUC *usecases.OrganizationUseCase) *OrganizationController {
+	return &OrganizationController{
+		uc: organizationUC,
+	}
+}
+
+func (controller *OrganizationController) CreateOrganization(c *gin.Context) {
+	var req CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	organization, admin, err := controller.uc.CreateOrganization(c.Request.Context(), req.Name, req.Slug, req.Username, req.Password)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"organization": organization,
+		"user": gin.H{
+			"id":       admin.Id,
+			"username": admin.Username,
+			"role":     admin.Role,
+		},
+	})
+}
+
+func (controller *OrganizationController) GetOrganization(c *gin.Context) {
+	organization, err := controller.uc.GetOrganization(c.Request.Context())
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, organization)
+}
+
+func (controller *OrganizationController) GetMembers(c *gin.Context) {
+	members, err := controller.uc.GetMembers(c.Request.Context())
 	if err != nil {
-		if errors.Is(err, domain.ErrValidationFailed) {
-			sendErrorResponse(c, http.StatusBadRequest, err.Error())
-			return
-		}
 		sendInternalErrorResponse(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, members)
+}
 
-	c.JSON(http.StatusCreated, createdTask)
+func (controller *OrganizationController) RemoveMember(c *gin.Context) {
+	if err := controller.uc.RemoveMember(c.Request.Context(), c.Param("userId")); err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
 }
 
-func (controller *TaskController) GetTaskByID(c *gin.Context) {
-	taskID := c.Param("id")
+func (controller *OrganizationController) CreateInvitation(c *gin.Context) {
+	var req CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	token, invitation, err := controller.uc.Invite(c.Request.Context(), req.Role, c.GetString("username"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	// The token is only ever returned here.
+	c.JSON(http.StatusCreated, gin.H{
+		"token":      token,
+		"invitation": invitation,
+	})
+}
 
-	task, err := controller.uc.GetTaskByID(c.Request.Context(), taskID)
+func (controller *OrganizationController) GetInvitations(c *gin.Context) {
+	invitations, err := controller.uc.GetInvitations(c.Request.Context())
 	if err != nil {
-		if errors.Is(err, domain.ErrTaskNotFound) {
-			sendErrorResponse(c, http.StatusNotFound, err.Error())
-			return
-		} else if errors.Is(err, domain.ErrValidationFailed) {
-			sendErrorResponse(c, http.StatusBadRequest, err.Error())
-			return
-		}
 		sendInternalErrorResponse(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, invitations)
+}
+
+func (controller *OrganizationController) RevokeInvitation(c *gin.Context) {
+	if err := controller.uc.RevokeInvitation(c.Request.Context(), c.Param("id")); err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
 
-	c.JSON(http.StatusOK, task)
+// --- RoleController ---
+
+type RoleController struct {
+	uc *usecases.RoleUseCase
 }
 
-func (controller *TaskController) GetAllTasks(c *gin.Context) {
-	tasks, err := controller.uc.GetAllTasks(c.Request.Context())
+func NewRoleController(roleUC *usecases.RoleUseCase) *RoleController {
+	return &RoleController{
+		uc: roleUC,
+	}
+}
+
+func (controller *RoleController) GetAllRoles(c *gin.Context) {
+	roles, err := controller.uc.GetAllRoles(c.Request.Context())
 	if err != nil {
 		sendInternalErrorResponse(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, roles)
+}
 
-	c.JSON(http.StatusOK, tasks)
+func (controller *RoleController) DefineRole(c *gin.Context) {
+	var req DefineRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	role, err := controller.uc.DefineRole(c.Request.Context(), domain.UserRole(c.Param("name")), req.Permissions)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, role)
 }
 
-func (controller *TaskController) UpdateTask(c *gin.Context) {
-	taskID := c.Param("id")
-	var req UpdateTaskRequest
+// --- ServiceAccountController ---
+
+type ServiceAccountController struct {
+	uc *usecases.ServiceAccountUseCase
+}
+
+func NewServiceAccountController(serviceAccountUC *usecases.ServiceAccountUseCase) *ServiceAccountController {
+	return &ServiceAccountController{
+		uc: serviceAccountUC,
+	}
+}
+
+func (controller *ServiceAccountController) CreateServiceAccount(c *gin.Context) {
+	var req CreateServiceAccountRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		sendErrorResponse(c, http.StatusBadRequest, err.Error())
+		sendBindingErrorResponse(c, err)
 		return
 	}
+	account, err := controller.uc.CreateServiceAccount(c.Request.Context(), req.Name, req.Description, c.GetString("username"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, account)
+}
 
-	updatedTask, err := controller.uc.UpdateTask(
-		c.Request.Context(),
-		taskID,
-		req.Title,       // Pass pointer for optional string
-		req.Description, // Pass pointer for optional string
-		req.DueDate,     // Pass pointer for optional time.Time
-		req.Status,      // Pass pointer for optional TaskStatus
-	)
+func (controller *ServiceAccountController) GetAllServiceAccounts(c *gin.Context) {
+	accounts, err := controller.uc.GetAllServiceAccounts(c.Request.Context())
 	if err != nil {
-		if errors.Is(err, domain.ErrTaskNotFound) {
-			sendErrorResponse(c, http.StatusNotFound, err.Error())
-			return
-		} else if errors.Is(err, domain.ErrValidationFailed) {
-			sendErrorResponse(c, http.StatusBadRequest, err.Error())
-			return
-		}
 		sendInternalErrorResponse(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, accounts)
+}
 
-	c.JSON(http.StatusOK, updatedTask)
+func (controller *ServiceAccountController) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	plaintext, key, err := controller.uc.CreateAPIKey(c.Request.Context(), c.Param("id"), req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	// The plaintext key is only ever returned here.
+	c.JSON(http.StatusCreated, gin.H{
+		"key":     plaintext,
+		"api_key": key,
+	})
 }
 
-func (controller *TaskController) DeleteTask(c *gin.Context) {
-	taskID := c.Param("id")
+func (controller *ServiceAccountController) GetAPIKeys(c *gin.Context) {
+	keys, err := controller.uc.GetAPIKeys(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
 
-	err := controller.uc.DeleteTask(c.Request.Context(), taskID)
+func (controller *ServiceAccountController) RevokeAPIKey(c *gin.Context) {
+	if err := controller.uc.RevokeAPIKey(c.Request.Context(), c.Param("id"), c.Param("keyId")); err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// --- ProjectController ---
+
+type ProjectController struct {
+	uc *usecases.ProjectUseCase
+}
+
+func NewProjectController(projectUC *usecases.ProjectUseCase) *ProjectController {
+	return &ProjectController{
+		uc: projectUC,
+	}
+}
+
+func (controller *ProjectController) CreateProject(c *gin.Context) {
+	var req CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	project, err := controller.uc.CreateProject(c.Request.Context(), req.Name, req.Description)
 	if err != nil {
-		if errors.Is(err, domain.ErrTaskNotFound) {
-			sendErrorResponse(c, http.StatusNotFound, err.Error())
-			return
-		} else if errors.Is(err, domain.ErrValidationFailed) { // For invalid ID format
-			sendErrorResponse(c, http.StatusBadRequest, err.Error())
-			return
-		}
-		sendInternalErrorResponse(c, err)
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, project)
+}
+
+func (controller *ProjectController) GetMyProjects(c *gin.Context) {
+	projects, err := controller.uc.GetMyProjects(c.Request.Context())
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, projects)
+}
+
+func (controller *ProjectController) GetProject(c *gin.Context) {
+	project, err := controller.uc.GetProject(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, project)
+}
+
+func (controller *ProjectController) SetMember(c *gin.Context) {
+	var req SetProjectMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	project, err := controller.uc.SetMember(c.Request.Context(), c.Param("id"), c.Param("userId"), req.Role)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, project)
+}
+
+func (controller *ProjectController) RemoveMember(c *gin.Context) {
+	project, err := controller.uc.RemoveMember(c.Request.Context(), c.Param("id"), c.Param("userId"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, project)
+}
+
+// --- AttachmentController ---
+
+// multipartOverhead allows for the multipart boundaries and headers around the file.
+const multipartOverhead = 1 << 20
 
+type AttachmentController struct {
+	uc *usecases.AttachmentUseCase
+}
+
+func NewAttachmentController(attachmentUC *usecases.AttachmentUseCase) *AttachmentController {
+	return &AttachmentController{
+		uc: attachmentUC,
+	}
+}
+
+// UploadAttachment expects a multipart form with the content in the "file" field.
+func (controller *AttachmentController) UploadAttachment(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, controller.uc.MaxSize()+multipartOverhead)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			sendDomainErrorResponse(c, domain.ErrAttachmentTooLarge)
+			return
+		}
+		sendErrorResponse(c, http.StatusBadRequest, infrastructure.CodeInvalidRequestBody, "a file must be uploaded in the 'file' form field")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		sendInternalErrorResponse(c, err)
+		return
+	}
+	defer file.Close()
+
+	contentType := fileHeader.Header.Get("Content-Type")
+	attachment, err := controller.uc.Upload(c.Request.Context(), c.Param("id"), fileHeader.Filename, contentType, fileHeader.Size, file)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, attachment)
+}
+
+func (controller *AttachmentController) GetAttachments(c *gin.Context) {
+	attachments, err := controller.uc.GetAttachments(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, attachments)
+}
+
+func (controller *AttachmentController) DownloadAttachment(c *gin.Context) {
+	attachment, content, err := controller.uc.Download(c.Request.Context(), c.Param("id"), c.Param("attachmentId"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	defer content.Close()
+
+	// Attachments are always downloaded, never rendered, and browsers must not guess
+	// another type than the one checked at upload.
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName})
+	if disposition == "" {
+		// The file name cannot be encoded; the browser picks one.
+		disposition = "attachment"
+	}
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    disposition,
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (controller *AttachmentController) DeleteAttachment(c *gin.Context) {
+	if err := controller.uc.Delete(c.Request.Context(), c.Param("id"), c.Param("attachmentId")); err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// --- TaskHistoryController ---
+
+type TaskHistoryController struct {
+	uc *usecases.TaskHistoryUseCase
+}
+
+func NewTaskHistoryController(historyUC *usecases.TaskHistoryUseCase) *TaskHistoryController {
+	return &TaskHistoryController{
+		uc: historyUC,
+	}
+}
+
+func (controller *TaskHistoryController) GetHistory(c *gin.Context) {
+	revisions, err := controller.uc.GetHistory(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, revisions)
+}
+
+func (controller *TaskHistoryController) RevertTask(c *gin.Context) {
+	task, err := controller.uc.RevertTask(c.Request.Context(), c.Param("id"), c.Param("revisionId"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// --- CalendarController ---
+
+type CalendarController struct {
+	uc *usecases.CalendarUseCase
+}
+
+func NewCalendarController(calendarUC *usecases.CalendarUseCase) *CalendarController {
+	return &CalendarController{
+		uc: calendarUC,
+	}
+}
+
+// RotateFeedToken returns the new token once; it is part of the feed URL.
+func (controller *CalendarController) RotateFeedToken(c *gin.Context) {
+	token, err := controller.uc.RotateFeedToken(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"token": token, "feed_path": "/calendar/" + token})
+}
+
+func (controller *CalendarController) RevokeFeedToken(c *gin.Context) {
+	if err := controller.uc.RevokeFeedToken(c.Request.Context(), c.GetString("userID")); err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (controller *CalendarController) GetFeed(c *gin.Context) {
+	kind := domain.CalendarItemKind(c.DefaultQuery("type", string(domain.CalendarEvents)))
+	var calendar bytes.Buffer
+	if err := controller.uc.RenderFeed(c.Request.Context(), c.Param("token"), kind, &calendar); err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", calendar.Bytes())
+}
+
+// --- NotificationController ---
+
+type NotificationController struct {
+	uc *usecases.NotificationUseCase
+}
+
+func NewNotificationController(notificationUC *usecases.NotificationUseCase) *NotificationController {
+	return &NotificationController{
+		uc: notificationUC,
+	}
+}
+
+// GetNotifications accepts limit, offset and unread ("true" for unread notifications
+// only) query parameters.
+func (controller *NotificationController) GetNotifications(c *gin.Context) {
+	query := domain.NotificationQuery{UnreadOnly: c.Query("unread") == "true"}
+	for name, target := range map[string]*int{"limit": &query.Limit, "offset": &query.Offset} {
+		if value := c.Query(name); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				sendErrorResponse(c, http.StatusBadRequest, infrastructure.CodeBadRequest, name+" must be an integer")
+				return
+			}
+			*target = parsed
+		}
+	}
+	page, err := controller.uc.GetNotifications(c.Request.Context(), c.GetString("userID"), query)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+func (controller *NotificationController) MarkRead(c *gin.Context) {
+	if err := controller.uc.MarkRead(c.Request.Context(), c.GetString("userID"), c.Param("id")); err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (controller *NotificationController) MarkAllRead(c *gin.Context) {
+	marked, err := controller.uc.MarkAllRead(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		sendInternalErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+func (controller *NotificationController) GetPreferences(c *gin.Context) {
+	preferences, err := controller.uc.GetPreferences(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, preferences)
+}
+
+// SetPreferences takes an object mapping notification types to whether they are
+// delivered; types it does not name keep their setting.
+func (controller *NotificationController) SetPreferences(c *gin.Context) {
+	var req domain.NotificationPreferences
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	preferences, err := controller.uc.SetPreferences(c.Request.Context(), c.GetString("userID"), req)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, preferences)
+}
+
+// --- TaskBulkController ---
+
+type TaskBulkController struct {
+	uc *usecases.TaskBulkUseCase
+}
+
+func NewTaskBulkController(bulkUC *usecases.TaskBulkUseCase) *TaskBulkController {
+	return &TaskBulkController{
+		uc: bulkUC,
+	}
+}
+
+func (controller *TaskBulkController) ExecuteBulk(c *gin.Context) {
+	var req BulkTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+
+	var result *usecases.BulkResult
+	var err error
+	switch {
+	case len(req.Operations) > 0 && req.Filter == nil && req.Update == nil:
+		operations := make([]usecases.BulkOperation, 0, len(req.Operations))
+		for _, op := range req.Operations {
+			operations = append(operations, usecases.BulkOperation{
+				Action:      op.Action,
+				TaskID:      op.ID,
+				Title:       op.Title,
+				Description: op.Description,
+				DueDate:     optionalDueDateIn(c, op.DueDate),
+				Status:      op.Status,
+			})
+		}
+		result, err = controller.uc.Execute(c.Request.Context(), operations, req.Atomic)
+	case len(req.Operations) == 0 && req.Filter != nil && req.Update != nil:
+		filter := usecases.TaskQuery{
+			Statuses:  req.Filter.Status,
+			ProjectID: req.Filter.ProjectID,
+			DueBefore: optionalDueDateIn(c, req.Filter.DueBefore),
+			DueAfter:  optionalDueDateIn(c, req.Filter.DueAfter),
+		}
+		result, err = controller.uc.UpdateWhere(c.Request.Context(), filter,
+			req.Update.Title, req.Update.Description, optionalDueDateIn(c, req.Update.DueDate), req.Update.Status, req.Atomic)
+	default:
+		sendErrorResponse(c, http.StatusBadRequest, infrastructure.CodeInvalidRequestBody, "request must contain either operations, or a filter together with an update")
+		return
+	}
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+
+	// Item errors are reported like the errors of single requests, so that internal
+	// details stay in the log.
+	failed := result.FirstFailure()
+	var failedStatus int
+	var failedCode infrastructure.ErrorCode
+	for _, item := range result.Results {
+		if item.Err() == nil {
+			continue
+		}
+		status, code, detail := infrastructure.ClassifyRequestError(c, item.Err())
+		item.Error = detail
+		if item == failed {
+			failedStatus, failedCode = status, code
+		}
+	}
+
+	if result.Atomic && !result.Committed && failed != nil {
+		problem := infrastructure.NewProblem(c, failedStatus, infrastructure.CodeBulkRolledBack,
+			fmt.Sprintf("bulk operation rolled back: operation %d failed: %s", failed.Index, failed.Error))
+		infrastructure.AbortWithProblemBody(c, failedStatus, bulkRollbackProblem{
+			Problem:         problem,
+			Committed:       false,
+			FailedOperation: bulkFailure{Index: failed.Index, Code: failedCode},
+			Results:         result.Results,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// --- TaskController ---
+
+type TaskController struct {
+	uc *usecases.TaskUseCase
+}
+
+func NewTaskController(taskUC *usecases.TaskUseCase) *TaskController {
+	return &TaskController{
+		uc: taskUC,
+	}
+}
+
+func (controller *TaskController) CreateTask(c *gin.Context) {
+	var req CreateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+
+	createdTask, err := controller.uc.CreateTask(c.Request.Context(), req.Title, req.Description, dueDateIn(c, req.DueDate), req.Status)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, createdTask)
+}
+
+func (controller *TaskController) GetTaskByID(c *gin.Context) {
+	taskID := c.Param("id")
+
+	task, err := controller.uc.GetTaskByID(c.Request.Context(), taskID)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+
+	infrastructure.JSONWithETag(c, task)
+}
+
+func (controller *TaskController) GetAllTasks(c *gin.Context) {
+	tasks, err := controller.uc.GetAllTasks(c.Request.Context())
+	if err != nil {
+		sendInternalErrorResponse(c, err)
+		return
+	}
+
+	infrastructure.JSONWithETag(c, tasks)
+}
+
+// UpdateTask replaces the task with the request body.
+func (controller *TaskController) UpdateTask(c *gin.Context) {
+	controller.replaceTask(c, c.Param("id"))
+}
+
+// PatchTask applies a JSON Merge Patch or a JSON Patch, depending on the content type.
+func (controller *TaskController) PatchTask(c *gin.Context) {
+	controller.patchTask(c, c.Param("id"))
+}
+
+func (controller *TaskController) replaceTask(c *gin.Context, taskID string) {
+	var req ReplaceTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	updatedTask, err := controller.uc.ReplaceTask(c.Request.Context(), taskID, req.Title, req.Description, *req.DueDate, req.Status, req.Tags)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updatedTask)
+}
+
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
+// patchTask treats plain application/json as a merge patch, which is what clients that
+// do not know about patch formats send.
+func (controller *TaskController) patchTask(c *gin.Context, taskID string) {
+	var updatedTask *domain.Task
+	var err error
+	switch c.ContentType() {
+	case mergePatchContentType, "application/json":
+		var patch domain.TaskPatch
+		if err := c.ShouldBindJSON(&patch); err != nil {
+			sendBindingErrorResponse(c, err)
+			return
+		}
+		updatedTask, err = controller.uc.PatchTask(c.Request.Context(), taskID, patch)
+	case jsonPatchContentType:
+		var operations []domain.PatchOperation
+		if err := c.ShouldBindJSON(&operations); err != nil {
+			sendBindingErrorResponse(c, err)
+			return
+		}
+		updatedTask, err = controller.uc.JSONPatchTask(c.Request.Context(), taskID, operations)
+	default:
+		sendErrorResponse(c, http.StatusUnsupportedMediaType, infrastructure.CodeUnsupportedContentType,
+			"PATCH requires "+mergePatchContentType+" or "+jsonPatchContentType)
+		return
+	}
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updatedTask)
+}
+
+func (controller *TaskController) DeleteTask(c *gin.Context) {
+	taskID := c.Param("id")
+
+	err := controller.uc.DeleteTask(c.Request.Context(), taskID)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (controller *TaskController) SetTags(c *gin.Context) {
+	var req SetTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	task, err := controller.uc.SetTags(c.Request.Context(), c.Param("id"), req.Tags)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// GetTaskStats accepts owner (a user ID, or "me"), tag, project_id, from and to
+// (YYYY-MM-DD) and tz (an IANA time zone name) query parameters.
+func (controller *TaskController) GetTaskStats(c *gin.Context) {
+	query := usecases.TaskStatsQuery{
+		OwnerID:   c.Query("owner"),
+		Tag:       c.Query("tag"),
+		ProjectID: c.Query("project_id"),
+	}
+	if query.OwnerID == "me" {
+		query.OwnerID = c.GetString("userID")
+	}
+	if tz := c.Query("tz"); tz != "" {
+		location, err := domain.LoadTimeZone(tz)
+		if err != nil {
+			sendErrorResponse(c, http.StatusBadRequest, infrastructure.CodeBadRequest, fmt.Sprintf("unknown time zone %q", tz))
+			return
+		}
+		query.Location = location
+	}
+	// A slice rather than a map keeps the reported error stable when both are bad.
+	bounds := []struct {
+		name   string
+		target **time.Time
+	}{{"from", &query.From}, {"to", &query.To}}
+	for _, bound := range bounds {
+		if value := c.Query(bound.name); value != "" {
+			day, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				sendErrorResponse(c, http.StatusBadRequest, infrastructure.CodeBadRequest, bound.name+" must be a date in YYYY-MM-DD format")
+				return
+			}
+			*bound.target = &day
+		}
+	}
+
+	stats, err := controller.uc.GetTaskStats(c.Request.Context(), query)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// --- Task dependency handlers ---
+
+func (controller *TaskController) AddDependency(c *gin.Context) {
+	var req AddDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	task, err := controller.uc.AddDependency(c.Request.Context(), c.Param("id"), req.BlockedBy)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+func (controller *TaskController) RemoveDependency(c *gin.Context) {
+	task, err := controller.uc.RemoveDependency(c.Request.Context(), c.Param("id"), c.Param("blockerId"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+func (controller *TaskController) GetDependencyGraph(c *gin.Context) {
+	tasks, err := controller.uc.GetDependencyGraph(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tasks)
+}
+
+// --- Task watcher handlers ---
+
+func (controller *TaskController) WatchTask(c *gin.Context) {
+	task, err := controller.uc.WatchTask(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+func (controller *TaskController) UnwatchTask(c *gin.Context) {
+	task, err := controller.uc.UnwatchTask(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// --- Project-scoped task handlers ---
+
+func (controller *TaskController) GetProjectTasks(c *gin.Context) {
+	tasks, err := controller.uc.GetProjectTasks(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	infrastructure.JSONWithETag(c, tasks)
+}
+
+func (controller *TaskController) CreateProjectTask(c *gin.Context) {
+	var req CreateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendBindingErrorResponse(c, err)
+		return
+	}
+	task, err := controller.uc.CreateProjectTask(c.Request.Context(), c.Param("id"), req.Title, req.Description, dueDateIn(c, req.DueDate), req.Status)
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, task)
+}
+
+func (controller *TaskController) GetProjectTask(c *gin.Context) {
+	ctx := c.Request.Context()
+	if err := controller.uc.EnsureTaskInProject(ctx, c.Param("id"), c.Param("taskId")); err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	task, err := controller.uc.GetTaskByID(ctx, c.Param("taskId"))
+	if err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	infrastructure.JSONWithETag(c, task)
+}
+
+func (controller *TaskController) UpdateProjectTask(c *gin.Context) {
+	if err := controller.uc.EnsureTaskInProject(c.Request.Context(), c.Param("id"), c.Param("taskId")); err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	controller.replaceTask(c, c.Param("taskId"))
+}
+
+func (controller *TaskController) PatchProjectTask(c *gin.Context) {
+	if err := controller.uc.EnsureTaskInProject(c.Request.Context(), c.Param("id"), c.Param("taskId")); err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	controller.patchTask(c, c.Param("taskId"))
+}
+
+func (controller *TaskController) DeleteProjectTask(c *gin.Context) {
+	ctx := c.Request.Context()
+	if err := controller.uc.EnsureTaskInProject(ctx, c.Param("id"), c.Param("taskId")); err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
+	if err := controller.uc.DeleteTask(ctx, c.Param("taskId")); err != nil {
+		sendDomainErrorResponse(c, err)
+		return
+	}
 	c.Status(http.StatusNoContent)
 }
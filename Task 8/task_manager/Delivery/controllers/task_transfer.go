@@ -0,0 +1,408 @@
+package controllers
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	infrastructure "A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportBytes limits the size of an import file.
+const maxImportBytes = 32 << 20
+
+// csvColumns is the column order of CSV exports. Imports match columns by name and
+// ignore the ones they do not use, so an export can be imported again.
+var csvColumns = []string{"id", "external_id", "title", "description", "duedate", "status", "project_id", "blocked_by"}
+
+// ExportTasks streams the tasks visible to the caller as CSV, a JSON array or
+// newline-delimited JSON. The filters are the same as for bulk updates.
+func (controller *TaskController) ExportTasks(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	encoder, contentType, ok := newTaskEncoder(format, c.Writer)
+	if !ok {
+		sendErrorResponse(c, http.StatusBadRequest, infrastructure.CodeBadRequest, fmt.Sprintf("unsupported export format %q (expected csv, json or ndjson)", format))
+		return
+	}
+	query, err := taskQueryFromRequest(c)
+	if err != nil {
+		sendErrorResponse(c, http.StatusBadRequest, infrastructure.CodeBadRequest, err.Error())
+		return
+	}
+
+	started := false
+	err = controller.uc.ExportTasks(c.Request.Context(), query, func(task *domain.Task) error {
+		if !started {
+			started = true
+			writeExportHeaders(c, contentType, format)
+			if err := encoder.begin(); err != nil {
+				return err
+			}
+		}
+		return encoder.encode(task)
+	})
+	if err == nil && !started {
+		// Nothing matched; an empty export is still a valid document.
+		started = true
+		writeExportHeaders(c, contentType, format)
+		err = encoder.begin()
+	}
+	if err == nil {
+		err = encoder.end()
+	}
+	if err != nil {
+		if started {
+			// The status line is already sent; all we can do is cut the response short.
+			log.Printf("task export aborted: %v", err)
+			c.Abort()
+			return
+		}
+		sendDomainErrorResponse(c, err)
+	}
+}
+
+func writeExportHeaders(c *gin.Context, contentType, format string) {
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "tasks." + format}))
+	c.Status(http.StatusOK)
+}
+
+// ImportTasks creates tasks from an uploaded CSV, JSON or NDJSON file sent as the
+// request body. The format comes from the format query parameter or the Content-Type.
+func (controller *TaskController) ImportTasks(c *gin.Context) {
+	format := c.Query("format")
+	if format == "" {
+		format = importFormatFromContentType(c.ContentType())
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+	source, err := newTaskImportSource(format, c.Request.Body)
+	if err != nil {
+		sendErrorResponse(c, http.StatusBadRequest, infrastructure.CodeBadRequest, err.Error())
+		return
+	}
+
+	opts := usecases.TaskImportOptions{
+		DryRun: c.Query("dry_run") == "true",
+		Upsert: c.Query("upsert") == "true",
+	}
+	report, err := controller.uc.ImportTasks(c.Request.Context(), source, opts)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		status, code := http.StatusBadRequest, infrastructure.CodeImportUnreadable
+		if errors.As(err, &tooLarge) {
+			status, code = http.StatusRequestEntityTooLarge, infrastructure.CodePayloadTooLarge
+		} else if !errors.Is(err, domain.ErrValidationFailed) {
+			sendInternalErrorResponse(c, err)
+			return
+		}
+		// Rows before the unreadable part may already be imported, so report them too.
+		infrastructure.AbortWithProblemBody(c, status, importFailureProblem{
+			Problem: infrastructure.NewProblem(c, status, code, strings.TrimPrefix(err.Error(), domain.ErrValidationFailed.Error()+": ")),
+			Report:  report,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// importFailureProblem reports an import file that could not be read to the end.
+type importFailureProblem struct {
+	infrastructure.Problem
+	Report *usecases.ImportReport `json:"report"`
+}
+
+func importFormatFromContentType(contentType string) string {
+	switch contentType {
+	case "text/csv":
+		return "csv"
+	case "application/x-ndjson":
+		return "ndjson"
+	default:
+		return "json"
+	}
+}
+
+// taskQueryFromRequest reads the status, project_id, due_before and due_after query
+// parameters. Statuses may be repeated or comma-separated.
+func taskQueryFromRequest(c *gin.Context) (usecases.TaskQuery, error) {
+	query := usecases.TaskQuery{ProjectID: c.Query("project_id")}
+	for _, value := range c.QueryArray("status") {
+		for _, status := range strings.Split(value, ",") {
+			query.Statuses = append(query.Statuses, domain.TaskStatus(strings.TrimSpace(status)))
+		}
+	}
+	bounds := []struct {
+		name   string
+		target **time.Time
+	}{{"due_before", &query.DueBefore}, {"due_after", &query.DueAfter}}
+	for _, bound := range bounds {
+		if value := c.Query(bound.name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return query, fmt.Errorf("%s must be an RFC 3339 timestamp", bound.name)
+			}
+			*bound.target = &parsed
+		}
+	}
+	return query, nil
+}
+
+// --- Export encoders ---
+
+type taskEncoder interface {
+	begin() error
+	encode(task *domain.Task) error
+	end() error
+}
+
+func newTaskEncoder(format string, w io.Writer) (taskEncoder, string, bool) {
+	switch format {
+	case "csv":
+		return &csvTaskEncoder{w: csv.NewWriter(w)}, "text/csv; charset=utf-8", true
+	case "json":
+		return &jsonTaskEncoder{w: w, enc: json.NewEncoder(w)}, "application/json; charset=utf-8", true
+	case "ndjson":
+		return &ndjsonTaskEncoder{enc: json.NewEncoder(w)}, "application/x-ndjson", true
+	}
+	return nil, "", false
+}
+
+type csvTaskEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvTaskEncoder) begin() error {
+	return e.w.Write(csvColumns)
+}
+
+func (e *csvTaskEncoder) encode(task *domain.Task) error {
+	projectID := ""
+	if !task.ProjectID.IsZero() {
+		projectID = task.ProjectID.Hex()
+	}
+	blockers := make([]string, 0, len(task.BlockedBy))
+	for _, blocker := range task.BlockedBy {
+		blockers = append(blockers, blocker.Hex())
+	}
+	return e.w.Write([]string{
+		task.Id.Hex(),
+		escapeCSVCell(task.ExternalID),
+		escapeCSVCell(task.Title),
+		escapeCSVCell(task.Description),
+		task.DueDate.UTC().Format(time.RFC3339),
+		string(task.Status),
+		projectID,
+		strings.Join(blockers, ";"),
+	})
+}
+
+// escapeCSVCell prefixes a quote to values a spreadsheet would evaluate as a
+// formula. CSV imports strip the prefix again.
+func escapeCSVCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeCSVCell reverses escapeCSVCell.
+func unescapeCSVCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func (e *csvTaskEncoder) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonTaskEncoder writes a JSON array one element at a time.
+type jsonTaskEncoder struct {
+	w     io.Writer
+	enc   *json.Encoder
+	count int
+}
+
+func (e *jsonTaskEncoder) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonTaskEncoder) encode(task *domain.Task) error {
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+	return e.enc.Encode(task)
+}
+
+func (e *jsonTaskEncoder) end() error {
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
+
+type ndjsonTaskEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonTaskEncoder) begin() error { return nil }
+
+func (e *ndjsonTaskEncoder) encode(task *domain.Task) error {
+	return e.enc.Encode(task)
+}
+
+func (e *ndjsonTaskEncoder) end() error { return nil }
+
+// --- Import decoders ---
+
+// importRecord is the JSON shape of an imported task. The due date is decoded as a
+// string so that a bad value fails only its own row.
+type importRecord struct {
+	ExternalID  string `json:"external_id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	DueDate     string `json:"duedate"`
+	Status      string `json:"status"`
+}
+
+func (r *importRecord) toRow(row int) (*usecases.TaskImportRow, error) {
+	// Plain dates are placed in the caller's time zone by the import.
+	var dueDate domain.DueDate
+	if r.DueDate != "" {
+		var err error
+		dueDate, err = domain.ParseDueDate(r.DueDate)
+		if err != nil {
+			return nil, &usecases.MalformedRowError{Row: row, Err: err}
+		}
+	}
+	return &usecases.TaskImportRow{
+		Row:         row,
+		ExternalID:  strings.TrimSpace(r.ExternalID),
+		Title:       r.Title,
+		Description: r.Description,
+		DueDate:     dueDate,
+		Status:      domain.TaskStatus(r.Status),
+	}, nil
+}
+
+func newTaskImportSource(format string, r io.Reader) (usecases.TaskImportSource, error) {
+	switch format {
+	case "csv":
+		return newCSVImportSource(r)
+	case "json":
+		return newJSONImportSource(r)
+	case "ndjson":
+		return newNDJSONImportSource(r), nil
+	}
+	return nil, fmt.Errorf("unsupported import format %q (expected csv, json or ndjson)", format)
+}
+
+func newCSVImportSource(r io.Reader) (usecases.TaskImportSource, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"title", "duedate", "status"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %q column", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	row := 0
+	return func() (*usecases.TaskImportRow, error) {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		row++
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, &usecases.MalformedRowError{Row: row, Err: parseErr.Err}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return (&importRecord{
+			ExternalID:  unescapeCSVCell(field(record, "external_id")),
+			Title:       unescapeCSVCell(field(record, "title")),
+			Description: unescapeCSVCell(field(record, "description")),
+			DueDate:     field(record, "duedate"),
+			Status:      field(record, "status"),
+		}).toRow(row)
+	}, nil
+}
+
+func newJSONImportSource(r io.Reader) (usecases.TaskImportSource, error) {
+	decoder := json.NewDecoder(r)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, errors.New("JSON import must be an array of tasks")
+	}
+	row := 0
+	return func() (*usecases.TaskImportRow, error) {
+		if !decoder.More() {
+			return nil, io.EOF
+		}
+		row++
+		// Decoding into a RawMessage first keeps the decoder usable when a row has wrong types.
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		var record importRecord
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return nil, &usecases.MalformedRowError{Row: row, Err: err}
+		}
+		return record.toRow(row)
+	}, nil
+}
+
+func newNDJSONImportSource(r io.Reader) usecases.TaskImportSource {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportBytes)
+	row := 0
+	return func() (*usecases.TaskImportRow, error) {
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			row++
+			var record importRecord
+			if err := json.Unmarshal(line, &record); err != nil {
+				return nil, &usecases.MalformedRowError{Row: row, Err: err}
+			}
+			return record.toRow(row)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+}
@@ -0,0 +1,107 @@
+package graphqlapi
+
+import (
+	infrastructure "A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Request is the body of a GraphQL request.
+type Request struct {
+	Query         string         `json:"query" binding:"required"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Handler serves GraphQL requests. Callers are authenticated by
+// AuthMiddleware.Authenticate before the handler runs, and resolvers read them from the
+// request context like the REST controllers' use cases do.
+type Handler struct {
+	schema graphql.Schema
+	limits Limits
+}
+
+func NewHandler(tasks *usecases.TaskUseCase, users *usecases.UserUseCase, limits Limits) (*Handler, error) {
+	schema, err := NewSchema(tasks, users)
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{schema: schema, limits: limits}, nil
+}
+
+// Serve executes the query in the request body. As usual for GraphQL, the response is
+// 200 OK with the errors listed in the body, even if the query could not be executed.
+func (h *Handler) Serve(c *gin.Context) {
+	var req Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		infrastructure.AbortWithBindingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, h.Execute(c.Request.Context(), req))
+}
+
+// Execute parses, validates and, if it is within the limits, runs a request.
+func (h *Handler) Execute(ctx context.Context, req Request) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		return &graphql.Result{Errors: withCode(gqlerrors.FormatErrors(err), infrastructure.CodeBadRequest)}
+	}
+	if validation := graphql.ValidateDocument(&h.schema, doc, nil); !validation.IsValid {
+		return &graphql.Result{Errors: withCode(validation.Errors, infrastructure.CodeBadRequest)}
+	}
+	operation := findOperation(doc, req.OperationName)
+	if operation == nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(&Error{Message: "unknown operation " + req.OperationName, Code: infrastructure.CodeBadRequest})}
+	}
+	if err := checkLimits(h.schema, doc, operation, h.limits); err != nil {
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{formatError(err)}}
+	}
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       contextWithUserCache(ctx),
+	})
+}
+
+// findOperation returns the operation to run: the one named, or the only one.
+func findOperation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" && found != nil {
+			return nil
+		}
+		if name == "" || (operation.Name != nil && operation.Name.Value == name) {
+			found = operation
+		}
+	}
+	return found
+}
+
+// formatError formats an error raised outside of a resolver, keeping its extensions.
+func formatError(err *Error) gqlerrors.FormattedError {
+	formatted := gqlerrors.NewFormattedError(err.Message)
+	formatted.Extensions = err.Extensions()
+	return formatted
+}
+
+// withCode gives syntax and validation errors a code like every other error.
+func withCode(errs []gqlerrors.FormattedError, code infrastructure.ErrorCode) []gqlerrors.FormattedError {
+	for i := range errs {
+		errs[i].Extensions = map[string]any{"code": code}
+	}
+	return errs
+}
@@ -0,0 +1,316 @@
+package graphqlapi_test
+
+import (
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/graphqlapi"
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	infrastructure "A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"A2SV_ProjectPhase/Task8/TaskManager/Repositories"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// --- In-memory repositories ---
+
+// memoryUserRepository counts lookups so that tests can check owners are cached.
+type memoryUserRepository struct {
+	users   map[primitive.ObjectID]*domain.User
+	lookups int
+}
+
+func (r *memoryUserRepository) CreateUser(c context.Context, user *domain.User) (*domain.User, error) {
+	r.users[user.Id] = user
+	return user, nil
+}
+
+func (r *memoryUserRepository) GetUserByUsername(c context.Context, username string) (*domain.User, error) {
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *memoryUserRepository) GetUserById(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+	r.lookups++
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *memoryUserRepository) GetUserByCalendarTokenHash(c context.Context, tokenHash string) (*domain.User, error) {
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *memoryUserRepository) UpdateUser(c context.Context, id primitive.ObjectID, user *domain.User) (*domain.User, error) {
+	r.users[id] = user
+	return user, nil
+}
+
+func (r *memoryUserRepository) GetAllUsers(c context.Context) ([]*domain.User, error) {
+	users := make([]*domain.User, 0, len(r.users))
+	for _, user := range r.users {
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func (r *memoryUserRepository) DeleteUser(c context.Context, id primitive.ObjectID) error {
+	if _, ok := r.users[id]; !ok {
+		return domain.ErrUserNotFound
+	}
+	delete(r.users, id)
+	return nil
+}
+
+func (r *memoryUserRepository) RecordTwoFactorStep(c context.Context, id primitive.ObjectID, step int64) (bool, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return false, domain.ErrUserNotFound
+	}
+	if step <= user.LastTwoFactorStep {
+		return false, nil
+	}
+	user.LastTwoFactorStep = step
+	return true, nil
+}
+
+func (r *memoryUserRepository) ConsumeRecoveryCode(c context.Context, id primitive.ObjectID, hash string) (bool, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return false, domain.ErrUserNotFound
+	}
+	i := slices.Index(user.RecoveryCodeHashes, hash)
+	if i < 0 {
+		return false, nil
+	}
+	user.RecoveryCodeHashes = slices.Delete(user.RecoveryCodeHashes, i, i+1)
+	return true, nil
+}
+
+func (r *memoryUserRepository) RecordTwoFactorAttempt(c context.Context, id primitive.ObjectID, at time.Time) (int, *time.Time, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return 0, nil, domain.ErrUserNotFound
+	}
+	failures, lastFailure := user.TwoFactorFailures, user.LastTwoFactorFailure
+	user.TwoFactorFailures++
+	user.LastTwoFactorFailure = &at
+	return failures, lastFailure, nil
+}
+
+func (r *memoryUserRepository) ResetTwoFactorFailures(c context.Context, id primitive.ObjectID) error {
+	if user, ok := r.users[id]; ok {
+		user.TwoFactorFailures, user.LastTwoFactorFailure = 0, nil
+	}
+	return nil
+}
+
+//===========================================================================
+// GraphQL Handler Test Suite
+//===========================================================================
+
+type GraphQLHandlerSuite struct {
+	suite.Suite
+	users   *memoryUserRepository
+	tasks   *usecases.TaskUseCase
+	handler *graphqlapi.Handler
+	user    *domain.User
+	ctx     context.Context
+}
+
+func TestGraphQLHandlerSuite(t *testing.T) {
+	suite.Run(t, new(GraphQLHandlerSuite))
+}
+
+func (s *GraphQLHandlerSuite) SetupTest() {
+	s.user = &domain.User{Id: primitive.NewObjectID(), OrganizationID: primitive.NewObjectID(), Username: "dashboard-user", Role: domain.RoleAdmin, TimeZone: "Europe/Berlin"}
+	s.users = &memoryUserRepository{users: map[primitive.ObjectID]*domain.User{s.user.Id: s.user}}
+	s.tasks = usecases.NewTaskUseCase(repositories.NewInMemoryTaskRepository(), repositories.NewInMemoryProjectRepository())
+	s.handler = s.newHandler(graphqlapi.DefaultLimits)
+	s.ctx = s.contextFor(domain.AllPermissions()...)
+}
+
+func (s *GraphQLHandlerSuite) newHandler(limits graphqlapi.Limits) *graphqlapi.Handler {
+	handler, err := graphqlapi.NewHandler(s.tasks, usecases.NewUserUseCase(s.users, nil, nil, nil, nil, nil, nil), limits)
+	s.Require().NoError(err)
+	return handler
+}
+
+func (s *GraphQLHandlerSuite) contextFor(permissions ...domain.Permission) context.Context {
+	c := domain.ContextWithOrganization(context.Background(), s.user.OrganizationID)
+	return domain.ContextWithActor(c, &domain.Actor{
+		UserID:      s.user.Id.Hex(),
+		Username:    s.user.Username,
+		Role:        s.user.Role,
+		Permissions: permissions,
+	})
+}
+
+func (s *GraphQLHandlerSuite) execute(ctx context.Context, query string, variables map[string]any) *graphql.Result {
+	return s.handler.Execute(ctx, graphqlapi.Request{Query: query, Variables: variables})
+}
+
+// errorCode returns the code of the only error in result.
+func (s *GraphQLHandlerSuite) errorCode(result *graphql.Result) any {
+	s.Require().Len(result.Errors, 1, "errors: %v", result.Errors)
+	return result.Errors[0].Extensions["code"]
+}
+
+func (s *GraphQLHandlerSuite) createTask(title, status string) string {
+	result := s.execute(s.ctx, `mutation($input: CreateTaskInput!) { createTask(input: $input) { id } }`, map[string]any{
+		"input": map[string]any{"title": title, "dueDate": time.Now().AddDate(0, 0, 7).Format(time.DateOnly), "status": status},
+	})
+	s.Require().Empty(result.Errors)
+	return result.Data.(map[string]any)["createTask"].(map[string]any)["id"].(string)
+}
+
+func (s *GraphQLHandlerSuite) TestTasksQuery() {
+	s.createTask("Plan the sprint", "PENDING")
+	s.createTask("Ship the dashboard", "DONE")
+	s.createTask("Review the designs", "PENDING")
+
+	result := s.execute(s.ctx, `{ tasks(statuses: [PENDING]) { title status owner { username } } }`, nil)
+	s.Require().Empty(result.Errors)
+	tasks := result.Data.(map[string]any)["tasks"].([]any)
+	s.Len(tasks, 2)
+	for _, task := range tasks {
+		task := task.(map[string]any)
+		s.Equal("PENDING", task["status"])
+		s.Equal(map[string]any{"username": "dashboard-user"}, task["owner"])
+	}
+	s.Equal(1, s.users.lookups, "The owner shared by both tasks should only be loaded once")
+}
+
+func (s *GraphQLHandlerSuite) TestMutations() {
+	id := s.createTask("Draft", "PENDING")
+
+	result := s.execute(s.ctx, `mutation($id: ID!) { updateTask(id: $id, input: {title: "Final", status: DONE, tags: ["ui"]}) { title description status tags completedAt } }`,
+		map[string]any{"id": id})
+	s.Require().Empty(result.Errors)
+	updated := result.Data.(map[string]any)["updateTask"].(map[string]any)
+	s.Equal("Final", updated["title"])
+	s.Equal("DONE", updated["status"])
+	s.Equal([]any{"ui"}, updated["tags"])
+	s.NotNil(updated["completedAt"], "Completing the task should record when")
+
+	result = s.execute(s.ctx, `mutation($id: ID!) { deleteTask(id: $id) }`, map[string]any{"id": id})
+	s.Require().Empty(result.Errors)
+	s.Equal(id, result.Data.(map[string]any)["deleteTask"])
+
+	result = s.execute(s.ctx, `query($id: ID!) { task(id: $id) { id } }`, map[string]any{"id": id})
+	s.Equal(infrastructure.CodeTaskNotFound, s.errorCode(result))
+}
+
+func (s *GraphQLHandlerSuite) TestErrors() {
+	s.Run("Missing Permission", func() {
+		readOnly := s.contextFor(domain.PermTasksRead)
+		result := s.execute(readOnly, `mutation { createTask(input: {title: "x", dueDate: "2999-01-01", status: PENDING}) { id } }`, nil)
+		s.Equal(infrastructure.CodeMissingPermission, s.errorCode(result))
+	})
+
+	s.Run("Validation Errors", func() {
+		result := s.execute(s.ctx, `mutation { createTask(input: {title: "x", dueDate: "next week", status: PENDING}) { id } }`, nil)
+		s.Equal(infrastructure.CodeValidationFailed, s.errorCode(result))
+		fields := result.Errors[0].Extensions["errors"].([]domain.FieldViolation)
+		s.Equal("duedate", fields[0].Field)
+	})
+
+	s.Run("Invalid Query", func() {
+		result := s.execute(s.ctx, `{ tasks { nonexistent } }`, nil)
+		s.Equal(infrastructure.CodeBadRequest, s.errorCode(result))
+	})
+}
+
+func (s *GraphQLHandlerSuite) TestLimits() {
+	s.createTask("Plan the sprint", "PENDING")
+	const query = `{ tasks { id owner { id username } } }`
+
+	handler := s.newHandler(graphqlapi.Limits{MaxDepth: 2})
+	result := handler.Execute(s.ctx, graphqlapi.Request{Query: query})
+	s.Equal(infrastructure.CodeQueryTooDeep, s.errorCode(result))
+	s.Nil(result.Data, "The query should not run")
+
+	// tasks (1) + 10 * (id (1) + owner (1 + id (1) + username (1))) = 41
+	handler = s.newHandler(graphqlapi.Limits{MaxComplexity: 40})
+	result = handler.Execute(s.ctx, graphqlapi.Request{Query: query})
+	s.Equal(infrastructure.CodeQueryTooComplex, s.errorCode(result))
+
+	s.Run("Fragments Count", func() {
+		result := handler.Execute(s.ctx, graphqlapi.Request{Query: `{ tasks { ...parts } } fragment parts on Task { id owner { id username } }`})
+		s.Equal(infrastructure.CodeQueryTooComplex, s.errorCode(result))
+	})
+
+	s.Run("Nested Fragments Are Walked Once", func() {
+		// Every fragment spreads the previous one twice, so the query expands to 2^40
+		// copies of id. Walking each copy would take hours.
+		var query strings.Builder
+		query.WriteString(`{ tasks { ...F40 } } fragment F0 on Task { id }`)
+		for i := 1; i <= 40; i++ {
+			fmt.Fprintf(&query, ` fragment F%d on Task { ...F%d ...F%d }`, i, i-1, i-1)
+		}
+		started := time.Now()
+		result := s.newHandler(graphqlapi.DefaultLimits).Execute(s.ctx, graphqlapi.Request{Query: query.String()})
+		s.Equal(infrastructure.CodeQueryTooComplex, s.errorCode(result))
+		s.Less(time.Since(started), time.Second)
+	})
+
+	s.Run("Introspection Counts Toward Depth Only", func() {
+		introspection := `{ __schema { types { name fields { name type { name ofType { name } } } } } }`
+		result := s.newHandler(graphqlapi.Limits{MaxDepth: 5}).Execute(s.ctx, graphqlapi.Request{Query: introspection})
+		s.Equal(infrastructure.CodeQueryTooDeep, s.errorCode(result))
+
+		result = s.newHandler(graphqlapi.Limits{MaxDepth: 6, MaxComplexity: 1}).Execute(s.ctx, graphqlapi.Request{Query: introspection})
+		s.Empty(result.Errors)
+	})
+
+	s.Run("Type Names Count Toward Depth Only", func() {
+		result := s.newHandler(graphqlapi.Limits{MaxDepth: 1}).Execute(s.ctx, graphqlapi.Request{Query: `{ me { __typename } }`})
+		s.Equal(infrastructure.CodeQueryTooDeep, s.errorCode(result))
+
+		result = s.newHandler(graphqlapi.Limits{MaxDepth: 2, MaxComplexity: 1}).Execute(s.ctx, graphqlapi.Request{Query: `{ me { __typename } }`})
+		s.Empty(result.Errors)
+	})
+
+	handler = s.newHandler(graphqlapi.Limits{MaxDepth: 3, MaxComplexity: 41})
+	result = handler.Execute(s.ctx, graphqlapi.Request{Query: query})
+	s.Empty(result.Errors, "A query at the limits should run")
+}
+
+func (s *GraphQLHandlerSuite) TestMe() {
+	result := s.execute(s.ctx, `{ me { id username role timeZone } }`, nil)
+	s.Require().Empty(result.Errors)
+	s.Equal(map[string]any{
+		"id":       s.user.Id.Hex(),
+		"username": "dashboard-user",
+		"role":     "Admin",
+		"timeZone": "Europe/Berlin",
+	}, result.Data.(map[string]any)["me"])
+}
+
+func (s *GraphQLHandlerSuite) TestServe() {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/graphql", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(s.ctx)
+	}, s.handler.Serve)
+
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "{ me { username } }"}`))
+	router.ServeHTTP(recorder, req)
+	s.Equal(http.StatusOK, recorder.Code)
+	s.JSONEq(`{"data": {"me": {"username": "dashboard-user"}}}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"variables": {}}`))
+	router.ServeHTTP(recorder, req)
+	s.Equal(http.StatusBadRequest, recorder.Code, "A request without a query should be rejected")
+}
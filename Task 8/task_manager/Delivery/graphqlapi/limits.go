@@ -0,0 +1,166 @@
+package graphqlapi
+
+import (
+	infrastructure "A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits bound the cost of a query before it is executed.
+type Limits struct {
+	// MaxDepth is the deepest allowed nesting of fields; `{ tasks { owner { id } } }`
+	// has a depth of 3.
+	MaxDepth int
+	// MaxComplexity is the highest allowed complexity. Every field costs 1, and the
+	// fields selected below a list count listComplexityFactor times.
+	MaxComplexity int
+}
+
+// DefaultLimits leave room for any reasonable dashboard query.
+var DefaultLimits = Limits{MaxDepth: 6, MaxComplexity: 500}
+
+// listComplexityFactor is the assumed length of lists when estimating complexity.
+const listComplexityFactor = 10
+
+// checkLimits rejects an operation that is too deep or too complex. Introspection
+// fields, whose names start with "__", count toward the depth, since types can be
+// nested without end through ofType, but not toward the complexity, since they only
+// read the schema. The document must already be valid.
+func checkLimits(schema graphql.Schema, doc *ast.Document, operation *ast.OperationDefinition, limits Limits) *Error {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+	root := schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	w := &costWalker{schema: schema, fragments: fragments, spreads: map[fragmentKey]cost{}, maxComplexity: math.MaxInt32}
+	if limits.MaxComplexity > 0 {
+		w.maxComplexity = limits.MaxComplexity + 1
+	}
+	depth, complexity := w.selectionSet(root, operation.SelectionSet)
+	if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		return &Error{
+			Message: fmt.Sprintf("query has a depth of %d, more than the allowed %d", depth, limits.MaxDepth),
+			Code:    infrastructure.CodeQueryTooDeep,
+		}
+	}
+	if limits.MaxComplexity > 0 && complexity > limits.MaxComplexity {
+		return &Error{
+			// The walk stops counting just past the limit, so the exact figure is unknown.
+			Message: fmt.Sprintf("query is more complex than the allowed %d", limits.MaxComplexity),
+			Code:    infrastructure.CodeQueryTooComplex,
+		}
+	}
+	return nil
+}
+
+type costWalker struct {
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	// spreads remembers the cost of each fragment, so that a fragment spread many
+	// times, or spreading other fragments many times, is only walked once per type.
+	spreads map[fragmentKey]cost
+	// maxComplexity caps every running total. Anything above the limit is rejected
+	// anyway, and the cap keeps fragments that double at every level from overflowing.
+	maxComplexity int
+}
+
+type fragmentKey struct {
+	name   string
+	parent *graphql.Object
+}
+
+type cost struct {
+	depth, complexity int
+}
+
+// selectionSet returns the depth and complexity of the fields selected on parent.
+func (w *costWalker) selectionSet(parent *graphql.Object, set *ast.SelectionSet) (depth, complexity int) {
+	if set == nil || parent == nil {
+		return 0, 0
+	}
+	for _, selection := range set.Selections {
+		var d, c int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			d, c = w.field(parent, selection)
+		case *ast.InlineFragment:
+			d, c = w.selectionSet(w.fragmentType(parent, selection.TypeCondition), selection.SelectionSet)
+		case *ast.FragmentSpread:
+			d, c = w.fragmentSpread(parent, selection)
+		}
+		depth = max(depth, d)
+		complexity = min(complexity+c, w.maxComplexity)
+	}
+	return depth, complexity
+}
+
+// fragmentSpread returns the cost of a named fragment spread on parent.
+func (w *costWalker) fragmentSpread(parent *graphql.Object, spread *ast.FragmentSpread) (depth, complexity int) {
+	fragment, ok := w.fragments[spread.Name.Value]
+	if !ok {
+		return 0, 0
+	}
+	key := fragmentKey{name: fragment.Name.Value, parent: parent}
+	if known, ok := w.spreads[key]; ok {
+		return known.depth, known.complexity
+	}
+	depth, complexity = w.selectionSet(w.fragmentType(parent, fragment.TypeCondition), fragment.SelectionSet)
+	w.spreads[key] = cost{depth: depth, complexity: complexity}
+	return depth, complexity
+}
+
+// metaFields are the introspection fields that every type or the query root has
+// without declaring them.
+var metaFields = map[string]*graphql.FieldDefinition{
+	"__schema":   graphql.SchemaMetaFieldDef,
+	"__type":     graphql.TypeMetaFieldDef,
+	"__typename": graphql.TypeNameMetaFieldDef,
+}
+
+func (w *costWalker) field(parent *graphql.Object, field *ast.Field) (depth, complexity int) {
+	definition, ok := parent.Fields()[field.Name.Value]
+	if !ok {
+		definition, ok = metaFields[field.Name.Value]
+	}
+	if !ok {
+		return 1, 1
+	}
+	fieldType, factor := definition.Type, 1
+	for {
+		switch t := fieldType.(type) {
+		case *graphql.NonNull:
+			fieldType = t.OfType
+			continue
+		case *graphql.List:
+			fieldType, factor = t.OfType, factor*listComplexityFactor
+			continue
+		}
+		break
+	}
+	object, _ := fieldType.(*graphql.Object)
+	childDepth, childComplexity := w.selectionSet(object, field.SelectionSet)
+	if strings.HasPrefix(field.Name.Value, "__") {
+		return 1 + childDepth, 0
+	}
+	return 1 + childDepth, min(1+factor*childComplexity, w.maxComplexity)
+}
+
+// fragmentType is the type a fragment selects on; fragments without a type condition
+// select on the enclosing type.
+func (w *costWalker) fragmentType(parent *graphql.Object, condition *ast.Named) *graphql.Object {
+	if condition == nil {
+		return parent
+	}
+	object, _ := w.schema.Type(condition.Name.Value).(*graphql.Object)
+	return object
+}
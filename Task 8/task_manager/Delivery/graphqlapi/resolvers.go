@@ -0,0 +1,216 @@
+package graphqlapi
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	infrastructure "A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Error is a GraphQL error whose code, the same as in the REST API's problems, is
+// reported in the "code" extension.
+type Error struct {
+	Message string
+	Code    infrastructure.ErrorCode
+	// Fields lists the invalid fields of validation errors.
+	Fields []domain.FieldViolation
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Extensions() map[string]any {
+	extensions := map[string]any{"code": e.Code}
+	if len(e.Fields) > 0 {
+		extensions["errors"] = e.Fields
+	}
+	return extensions
+}
+
+// resolverError converts an error returned by a use case. Errors without a mapping are
+// logged and reported as internal errors, so their text never reaches the client.
+func resolverError(p graphql.ResolveParams, err error) error {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return &Error{Message: validationErr.Error(), Code: infrastructure.CodeValidationFailed, Fields: validationErr.Violations}
+	}
+	if _, code, detail, ok := infrastructure.ClassifyError(err); ok {
+		return &Error{Message: detail, Code: code}
+	}
+	log.Printf("graphql %s: %v\n", p.Info.FieldName, err)
+	return &Error{Message: "An unexpected error occurred", Code: infrastructure.CodeInternal}
+}
+
+// requirePermission only resolves the field for callers holding permission, like
+// AuthMiddleware.RequirePermission does for REST routes.
+func requirePermission(permission domain.Permission, resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		actor, ok := domain.ActorFromContext(p.Context)
+		if !ok || !actor.Has(permission) {
+			return nil, &Error{Message: "Access forbidden: missing permission " + string(permission), Code: infrastructure.CodeMissingPermission}
+		}
+		return resolve(p)
+	}
+}
+
+func (r *resolver) listTasks(p graphql.ResolveParams) (any, error) {
+	tasks := []*domain.Task{}
+	err := r.tasks.ExportTasks(p.Context, taskQuery(p.Args), func(task *domain.Task) error {
+		tasks = append(tasks, task)
+		return nil
+	})
+	if err != nil {
+		return nil, resolverError(p, err)
+	}
+	return tasks, nil
+}
+
+func (r *resolver) getTask(p graphql.ResolveParams) (any, error) {
+	task, err := r.tasks.GetTaskByID(p.Context, p.Args["id"].(string))
+	if err != nil {
+		return nil, resolverError(p, err)
+	}
+	return task, nil
+}
+
+func (r *resolver) createTask(p graphql.ResolveParams) (any, error) {
+	input := p.Args["input"].(map[string]any)
+	dueDate, err := parseDueDate(input["dueDate"].(string))
+	if err != nil {
+		return nil, resolverError(p, err)
+	}
+	location, _ := domain.LocationFromContext(p.Context)
+	title := input["title"].(string)
+	description, _ := input["description"].(string)
+	status := input["status"].(domain.TaskStatus)
+
+	var task *domain.Task
+	if projectID, ok := input["projectId"].(string); ok {
+		task, err = r.tasks.CreateProjectTask(p.Context, projectID, title, description, dueDate.In(location), status)
+	} else {
+		task, err = r.tasks.CreateTask(p.Context, title, description, dueDate.In(location), status)
+	}
+	if err != nil {
+		return nil, resolverError(p, err)
+	}
+	return task, nil
+}
+
+// updateTaskFields maps the fields of UpdateTaskInput to the members of a task merge patch.
+var updateTaskFields = map[string]string{
+	"title":       "title",
+	"description": "description",
+	"dueDate":     "duedate",
+	"status":      "status",
+	"tags":        "tags",
+}
+
+// updateTask applies the input as a merge patch, so it validates and records history
+// exactly like PATCH /tasks/{id}.
+func (r *resolver) updateTask(p graphql.ResolveParams) (any, error) {
+	members := map[string]any{}
+	for name, value := range p.Args["input"].(map[string]any) {
+		members[updateTaskFields[name]] = value
+	}
+	data, err := json.Marshal(members)
+	if err != nil {
+		return nil, resolverError(p, err)
+	}
+	var patch domain.TaskPatch
+	if err := patch.UnmarshalJSON(data); err != nil {
+		return nil, resolverError(p, err)
+	}
+	task, err := r.tasks.PatchTask(p.Context, p.Args["id"].(string), patch)
+	if err != nil {
+		return nil, resolverError(p, err)
+	}
+	return task, nil
+}
+
+func (r *resolver) deleteTask(p graphql.ResolveParams) (any, error) {
+	id := p.Args["id"].(string)
+	if err := r.tasks.DeleteTask(p.Context, id); err != nil {
+		return nil, resolverError(p, err)
+	}
+	return id, nil
+}
+
+func (r *resolver) me(p graphql.ResolveParams) (any, error) {
+	actor, ok := domain.ActorFromContext(p.Context)
+	if !ok {
+		return nil, &Error{Message: "Authorization token required", Code: infrastructure.CodeAuthenticationRequired}
+	}
+	return actor, nil
+}
+
+func (r *resolver) viewerTimeZone(p graphql.ResolveParams) (any, error) {
+	actor := p.Source.(*domain.Actor)
+	if actor.Role == domain.RoleService {
+		return nil, nil
+	}
+	user, err := r.users.GetUser(p.Context, actor.UserID)
+	if err != nil {
+		return nil, resolverError(p, err)
+	}
+	if user.TimeZone == "" {
+		return nil, nil
+	}
+	return user.TimeZone, nil
+}
+
+// taskOwner loads the owner of a task. Owners are cached for the rest of the request,
+// since a list of tasks usually has few distinct owners.
+func (r *resolver) taskOwner(p graphql.ResolveParams) (any, error) {
+	task := p.Source.(*domain.Task)
+	if task.OwnerID == "" {
+		return nil, nil
+	}
+	user, err := usersFromContext(p.Context).get(p.Context, r, task.OwnerID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, resolverError(p, err)
+	}
+	return user, nil
+}
+
+type userCacheKey struct{}
+
+// userCache holds the users loaded during one request.
+type userCache struct {
+	mu    sync.Mutex
+	users map[string]*domain.User
+}
+
+func contextWithUserCache(c context.Context) context.Context {
+	return context.WithValue(c, userCacheKey{}, &userCache{users: make(map[string]*domain.User)})
+}
+
+// usersFromContext returns the request's cache, or an empty one if there is none.
+func usersFromContext(c context.Context) *userCache {
+	if cache, ok := c.Value(userCacheKey{}).(*userCache); ok {
+		return cache
+	}
+	return &userCache{users: make(map[string]*domain.User)}
+}
+
+func (cache *userCache) get(c context.Context, r *resolver, userID string) (*domain.User, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if user, ok := cache.users[userID]; ok {
+		return user, nil
+	}
+	user, err := r.users.GetUser(c, userID)
+	if err != nil {
+		return nil, err
+	}
+	cache.users[userID] = user
+	return user, nil
+}
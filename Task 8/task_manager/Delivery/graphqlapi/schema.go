@@ -0,0 +1,225 @@
+// Package graphqlapi serves a GraphQL endpoint that lets clients such as the dashboard
+// fetch tasks, their owners and the current user in one request.
+package graphqlapi
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"encoding/json"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// resolver resolves the fields of the schema with the use cases.
+type resolver struct {
+	tasks *usecases.TaskUseCase
+	users *usecases.UserUseCase
+}
+
+// NewSchema builds the GraphQL schema.
+func NewSchema(tasks *usecases.TaskUseCase, users *usecases.UserUseCase) (graphql.Schema, error) {
+	r := &resolver{tasks: tasks, users: users}
+
+	taskStatus := graphql.NewEnum(graphql.EnumConfig{
+		Name: "TaskStatus",
+		Values: graphql.EnumValueConfigMap{
+			"PENDING":     {Value: domain.Pending},
+			"IN_PROGRESS": {Value: domain.InProgress},
+			"DONE":        {Value: domain.Done},
+		},
+	})
+
+	user := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"id":       {Type: graphql.NewNonNull(graphql.ID), Resolve: userField(func(u *domain.User) any { return u.Id.Hex() })},
+			"username": {Type: graphql.NewNonNull(graphql.String), Resolve: userField(func(u *domain.User) any { return u.Username })},
+			"role":     {Type: graphql.NewNonNull(graphql.String), Resolve: userField(func(u *domain.User) any { return string(u.Role) })},
+		},
+	})
+
+	viewer := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Viewer",
+		Description: "The authenticated caller, a user or a service account.",
+		Fields: graphql.Fields{
+			"id":          {Type: graphql.NewNonNull(graphql.ID), Resolve: actorField(func(a *domain.Actor) any { return a.UserID })},
+			"username":    {Type: graphql.NewNonNull(graphql.String), Resolve: actorField(func(a *domain.Actor) any { return a.Username })},
+			"role":        {Type: graphql.NewNonNull(graphql.String), Resolve: actorField(func(a *domain.Actor) any { return string(a.Role) })},
+			"permissions": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))), Resolve: actorField(func(a *domain.Actor) any { return a.Permissions })},
+			"timeZone": {
+				Type:        graphql.String,
+				Description: "The time zone from the user's profile. Null for service accounts and users without one.",
+				Resolve:     r.viewerTimeZone,
+			},
+		},
+	})
+
+	task := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Task",
+		Fields: graphql.Fields{
+			"id":          {Type: graphql.NewNonNull(graphql.ID), Resolve: taskField(func(t *domain.Task) any { return t.Id.Hex() })},
+			"title":       {Type: graphql.NewNonNull(graphql.String), Resolve: taskField(func(t *domain.Task) any { return t.Title })},
+			"description": {Type: graphql.NewNonNull(graphql.String), Resolve: taskField(func(t *domain.Task) any { return t.Description })},
+			"dueDate":     {Type: graphql.NewNonNull(graphql.DateTime), Resolve: taskField(func(t *domain.Task) any { return t.DueDate })},
+			"status":      {Type: graphql.NewNonNull(taskStatus), Resolve: taskField(func(t *domain.Task) any { return t.Status })},
+			"projectId":   {Type: graphql.ID, Resolve: taskField(func(t *domain.Task) any { return optionalID(t.ProjectID.IsZero(), t.ProjectID.Hex()) })},
+			"tags":        {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))), Resolve: taskField(func(t *domain.Task) any { return nonNilStrings(t.Tags) })},
+			"blockedBy":   {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID))), Resolve: taskField(blockedBy)},
+			"completedAt": {Type: graphql.DateTime, Resolve: taskField(func(t *domain.Task) any { return t.CompletedAt })},
+			"owner": {
+				Type:        user,
+				Description: "The user who created the task. Null for tasks created by service accounts or imports.",
+				Resolve:     r.taskOwner,
+			},
+		},
+	})
+
+	taskFilters := graphql.FieldConfigArgument{
+		"statuses":  {Type: graphql.NewList(graphql.NewNonNull(taskStatus))},
+		"projectId": {Type: graphql.ID},
+		"dueBefore": {Type: graphql.DateTime},
+		"dueAfter":  {Type: graphql.DateTime},
+	}
+
+	createTaskInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateTaskInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":       {Type: graphql.NewNonNull(graphql.String)},
+			"description": {Type: graphql.String},
+			"dueDate":     {Type: graphql.NewNonNull(graphql.String), Description: "A date (YYYY-MM-DD) in the caller's time zone or an RFC 3339 date-time."},
+			"status":      {Type: graphql.NewNonNull(taskStatus)},
+			"projectId":   {Type: graphql.ID, Description: "Creates the task in this project."},
+		},
+	})
+
+	updateTaskInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "UpdateTaskInput",
+		Description: "The fields to change. Fields that are left out, or null, keep their value.",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":       {Type: graphql.String},
+			"description": {Type: graphql.String},
+			"dueDate":     {Type: graphql.String, Description: "A date (YYYY-MM-DD) in the caller's time zone or an RFC 3339 date-time."},
+			"status":      {Type: taskStatus},
+			"tags":        {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"tasks": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(task))),
+				Description: "The tasks visible to the caller that match every given filter.",
+				Args:        taskFilters,
+				Resolve:     requirePermission(domain.PermTasksRead, r.listTasks),
+			},
+			"task": {
+				Type:    task,
+				Args:    graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: requirePermission(domain.PermTasksRead, r.getTask),
+			},
+			"me": {
+				Type:    graphql.NewNonNull(viewer),
+				Resolve: r.me,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createTask": {
+				Type:    graphql.NewNonNull(task),
+				Args:    graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(createTaskInput)}},
+				Resolve: requirePermission(domain.PermTasksCreate, r.createTask),
+			},
+			"updateTask": {
+				Type: graphql.NewNonNull(task),
+				Args: graphql.FieldConfigArgument{
+					"id":    {Type: graphql.NewNonNull(graphql.ID)},
+					"input": {Type: graphql.NewNonNull(updateTaskInput)},
+				},
+				Resolve: requirePermission(domain.PermTasksUpdate, r.updateTask),
+			},
+			"deleteTask": {
+				Type:        graphql.NewNonNull(graphql.ID),
+				Description: "Deletes a task and returns its ID.",
+				Args:        graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve:     requirePermission(domain.PermTasksDelete, r.deleteTask),
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+func taskField(get func(task *domain.Task) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(*domain.Task)), nil
+	}
+}
+
+func userField(get func(user *domain.User) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(*domain.User)), nil
+	}
+}
+
+func actorField(get func(actor *domain.Actor) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(*domain.Actor)), nil
+	}
+}
+
+func blockedBy(task *domain.Task) any {
+	ids := make([]string, 0, len(task.BlockedBy))
+	for _, id := range task.BlockedBy {
+		ids = append(ids, id.Hex())
+	}
+	return ids
+}
+
+func optionalID(zero bool, id string) any {
+	if zero {
+		return nil
+	}
+	return id
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// taskQuery reads the filter arguments of the tasks query.
+func taskQuery(args map[string]any) usecases.TaskQuery {
+	var query usecases.TaskQuery
+	if statuses, ok := args["statuses"].([]any); ok {
+		for _, status := range statuses {
+			query.Statuses = append(query.Statuses, status.(domain.TaskStatus))
+		}
+	}
+	query.ProjectID, _ = args["projectId"].(string)
+	if dueBefore, ok := args["dueBefore"].(time.Time); ok {
+		query.DueBefore = &dueBefore
+	}
+	if dueAfter, ok := args["dueAfter"].(time.Time); ok {
+		query.DueAfter = &dueAfter
+	}
+	return query
+}
+
+// parseDueDate reads a due date the way the REST API does, reporting bad values as
+// validation errors of the duedate field.
+func parseDueDate(value string) (domain.DueDate, error) {
+	var dueDate domain.DueDate
+	data, err := json.Marshal(value)
+	if err != nil {
+		return dueDate, err
+	}
+	err = dueDate.UnmarshalJSON(data)
+	return dueDate, err
+}
@@ -0,0 +1,124 @@
+package grpcapi
+
+import (
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/grpcapi/taskmanagerv1"
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func taskToProto(task *domain.Task) *taskmanagerv1.Task {
+	if task == nil {
+		return nil
+	}
+	message := &taskmanagerv1.Task{
+		Id:          task.Id.Hex(),
+		Title:       task.Title,
+		Description: task.Description,
+		DueDate:     timestamppb.New(task.DueDate),
+		Status:      string(task.Status),
+		ExternalId:  task.ExternalID,
+		OwnerId:     task.OwnerID,
+		Tags:        task.Tags,
+		Watchers:    task.Watchers,
+	}
+	if !task.ProjectID.IsZero() {
+		message.ProjectId = task.ProjectID.Hex()
+	}
+	for _, blocker := range task.BlockedBy {
+		message.BlockedBy = append(message.BlockedBy, blocker.Hex())
+	}
+	if task.CompletedAt != nil {
+		message.CompletedAt = timestamppb.New(*task.CompletedAt)
+	}
+	return message
+}
+
+func taskEventToProto(event domain.TaskEvent) *taskmanagerv1.TaskEvent {
+	return &taskmanagerv1.TaskEvent{
+		Type:       string(event.Type),
+		Task:       taskToProto(event.Task),
+		Previous:   taskToProto(event.Previous),
+		OccurredAt: timestamppb.New(event.OccurredAt),
+	}
+}
+
+// taskQuery turns a selector into a use case query. A nil selector selects every
+// task the caller can see.
+func taskQuery(selector *taskmanagerv1.TaskSelector) (usecases.TaskQuery, error) {
+	query := usecases.TaskQuery{ProjectID: selector.GetProjectId()}
+	for _, status := range selector.GetStatuses() {
+		query.Statuses = append(query.Statuses, domain.TaskStatus(status))
+	}
+	var err error
+	if query.DueBefore, err = optionalTime("due_before", selector.GetDueBefore()); err != nil {
+		return usecases.TaskQuery{}, err
+	}
+	if query.DueAfter, err = optionalTime("due_after", selector.GetDueAfter()); err != nil {
+		return usecases.TaskQuery{}, err
+	}
+	return query, nil
+}
+
+func optionalTime(field string, timestamp *timestamppb.Timestamp) (*time.Time, error) {
+	if timestamp == nil {
+		return nil, nil
+	}
+	if err := timestamp.CheckValid(); err != nil {
+		violations := &domain.ValidationError{}
+		violations.Add(field, domain.RuleFormat, field+" is not a valid timestamp")
+		return nil, violations
+	}
+	value := timestamp.AsTime()
+	return &value, nil
+}
+
+// taskPatch turns an update into the merge patch the REST API would send.
+func taskPatch(req *taskmanagerv1.UpdateTaskRequest) (domain.TaskPatch, error) {
+	var patch domain.TaskPatch
+	if req.Title != nil {
+		patch.Title = domain.Present(*req.Title)
+	}
+	if req.Description != nil {
+		patch.Description = domain.Present(*req.Description)
+	}
+	if req.DueDate != nil {
+		dueDate, err := domain.ParseDueDate(*req.DueDate)
+		if err != nil {
+			return domain.TaskPatch{}, err
+		}
+		patch.DueDate = domain.Present(dueDate)
+	}
+	if req.Status != nil {
+		patch.Status = domain.Present(domain.TaskStatus(*req.Status))
+	}
+	if req.Tags != nil {
+		patch.Tags = domain.Present(req.Tags.GetTags())
+	}
+	return patch, nil
+}
+
+// Page tokens hold the ID of the last task of the previous page. They are encoded so
+// that clients treat them as opaque.
+
+func pageToken(lastID primitive.ObjectID) string {
+	return base64.RawURLEncoding.EncodeToString(lastID[:])
+}
+
+func parsePageToken(token string) (primitive.ObjectID, error) {
+	var id primitive.ObjectID
+	if token == "" {
+		return id, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(decoded) != len(id) {
+		return id, fmt.Errorf("%w: invalid page token", domain.ErrValidationFailed)
+	}
+	copy(id[:], decoded)
+	return id, nil
+}
@@ -0,0 +1,203 @@
+// Package grpcapi serves the task service over gRPC for internal services that want
+// typed, streaming calls instead of the REST API.
+//
+// The services are defined in proto/taskmanager/v1/taskmanager.proto; the generated
+// messages, clients and service descriptors are in the taskmanagerv1 package.
+package grpcapi
+
+//go:generate protoc -I proto --go_out=. --go_opt=module=A2SV_ProjectPhase/Task8/TaskManager/Delivery/grpcapi --go-grpc_out=. --go-grpc_opt=module=A2SV_ProjectPhase/Task8/TaskManager/Delivery/grpcapi taskmanager/v1/taskmanager.proto
+
+import (
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/grpcapi/taskmanagerv1"
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	infrastructure "A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Full method names, as seen by interceptors.
+const (
+	MethodCreateTask = taskmanagerv1.TaskService_CreateTask_FullMethodName
+	MethodGetTask    = taskmanagerv1.TaskService_GetTask_FullMethodName
+	MethodUpdateTask = taskmanagerv1.TaskService_UpdateTask_FullMethodName
+	MethodDeleteTask = taskmanagerv1.TaskService_DeleteTask_FullMethodName
+	MethodListTasks  = taskmanagerv1.TaskService_ListTasks_FullMethodName
+	MethodWatchTasks = taskmanagerv1.TaskService_WatchTasks_FullMethodName
+
+	MethodLogin           = taskmanagerv1.AuthService_Login_FullMethodName
+	MethodVerifyTwoFactor = taskmanagerv1.AuthService_VerifyTwoFactor_FullMethodName
+)
+
+// ListTasks returns DefaultPageSize tasks unless the request asks for another page
+// size, which may be at most MaxPageSize.
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+// NewServer creates a gRPC server serving the task and auth services. Calls are
+// authenticated by auth and need the same permissions as the matching REST routes;
+// errors are reported with the codes of the REST API, see infrastructure.GRPCStatus.
+func NewServer(auth *infrastructure.GRPCAuthInterceptor, tasks taskmanagerv1.TaskServiceServer, users taskmanagerv1.AuthServiceServer, opts ...grpc.ServerOption) *grpc.Server {
+	auth.AllowUnauthenticated(MethodLogin, MethodVerifyTwoFactor)
+	auth.RequirePermission(MethodCreateTask, domain.PermTasksCreate)
+	auth.RequirePermission(MethodGetTask, domain.PermTasksRead)
+	auth.RequirePermission(MethodUpdateTask, domain.PermTasksUpdate)
+	auth.RequirePermission(MethodDeleteTask, domain.PermTasksDelete)
+	auth.RequirePermission(MethodListTasks, domain.PermTasksRead)
+	auth.RequirePermission(MethodWatchTasks, domain.PermTasksRead)
+
+	// The error interceptors come first so that they see what the handlers return.
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(infrastructure.GRPCUnaryErrors(), auth.Unary()),
+		grpc.ChainStreamInterceptor(infrastructure.GRPCStreamErrors(), auth.Stream()),
+	}, opts...)
+	server := grpc.NewServer(opts...)
+	taskmanagerv1.RegisterTaskServiceServer(server, tasks)
+	taskmanagerv1.RegisterAuthServiceServer(server, users)
+	return server
+}
+
+// TaskServer implements taskmanagerv1.TaskServiceServer with the task use cases.
+type TaskServer struct {
+	taskmanagerv1.UnimplementedTaskServiceServer
+	tasks   *usecases.TaskUseCase
+	watches *usecases.TaskWatchUseCase
+}
+
+func NewTaskServer(tasks *usecases.TaskUseCase, watches *usecases.TaskWatchUseCase) *TaskServer {
+	return &TaskServer{tasks: tasks, watches: watches}
+}
+
+func (s *TaskServer) CreateTask(c context.Context, req *taskmanagerv1.CreateTaskRequest) (*taskmanagerv1.Task, error) {
+	dueDate, err := domain.ParseDueDate(req.DueDate)
+	if err != nil {
+		return nil, err
+	}
+	location, _ := domain.LocationFromContext(c)
+	status := domain.TaskStatus(req.Status)
+	var task *domain.Task
+	if req.ProjectId != "" {
+		task, err = s.tasks.CreateProjectTask(c, req.ProjectId, req.Title, req.Description, dueDate.In(location), status)
+	} else {
+		task, err = s.tasks.CreateTask(c, req.Title, req.Description, dueDate.In(location), status)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return taskToProto(task), nil
+}
+
+func (s *TaskServer) GetTask(c context.Context, req *taskmanagerv1.GetTaskRequest) (*taskmanagerv1.Task, error) {
+	task, err := s.tasks.GetTaskByID(c, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return taskToProto(task), nil
+}
+
+func (s *TaskServer) UpdateTask(c context.Context, req *taskmanagerv1.UpdateTaskRequest) (*taskmanagerv1.Task, error) {
+	patch, err := taskPatch(req)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.tasks.PatchTask(c, req.Id, patch)
+	if err != nil {
+		return nil, err
+	}
+	return taskToProto(task), nil
+}
+
+func (s *TaskServer) DeleteTask(c context.Context, req *taskmanagerv1.DeleteTaskRequest) (*emptypb.Empty, error) {
+	if err := s.tasks.DeleteTask(c, req.Id); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// errPageFull stops the export once a page and the first task after it were read.
+var errPageFull = errors.New("page full")
+
+// ListTasks reads one more task than fits on the page to find out whether there is
+// a next page.
+func (s *TaskServer) ListTasks(c context.Context, req *taskmanagerv1.ListTasksRequest) (*taskmanagerv1.ListTasksResponse, error) {
+	pageSize := int(req.PageSize)
+	switch {
+	case pageSize == 0:
+		pageSize = DefaultPageSize
+	case pageSize < 0 || pageSize > MaxPageSize:
+		violations := &domain.ValidationError{}
+		violations.Add("page_size", domain.RuleMax, fmt.Sprintf("page_size must be between 1 and %d", MaxPageSize))
+		return nil, violations
+	}
+	query, err := taskQuery(req.Selector)
+	if err != nil {
+		return nil, err
+	}
+	if query.AfterID, err = parsePageToken(req.PageToken); err != nil {
+		return nil, err
+	}
+
+	resp := &taskmanagerv1.ListTasksResponse{Tasks: []*taskmanagerv1.Task{}}
+	var last *domain.Task
+	err = s.tasks.ExportTasks(c, query, func(task *domain.Task) error {
+		if len(resp.Tasks) == pageSize {
+			resp.NextPageToken = pageToken(last.Id)
+			return errPageFull
+		}
+		resp.Tasks = append(resp.Tasks, taskToProto(task))
+		last = task
+		return nil
+	})
+	if err != nil && !errors.Is(err, errPageFull) {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *TaskServer) WatchTasks(req *taskmanagerv1.WatchTasksRequest, stream taskmanagerv1.TaskService_WatchTasksServer) error {
+	query, err := taskQuery(req.Selector)
+	if err != nil {
+		return err
+	}
+	return s.watches.WatchTasks(stream.Context(), query, func(event domain.TaskEvent) error {
+		return stream.Send(taskEventToProto(event))
+	})
+}
+
+// AuthServer implements taskmanagerv1.AuthServiceServer with the user use case, so
+// that clients can obtain a token without going through the REST API.
+type AuthServer struct {
+	taskmanagerv1.UnimplementedAuthServiceServer
+	users *usecases.UserUseCase
+}
+
+func NewAuthServer(users *usecases.UserUseCase) *AuthServer {
+	return &AuthServer{users: users}
+}
+
+func (s *AuthServer) Login(c context.Context, req *taskmanagerv1.LoginRequest) (*taskmanagerv1.LoginResponse, error) {
+	result, err := s.users.Login(c, req.Organization, req.Username, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	return &taskmanagerv1.LoginResponse{
+		Token:                       result.Token,
+		ChallengeToken:              result.ChallengeToken,
+		TwoFactorRequired:           result.TwoFactorRequired,
+		TwoFactorEnrollmentRequired: result.EnrollmentRequired,
+	}, nil
+}
+
+func (s *AuthServer) VerifyTwoFactor(c context.Context, req *taskmanagerv1.VerifyTwoFactorRequest) (*taskmanagerv1.LoginResponse, error) {
+	token, err := s.users.VerifyTwoFactorLogin(c, req.ChallengeToken, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	return &taskmanagerv1.LoginResponse{Token: token}, nil
+}
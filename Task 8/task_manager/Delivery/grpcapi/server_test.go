@@ -0,0 +1,274 @@
+package grpcapi_test
+
+import (
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/grpcapi"
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/grpcapi/taskmanagerv1"
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	infrastructure "A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"A2SV_ProjectPhase/Task8/TaskManager/Repositories"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+//===========================================================================
+// gRPC Server Test Suite
+//===========================================================================
+
+type GRPCServerSuite struct {
+	suite.Suite
+	server *grpc.Server
+	conn   *grpc.ClientConn
+	client taskmanagerv1.TaskServiceClient
+	jwt    domain.JwtService
+	ctx    context.Context
+}
+
+func TestGRPCServerSuite(t *testing.T) {
+	suite.Run(t, new(GRPCServerSuite))
+}
+
+func (s *GRPCServerSuite) SetupTest() {
+	tasks := usecases.NewTaskUseCase(repositories.NewInMemoryTaskRepository(), repositories.NewInMemoryProjectRepository())
+	jwtService := infrastructure.NewJwtService("test-secret")
+	s.server = grpcapi.NewServer(
+		infrastructure.NewGRPCAuthInterceptor(jwtService, nil, nil),
+		grpcapi.NewTaskServer(tasks, usecases.NewTaskWatchUseCase(tasks)),
+		grpcapi.NewAuthServer(nil),
+	)
+	listener := bufconn.Listen(1 << 20)
+	go s.server.Serve(listener)
+
+	var err error
+	s.conn, err = grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(c context.Context, _ string) (net.Conn, error) { return listener.DialContext(c) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	s.Require().NoError(err)
+	s.client = taskmanagerv1.NewTaskServiceClient(s.conn)
+
+	s.jwt = jwtService
+	s.ctx = s.adminContext(primitive.NewObjectID())
+}
+
+// adminContext authenticates calls as an admin of the given organization.
+func (s *GRPCServerSuite) adminContext(organizationID primitive.ObjectID) context.Context {
+	user := &domain.User{Id: primitive.NewObjectID(), Username: "grpc-admin", Role: domain.RoleAdmin, OrganizationID: organizationID}
+	token, err := s.jwt.GetSignedToken(context.Background(), user, domain.AllPermissions())
+	s.Require().NoError(err)
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func (s *GRPCServerSuite) TearDownTest() {
+	s.conn.Close()
+	s.server.Stop()
+}
+
+func (s *GRPCServerSuite) createTask(title string, status domain.TaskStatus) *taskmanagerv1.Task {
+	task, err := s.client.CreateTask(s.ctx, &taskmanagerv1.CreateTaskRequest{
+		Title:   title,
+		DueDate: time.Now().AddDate(0, 0, 7).Format(time.DateOnly),
+		Status:  string(status),
+	})
+	s.Require().NoError(err)
+	return task
+}
+
+func (s *GRPCServerSuite) TestTaskCRUD() {
+	created := s.createTask("Write the gRPC docs", domain.Pending)
+	s.NotEmpty(created.Id)
+
+	fetched, err := s.client.GetTask(s.ctx, &taskmanagerv1.GetTaskRequest{Id: created.Id})
+	s.Require().NoError(err)
+	s.Equal("Write the gRPC docs", fetched.Title)
+
+	done := string(domain.Done)
+	updated, err := s.client.UpdateTask(s.ctx, &taskmanagerv1.UpdateTaskRequest{Id: created.Id, Status: &done, Tags: &taskmanagerv1.TagList{Tags: []string{"docs"}}})
+	s.Require().NoError(err)
+	s.Equal(done, updated.Status)
+	s.Equal([]string{"docs"}, updated.Tags)
+	s.NotNil(updated.CompletedAt)
+	s.Equal("Write the gRPC docs", updated.Title, "Fields that are not set should not change")
+
+	s.createTask("Still pending", domain.Pending)
+	list, err := s.client.ListTasks(s.ctx, &taskmanagerv1.ListTasksRequest{Selector: &taskmanagerv1.TaskSelector{Statuses: []string{done}}})
+	s.Require().NoError(err)
+	s.Require().Len(list.Tasks, 1)
+	s.Equal(created.Id, list.Tasks[0].Id)
+	s.Empty(list.NextPageToken)
+
+	_, err = s.client.DeleteTask(s.ctx, &taskmanagerv1.DeleteTaskRequest{Id: created.Id})
+	s.Require().NoError(err)
+	_, err = s.client.GetTask(s.ctx, &taskmanagerv1.GetTaskRequest{Id: created.Id})
+	s.Equal(codes.NotFound, status.Code(err))
+	s.Equal(string(infrastructure.CodeTaskNotFound), errorReason(err))
+}
+
+func (s *GRPCServerSuite) TestListTasksPages() {
+	var created []string
+	for _, title := range []string{"one", "two", "three", "four", "five"} {
+		created = append(created, s.createTask(title, domain.Pending).Id)
+	}
+
+	var listed []string
+	req := &taskmanagerv1.ListTasksRequest{PageSize: 2}
+	for pages := 1; ; pages++ {
+		s.Require().LessOrEqual(pages, 3, "Five tasks should fit on three pages of two")
+		page, err := s.client.ListTasks(s.ctx, req)
+		s.Require().NoError(err)
+		s.LessOrEqual(len(page.Tasks), 2)
+		for _, task := range page.Tasks {
+			listed = append(listed, task.Id)
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		req.PageToken = page.NextPageToken
+	}
+	s.Equal(created, listed, "Every task should be listed once, in order")
+}
+
+func (s *GRPCServerSuite) TestErrors() {
+	s.Run("Unauthenticated", func() {
+		_, err := s.client.ListTasks(context.Background(), &taskmanagerv1.ListTasksRequest{})
+		s.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	s.Run("Validation Errors", func() {
+		_, err := s.client.CreateTask(s.ctx, &taskmanagerv1.CreateTaskRequest{
+			DueDate: time.Now().AddDate(0, 0, 1).Format(time.DateOnly),
+			Status:  "Someday",
+		})
+		s.Equal(codes.InvalidArgument, status.Code(err))
+		s.Equal(string(infrastructure.CodeValidationFailed), errorReason(err))
+		var fields []string
+		for _, detail := range status.Convert(err).Details() {
+			if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+				for _, violation := range badRequest.FieldViolations {
+					fields = append(fields, violation.Field)
+				}
+			}
+		}
+		s.ElementsMatch([]string{"title", "status"}, fields)
+	})
+
+	s.Run("Malformed Due Date", func() {
+		_, err := s.client.CreateTask(s.ctx, &taskmanagerv1.CreateTaskRequest{Title: "Soon", DueDate: "next week", Status: string(domain.Pending)})
+		s.Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	s.Run("Invalid Page Token", func() {
+		_, err := s.client.ListTasks(s.ctx, &taskmanagerv1.ListTasksRequest{PageToken: "not a token"})
+		s.Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	s.Run("Page Size Too Large", func() {
+		_, err := s.client.ListTasks(s.ctx, &taskmanagerv1.ListTasksRequest{PageSize: grpcapi.MaxPageSize + 1})
+		s.Equal(codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func (s *GRPCServerSuite) TestWatchTasks() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+	watch, err := s.client.WatchTasks(ctx, &taskmanagerv1.WatchTasksRequest{Selector: &taskmanagerv1.TaskSelector{Statuses: []string{string(domain.Pending)}}})
+	s.Require().NoError(err)
+	events := make(chan *taskmanagerv1.TaskEvent, 64)
+	go func() {
+		for {
+			event, err := watch.Recv()
+			if err != nil {
+				close(events)
+				return
+			}
+			events <- event
+		}
+	}()
+
+	// The watch is registered on the server asynchronously; probe until it sees tasks.
+	s.Require().Eventually(func() bool {
+		s.createTask("probe", domain.Pending)
+		select {
+		case <-events:
+			return true
+		case <-time.After(20 * time.Millisecond):
+			return false
+		}
+	}, 2*time.Second, time.Millisecond)
+
+	s.createTask("ignored", domain.Done)
+	s.createTask("matched", domain.Pending)
+	for event := range events {
+		if event.Task.Title == "probe" {
+			continue
+		}
+		s.Equal(string(domain.TaskCreated), event.Type)
+		s.Equal("matched", event.Task.Title, "Tasks outside the selection should not be sent")
+		break
+	}
+}
+
+func (s *GRPCServerSuite) TestWatchTasksStaysInOrganization() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+	watch, err := s.client.WatchTasks(ctx, &taskmanagerv1.WatchTasksRequest{})
+	s.Require().NoError(err)
+	events := make(chan *taskmanagerv1.TaskEvent, 64)
+	go func() {
+		for {
+			event, err := watch.Recv()
+			if err != nil {
+				close(events)
+				return
+			}
+			events <- event
+		}
+	}()
+	s.Require().Eventually(func() bool {
+		s.createTask("probe", domain.Pending)
+		select {
+		case <-events:
+			return true
+		case <-time.After(20 * time.Millisecond):
+			return false
+		}
+	}, 2*time.Second, time.Millisecond)
+
+	// An admin of another organization sees every task of theirs, and so would an
+	// unscoped watch.
+	_, err = s.client.CreateTask(s.adminContext(primitive.NewObjectID()), &taskmanagerv1.CreateTaskRequest{
+		Title:   "other organization",
+		DueDate: time.Now().AddDate(0, 0, 7).Format(time.DateOnly),
+		Status:  string(domain.Pending),
+	})
+	s.Require().NoError(err)
+	s.createTask("own organization", domain.Pending)
+	for event := range events {
+		if event.Task.Title == "probe" {
+			continue
+		}
+		s.Equal("own organization", event.Task.Title, "Tasks of other organizations should not be sent")
+		break
+	}
+}
+
+func errorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
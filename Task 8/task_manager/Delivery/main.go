@@ -3,7 +3,11 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -13,6 +17,8 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/controllers"
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/graphqlapi"
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/grpcapi"
 	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/routers"
 	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
 	infrastructure "A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
@@ -43,6 +49,77 @@ func main() {
 		log.Println("WARNING: JWT_SECRET environment variable not set. Using default secret.")
 	}
 
+	twoFactorKey := os.Getenv("TWO_FACTOR_ENCRYPTION_KEY")
+	if twoFactorKey == "" {
+		twoFactorKey = jwtSecretKey
+		log.Println("WARNING: TWO_FACTOR_ENCRYPTION_KEY environment variable not set. Deriving it from JWT_SECRET.")
+	}
+	requireAdminTwoFactor := os.Getenv("REQUIRE_ADMIN_2FA") == "true"
+
+	attachmentPolicy := domain.DefaultAttachmentPolicy()
+	if maxBytes := os.Getenv("ATTACHMENT_MAX_BYTES"); maxBytes != "" {
+		attachmentPolicy.MaxSize, err = strconv.ParseInt(maxBytes, 10, 64)
+		if err != nil || attachmentPolicy.MaxSize <= 0 {
+			log.Fatalf("Fatal: ATTACHMENT_MAX_BYTES must be a positive integer, got %q", maxBytes)
+		}
+	}
+	if allowedTypes := os.Getenv("ATTACHMENT_ALLOWED_TYPES"); allowedTypes != "" {
+		attachmentPolicy.AllowedContentTypes = nil
+		for _, contentType := range strings.Split(allowedTypes, ",") {
+			attachmentPolicy.AllowedContentTypes = append(attachmentPolicy.AllowedContentTypes, strings.ToLower(strings.TrimSpace(contentType)))
+		}
+	}
+	idempotencyTTL := 24 * time.Hour
+	if ttl := os.Getenv("IDEMPOTENCY_TTL"); ttl != "" {
+		idempotencyTTL, err = time.ParseDuration(ttl)
+		if err != nil || idempotencyTTL <= 0 {
+			log.Fatalf("Fatal: IDEMPOTENCY_TTL must be a positive duration such as \"24h\", got %q", ttl)
+		}
+	}
+	attachmentStorage := os.Getenv("ATTACHMENT_STORAGE")
+	attachmentDir := os.Getenv("ATTACHMENT_DIR")
+	if attachmentDir == "" {
+		attachmentDir = "./attachments"
+	}
+
+	// TASK_CACHE_SIZE=0 turns the task cache off, e.g. when several instances share
+	// the database and must see each other's writes at once.
+	taskCacheOptions := repositories.TaskCacheOptions{MaxEntries: repositories.DefaultTaskCacheEntries}
+	if size := os.Getenv("TASK_CACHE_SIZE"); size != "" {
+		taskCacheOptions.MaxEntries, err = strconv.Atoi(size)
+		if err != nil || taskCacheOptions.MaxEntries < 0 {
+			log.Fatalf("Fatal: TASK_CACHE_SIZE must be a non-negative integer, got %q", size)
+		}
+	}
+	if ttl := os.Getenv("TASK_CACHE_TTL"); ttl != "" {
+		taskCacheOptions.TTL, err = time.ParseDuration(ttl)
+		if err != nil || taskCacheOptions.TTL <= 0 {
+			log.Fatalf("Fatal: TASK_CACHE_TTL must be a positive duration such as \"30s\", got %q", ttl)
+		}
+	}
+
+	// How often to look for tasks that became overdue and notify their owners.
+	overdueCheckInterval := 15 * time.Minute
+	if interval := os.Getenv("OVERDUE_CHECK_INTERVAL"); interval != "" {
+		overdueCheckInterval, err = time.ParseDuration(interval)
+		if err != nil || overdueCheckInterval <= 0 {
+			log.Fatalf("Fatal: OVERDUE_CHECK_INTERVAL must be a positive duration such as \"15m\", got %q", interval)
+		}
+	}
+
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+
+	// The operator token admits the operators of the deployment, for example to create
+	// organizations or read the cache counters. Without one, nobody can.
+	operatorToken := os.Getenv("OPERATOR_TOKEN")
+	if operatorToken == "" {
+		log.Println("WARNING: OPERATOR_TOKEN environment variable not set. Operator routes are disabled.")
+	}
+	allowOrganizationSignup := os.Getenv("ALLOW_ORG_SIGNUP") == "true"
+
 	adminUsername := os.Getenv("ADMIN_USERNAME")
 	if adminUsername == "" {
 		adminUsername = "admin"
@@ -75,19 +152,123 @@ func main() {
 	// We need passwordService here directly for hashing admin password
 	passwordService := infrastructure.NewBcryptPasswordService(bcrypt.DefaultCost)
 	jwtService := infrastructure.NewJwtService(jwtSecretKey) // Still needed for JWTs later
+	totpService := infrastructure.NewTOTPService("TaskManager")
+	secretCipher, err := infrastructure.NewAESSecretCipher(twoFactorKey)
+	if err != nil {
+		log.Fatalf("Fatal: Failed to initialize secret cipher: %v", err)
+	}
+	var blobStore domain.BlobStore
+	switch attachmentStorage {
+	case "", "local":
+		blobStore, err = infrastructure.NewLocalBlobStore(attachmentDir)
+	case "gridfs":
+		blobStore, err = infrastructure.NewGridFSBlobStore(db, "attachments8")
+	default:
+		log.Fatalf("Fatal: Unknown ATTACHMENT_STORAGE %q (expected \"local\" or \"gridfs\")", attachmentStorage)
+	}
+	if err != nil {
+		log.Fatalf("Fatal: Failed to initialize attachment storage: %v", err)
+	}
 	log.Println("Infrastructure services initialized.")
 
 	// --- 3. Instantiate Concrete Repository Implementations (Needed for bootstrapping) ---
 	userCollection := db.Collection("user8")
 	taskCollection := db.Collection("task8")
+	roleCollection := db.Collection("role8")
+	serviceAccountCollection := db.Collection("serviceaccount8")
+	apiKeyCollection := db.Collection("apikey8")
+	projectCollection := db.Collection("project8")
+	attachmentCollection := db.Collection("attachment8")
+	revisionCollection := db.Collection("revision8")
+	idempotencyCollection := db.Collection("idempotency8")
+	organizationCollection := db.Collection("organization8")
+	invitationCollection := db.Collection("invitation8")
+	notificationCollection := db.Collection("notification8")
 
 	userRepo := repositories.NewMongoDBUserRepository(userCollection) // Needed directly for admin check/create
-	taskRepo := repositories.NewMongoDBTaskRepository(taskCollection)
+	var taskRepo domain.TaskRepository = repositories.NewMongoDBTaskRepository(taskCollection)
+	var transactor domain.Transactor = repositories.NewMongoTransactor(mongoClient)
+	// Use cases run their multi-step writes in transactions when the server supports
+	// them. Bulk requests keep the real transactor, so that atomic mode fails loudly
+	// instead of silently running without one.
+	var unitOfWork domain.Transactor = transactor
+	supportsTransactions, err := repositories.SupportsTransactions(context.Background(), mongoClient)
+	if err != nil {
+		log.Fatalf("Fatal: %v", err)
+	}
+	if !supportsTransactions {
+		unitOfWork = domain.NoopTransactor{}
+		log.Println("WARNING: MongoDB is not a replica set; multi-step writes will run without transactions.")
+	}
+	var taskCacheStats func() any
+	if taskCacheOptions.MaxEntries > 0 {
+		cachedTaskRepo := repositories.NewCachedTaskRepository(taskRepo, taskCacheOptions)
+		taskCacheStats = func() any { return cachedTaskRepo.Stats() }
+		taskRepo, transactor, unitOfWork = cachedTaskRepo, cachedTaskRepo.WrapTransactor(transactor), cachedTaskRepo.WrapTransactor(unitOfWork)
+	}
+	roleRepo := repositories.NewMongoDBRoleRepository(roleCollection)
+	serviceAccountRepo := repositories.NewMongoDBServiceAccountRepository(serviceAccountCollection)
+	apiKeyRepo := repositories.NewMongoDBAPIKeyRepository(apiKeyCollection)
+	projectRepo := repositories.NewMongoDBProjectRepository(projectCollection)
+	attachmentRepo := repositories.NewMongoDBAttachmentRepository(attachmentCollection)
+	revisionRepo := repositories.NewMongoDBRevisionRepository(revisionCollection)
+	idempotencyRepo := repositories.NewMongoDBIdempotencyRepository(idempotencyCollection)
+	organizationRepo := repositories.NewMongoDBOrganizationRepository(organizationCollection)
+	invitationRepo := repositories.NewMongoDBInvitationRepository(invitationCollection)
+	notificationRepo := repositories.NewMongoDBNotificationRepository(notificationCollection)
+	if err := idempotencyRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("Fatal: %v", err)
+	}
+	if err := organizationRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("Fatal: %v", err)
+	}
+	if err := invitationRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("Fatal: %v", err)
+	}
+	if err := notificationRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("Fatal: %v", err)
+	}
 	log.Println("Repositories initialized.")
 
-	// --- 4. Implement Default Admin User Bootstrapping (Directly using Repo and PasswordService) ---
+	// --- 4. Move Data Written Before Organizations Existed Into the Default Organization ---
+	defaultOrg, err := organizationRepo.GetOrganizationBySlug(context.Background(), domain.DefaultOrganizationSlug)
+	if errors.Is(err, domain.ErrOrganizationNotFound) {
+		log.Println("Default organization not found. Attempting to create...")
+		defaultOrg, err = organizationRepo.CreateOrganization(context.Background(), &domain.Organization{
+			Name:      "Default",
+			Slug:      domain.DefaultOrganizationSlug,
+			CreatedAt: time.Now(),
+		})
+		if errors.Is(err, domain.ErrOrganizationSlugTaken) {
+			// Another instance created it first.
+			defaultOrg, err = organizationRepo.GetOrganizationBySlug(context.Background(), domain.DefaultOrganizationSlug)
+		}
+	}
+	if err != nil {
+		log.Fatalf("Fatal: Failed to set up the default organization: %v", err)
+	}
+	systemCtx := domain.ContextWithSystemScope(context.Background()) // Moving data between organizations is a trusted internal call
+	err = repositories.AdoptUnscopedDocuments(systemCtx, defaultOrg.Id,
+		userCollection, taskCollection, serviceAccountCollection, apiKeyCollection, projectCollection, attachmentCollection, revisionCollection)
+	if err != nil {
+		log.Fatalf("Fatal: %v", err)
+	}
+	if err := roleRepo.AdoptUnscopedRoles(systemCtx, defaultOrg.Id); err != nil {
+		log.Fatalf("Fatal: %v", err)
+	}
+	// Uniqueness is per organization, so these indexes can only be built once every
+	// document has one.
+	if err := userRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("Fatal: %v", err)
+	}
+	if err := roleRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("Fatal: %v", err)
+	}
+	log.Printf("Default organization ready (ID: %s).\n", defaultOrg.Id.Hex())
+
+	// --- 5. Implement Default Admin User Bootstrapping (Directly using Repo and PasswordService) ---
 	log.Printf("Checking for default admin user '%s'...", adminUsername)
-	adminUserCtx := context.Background() // Use background context for startup task
+	adminUserCtx := domain.ContextWithOrganization(context.Background(), defaultOrg.Id) // The admin belongs to the default organization
 
 	// Try to find the admin user
 	_, err = userRepo.GetUserByUsername(adminUserCtx, adminUsername)
@@ -105,6 +286,8 @@ func main() {
 				Username:     adminUsername,
 				PasswordHash: hashedPassword,
 				Role:         domain.RoleAdmin, // Explicitly set role to Admin
+
+				OrganizationID: defaultOrg.Id,
 			}
 
 			// Save the admin user directly via the repository
@@ -123,28 +306,111 @@ func main() {
 	}
 	log.Println("Admin bootstrapping complete.")
 
-	// --- 5. Instantiate Usecases (Injecting Repositories and Infrastructure Services as Interfaces) ---
+	// Seed the built-in role definitions (permission sets) if they are missing.
+	roleUsecase := usecases.NewRoleUseCase(roleRepo)
+	if err := roleUsecase.EnsureDefaultRoles(adminUserCtx); err != nil {
+		log.Fatalf("Fatal: Failed to seed default roles: %v", err)
+	}
+	if err := roleUsecase.UpgradeDefaultRoles(systemCtx); err != nil {
+		log.Fatalf("Fatal: Failed to grant new permissions to the built-in roles: %v", err)
+	}
+	log.Println("Role definitions ready.")
+
+	// --- 6. Instantiate Usecases (Injecting Repositories and Infrastructure Services as Interfaces) ---
 	// Note: userUsecase is initialized *after* bootstrapping
-	userUsecase := usecases.NewUserUseCase(userRepo, jwtService, passwordService)
-	taskUsecase := usecases.NewTaskUseCase(taskRepo)
+	userUsecase := usecases.NewUserUseCase(userRepo, roleRepo, organizationRepo, jwtService, passwordService, totpService, secretCipher)
+	if requireAdminTwoFactor {
+		userUsecase.RequireTwoFactorFor(domain.PermUsersManage)
+		log.Println("Two-factor authentication is required for accounts that can manage users.")
+	}
+	userUsecase.SetTransactor(unitOfWork)
+	taskUsecase := usecases.NewTaskUseCase(taskRepo, projectRepo)
+	taskUsecase.SetTransactor(unitOfWork)
+	taskUsecase.SetUserRepository(userRepo)
+	projectUsecase := usecases.NewProjectUseCase(projectRepo, userRepo)
+	attachmentUsecase := usecases.NewAttachmentUseCase(attachmentRepo, blobStore, taskUsecase, attachmentPolicy)
+	historyUsecase := usecases.NewTaskHistoryUseCase(revisionRepo, taskUsecase)
+	calendarUsecase := usecases.NewCalendarUseCase(userRepo, roleRepo, taskUsecase, infrastructure.NewICalRenderer("-//A2SV//Task Manager//EN", "taskmanager"))
+	bulkUsecase := usecases.NewTaskBulkUseCase(taskUsecase, transactor)
+	serviceAccountUsecase := usecases.NewServiceAccountUseCase(serviceAccountRepo, apiKeyRepo)
+	watchUsecase := usecases.NewTaskWatchUseCase(taskUsecase)
+	notificationUsecase := usecases.NewNotificationUseCase(notificationRepo, userRepo, roleRepo, taskUsecase)
+	organizationUsecase := usecases.NewOrganizationUseCase(organizationRepo, invitationRepo, userUsecase, roleUsecase)
+	organizationUsecase.SetTransactor(unitOfWork)
+	organizationUsecase.OnMemberRemoved(projectUsecase.RemoveFromAllProjects)
+	organizationUsecase.OnMemberRemoved(taskUsecase.UnwatchAllTasks)
+	organizationUsecase.OnMemberRemoved(serviceAccountUsecase.DeleteServiceAccountsCreatedBy)
 	log.Println("Usecases initialized.")
 
-	// --- 6. Instantiate Delivery Controllers (Injecting Usecases) ---
-	userController := controllers.NewUserController(userUsecase)
+	// --- 7. Instantiate Delivery Controllers (Injecting Usecases) ---
+	userController := controllers.NewUserController(userUsecase, organizationUsecase)
+	organizationController := controllers.NewOrganizationController(organizationUsecase)
 	taskController := controllers.NewTaskController(taskUsecase)
-	authMiddleware := infrastructure.NewAuthMiddleware(jwtService)
+	roleController := controllers.NewRoleController(roleUsecase)
+	serviceAccountController := controllers.NewServiceAccountController(serviceAccountUsecase)
+	projectController := controllers.NewProjectController(projectUsecase)
+	attachmentController := controllers.NewAttachmentController(attachmentUsecase)
+	historyController := controllers.NewTaskHistoryController(historyUsecase)
+	calendarController := controllers.NewCalendarController(calendarUsecase)
+	bulkController := controllers.NewTaskBulkController(bulkUsecase)
+	notificationController := controllers.NewNotificationController(notificationUsecase)
+	authMiddleware := infrastructure.NewAuthMiddleware(jwtService, serviceAccountUsecase, userUsecase)
+	operatorMiddleware := infrastructure.NewOperatorMiddleware(operatorToken)
+	idempotencyMiddleware := infrastructure.NewIdempotencyMiddleware(idempotencyRepo, idempotencyTTL)
+	graphQLHandler, err := graphqlapi.NewHandler(taskUsecase, userUsecase, graphqlapi.DefaultLimits)
+	if err != nil {
+		log.Fatalf("Fatal: Failed to build the GraphQL schema: %v", err)
+	}
 	log.Println("Controllers and middleware initialized.")
 
-	// --- 7. Set Up Delivery Routers ---
-	router := gin.Default()
-	{
-		routers.SetupUserRouters(router, userController)
-		routers.SetupTaskRoutes(router, taskController, authMiddleware)
-	}
+	// --- 8. Set Up Delivery Routers ---
+	// gin.Default's recovery middleware answers in plain text, so use our own.
+	router := gin.New()
+	router.Use(gin.Logger())
+	routers.SetupRoutes(router, routers.Handlers{
+		Users:                   userController,
+		Organizations:           organizationController,
+		Tasks:                   taskController,
+		Roles:                   roleController,
+		ServiceAccounts:         serviceAccountController,
+		Projects:                projectController,
+		Attachments:             attachmentController,
+		History:                 historyController,
+		Bulk:                    bulkController,
+		Calendar:                calendarController,
+		Notifications:           notificationController,
+		GraphQL:                 graphQLHandler,
+		Auth:                    authMiddleware,
+		Operator:                operatorMiddleware,
+		Idempotency:             idempotencyMiddleware,
+		TaskCacheStats:          taskCacheStats,
+		AllowOrganizationSignup: allowOrganizationSignup,
+	})
 
 	log.Println("All Routers configured.")
 
-	// --- 8. Start the HTTP Server ---
+	// --- 9. Start the gRPC Server on its own port ---
+	grpcServer := grpcapi.NewServer(
+		infrastructure.NewGRPCAuthInterceptor(jwtService, serviceAccountUsecase, userUsecase),
+		grpcapi.NewTaskServer(taskUsecase, watchUsecase),
+		grpcapi.NewAuthServer(userUsecase),
+	)
+	grpcListener, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		log.Fatalf("Fatal: Failed to listen on gRPC port %s: %v", grpcPort, err)
+	}
+	go func() {
+		log.Printf("gRPC server starting on :%s", grpcPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatalf("Fatal: gRPC server stopped: %v", err)
+		}
+	}()
+
+	// --- 10. Start the Overdue Task Checks ---
+	go notificationUsecase.RunOverdueChecks(systemCtx, overdueCheckInterval) // Overdue checks cover every organization
+	log.Printf("Checking for overdue tasks every %s.", overdueCheckInterval)
+
+	// --- 11. Start the HTTP Server ---
 	log.Printf("Server starting on :8080")
 	log.Fatal(router.Run(":8080"))
 }
@@ -1,36 +1,263 @@
 package routers
 
 import (
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/apidocs"
 	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/controllers"
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/graphqlapi"
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
 	infrastructure "A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupUserRouters(router *gin.Engine, userController *controllers.UserController) {
+// SetupCommonMiddleware registers the middleware shared by every route and makes
+// binding errors name fields as clients send them. It must run before any routes are
+// added, since gin only applies middleware to later routes.
+func SetupCommonMiddleware(router *gin.Engine) {
+	infrastructure.UseJSONFieldNames()
+	router.Use(infrastructure.RequestID(), infrastructure.RecoverWithProblem(), infrastructure.TimeZone())
+	router.NoRoute(infrastructure.NoRouteHandler)
+}
+
+// Handlers holds the controllers and middleware that the routes are wired to.
+// Controllers may be nil when the routes are only listed, never served.
+type Handlers struct {
+	Users           *controllers.UserController
+	Organizations   *controllers.OrganizationController
+	Tasks           *controllers.TaskController
+	Roles           *controllers.RoleController
+	ServiceAccounts *controllers.ServiceAccountController
+	Projects        *controllers.ProjectController
+	Attachments     *controllers.AttachmentController
+	History         *controllers.TaskHistoryController
+	Bulk            *controllers.TaskBulkController
+	Calendar        *controllers.CalendarController
+	Notifications   *controllers.NotificationController
+	GraphQL         *graphqlapi.Handler
+
+	Auth     *infrastructure.AuthMiddleware
+	Operator *infrastructure.OperatorMiddleware
+	// Idempotency may be nil, in which case the Idempotency-Key header is ignored.
+	Idempotency *infrastructure.IdempotencyMiddleware
+
+	// TaskCacheStats may be nil when the cache is off.
+	TaskCacheStats          func() any
+	AllowOrganizationSignup bool
+}
+
+// SetupRoutes registers the common middleware and every route of the API.
+func SetupRoutes(router *gin.Engine, h Handlers) {
+	SetupCommonMiddleware(router)
+	SetupDocsRoutes(router)
+	SetupDebugRoutes(router, h.Operator, h.TaskCacheStats)
+	SetupUserRouters(router, h.Users, h.Auth)
+	SetupTaskRoutes(router, h.Tasks, h.Auth, h.Idempotency)
+	SetupOrganizationRoutes(router, h.Organizations, h.Auth, h.Operator, h.AllowOrganizationSignup)
+	SetupRoleRoutes(router, h.Roles, h.Users, h.Auth)
+	SetupServiceAccountRoutes(router, h.ServiceAccounts, h.Auth)
+	SetupProjectRoutes(router, h.Projects, h.Tasks, h.Auth, h.Idempotency)
+	SetupAttachmentRoutes(router, h.Attachments, h.Auth)
+	SetupTaskHistoryRoutes(router, h.History, h.Auth)
+	SetupTaskBulkRoutes(router, h.Bulk, h.Auth, h.Idempotency)
+	SetupCalendarRoutes(router, h.Calendar, h.Auth)
+	SetupNotificationRoutes(router, h.Notifications, h.Auth)
+	SetupGraphQLRoutes(router, h.GraphQL, h.Auth)
+}
+
+// SetupDocsRoutes serves the OpenAPI document and a Swagger UI page. Both are public.
+func SetupDocsRoutes(router *gin.Engine) {
+	router.GET("/openapi.json", apidocs.ServeSpec)
+	router.GET("/docs", apidocs.ServeSwaggerUI)
+}
+
+// SetupDebugRoutes serves the task cache counters to the operators of the deployment.
+// The counters cover every organization, so organization administrators cannot see
+// them. taskCacheStats may be nil when the cache is off.
+func SetupDebugRoutes(router *gin.Engine, operatorMiddleware *infrastructure.OperatorMiddleware, taskCacheStats func() any) {
+	router.GET("/debug/vars", operatorMiddleware.RequireOperator(), func(c *gin.Context) {
+		vars := gin.H{"task_cache": nil}
+		if taskCacheStats != nil {
+			vars["task_cache"] = taskCacheStats()
+		}
+		c.JSON(http.StatusOK, vars)
+	})
+}
+
+// SetupGraphQLRoutes serves the GraphQL endpoint. Its resolvers check permissions per
+// field, so the route itself only requires authentication.
+func SetupGraphQLRoutes(router *gin.Engine, graphQLHandler *graphqlapi.Handler, authMiddleware *infrastructure.AuthMiddleware) {
+	router.POST("/graphql", authMiddleware.Authenticate(), graphQLHandler.Serve)
+}
+
+func SetupUserRouters(router *gin.Engine, userController *controllers.UserController, authMiddleware *infrastructure.AuthMiddleware) {
 	userRoutes := router.Group("/user")
 	{
 		userRoutes.POST("/register", userController.RegisterUser)
 		userRoutes.POST("/login", userController.Login)
+		userRoutes.POST("/login/2fa", userController.VerifyTwoFactorLogin)
+
+		// Enrollment also accepts the challenge token handed to users who must
+		// enroll before they can log in.
+		enrollmentRoutes := userRoutes.Group("/2fa")
+		enrollmentRoutes.Use(authMiddleware.AuthenticateForEnrollment())
+		{
+			enrollmentRoutes.POST("/enroll", userController.BeginTwoFactorEnrollment)
+			enrollmentRoutes.POST("/confirm", userController.ConfirmTwoFactorEnrollment)
+		}
+		userRoutes.POST("/2fa/disable", authMiddleware.Authenticate(), userController.DisableTwoFactor)
+		userRoutes.PUT("/timezone", authMiddleware.Authenticate(), userController.SetTimeZone)
 	}
 }
 
-func SetupTaskRoutes(router *gin.Engine, taskController *controllers.TaskController, authMiddleware *infrastructure.AuthMiddleware) {
+// SetupTaskRoutes registers the task routes. idempotency may be nil, in which case the
+// Idempotency-Key header is ignored.
+func SetupTaskRoutes(router *gin.Engine, taskController *controllers.TaskController, authMiddleware *infrastructure.AuthMiddleware, idempotency *infrastructure.IdempotencyMiddleware) {
 	taskRoutes := router.Group("/tasks")
+	// Apply Authenticate() FIRST, then the per-route RequirePermission() checks
+	taskRoutes.Use(authMiddleware.Authenticate())
 	{
-		authenticatedTaskRoutes := taskRoutes.Use(authMiddleware.Authenticate())
-		{
-			authenticatedTaskRoutes.GET("/", taskController.GetAllTasks)
-			authenticatedTaskRoutes.GET("/:id", taskController.GetTaskByID)
-		}
+		taskRoutes.GET("/", authMiddleware.RequirePermission(domain.PermTasksRead), taskController.GetAllTasks)
+		taskRoutes.GET("/:id", authMiddleware.RequirePermission(domain.PermTasksRead), taskController.GetTaskByID)
+		taskRoutes.POST("/", authMiddleware.RequirePermission(domain.PermTasksCreate), idempotency.Handle(), taskController.CreateTask)
+		taskRoutes.PUT("/:id", authMiddleware.RequirePermission(domain.PermTasksUpdate), taskController.UpdateTask)
+		taskRoutes.PATCH("/:id", authMiddleware.RequirePermission(domain.PermTasksUpdate), taskController.PatchTask)
+		taskRoutes.DELETE("/:id", authMiddleware.RequirePermission(domain.PermTasksDelete), taskController.DeleteTask)
+
+		taskRoutes.GET("/stats", authMiddleware.RequirePermission(domain.PermTasksRead), taskController.GetTaskStats)
+		taskRoutes.PUT("/:id/tags", authMiddleware.RequirePermission(domain.PermTasksUpdate), taskController.SetTags)
+		taskRoutes.GET("/export", authMiddleware.RequirePermission(domain.PermTasksRead), taskController.ExportTasks)
+		taskRoutes.POST("/import", authMiddleware.RequirePermission(domain.PermTasksImport), idempotency.Handle(), taskController.ImportTasks)
+
+		taskRoutes.GET("/:id/dependencies", authMiddleware.RequirePermission(domain.PermTasksRead), taskController.GetDependencyGraph)
+		taskRoutes.POST("/:id/dependencies", authMiddleware.RequirePermission(domain.PermTasksUpdate), taskController.AddDependency)
+		taskRoutes.DELETE("/:id/dependencies/:blockerId", authMiddleware.RequirePermission(domain.PermTasksUpdate), taskController.RemoveDependency)
+
+		// Watching only needs read access: it changes the caller's subscriptions, not the task.
+		taskRoutes.POST("/:id/watch", authMiddleware.RequirePermission(domain.PermTasksRead), taskController.WatchTask)
+		taskRoutes.DELETE("/:id/watch", authMiddleware.RequirePermission(domain.PermTasksRead), taskController.UnwatchTask)
+	}
+}
+
+// SetupTaskBulkRoutes only requires tasks:update up front; the use case checks
+// tasks:create and tasks:delete for the individual operations that need them.
+func SetupTaskBulkRoutes(router *gin.Engine, bulkController *controllers.TaskBulkController, authMiddleware *infrastructure.AuthMiddleware, idempotency *infrastructure.IdempotencyMiddleware) {
+	router.POST("/tasks/bulk", authMiddleware.Authenticate(), authMiddleware.RequirePermission(domain.PermTasksUpdate), idempotency.Handle(), bulkController.ExecuteBulk)
+}
+
+// SetupCalendarRoutes registers the token management routes and the feed itself. The
+// feed is authenticated by the secret token in its URL, since calendar apps cannot send
+// an Authorization header.
+func SetupCalendarRoutes(router *gin.Engine, calendarController *controllers.CalendarController, authMiddleware *infrastructure.AuthMiddleware) {
+	tokenRoutes := router.Group("/user/calendar-token")
+	tokenRoutes.Use(authMiddleware.Authenticate())
+	{
+		tokenRoutes.POST("", calendarController.RotateFeedToken)
+		tokenRoutes.DELETE("", calendarController.RevokeFeedToken)
+	}
+	router.GET("/calendar/:token", calendarController.GetFeed)
+}
+
+func SetupAttachmentRoutes(router *gin.Engine, attachmentController *controllers.AttachmentController, authMiddleware *infrastructure.AuthMiddleware) {
+	attachmentRoutes := router.Group("/tasks/:id/attachments")
+	attachmentRoutes.Use(authMiddleware.Authenticate())
+	{
+		attachmentRoutes.POST("/", authMiddleware.RequirePermission(domain.PermTasksUpdate), attachmentController.UploadAttachment)
+		attachmentRoutes.GET("/", authMiddleware.RequirePermission(domain.PermTasksRead), attachmentController.GetAttachments)
+		attachmentRoutes.GET("/:attachmentId", authMiddleware.RequirePermission(domain.PermTasksRead), attachmentController.DownloadAttachment)
+		attachmentRoutes.DELETE("/:attachmentId", authMiddleware.RequirePermission(domain.PermTasksUpdate), attachmentController.DeleteAttachment)
+	}
+}
+
+func SetupTaskHistoryRoutes(router *gin.Engine, historyController *controllers.TaskHistoryController, authMiddleware *infrastructure.AuthMiddleware) {
+	historyRoutes := router.Group("/tasks/:id/history")
+	historyRoutes.Use(authMiddleware.Authenticate())
+	{
+		historyRoutes.GET("/", authMiddleware.RequirePermission(domain.PermTasksRead), historyController.GetHistory)
+		historyRoutes.POST("/:revisionId/revert", authMiddleware.RequirePermission(domain.PermTasksUpdate, domain.PermTasksRevert), historyController.RevertTask)
+	}
+}
+
+func SetupRoleRoutes(router *gin.Engine, roleController *controllers.RoleController, userController *controllers.UserController, authMiddleware *infrastructure.AuthMiddleware) {
+	manageRoutes := router.Group("/")
+	manageRoutes.Use(authMiddleware.Authenticate(), authMiddleware.RequirePermission(domain.PermUsersManage))
+	{
+		manageRoutes.GET("/roles", roleController.GetAllRoles)
+		manageRoutes.PUT("/roles/:name", roleController.DefineRole)
+		manageRoutes.PUT("/users/:id/role", userController.AssignRole)
+	}
+}
+
+// SetupOrganizationRoutes registers the organization routes. Only operators may create
+// organizations, unless allowSignup opens that to anyone; everything else acts on the
+// caller's own organization.
+func SetupOrganizationRoutes(router *gin.Engine, organizationController *controllers.OrganizationController, authMiddleware *infrastructure.AuthMiddleware, operatorMiddleware *infrastructure.OperatorMiddleware, allowSignup bool) {
+	if allowSignup {
+		router.POST("/organizations", organizationController.CreateOrganization)
+	} else {
+		router.POST("/organizations", operatorMiddleware.RequireOperator(), organizationController.CreateOrganization)
+	}
 
-		adminTaskRoutes := taskRoutes.Group("/")
-		// Apply Authenticate() FIRST, then AuthorizeAdmin()
-		adminTaskRoutes.Use(authMiddleware.Authenticate(), authMiddleware.AuthorizeAdmin())
+	organizationRoutes := router.Group("/organization")
+	organizationRoutes.Use(authMiddleware.Authenticate())
+	{
+		organizationRoutes.GET("", organizationController.GetOrganization)
+
+		manageRoutes := organizationRoutes.Group("")
+		manageRoutes.Use(authMiddleware.RequirePermission(domain.PermUsersManage))
 		{
-			adminTaskRoutes.POST("/", taskController.CreateTask)
-			adminTaskRoutes.PUT("/:id", taskController.UpdateTask)
-			adminTaskRoutes.DELETE("/:id", taskController.DeleteTask)
+			manageRoutes.GET("/members", organizationController.GetMembers)
+			manageRoutes.DELETE("/members/:userId", organizationController.RemoveMember)
+			manageRoutes.POST("/invitations", organizationController.CreateInvitation)
+			manageRoutes.GET("/invitations", organizationController.GetInvitations)
+			manageRoutes.DELETE("/invitations/:id", organizationController.RevokeInvitation)
 		}
 	}
 }
+
+// SetupNotificationRoutes registers the caller's inbox. Everyone has one, so only
+// authentication is required.
+func SetupNotificationRoutes(router *gin.Engine, notificationController *controllers.NotificationController, authMiddleware *infrastructure.AuthMiddleware) {
+	notificationRoutes := router.Group("/me/notifications")
+	notificationRoutes.Use(authMiddleware.Authenticate())
+	{
+		notificationRoutes.GET("", notificationController.GetNotifications)
+		notificationRoutes.POST("/read", notificationController.MarkAllRead)
+		notificationRoutes.POST("/:id/read", notificationController.MarkRead)
+		notificationRoutes.GET("/preferences", notificationController.GetPreferences)
+		notificationRoutes.PUT("/preferences", notificationController.SetPreferences)
+	}
+}
+
+func SetupServiceAccountRoutes(router *gin.Engine, serviceAccountController *controllers.ServiceAccountController, authMiddleware *infrastructure.AuthMiddleware) {
+	accountRoutes := router.Group("/admin/service-accounts")
+	accountRoutes.Use(authMiddleware.Authenticate(), authMiddleware.RequirePermission(domain.PermUsersManage))
+	{
+		accountRoutes.POST("/", serviceAccountController.CreateServiceAccount)
+		accountRoutes.GET("/", serviceAccountController.GetAllServiceAccounts)
+		accountRoutes.POST("/:id/keys", serviceAccountController.CreateAPIKey)
+		accountRoutes.GET("/:id/keys", serviceAccountController.GetAPIKeys)
+		accountRoutes.DELETE("/:id/keys/:keyId", serviceAccountController.RevokeAPIKey)
+	}
+}
+
+// SetupProjectRoutes registers project and project-scoped task routes. Access to these
+// is decided by project membership inside the use cases, so only authentication is required here.
+func SetupProjectRoutes(router *gin.Engine, projectController *controllers.ProjectController, taskController *controllers.TaskController, authMiddleware *infrastructure.AuthMiddleware, idempotency *infrastructure.IdempotencyMiddleware) {
+	projectRoutes := router.Group("/projects")
+	projectRoutes.Use(authMiddleware.Authenticate())
+	{
+		projectRoutes.POST("/", idempotency.Handle(), projectController.CreateProject)
+		projectRoutes.GET("/", projectController.GetMyProjects)
+		projectRoutes.GET("/:id", projectController.GetProject)
+		projectRoutes.PUT("/:id/members/:userId", projectController.SetMember)
+		projectRoutes.DELETE("/:id/members/:userId", projectController.RemoveMember)
+
+		projectRoutes.GET("/:id/tasks", taskController.GetProjectTasks)
+		projectRoutes.POST("/:id/tasks", idempotency.Handle(), taskController.CreateProjectTask)
+		projectRoutes.GET("/:id/tasks/:taskId", taskController.GetProjectTask)
+		projectRoutes.PUT("/:id/tasks/:taskId", taskController.UpdateProjectTask)
+		projectRoutes.PATCH("/:id/tasks/:taskId", taskController.PatchProjectTask)
+		projectRoutes.DELETE("/:id/tasks/:taskId", taskController.DeleteProjectTask)
+	}
+}
@@ -0,0 +1,246 @@
+package routers_test
+
+import (
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/apidocs"
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/controllers"
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/graphqlapi"
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/routers"
+	infrastructure "A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+// openAPISchema is the part of an OpenAPI schema object the tests look at.
+type openAPISchema struct {
+	Ref        string                    `json:"$ref"`
+	Type       string                    `json:"type"`
+	Required   []string                  `json:"required"`
+	Properties map[string]*openAPISchema `json:"properties"`
+	Items      *openAPISchema            `json:"items"`
+}
+
+type openAPIParameter struct {
+	Ref  string `json:"$ref"`
+	Name string `json:"name"`
+	In   string `json:"in"`
+}
+
+type openAPIOperation struct {
+	Parameters []openAPIParameter `json:"parameters"`
+}
+
+type openAPIDocument struct {
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components struct {
+		Schemas map[string]*openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+// requestDTOs are the request bodies described in the spec, by schema name.
+var requestDTOs = map[string]any{
+	"UserRegisterLogin":           controllers.UserRegisterLogin{},
+	"SetTimeZoneRequest":          controllers.SetTimeZoneRequest{},
+	"TwoFactorLoginRequest":       controllers.TwoFactorLoginRequest{},
+	"TwoFactorCodeRequest":        controllers.TwoFactorCodeRequest{},
+	"DefineRoleRequest":           controllers.DefineRoleRequest{},
+	"AssignRoleRequest":           controllers.AssignRoleRequest{},
+	"CreateOrganizationRequest":   controllers.CreateOrganizationRequest{},
+	"CreateInvitationRequest":     controllers.CreateInvitationRequest{},
+	"CreateServiceAccountRequest": controllers.CreateServiceAccountRequest{},
+	"CreateAPIKeyRequest":         controllers.CreateAPIKeyRequest{},
+	"CreateProjectRequest":        controllers.CreateProjectRequest{},
+	"SetProjectMemberRequest":     controllers.SetProjectMemberRequest{},
+	"CreateTaskRequest":           controllers.CreateTaskRequest{},
+	"ReplaceTaskRequest":          controllers.ReplaceTaskRequest{},
+	"UpdateTaskRequest":           controllers.UpdateTaskRequest{},
+	"SetTagsRequest":              controllers.SetTagsRequest{},
+	"AddDependencyRequest":        controllers.AddDependencyRequest{},
+	"BulkTaskRequest":             controllers.BulkTaskRequest{},
+	"BulkTaskOperation":           controllers.BulkTaskOperation{},
+	"BulkTaskFilterRequest":       controllers.BulkTaskFilterRequest{},
+	"GraphQLRequest":              graphqlapi.Request{},
+}
+
+//===========================================================================
+// OpenAPI Consistency Test Suite
+//===========================================================================
+
+// OpenAPISuite checks the embedded OpenAPI document against the gin routes and the
+// request DTOs, so that the two cannot drift apart.
+type OpenAPISuite struct {
+	suite.Suite
+	doc    openAPIDocument
+	routes gin.RoutesInfo
+}
+
+func TestOpenAPISuite(t *testing.T) {
+	suite.Run(t, new(OpenAPISuite))
+}
+
+func (s *OpenAPISuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+	s.Require().NoError(json.Unmarshal(apidocs.Spec(), &s.doc), "openapi.json must be valid JSON")
+
+	// The handlers are only registered, never called, so the controllers can be nil.
+	router := gin.New()
+	routers.SetupRoutes(router, routers.Handlers{
+		Auth:     infrastructure.NewAuthMiddleware(nil, nil, nil),
+		Operator: infrastructure.NewOperatorMiddleware(""),
+	})
+	s.routes = router.Routes()
+}
+
+// undocumentedRoutes serve the documentation itself.
+var undocumentedRoutes = map[string]bool{"get /openapi.json": true, "get /docs": true}
+
+var ginParam = regexp.MustCompile(`[:*]([^/]+)`)
+
+// specPath converts a gin path such as /tasks/:id/ to its OpenAPI form, /tasks/{id}.
+func specPath(path string) string {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return ginParam.ReplaceAllString(path, "{$1}")
+}
+
+func (s *OpenAPISuite) TestEveryRouteIsDocumented() {
+	registered := map[string]bool{}
+	for _, route := range s.routes {
+		path := specPath(route.Path)
+		method := strings.ToLower(route.Method)
+		registered[method+" "+path] = true
+		if undocumentedRoutes[method+" "+path] {
+			continue
+		}
+
+		operation, ok := s.doc.Paths[path][method]
+		if !s.Truef(ok, "%s %s is not in openapi.json", route.Method, path) {
+			continue
+		}
+		var want []string
+		for _, match := range ginParam.FindAllStringSubmatch(route.Path, -1) {
+			want = append(want, match[1])
+		}
+		var got []string
+		for _, parameter := range operation.Parameters {
+			if parameter.In == "path" {
+				got = append(got, parameter.Name)
+			}
+		}
+		sort.Strings(want)
+		sort.Strings(got)
+		s.Equalf(want, got, "path parameters of %s %s", route.Method, path)
+	}
+
+	for path, operations := range s.doc.Paths {
+		for method := range operations {
+			s.Truef(registered[method+" "+path], "openapi.json documents %s %s, which has no route", strings.ToUpper(method), path)
+		}
+	}
+}
+
+func (s *OpenAPISuite) TestRequestSchemasMatchDTOs() {
+	for name, dto := range requestDTOs {
+		s.Run(name, func() {
+			schema, ok := s.doc.Components.Schemas[name]
+			s.Require().Truef(ok, "openapi.json has no schema %s", name)
+
+			dtoType := reflect.TypeOf(dto)
+			var properties, required []string
+			for i := 0; i < dtoType.NumField(); i++ {
+				field := dtoType.Field(i)
+				jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+				if !field.IsExported() || jsonName == "-" {
+					continue
+				}
+				if jsonName == "" {
+					jsonName = field.Name
+				}
+				properties = append(properties, jsonName)
+				if slices.Contains(strings.Split(field.Tag.Get("binding"), ","), "required") {
+					required = append(required, jsonName)
+				}
+
+				property, ok := schema.Properties[jsonName]
+				if s.Truef(ok, "%s.%s is not in the schema", name, jsonName) {
+					s.Equalf(jsonType(field.Type), s.schemaType(property), "type of %s.%s", name, jsonName)
+				}
+			}
+
+			s.ElementsMatch(properties, keys(schema.Properties), "properties of %s", name)
+			s.ElementsMatch(required, schema.Required, "required fields of %s", name)
+		})
+	}
+}
+
+func (s *OpenAPISuite) TestReferencesResolve() {
+	refs := regexp.MustCompile(`"\$ref":\s*"#/components/(\w+)/(\w+)"`)
+	var components map[string]map[string]json.RawMessage
+	var doc struct {
+		Components json.RawMessage `json:"components"`
+	}
+	s.Require().NoError(json.Unmarshal(apidocs.Spec(), &doc))
+	s.Require().NoError(json.Unmarshal(doc.Components, &components))
+	for _, match := range refs.FindAllStringSubmatch(string(apidocs.Spec()), -1) {
+		_, ok := components[match[1]][match[2]]
+		s.Truef(ok, "#/components/%s/%s does not exist", match[1], match[2])
+	}
+}
+
+// schemaType returns the JSON type of a schema, following references.
+func (s *OpenAPISuite) schemaType(schema *openAPISchema) string {
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		target, ok := s.doc.Components.Schemas[name]
+		if !s.Truef(ok, "unknown schema %s", schema.Ref) {
+			return ""
+		}
+		return s.schemaType(target)
+	}
+	return schema.Type
+}
+
+var jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// jsonType returns the JSON type a Go type is decoded from.
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) || reflect.PointerTo(t).Implements(jsonUnmarshaler) {
+		// Types with their own decoding in this API all read strings.
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func keys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"slices"
+)
+
+// Actor is the authenticated caller of a use case. The delivery layer attaches it
+// to the request context after authentication so that use cases can make
+// authorization decisions without depending on the web framework.
+type Actor struct {
+	UserID      string
+	Username    string
+	Role        UserRole
+	Permissions []Permission
+}
+
+func (a *Actor) Has(permission Permission) bool {
+	return slices.Contains(a.Permissions, permission)
+}
+
+// SystemActor is the actor of trusted internal calls, such as startup tasks and
+// background jobs. It holds every permission.
+var SystemActor = &Actor{Username: "system", Permissions: AllPermissions()}
+
+type actorContextKey struct{}
+
+func ContextWithActor(c context.Context, actor *Actor) context.Context {
+	return context.WithValue(c, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the caller stored by ContextWithActor. Calls made without
+// an actor are not trusted; internal calls use SystemActor.
+func ActorFromContext(c context.Context) (*Actor, bool) {
+	actor, ok := c.Value(actorContextKey{}).(*Actor)
+	return actor, ok && actor != nil
+}
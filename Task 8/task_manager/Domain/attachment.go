@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Attachment is the metadata of a file attached to a task. The content lives in a
+// BlobStore under StorageKey.
+type Attachment struct {
+	Id             primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	TaskID         primitive.ObjectID `json:"task_id" bson:"task_id"`
+	FileName       string             `json:"file_name" bson:"file_name"`
+	ContentType    string             `json:"content_type" bson:"content_type"`
+	Size           int64              `json:"size" bson:"size"`
+	StorageKey     string             `json:"-" bson:"storage_key"`
+	UploadedBy     string             `json:"uploaded_by" bson:"uploaded_by"`
+	CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+	OrganizationID primitive.ObjectID `json:"-" bson:"organization_id,omitempty"`
+}
+
+// AttachmentPolicy limits what can be uploaded.
+type AttachmentPolicy struct {
+	MaxSize int64
+	// AllowedContentTypes lists accepted media types. Entries ending in "/*" match a
+	// whole type, e.g. "image/*".
+	AllowedContentTypes []string
+}
+
+func DefaultAttachmentPolicy() AttachmentPolicy {
+	return AttachmentPolicy{
+		MaxSize: 10 << 20, // 10 MiB
+		AllowedContentTypes: []string{
+			"image/*",
+			"text/plain",
+			"text/csv",
+			"application/pdf",
+			"application/json",
+			"application/zip",
+		},
+	}
+}
+
+// Check validates the declared size and content type of an upload.
+func (p AttachmentPolicy) Check(contentType string, size int64) error {
+	if size > p.MaxSize {
+		return fmt.Errorf("%w: %d bytes exceeds the limit of %d bytes", ErrAttachmentTooLarge, size, p.MaxSize)
+	}
+	return p.checkType(contentType)
+}
+
+// ContentType decides the type an upload is stored and served with, from the type the
+// client declared and the type detected from its first bytes (as by
+// http.DetectContentType), and checks it against the allowed types. The detected type
+// wins, so that e.g. an HTML page cannot be uploaded as an image. Only plain text, which
+// detection cannot tell apart from CSV or JSON, keeps a declared textual type.
+func (p AttachmentPolicy) ContentType(declared, detected string) (string, error) {
+	contentType := detected
+	if mediaType(detected) == "text/plain" && isTextual(mediaType(declared)) {
+		contentType = declared
+	}
+	if err := p.checkType(contentType); err != nil {
+		return "", err
+	}
+	return contentType, nil
+}
+
+func (p AttachmentPolicy) checkType(contentType string) error {
+	mediaType := mediaType(contentType)
+	major, _, _ := strings.Cut(mediaType, "/")
+	if !slices.Contains(p.AllowedContentTypes, mediaType) && !slices.Contains(p.AllowedContentTypes, major+"/*") {
+		return fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+	}
+	return nil
+}
+
+// mediaType returns the lower-case media type of a Content-Type without parameters.
+func mediaType(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+func isTextual(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/") || mediaType == "application/json"
+}
+
+// BlobStore stores attachment content by key.
+type BlobStore interface {
+	Put(c context.Context, key string, content io.Reader) (int64, error)
+	Open(c context.Context, key string) (io.ReadCloser, error)
+	Delete(c context.Context, key string) error
+}
+
+type AttachmentRepository interface {
+	CreateAttachment(c context.Context, attachment *Attachment) (*Attachment, error)
+	GetAttachmentById(c context.Context, id primitive.ObjectID) (*Attachment, error)
+	GetAttachmentsByTask(c context.Context, taskID primitive.ObjectID) ([]*Attachment, error)
+	DeleteAttachment(c context.Context, id primitive.ObjectID) error
+	DeleteAttachmentsByTask(c context.Context, taskID primitive.ObjectID) error
+}
+
+var (
+	ErrAttachmentNotFound     = errors.New("attachment not found")
+	ErrAttachmentTooLarge     = errors.New("attachment too large")
+	ErrUnsupportedContentType = errors.New("unsupported attachment content type")
+	ErrBlobNotFound           = errors.New("blob not found")
+)
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// CalendarItemKind selects how tasks appear in a calendar feed. Events show up in
+// every calendar app; to-dos carry the task status but are not supported everywhere.
+type CalendarItemKind string
+
+const (
+	CalendarEvents CalendarItemKind = "event"
+	CalendarTodos  CalendarItemKind = "todo"
+)
+
+func (kind CalendarItemKind) IsValid() bool {
+	return kind == CalendarEvents || kind == CalendarTodos
+}
+
+// CalendarRenderer writes tasks as an iCalendar (RFC 5545) document.
+type CalendarRenderer interface {
+	Render(c context.Context, w io.Writer, name string, tasks []*Task, kind CalendarItemKind) error
+}
+
+var ErrInvalidFeedToken = errors.New("invalid calendar feed token")
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Clock tells the current time. Use cases read the time through a Clock so that tests
+// can control it.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock backed by the system time.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// LoadTimeZone loads an IANA time zone such as "Europe/Berlin". "Local" is rejected
+// because it names the server's zone rather than the caller's.
+func LoadTimeZone(name string) (*time.Location, error) {
+	location, err := time.LoadLocation(name)
+	if err != nil || name == "" || name == "Local" {
+		return nil, fmt.Errorf("%w: unknown time zone %q", ErrValidationFailed, name)
+	}
+	return location, nil
+}
+
+type locationContextKey struct{}
+
+// ContextWithLocation records the caller's time zone, which decides what "today" is
+// for them.
+func ContextWithLocation(c context.Context, location *time.Location) context.Context {
+	return context.WithValue(c, locationContextKey{}, location)
+}
+
+// LocationFromContext returns the time zone stored by ContextWithLocation. Without
+// one it returns UTC and false.
+func LocationFromContext(c context.Context) (*time.Location, bool) {
+	location, ok := c.Value(locationContextKey{}).(*time.Location)
+	if !ok || location == nil {
+		return time.UTC, false
+	}
+	return location, true
+}
+
+// StartOfDay returns midnight of the day t falls on, in t's location.
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// DueDate is a due date as clients send it: either an RFC 3339 date-time, which
+// carries its own time of day and offset, or a plain date (YYYY-MM-DD), which means
+// the start of that day in the caller's time zone.
+type DueDate struct {
+	Time     time.Time
+	DateOnly bool
+}
+
+func (d *DueDate) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseDueDate(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// ParseDueDate reads a date (YYYY-MM-DD) or an RFC 3339 date-time. Other values are
+// reported in a *ValidationError on the "duedate" field.
+func ParseDueDate(value string) (DueDate, error) {
+	if day, err := time.Parse(time.DateOnly, value); err == nil {
+		return DueDate{Time: day, DateOnly: true}, nil
+	}
+	instant, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		violations := &ValidationError{}
+		violations.Add("duedate", RuleFormat, "duedate must be a date (YYYY-MM-DD) or an RFC 3339 date-time")
+		return DueDate{}, violations
+	}
+	return DueDate{Time: instant}, nil
+}
+
+// MarshalJSON writes plain dates as YYYY-MM-DD, so they stay plain dates when read back.
+func (d DueDate) MarshalJSON() ([]byte, error) {
+	if d.DateOnly {
+		return json.Marshal(d.Time.Format(time.DateOnly))
+	}
+	return json.Marshal(d.Time.Format(time.RFC3339Nano))
+}
+
+// In resolves the due date to an instant, placing plain dates in location.
+func (d DueDate) In(location *time.Location) time.Time {
+	if d.DateOnly {
+		return time.Date(d.Time.Year(), d.Time.Month(), d.Time.Day(), 0, 0, 0, 0, location)
+	}
+	return d.Time
+}
+
+// OverdueAt returns when a task due at due becomes overdue for someone in location.
+// Plain dates are stored as the start of their day, so a due date at midnight in
+// location stays due for the whole day and is only overdue once the next day begins.
+func OverdueAt(due time.Time, location *time.Location) time.Time {
+	local := due.In(location)
+	if local.Equal(StartOfDay(local)) {
+		return local.AddDate(0, 0, 1)
+	}
+	return due
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+	ErrTaskBlocked     = errors.New("task is blocked by unfinished tasks")
+)
+
+// DependencyGraph maps a task ID to the IDs of the tasks blocking it.
+type DependencyGraph map[primitive.ObjectID][]primitive.ObjectID
+
+// NewDependencyGraph builds the graph of blocked-by links between the given tasks.
+func NewDependencyGraph(tasks []*Task) DependencyGraph {
+	graph := DependencyGraph{}
+	for _, task := range tasks {
+		graph[task.Id] = task.BlockedBy
+	}
+	return graph
+}
+
+// WouldCreateCycle reports whether making taskID blocked by blockerID would close a
+// cycle, i.e. whether blockerID already depends on taskID directly or transitively.
+// The graph must contain the transitive blockers of blockerID.
+func (g DependencyGraph) WouldCreateCycle(taskID, blockerID primitive.ObjectID) bool {
+	if taskID == blockerID {
+		return true
+	}
+	visited := map[primitive.ObjectID]bool{}
+	stack := []primitive.ObjectID{blockerID}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == taskID {
+			return true
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		stack = append(stack, g[current]...)
+	}
+	return false
+}
+
+// TopologicalOrder sorts tasks so that every task comes after the tasks blocking it.
+// Links to tasks outside the given set are ignored. Ties keep the input order.
+func TopologicalOrder(tasks []*Task) ([]*Task, error) {
+	byID := make(map[primitive.ObjectID]*Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.Id] = task
+	}
+
+	pending := make(map[primitive.ObjectID]int, len(tasks))
+	dependents := map[primitive.ObjectID][]primitive.ObjectID{}
+	for _, task := range tasks {
+		for _, blockerID := range task.BlockedBy {
+			if _, ok := byID[blockerID]; !ok {
+				continue
+			}
+			pending[task.Id]++
+			dependents[blockerID] = append(dependents[blockerID], task.Id)
+		}
+	}
+
+	ordered := make([]*Task, 0, len(tasks))
+	queue := []primitive.ObjectID{}
+	for _, task := range tasks {
+		if pending[task.Id] == 0 {
+			queue = append(queue, task.Id)
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		ordered = append(ordered, byID[current])
+		for _, dependentID := range dependents[current] {
+			pending[dependentID]--
+			if pending[dependentID] == 0 {
+				queue = append(queue, dependentID)
+			}
+		}
+	}
+
+	if len(ordered) != len(tasks) {
+		return nil, ErrDependencyCycle
+	}
+	return ordered, nil
+}
@@ -1,9 +1,12 @@
 package domain
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
@@ -32,36 +35,168 @@ type Task struct {
 	Description string             `json:"description" bson:"description"`
 	DueDate     time.Time          `json:"duedate" bson:"duedate"`
 	Status      TaskStatus         `json:"status" bson:"status"`
+	ProjectID   primitive.ObjectID `json:"project_id,omitempty" bson:"project_id,omitempty"`
+	// BlockedBy lists the tasks that must be Done before this task can start.
+	BlockedBy []primitive.ObjectID `json:"blocked_by,omitempty" bson:"blocked_by,omitempty"`
+	// ExternalID identifies the task in another system it was imported from.
+	ExternalID string `json:"external_id,omitempty" bson:"external_id,omitempty"`
+	// OwnerID is the ID of the user who created the task, if it was created by a user.
+	OwnerID string   `json:"owner_id,omitempty" bson:"owner_id,omitempty"`
+	Tags    []string `json:"tags,omitempty" bson:"tags,omitempty"`
+	// Watchers lists the IDs of the users who are notified when the task changes.
+	Watchers []string `json:"watchers,omitempty" bson:"watchers,omitempty"`
+	// CompletedAt is set when the task becomes Done.
+	CompletedAt *time.Time `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	// OrganizationID is set by the repository from the caller's organization.
+	OrganizationID primitive.ObjectID `json:"-" bson:"organization_id,omitempty"`
 }
 
-func NewTask(title string, description string, dueDate time.Time, status TaskStatus) (*Task, error) {
+// NewTask validates every field and reports all violations at once in a *ValidationError.
+// now is the current time in the caller's time zone; due dates before the start of
+// that day are rejected.
+func NewTask(title string, description string, dueDate time.Time, status TaskStatus, now time.Time) (*Task, error) {
+	violations := &ValidationError{}
 	if title == "" {
-		return nil, errors.New("task title cannot be empty")
+		violations.Add("title", RuleRequired, "task title cannot be empty")
 	}
 	if !status.IsValid() {
-		return nil, errors.New("invalid task status")
+		violations.Add("status", RuleOneOf, "invalid task status")
 	}
 	if dueDate.IsZero() {
-		return nil, errors.New("task due date cannot be empty")
+		violations.Add("duedate", RuleRequired, "task due date cannot be empty")
+	} else if dueDate.Before(StartOfDay(now)) {
+		violations.Add("duedate", RuleNotPast, "task due date cannot be in the past")
 	}
-	if dueDate.Before(time.Now().Truncate(24 * time.Hour)) {
-		return nil, errors.New("task due date cannot be in the past")
+	if err := violations.Err(); err != nil {
+		return nil, err
 	}
-	return &Task{
+	task := &Task{
 		Id:          primitive.NilObjectID,
 		Title:       title,
 		Description: description,
 		DueDate:     dueDate,
-		Status:      status,
-	}, nil
+	}
+	task.SetStatus(status, now)
+	return task, nil
+}
+
+// SetStatus changes the status and keeps CompletedAt in step with it.
+func (t *Task) SetStatus(status TaskStatus, now time.Time) {
+	switch {
+	case status == Done && t.Status != Done:
+		completedAt := now.UTC()
+		t.CompletedAt = &completedAt
+	case status != Done:
+		t.CompletedAt = nil
+	}
+	t.Status = status
+}
+
+const (
+	MaxTagsPerTask = 20
+	MaxTagLength   = 50
+)
+
+// NormalizeTags trims, lowercases and de-duplicates tags, dropping empty ones. Invalid
+// tags are reported in a *ValidationError on the "tags" field.
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || slices.Contains(normalized, tag) {
+			continue
+		}
+		if len(tag) > MaxTagLength {
+			violations := &ValidationError{}
+			violations.Add("tags", RuleMax, fmt.Sprintf("tag %q is longer than %d characters", tag, MaxTagLength))
+			return nil, violations
+		}
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > MaxTagsPerTask {
+		violations := &ValidationError{}
+		violations.Add("tags", RuleMax, fmt.Sprintf("a task can have at most %d tags", MaxTagsPerTask))
+		return nil, violations
+	}
+	return normalized, nil
+}
+
+// TaskFilter narrows task queries. The zero value matches every task.
+type TaskFilter struct {
+	// ProjectID, if set, only matches tasks of that project.
+	ProjectID primitive.ObjectID
+	// Restricted limits results to tasks without a project or in one of the
+	// VisibleProjects, which is how per-project membership is enforced.
+	Restricted      bool
+	VisibleProjects []primitive.ObjectID
+	// IDs, if set, only matches the listed tasks.
+	IDs []primitive.ObjectID
+	// BlockedByAny, if set, only matches tasks blocked by at least one of the listed tasks.
+	BlockedByAny []primitive.ObjectID
+	// Statuses, if set, only matches tasks in one of the listed statuses.
+	Statuses []TaskStatus
+	// DueBefore and DueAfter bound the due date; both bounds are exclusive.
+	DueBefore *time.Time
+	DueAfter  *time.Time
+	// OwnerID and Tag, if set, only match tasks created by that user or carrying that tag.
+	OwnerID string
+	Tag     string
+	// AfterID, if set, only matches tasks with a greater ID. StreamTasks returns tasks
+	// in ID order, so this continues a listing after its last task.
+	AfterID primitive.ObjectID
+}
+
+// Matches reports whether a task satisfies the filter. It defines the filter semantics
+// for repositories that cannot translate the filter into a query.
+func (filter TaskFilter) Matches(task *Task) bool {
+	switch {
+	case !filter.ProjectID.IsZero() && task.ProjectID != filter.ProjectID:
+		return false
+	case len(filter.IDs) > 0 && !slices.Contains(filter.IDs, task.Id):
+		return false
+	case len(filter.BlockedByAny) > 0 && !slices.ContainsFunc(task.BlockedBy, func(id primitive.ObjectID) bool {
+		return slices.Contains(filter.BlockedByAny, id)
+	}):
+		return false
+	case len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, task.Status):
+		return false
+	case filter.DueBefore != nil && !task.DueDate.Before(*filter.DueBefore):
+		return false
+	case filter.DueAfter != nil && !task.DueDate.After(*filter.DueAfter):
+		return false
+	case filter.OwnerID != "" && task.OwnerID != filter.OwnerID:
+		return false
+	case filter.Tag != "" && !slices.Contains(task.Tags, filter.Tag):
+		return false
+	case !filter.AfterID.IsZero() && bytes.Compare(task.Id[:], filter.AfterID[:]) <= 0:
+		return false
+	case filter.Restricted && !task.ProjectID.IsZero() && !slices.Contains(filter.VisibleProjects, task.ProjectID):
+		return false
+	}
+	return true
 }
 
 type TaskRepository interface {
 	CreateTask(c context.Context, task *Task) (*Task, error)
 	GetTaskById(c context.Context, id primitive.ObjectID) (*Task, error)
-	GetAllTasks(c context.Context) ([]*Task, error)
+	GetAllTasks(c context.Context, filter TaskFilter) ([]*Task, error)
+	// StreamTasks calls fn for each matching task without loading them all into memory.
+	// It stops at the first error returned by fn.
+	StreamTasks(c context.Context, filter TaskFilter, fn func(task *Task) error) error
+	GetTaskByExternalID(c context.Context, externalID string) (*Task, error)
+	// GetTaskStats aggregates the tasks matching the filter; see ComputeTaskStats.
+	GetTaskStats(c context.Context, filter TaskFilter, params TaskStatsParams) (*TaskStats, error)
 	UpdateTask(c context.Context, id primitive.ObjectID, task *Task) (*Task, error)
 	DeleteTask(c context.Context, id primitive.ObjectID) error
+	AddBlocker(c context.Context, taskID, blockerID primitive.ObjectID) error
+	RemoveBlocker(c context.Context, taskID, blockerID primitive.ObjectID) error
+	// RemoveBlockerFromAll unlinks a task from every task it blocks, e.g. when it is deleted.
+	RemoveBlockerFromAll(c context.Context, blockerID primitive.ObjectID) error
+	AddWatcher(c context.Context, taskID primitive.ObjectID, userID string) error
+	RemoveWatcher(c context.Context, taskID primitive.ObjectID, userID string) error
+	// RemoveWatcherFromAll stops a user from watching any task, e.g. when they leave
+	// the organization.
+	RemoveWatcherFromAll(c context.Context, userID string) error
 }
 
 type UserRole string
@@ -71,6 +206,8 @@ const (
 	RoleUser  UserRole = "User"
 )
 
+// IsValid reports whether the role is one of the built-in roles. Additional roles
+// may be defined through the RoleRepository.
 func (role UserRole) IsValid() bool {
 	switch role {
 	case RoleAdmin, RoleUser:
@@ -84,11 +221,48 @@ type User struct {
 	Username     string             `json:"username" bson:"username"`
 	PasswordHash string             `json:"-" bson:"password"`
 	Role         UserRole           `json:"role" bson:"role"`
+	// OrganizationID is the organization the user is a member of. Usernames are unique
+	// within an organization.
+	OrganizationID primitive.ObjectID `json:"organization_id,omitempty" bson:"organization_id,omitempty"`
+
+	// Two-factor authentication state. Secrets are stored encrypted and
+	// recovery codes are stored hashed; none of them are ever serialized to clients.
+	TwoFactorEnabled       bool     `json:"two_factor_enabled" bson:"two_factor_enabled"`
+	TwoFactorSecret        string   `json:"-" bson:"two_factor_secret,omitempty"`
+	PendingTwoFactorSecret string   `json:"-" bson:"pending_two_factor_secret,omitempty"`
+	RecoveryCodeHashes     []string `json:"-" bson:"recovery_codes,omitempty"`
+	// LastTwoFactorStep is the TOTP time step of the last accepted code; codes of that
+	// step or earlier ones are rejected so that an observed code cannot be replayed.
+	LastTwoFactorStep int64 `json:"-" bson:"last_two_factor_step,omitempty"`
+	// TwoFactorFailures counts the failed second-factor attempts since the last
+	// successful one, the latest of which was at LastTwoFactorFailure.
+	TwoFactorFailures    int        `json:"-" bson:"two_factor_failures,omitempty"`
+	LastTwoFactorFailure *time.Time `json:"-" bson:"last_two_factor_failure,omitempty"`
+
+	// CalendarTokenHash is the SHA-256 digest of the secret calendar feed token.
+	// Clearing it revokes the feed.
+	CalendarTokenHash string `json:"-" bson:"calendar_token_hash,omitempty"`
+
+	// TimeZone is the IANA name of the user's time zone, used to decide what "today"
+	// is when no Time-Zone header is sent. Empty means UTC.
+	TimeZone string `json:"time_zone,omitempty" bson:"time_zone,omitempty"`
+
+	// MutedNotifications lists the notification types the user turned off.
+	MutedNotifications []NotificationType `json:"-" bson:"muted_notifications,omitempty"`
 }
 
 func NewUser(username string, hashedPassword string) (*User, error) {
-	if username == "" || hashedPassword == "" {
-		return nil, fmt.Errorf("%w: missing required user fields for new user", ErrValidationFailed)
+	violations := &ValidationError{}
+	if username == "" {
+		violations.Add("username", RuleRequired, "username cannot be empty")
+	} else if !usernamePattern.MatchString(username) {
+		violations.Add("username", RuleFormat, `username may only contain letters, digits, "_", "." and "-", and cannot end with "."`)
+	}
+	if hashedPassword == "" {
+		violations.Add("password", RuleRequired, "password cannot be empty")
+	}
+	if err := violations.Err(); err != nil {
+		return nil, err
 	}
 	return &User{
 		Id:           primitive.NilObjectID,
@@ -101,10 +275,25 @@ func NewUser(username string, hashedPassword string) (*User, error) {
 type UserRepository interface {
 	CreateUser(c context.Context, user *User) (*User, error)
 	GetUserByUsername(c context.Context, username string) (*User, error)
-	// GetUserById(c context.Context, id primitive.ObjectID) (*User, error)
-	// GetAllUsers(c context.Context) ([]*User, error)
-	// UpdateUser(c context.Context, id primitive.ObjectID, user *User) (*User, error)
-	// DeleteUser(c context.Context, id primitive.ObjectID) error
+	GetUserById(c context.Context, id primitive.ObjectID) (*User, error)
+	// GetUserByCalendarTokenHash finds the user in any organization: the feed URL is the
+	// only credential a calendar app sends.
+	GetUserByCalendarTokenHash(c context.Context, tokenHash string) (*User, error)
+	GetAllUsers(c context.Context) ([]*User, error)
+	UpdateUser(c context.Context, id primitive.ObjectID, user *User) (*User, error)
+	DeleteUser(c context.Context, id primitive.ObjectID) error
+	// RecordTwoFactorStep atomically sets the user's LastTwoFactorStep to step if it is
+	// lower. It reports false if step was already used, i.e. the code is a replay.
+	RecordTwoFactorStep(c context.Context, id primitive.ObjectID, step int64) (bool, error)
+	// ConsumeRecoveryCode atomically removes the recovery code hash from the user. It
+	// reports false if the user no longer has it, i.e. the code was already used.
+	ConsumeRecoveryCode(c context.Context, id primitive.ObjectID, hash string) (bool, error)
+	// RecordTwoFactorAttempt atomically counts a second-factor attempt made at the given
+	// time as a failure and returns TwoFactorFailures and LastTwoFactorFailure as they
+	// were before it. Successful attempts are taken back with ResetTwoFactorFailures.
+	RecordTwoFactorAttempt(c context.Context, id primitive.ObjectID, at time.Time) (failures int, lastFailure *time.Time, err error)
+	// ResetTwoFactorFailures clears the failure count after a successful attempt.
+	ResetTwoFactorFailures(c context.Context, id primitive.ObjectID) error
 }
 
 type PasswordService interface {
@@ -112,22 +301,72 @@ type PasswordService interface {
 	Compare(c context.Context, password string, hashedPassword string) error
 }
 
+// TokenPurpose distinguishes short-lived challenge tokens from regular access tokens.
+// Access tokens carry an empty purpose.
+type TokenPurpose string
+
+const (
+	PurposeTwoFactorLogin  TokenPurpose = "2fa_login"
+	PurposeTwoFactorEnroll TokenPurpose = "2fa_enroll"
+)
+
 type Claims struct {
-	UserId   string
-	Role     UserRole
-	Username string
+	UserId      string
+	Role        UserRole
+	Username    string
+	Permissions []Permission `json:",omitempty"`
+	Purpose     TokenPurpose `json:",omitempty"`
+	TimeZone    string       `json:",omitempty"`
+	// OrganizationID scopes every request made with the token to the caller's organization.
+	OrganizationID string `json:",omitempty"`
 	jwt.StandardClaims
 }
 
 type JwtService interface {
-	GetSignedToken(c context.Context, user *User) (string, error)
+	// GetSignedToken issues an access token. Permissions are resolved from the
+	// user's role at login time and embedded in the token.
+	GetSignedToken(c context.Context, user *User, permissions []Permission) (string, error)
+	// GetChallengeToken issues a short-lived token that only proves the password step
+	// of a login; it must never be accepted as an access token.
+	GetChallengeToken(c context.Context, user *User, purpose TokenPurpose) (string, error)
 	ParseToken(c context.Context, token string) (*Claims, error)
 }
 
+// SessionValidator checks, on every request, that the user a token was issued to is
+// still a member of its organization. Tokens of removed members are rejected with
+// ErrSessionRevoked before they expire.
+type SessionValidator interface {
+	ValidateSession(c context.Context, claims *Claims) error
+}
+
+// TOTPService implements time-based one-time passwords (RFC 6238).
+type TOTPService interface {
+	GenerateSecret(c context.Context) (string, error)
+	ProvisioningURI(c context.Context, accountName string, secret string) string
+	// Validate checks a code and returns the time step it was generated for.
+	Validate(c context.Context, code string, secret string) (step int64, ok bool)
+}
+
+// SecretCipher encrypts secrets that must be recoverable, such as TOTP seeds.
+type SecretCipher interface {
+	Encrypt(c context.Context, plaintext string) (string, error)
+	Decrypt(c context.Context, ciphertext string) (string, error)
+}
+
 var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrUsernameTaken      = errors.New("username already taken")
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrSessionRevoked     = errors.New("session revoked")
 	ErrTaskNotFound       = errors.New("task not found")
 	ErrValidationFailed   = errors.New("validation failed")
+
+	ErrInvalidTwoFactorCode       = errors.New("invalid two-factor code")
+	ErrTwoFactorAlreadyEnabled    = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorNotEnabled        = errors.New("two-factor authentication not enabled")
+	ErrTwoFactorEnrollmentMissing = errors.New("two-factor enrollment not started")
+	// ErrTooManyTwoFactorAttempts rejects second-factor codes while a user is locked
+	// out after repeated failures.
+	ErrTooManyTwoFactorAttempts = errors.New("too many failed two-factor attempts")
+	ErrTwoFactorRequired        = errors.New("two-factor authentication is required for this account")
 )
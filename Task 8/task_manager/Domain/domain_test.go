@@ -2,10 +2,13 @@ package domain_test
 
 import (
 	"A2SV_ProjectPhase/Task8/TaskManager/Domain" // Adjust your import path
+	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 //===========================================================================
@@ -29,7 +32,7 @@ func (s *TaskSuite) TestSuccess() {
 	dueDate := time.Now().Add(24 * time.Hour).Truncate(24 * time.Hour)
 	status := domain.Pending
 
-	task, err := domain.NewTask(title, description, dueDate, status)
+	task, err := domain.NewTask(title, description, dueDate, status, time.Now())
 
 	// Use Require for checks that must pass for the test to be valid.
 	s.Require().NoError(err, "NewTask should not return an error on valid input")
@@ -88,13 +91,70 @@ func (s *TaskSuite) TestValidation() {
 
 	for _, tc := range testCases {
 		s.Run(tc.name, func() {
-			_, err := domain.NewTask(tc.title, tc.description, tc.dueDate, tc.status)
+			_, err := domain.NewTask(tc.title, tc.description, tc.dueDate, tc.status, time.Now())
 			s.Require().Error(err, "Expected an error for invalid input")
 			s.Equal(tc.expectedError, err.Error(), "Error message mismatch")
 		})
 	}
 }
 
+// TestValidationReportsEveryField checks that NewTask collects all violations.
+func (s *TaskSuite) TestValidationReportsEveryField() {
+	_, err := domain.NewTask("", "desc", time.Now().Add(-48*time.Hour), "InvalidStatus", time.Now())
+
+	var validationErr *domain.ValidationError
+	s.Require().ErrorAs(err, &validationErr)
+	s.ErrorIs(err, domain.ErrValidationFailed)
+	s.Equal([]domain.FieldViolation{
+		{Field: "title", Rule: domain.RuleRequired, Message: "task title cannot be empty"},
+		{Field: "status", Rule: domain.RuleOneOf, Message: "invalid task status"},
+		{Field: "duedate", Rule: domain.RuleNotPast, Message: "task due date cannot be in the past"},
+	}, validationErr.Violations)
+	s.Equal("task title cannot be empty; invalid task status; task due date cannot be in the past", err.Error())
+
+	s.NoError((&domain.ValidationError{}).Err(), "an empty ValidationError is not an error")
+}
+
+// TestDueDateUsesCallerTimeZone checks that "today" is the caller's day, not UTC's.
+func (s *TaskSuite) TestDueDateUsesCallerTimeZone() {
+	losAngeles, err := domain.LoadTimeZone("America/Los_Angeles")
+	s.Require().NoError(err)
+	// 8 PM on March 9 in Los Angeles is already March 10 in UTC.
+	now := time.Date(2026, time.March, 9, 20, 0, 0, 0, losAngeles)
+	today := domain.DueDate{Time: time.Date(2026, time.March, 9, 0, 0, 0, 0, time.UTC), DateOnly: true}
+
+	task, err := domain.NewTask("Title", "desc", today.In(losAngeles), domain.Pending, now)
+	s.Require().NoError(err, "today is not in the past")
+	s.True(task.DueDate.Equal(time.Date(2026, time.March, 9, 7, 0, 0, 0, time.UTC)))
+
+	_, err = domain.NewTask("Title", "desc", now.AddDate(0, 0, -1), domain.Pending, now)
+	s.ErrorIs(err, domain.ErrValidationFailed)
+}
+
+// TestDueDateUnmarshal tests the due date formats accepted from clients.
+func (s *TaskSuite) TestDueDateUnmarshal() {
+	var dueDate domain.DueDate
+	s.Require().NoError(json.Unmarshal([]byte(`"2026-03-09"`), &dueDate))
+	s.True(dueDate.DateOnly)
+	tokyo, err := domain.LoadTimeZone("Asia/Tokyo")
+	s.Require().NoError(err)
+	s.Equal("2026-03-09T00:00:00+09:00", dueDate.In(tokyo).Format(time.RFC3339))
+
+	s.Require().NoError(json.Unmarshal([]byte(`"2026-03-09T17:30:00+01:00"`), &dueDate))
+	s.False(dueDate.DateOnly)
+	s.Equal("2026-03-09T17:30:00+01:00", dueDate.In(tokyo).Format(time.RFC3339), "date-times keep their own offset")
+
+	err = json.Unmarshal([]byte(`"09/03/2026"`), &dueDate)
+	var validationErr *domain.ValidationError
+	s.Require().ErrorAs(err, &validationErr)
+	s.Equal(domain.RuleFormat, validationErr.Violations[0].Rule)
+
+	for _, name := range []string{"", "Local", "Mars/Olympus_Mons"} {
+		_, err := domain.LoadTimeZone(name)
+		s.ErrorIs(err, domain.ErrValidationFailed, name)
+	}
+}
+
 // TestStatusIsValid tests the IsValid method for TaskStatus.
 func (s *TaskSuite) TestStatusIsValid() {
 	testCases := []struct {
@@ -116,6 +176,19 @@ func (s *TaskSuite) TestStatusIsValid() {
 	}
 }
 
+// TestMentions tests finding @username mentions in a description.
+func (s *TaskSuite) TestMentions() {
+	s.Equal([]string{"alice", "bob.smith", "carol"}, domain.Mentions("@alice, please ask @bob.smith. cc (@carol) and @alice"))
+	s.Empty(domain.Mentions("mail alice@example.com or write @@bob"), "Email addresses are not mentions")
+	s.Empty(domain.Mentions("@ nobody"))
+
+	many := ""
+	for i := range domain.MaxMentions + 5 {
+		many += fmt.Sprintf("@user%d ", i)
+	}
+	s.Len(domain.Mentions(many), domain.MaxMentions, "Only the first mentions are resolved")
+}
+
 //===========================================================================
 // User Test Suite
 //===========================================================================
@@ -151,10 +224,11 @@ func (s *UserSuite) TestValidation_MissingFields() {
 		name     string
 		username string
 		password string
+		fields   []string
 	}{
-		{"Empty username", "", "pass"},
-		{"Empty password", "user", ""},
-		{"Both empty", "", ""},
+		{"Empty username", "", "pass", []string{"username"}},
+		{"Empty password", "user", "", []string{"password"}},
+		{"Both empty", "", "", []string{"username", "password"}},
 	}
 
 	for _, tc := range testCases {
@@ -162,6 +236,14 @@ func (s *UserSuite) TestValidation_MissingFields() {
 			_, err := domain.NewUser(tc.username, tc.password)
 			s.Require().Error(err)
 			s.ErrorIs(err, domain.ErrValidationFailed)
+
+			var validationErr *domain.ValidationError
+			s.Require().ErrorAs(err, &validationErr)
+			var fields []string
+			for _, violation := range validationErr.Violations {
+				fields = append(fields, violation.Field)
+			}
+			s.Equal(tc.fields, fields)
 		})
 	}
 }
@@ -185,3 +267,383 @@ func (s *UserSuite) TestRoleIsValid() {
 		})
 	}
 }
+
+// TestValidation_UsernameFormat tests that usernames can be written as mentions.
+func (s *UserSuite) TestValidation_UsernameFormat() {
+	for _, username := range []string{"alice", "bob.smith", "carol_d-2"} {
+		_, err := domain.NewUser(username, "pass")
+		s.NoError(err, username)
+	}
+	for _, username := range []string{"test user", "jürgen", "bob.", "@alice"} {
+		_, err := domain.NewUser(username, "pass")
+		s.ErrorIs(err, domain.ErrValidationFailed, username)
+	}
+}
+
+//===========================================================================
+// RoleDefinition Test Suite
+//===========================================================================
+
+// RoleDefinitionSuite defines the test suite for role permission sets.
+type RoleDefinitionSuite struct {
+	suite.Suite
+}
+
+// TestRoleDefinitionSuite is the runner for the RoleDefinitionSuite.
+func TestRoleDefinitionSuite(t *testing.T) {
+	suite.Run(t, new(RoleDefinitionSuite))
+}
+
+// TestNewRoleDefinition tests validation and de-duplication of permissions.
+func (s *RoleDefinitionSuite) TestNewRoleDefinition() {
+	role, err := domain.NewRoleDefinition("Triager", []domain.Permission{domain.PermTasksRead, domain.PermTasksUpdate, domain.PermTasksRead})
+	s.Require().NoError(err)
+	s.Equal([]domain.Permission{domain.PermTasksRead, domain.PermTasksUpdate}, role.Permissions)
+	s.True(role.Has(domain.PermTasksUpdate))
+	s.False(role.Has(domain.PermTasksDelete))
+
+	_, err = domain.NewRoleDefinition("Triager", []domain.Permission{"tasks:archive"})
+	s.ErrorIs(err, domain.ErrValidationFailed)
+
+	_, err = domain.NewRoleDefinition("", nil)
+	s.ErrorIs(err, domain.ErrValidationFailed)
+}
+
+// TestDefaultRoleDefinitions ensures the defaults preserve the original Admin/User split.
+func (s *RoleDefinitionSuite) TestDefaultRoleDefinitions() {
+	roles := map[domain.UserRole]*domain.RoleDefinition{}
+	for _, role := range domain.DefaultRoleDefinitions() {
+		roles[role.Name] = role
+	}
+
+	s.Require().Contains(roles, domain.RoleAdmin)
+	s.Require().Contains(roles, domain.RoleUser)
+	for _, permission := range domain.AllPermissions() {
+		s.True(roles[domain.RoleAdmin].Has(permission), "Admin should hold %s", permission)
+	}
+	s.Equal([]domain.Permission{domain.PermTasksRead}, roles[domain.RoleUser].Permissions)
+}
+
+//===========================================================================
+// Project Test Suite
+//===========================================================================
+
+type ProjectSuite struct {
+	suite.Suite
+}
+
+func TestProjectSuite(t *testing.T) {
+	suite.Run(t, new(ProjectSuite))
+}
+
+func (s *ProjectSuite) TestNewProject() {
+	project, err := domain.NewProject("Website", "", "owner", time.Now())
+	s.Require().NoError(err)
+	role, ok := project.MemberRole("owner")
+	s.True(ok)
+	s.Equal(domain.ProjectOwner, role)
+
+	_, err = domain.NewProject("", "", "owner", time.Now())
+	s.ErrorIs(err, domain.ErrValidationFailed)
+}
+
+func (s *ProjectSuite) TestMembership() {
+	project, _ := domain.NewProject("Website", "", "owner", time.Now())
+
+	s.Require().NoError(project.SetMember("editor", domain.ProjectEditor))
+	s.Require().NoError(project.SetMember("editor", domain.ProjectViewer))
+	role, _ := project.MemberRole("editor")
+	s.Equal(domain.ProjectViewer, role)
+	s.False(role.CanEditTasks())
+
+	s.ErrorIs(project.SetMember("other", "guest"), domain.ErrValidationFailed)
+	s.ErrorIs(project.SetMember("owner", domain.ProjectEditor), domain.ErrValidationFailed, "the last owner cannot be demoted")
+	s.ErrorIs(project.RemoveMember("owner"), domain.ErrValidationFailed, "the last owner cannot leave")
+	s.ErrorIs(project.RemoveMember("missing"), domain.ErrProjectMemberNotFound)
+
+	s.Require().NoError(project.RemoveMember("editor"))
+	s.Len(project.Members, 1)
+}
+
+//===========================================================================
+// Dependency Test Suite
+//===========================================================================
+
+type DependencySuite struct {
+	suite.Suite
+}
+
+func TestDependencySuite(t *testing.T) {
+	suite.Run(t, new(DependencySuite))
+}
+
+func (s *DependencySuite) TestTopologicalOrder() {
+	a := &domain.Task{Id: primitive.NewObjectID(), Title: "A"}
+	b := &domain.Task{Id: primitive.NewObjectID(), Title: "B", BlockedBy: []primitive.ObjectID{a.Id}}
+	c := &domain.Task{Id: primitive.NewObjectID(), Title: "C", BlockedBy: []primitive.ObjectID{a.Id, b.Id, primitive.NewObjectID()}}
+
+	ordered, err := domain.TopologicalOrder([]*domain.Task{c, b, a})
+	s.Require().NoError(err)
+	s.Equal([]*domain.Task{a, b, c}, ordered)
+
+	a.BlockedBy = []primitive.ObjectID{c.Id}
+	_, err = domain.TopologicalOrder([]*domain.Task{a, b, c})
+	s.ErrorIs(err, domain.ErrDependencyCycle)
+}
+
+func (s *DependencySuite) TestWouldCreateCycle() {
+	a, b, c := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	// c is blocked by b, b is blocked by a
+	graph := domain.DependencyGraph{c: {b}, b: {a}}
+
+	s.True(graph.WouldCreateCycle(a, c), "a blocked by c closes a -> c -> b -> a")
+	s.True(graph.WouldCreateCycle(a, a))
+	s.False(graph.WouldCreateCycle(c, a))
+}
+
+//===========================================================================
+// AttachmentPolicy Test Suite
+//===========================================================================
+
+type AttachmentPolicySuite struct {
+	suite.Suite
+}
+
+func TestAttachmentPolicySuite(t *testing.T) {
+	suite.Run(t, new(AttachmentPolicySuite))
+}
+
+func (s *AttachmentPolicySuite) TestCheck() {
+	policy := domain.AttachmentPolicy{MaxSize: 100, AllowedContentTypes: []string{"application/pdf", "image/*"}}
+
+	s.NoError(policy.Check("application/pdf", 100))
+	s.NoError(policy.Check("Image/PNG", 1), "media types are case-insensitive")
+	s.NoError(policy.Check("application/pdf; name=spec.pdf", 1), "parameters are ignored")
+	s.ErrorIs(policy.Check("application/pdf", 101), domain.ErrAttachmentTooLarge)
+	s.ErrorIs(policy.Check("text/html", 1), domain.ErrUnsupportedContentType)
+	s.ErrorIs(policy.Check("", 1), domain.ErrUnsupportedContentType)
+}
+
+func (s *AttachmentPolicySuite) TestContentType() {
+	policy := domain.AttachmentPolicy{MaxSize: 100, AllowedContentTypes: []string{"image/*", "text/csv", "application/json"}}
+
+	contentType, err := policy.ContentType("image/jpeg", "image/png")
+	s.Require().NoError(err)
+	s.Equal("image/png", contentType, "the detected type wins")
+
+	contentType, err = policy.ContentType("text/csv", "text/plain; charset=utf-8")
+	s.Require().NoError(err)
+	s.Equal("text/csv", contentType, "plain text keeps a declared textual type")
+
+	_, err = policy.ContentType("image/png", "text/html; charset=utf-8")
+	s.ErrorIs(err, domain.ErrUnsupportedContentType)
+	_, err = policy.ContentType("image/png", "text/plain; charset=utf-8")
+	s.ErrorIs(err, domain.ErrUnsupportedContentType, "text cannot pass as an image")
+	_, err = policy.ContentType("application/json", "application/octet-stream")
+	s.ErrorIs(err, domain.ErrUnsupportedContentType)
+}
+
+//===========================================================================
+// Revision Test Suite
+//===========================================================================
+
+type RevisionSuite struct {
+	suite.Suite
+}
+
+func TestRevisionSuite(t *testing.T) {
+	suite.Run(t, new(RevisionSuite))
+}
+
+func (s *RevisionSuite) TestDiffTasks() {
+	dueDate := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := &domain.Task{Title: "Old", Description: "Same", DueDate: dueDate, Status: domain.Pending}
+	after := &domain.Task{Title: "New", Description: "Same", DueDate: dueDate.Add(24 * time.Hour), Status: domain.Pending}
+
+	s.Equal([]domain.FieldChange{
+		{Field: "title", Old: "Old", New: "New"},
+		{Field: "duedate", Old: "2099-01-01T00:00:00Z", New: "2099-01-02T00:00:00Z"},
+	}, domain.DiffTasks(before, after))
+
+	s.Empty(domain.DiffTasks(before, before))
+	s.Len(domain.DiffTasks(nil, before), 4, "a new task reports every set field")
+}
+
+//===========================================================================
+// Task Statistics Test Suite
+//===========================================================================
+
+type TaskStatsSuite struct {
+	suite.Suite
+}
+
+func TestTaskStatsSuite(t *testing.T) {
+	suite.Run(t, new(TaskStatsSuite))
+}
+
+func (s *TaskStatsSuite) TestSetStatusTracksCompletion() {
+	now := time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC)
+	task := &domain.Task{Status: domain.Pending}
+
+	task.SetStatus(domain.Done, now)
+	s.Require().NotNil(task.CompletedAt)
+	s.Equal(now, *task.CompletedAt)
+
+	task.SetStatus(domain.Done, now.Add(time.Hour))
+	s.Equal(now, *task.CompletedAt, "staying Done keeps the original completion time")
+
+	task.SetStatus(domain.InProgress, now)
+	s.Nil(task.CompletedAt)
+}
+
+func (s *TaskStatsSuite) TestNormalizeTags() {
+	tags, err := domain.NormalizeTags([]string{" Backend", "backend", "", "UI"})
+	s.Require().NoError(err)
+	s.Equal([]string{"backend", "ui"}, tags)
+
+	_, err = domain.NormalizeTags([]string{string(make([]byte, domain.MaxTagLength+1))})
+	s.ErrorIs(err, domain.ErrValidationFailed)
+}
+
+func (s *TaskStatsSuite) TestFilterMatches() {
+	project := primitive.NewObjectID()
+	task := &domain.Task{Id: primitive.NewObjectID(), Status: domain.Pending, ProjectID: project, OwnerID: "alice", Tags: []string{"ui"}}
+
+	s.True(domain.TaskFilter{}.Matches(task))
+	s.True(domain.TaskFilter{OwnerID: "alice", Tag: "ui", Statuses: []domain.TaskStatus{domain.Pending}}.Matches(task))
+	s.False(domain.TaskFilter{OwnerID: "bob"}.Matches(task))
+	s.False(domain.TaskFilter{Tag: "backend"}.Matches(task))
+	s.False(domain.TaskFilter{Restricted: true}.Matches(task))
+	s.True(domain.TaskFilter{Restricted: true, VisibleProjects: []primitive.ObjectID{project}}.Matches(task))
+}
+
+func (s *TaskStatsSuite) TestComputeTaskStats() {
+	now := time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC) // a Wednesday
+	completed := func(day int) *time.Time {
+		t := time.Date(2025, 3, day, 15, 0, 0, 0, time.UTC)
+		return &t
+	}
+	tasks := []*domain.Task{
+		{Status: domain.Pending, DueDate: now.Add(-48 * time.Hour)},     // overdue, due this week
+		{Status: domain.InProgress, DueDate: now.Add(24 * time.Hour)},   // due this week
+		{Status: domain.Pending, DueDate: now.Add(10 * 24 * time.Hour)}, // later
+		{Status: domain.Pending, DueDate: domain.StartOfDay(now)},       // due today, not overdue yet
+		{Status: domain.Done, DueDate: now.Add(-72 * time.Hour), CompletedAt: completed(10)},
+		{Status: domain.Done, DueDate: now, CompletedAt: completed(10)},
+		{Status: domain.Done, DueDate: now, CompletedAt: completed(1)}, // outside the range
+	}
+	params := domain.TaskStatsParams{
+		Now:       now,
+		WeekStart: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		WeekEnd:   time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC),
+		From:      time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC),
+		Location:  time.UTC,
+	}
+
+	stats := domain.ComputeTaskStats(tasks, params)
+	s.Equal(7, stats.Total)
+	s.Equal(map[domain.TaskStatus]int{domain.Pending: 3, domain.InProgress: 1, domain.Done: 3}, stats.ByStatus)
+	s.Equal(1, stats.Overdue)
+	s.Equal(3, stats.DueThisWeek)
+	s.Equal([]domain.DailyCount{
+		{Date: "2025-03-09", Count: 0},
+		{Date: "2025-03-10", Count: 2},
+		{Date: "2025-03-11", Count: 0},
+		{Date: "2025-03-12", Count: 0},
+	}, stats.CompletionsPerDay)
+
+	tomorrow := params
+	tomorrow.Now = domain.StartOfDay(now).AddDate(0, 0, 1)
+	s.Equal(2, domain.ComputeTaskStats(tasks, tomorrow).Overdue, "a plain date is overdue once its day has ended")
+
+	empty := domain.ComputeTaskStats(nil, params)
+	s.Equal(map[domain.TaskStatus]int{domain.Pending: 0, domain.InProgress: 0, domain.Done: 0}, empty.ByStatus)
+	s.Len(empty.CompletionsPerDay, 4)
+}
+
+//===========================================================================
+// Task Patch Test Suite
+//===========================================================================
+
+type TaskPatchSuite struct {
+	suite.Suite
+}
+
+func TestTaskPatchSuite(t *testing.T) {
+	suite.Run(t, new(TaskPatchSuite))
+}
+
+func (s *TaskPatchSuite) TestMergePatch() {
+	var patch domain.TaskPatch
+	s.Require().NoError(json.Unmarshal([]byte(`{"title": "New", "description": null}`), &patch))
+	s.Equal(domain.Present("New"), patch.Title)
+	s.Equal(domain.PatchField[string]{Set: true, Null: true}, patch.Description)
+	s.False(patch.DueDate.Set)
+	s.False(patch.Tags.Set)
+
+	err := json.Unmarshal([]byte(`{"id": "1", "owner_id": "x", "duedate": "tomorrow"}`), &patch)
+	var validationErr *domain.ValidationError
+	s.Require().ErrorAs(err, &validationErr)
+	s.Equal([]domain.FieldViolation{
+		{Field: "duedate", Rule: domain.RuleFormat, Message: "duedate must be a date (YYYY-MM-DD) or an RFC 3339 date-time"},
+		{Field: "id", Rule: domain.RuleNotPatchable, Message: "id cannot be changed with a patch"},
+		{Field: "owner_id", Rule: domain.RuleNotPatchable, Message: "owner_id cannot be changed with a patch"},
+	}, validationErr.Violations)
+
+	var typeErr *json.UnmarshalTypeError
+	s.Require().ErrorAs(json.Unmarshal([]byte(`{"tags": "a,b"}`), &patch), &typeErr)
+	s.Equal("tags", typeErr.Field)
+
+	s.ErrorIs(json.Unmarshal([]byte(`null`), &patch), domain.ErrValidationFailed)
+}
+
+// TestMergePatchRoundTrip tests that a marshaled patch reads back unchanged, as it
+// does when the gRPC API sends one.
+func (s *TaskPatchSuite) TestMergePatchRoundTrip() {
+	var patch domain.TaskPatch
+	s.Require().NoError(json.Unmarshal([]byte(`{"duedate": "2026-03-09", "description": null, "tags": ["ui"]}`), &patch))
+
+	data, err := json.Marshal(patch)
+	s.Require().NoError(err)
+	s.JSONEq(`{"duedate": "2026-03-09", "description": null, "tags": ["ui"]}`, string(data))
+
+	var decoded domain.TaskPatch
+	s.Require().NoError(json.Unmarshal(data, &decoded))
+	s.Equal(patch, decoded)
+}
+
+func (s *TaskPatchSuite) TestJSONPatch() {
+	task := &domain.Task{Title: "Title", Status: domain.Pending, Tags: []string{"a", "b", "c"}}
+
+	patch, err := task.JSONPatch([]domain.PatchOperation{
+		{Op: "move", From: "/tags/0", Path: "/tags/-"},
+		{Op: "add", Path: "/tags/0", Value: json.RawMessage(`"z"`)},
+		{Op: "remove", Path: "/tags/1"},
+		{Op: "test", Path: "/tags", Value: json.RawMessage(`["z", "c", "a"]`)},
+	})
+	s.Require().NoError(err)
+	s.Equal(domain.Present([]string{"z", "c", "a"}), patch.Tags)
+	s.Equal(domain.Present("Title"), patch.Title, "untouched fields keep their value")
+	s.Equal([]string{"a", "b", "c"}, task.Tags, "the task itself is not changed")
+
+	patch, err = task.JSONPatch([]domain.PatchOperation{{Op: "remove", Path: "/tags"}})
+	s.Require().NoError(err)
+	s.True(patch.Tags.Null, "removing a field clears it")
+
+	invalid := []domain.PatchOperation{
+		{Op: "add", Path: "title"},
+		{Op: "jump", Path: "/title"},
+		{Op: "add", Path: "/tags/01", Value: json.RawMessage(`"x"`)},
+		{Op: "remove", Path: "/missing"},
+		{Op: "move", From: "/tags", Path: "/tags/0"},
+		{Op: "replace", Path: "/title"},
+	}
+	for _, operation := range invalid {
+		_, err := task.JSONPatch([]domain.PatchOperation{operation})
+		s.ErrorIs(err, domain.ErrInvalidPatch, "%s %s", operation.Op, operation.Path)
+	}
+
+	_, err = task.JSONPatch([]domain.PatchOperation{{Op: "test", Path: "/title", Value: json.RawMessage(`"Other"`)}})
+	s.ErrorIs(err, domain.ErrPatchTestFailed)
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrIdempotencyKeyNotHeld is returned when a request changes a record it no longer
+// holds, because its reservation expired and another request took the key over.
+var ErrIdempotencyKeyNotHeld = errors.New("idempotency key is no longer held")
+
+// IdempotencyRecord remembers a request sent with an Idempotency-Key so that retries
+// of it can be answered with the original response instead of being executed again.
+// Key combines the caller's user ID with the client's key, because keys are only
+// unique per client. Owner is a random token of the request that reserved the key;
+// only that request may extend, complete or release the record.
+type IdempotencyRecord struct {
+	Key         string    `bson:"_id"`
+	RequestHash string    `bson:"request_hash"`
+	Owner       string    `bson:"owner,omitempty"`
+	Completed   bool      `bson:"completed"`
+	StatusCode  int       `bson:"status_code,omitempty"`
+	ContentType string    `bson:"content_type,omitempty"`
+	Body        []byte    `bson:"body,omitempty"`
+	ExpiresAt   time.Time `bson:"expires_at"`
+}
+
+// IdempotencyStore keeps idempotency records until they expire.
+type IdempotencyStore interface {
+	// Reserve stores record unless an unexpired record with the same key exists, in
+	// which case it returns that record and stores nothing. It returns nil when the
+	// caller now holds the key.
+	Reserve(c context.Context, record *IdempotencyRecord) (*IdempotencyRecord, error)
+	// Extend moves the expiry of the unfinished record held by record.Owner to
+	// record.ExpiresAt, or returns ErrIdempotencyKeyNotHeld.
+	Extend(c context.Context, record *IdempotencyRecord) error
+	// Complete replaces the unfinished record held by record.Owner with its completed
+	// version, or returns ErrIdempotencyKeyNotHeld.
+	Complete(c context.Context, record *IdempotencyRecord) error
+	// Release deletes the unfinished record held by record.Owner, so that the request
+	// can be retried.
+	Release(c context.Context, record *IdempotencyRecord) error
+}
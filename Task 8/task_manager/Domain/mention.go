@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MaxMentions is how many users one description can mention. Further mentions are
+// left as plain text, so that a description cannot look up every user.
+const MaxMentions = 20
+
+// mentionPattern matches "@username" at the start of the text or after a character
+// that cannot be part of a word, so that email addresses are not taken for mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.-]+)`)
+
+// usernamePattern accepts the usernames a mention can name: ASCII letters, digits,
+// "_", "." and "-", not ending in a full stop, which would end the sentence instead.
+var usernamePattern = regexp.MustCompile(`^[\w.-]*[\w-]$`)
+
+// Mentions returns the usernames mentioned with "@username" in text, without
+// duplicates and in the order they first appear, up to MaxMentions. A trailing full
+// stop ends the sentence rather than the username.
+func Mentions(text string) []string {
+	usernames := []string{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		if len(usernames) == MaxMentions {
+			break
+		}
+		username := strings.TrimRight(match[1], ".")
+		if username != "" && !slices.Contains(usernames, username) {
+			usernames = append(usernames, username)
+		}
+	}
+	return usernames
+}
+
+// IsWatchedBy reports whether the user is one of the task's watchers.
+func (t *Task) IsWatchedBy(userID string) bool {
+	return slices.Contains(t.Watchers, userID)
+}
+
+// Watch adds the users to the task's watchers unless they already watch it. The
+// watchers are copied first, so copies of the task made earlier keep their own list.
+func (t *Task) Watch(userIDs ...string) {
+	watchers := slices.Clone(t.Watchers)
+	for _, userID := range userIDs {
+		if !slices.Contains(watchers, userID) {
+			watchers = append(watchers, userID)
+		}
+	}
+	t.Watchers = watchers
+}
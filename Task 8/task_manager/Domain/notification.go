@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationType names the kind of event a notification reports. Users can turn each
+// type off in their preferences. Tasks have no assignees or comments yet, so there are
+// no types for being assigned a task or for comments on it; they are a follow-up.
+type NotificationType string
+
+const (
+	// NotificationTaskUpdated tells a task's owner and watchers that someone else changed
+	// it.
+	NotificationTaskUpdated NotificationType = "task.updated"
+	// NotificationTaskDeleted tells a task's owner and watchers that someone else deleted
+	// it.
+	NotificationTaskDeleted NotificationType = "task.deleted"
+	// NotificationTaskOverdue tells a task's owner that its due date passed before it
+	// was done.
+	NotificationTaskOverdue NotificationType = "task.overdue"
+	// NotificationTaskMentioned tells a user that someone mentioned them in a task and
+	// made them a watcher.
+	NotificationTaskMentioned NotificationType = "task.mentioned"
+)
+
+// NotificationTypes lists every notification type, in the order preferences are shown.
+func NotificationTypes() []NotificationType {
+	return []NotificationType{NotificationTaskUpdated, NotificationTaskDeleted, NotificationTaskOverdue, NotificationTaskMentioned}
+}
+
+func (t NotificationType) IsValid() bool {
+	return slices.Contains(NotificationTypes(), t)
+}
+
+// Notification is an entry in a user's inbox.
+type Notification struct {
+	Id     primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID string             `json:"user_id" bson:"user_id"`
+	Type   NotificationType   `json:"type" bson:"type"`
+	TaskID primitive.ObjectID `json:"task_id" bson:"task_id"`
+	// TaskTitle is the title when the notification was created, so that notifications
+	// about deleted tasks still say which task they were about.
+	TaskTitle string `json:"task_title" bson:"task_title"`
+	// Changes lists what changed, for NotificationTaskUpdated.
+	Changes []FieldChange `json:"changes,omitempty" bson:"changes,omitempty"`
+	// ActorID is the user who caused the notification; empty for notifications raised
+	// by the server itself, such as overdue reminders.
+	ActorID   string     `json:"actor_id,omitempty" bson:"actor_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at" bson:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty" bson:"read_at,omitempty"`
+	// Key, if set, makes the notification unique: a second one with the same key is
+	// rejected with ErrNotificationExists. It keeps periodic checks from repeating
+	// themselves.
+	Key            string             `json:"-" bson:"key,omitempty"`
+	OrganizationID primitive.ObjectID `json:"-" bson:"organization_id,omitempty"`
+}
+
+// NotificationQuery selects a page of a user's notifications, newest first.
+type NotificationQuery struct {
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
+
+const (
+	DefaultNotificationPageSize = 20
+	MaxNotificationPageSize     = 100
+)
+
+// NotificationPage is one page of a user's inbox. Total counts the notifications the
+// query selects across all pages; Unread counts every unread notification.
+type NotificationPage struct {
+	Notifications []*Notification `json:"notifications"`
+	Total         int64           `json:"total"`
+	Unread        int64           `json:"unread"`
+	Limit         int             `json:"limit"`
+	Offset        int             `json:"offset"`
+}
+
+// NotificationPreferences says for each notification type whether the user receives it.
+type NotificationPreferences map[NotificationType]bool
+
+// NotificationPreferences returns the user's preferences. Every type is on unless the
+// user turned it off.
+func (u *User) NotificationPreferences() NotificationPreferences {
+	preferences := NotificationPreferences{}
+	for _, t := range NotificationTypes() {
+		preferences[t] = !slices.Contains(u.MutedNotifications, t)
+	}
+	return preferences
+}
+
+// WantsNotification reports whether the user receives notifications of the given type.
+func (u *User) WantsNotification(t NotificationType) bool {
+	return !slices.Contains(u.MutedNotifications, t)
+}
+
+// SetNotificationPreferences changes the types named in preferences and keeps the
+// others as they are.
+func (u *User) SetNotificationPreferences(preferences NotificationPreferences) error {
+	violations := &ValidationError{}
+	for t := range preferences {
+		if !t.IsValid() {
+			violations.Add(string(t), RuleOneOf, "unknown notification type")
+		}
+	}
+	if err := violations.Err(); err != nil {
+		return err
+	}
+	muted := []NotificationType{}
+	for _, t := range NotificationTypes() {
+		enabled, ok := preferences[t]
+		if !ok {
+			enabled = u.WantsNotification(t)
+		}
+		if !enabled {
+			muted = append(muted, t)
+		}
+	}
+	u.MutedNotifications = muted
+	return nil
+}
+
+type NotificationRepository interface {
+	// CreateNotification reports ErrNotificationExists if a notification with the same
+	// Key exists.
+	CreateNotification(c context.Context, notification *Notification) (*Notification, error)
+	// GetNotifications returns the page of the user's notifications selected by query,
+	// newest first, and how many notifications the query selects in total.
+	GetNotifications(c context.Context, userID string, query NotificationQuery) ([]*Notification, int64, error)
+	CountUnread(c context.Context, userID string) (int64, error)
+	// MarkRead marks one of the user's notifications read. Marking a read notification
+	// again keeps its original ReadAt.
+	MarkRead(c context.Context, userID string, id primitive.ObjectID, at time.Time) error
+	// MarkAllRead marks every unread notification of the user read and returns how many
+	// there were.
+	MarkAllRead(c context.Context, userID string, at time.Time) (int64, error)
+}
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrNotificationExists   = errors.New("notification already exists")
+)
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultOrganizationSlug names the organization that existing data is moved into, that
+// open registration joins, and that logins without an organization go to.
+const DefaultOrganizationSlug = "default"
+
+// Organization is a tenant. Users, tasks and everything attached to them belong to
+// exactly one organization and are invisible to all others.
+type Organization struct {
+	Id   primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Name string             `json:"name" bson:"name"`
+	// Slug identifies the organization at login, since usernames are only unique
+	// within an organization.
+	Slug      string    `json:"slug" bson:"slug"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{0,48}[a-z0-9])?$`)
+
+func NewOrganization(name string, slug string, now time.Time) (*Organization, error) {
+	violations := &ValidationError{}
+	if name == "" {
+		violations.Add("name", RuleRequired, "organization name cannot be empty")
+	}
+	if slug == "" {
+		violations.Add("slug", RuleRequired, "organization slug cannot be empty")
+	} else if !slugPattern.MatchString(slug) {
+		violations.Add("slug", RuleFormat, "organization slug must be 1 to 50 lowercase letters, digits or hyphens, and cannot start or end with a hyphen")
+	}
+	if err := violations.Err(); err != nil {
+		return nil, err
+	}
+	return &Organization{
+		Id:        primitive.NilObjectID,
+		Name:      name,
+		Slug:      slug,
+		CreatedAt: now,
+	}, nil
+}
+
+type OrganizationRepository interface {
+	// CreateOrganization reports ErrOrganizationSlugTaken if the slug is in use.
+	CreateOrganization(c context.Context, organization *Organization) (*Organization, error)
+	GetOrganizationById(c context.Context, id primitive.ObjectID) (*Organization, error)
+	GetOrganizationBySlug(c context.Context, slug string) (*Organization, error)
+}
+
+// Invitation lets someone register as a member of an organization. Only a hash of its
+// token is stored; the token is shown once when the invitation is created.
+type Invitation struct {
+	Id             primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	OrganizationID primitive.ObjectID `json:"-" bson:"organization_id,omitempty"`
+	TokenHash      string             `json:"-" bson:"token_hash"`
+	// Role is the role the new member gets.
+	Role      UserRole  `json:"role" bson:"role"`
+	CreatedBy string    `json:"created_by" bson:"created_by"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	ExpiresAt time.Time `json:"expires_at" bson:"expires_at"`
+}
+
+// InvitationTTL is how long an invitation can be accepted.
+const InvitationTTL = 7 * 24 * time.Hour
+
+func NewInvitation(role UserRole, createdBy string, now time.Time) (*Invitation, error) {
+	if role == "" {
+		violations := &ValidationError{}
+		violations.Add("role", RuleRequired, "invitation role cannot be empty")
+		return nil, violations
+	}
+	return &Invitation{
+		Id:        primitive.NilObjectID,
+		Role:      role,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		ExpiresAt: now.Add(InvitationTTL),
+	}, nil
+}
+
+func (i *Invitation) IsActive(now time.Time) bool {
+	return now.Before(i.ExpiresAt)
+}
+
+type InvitationRepository interface {
+	CreateInvitation(c context.Context, invitation *Invitation) (*Invitation, error)
+	// GetInvitationByTokenHash finds an invitation in any organization, since whoever
+	// accepts it is not a member of one yet.
+	GetInvitationByTokenHash(c context.Context, tokenHash string) (*Invitation, error)
+	GetAllInvitations(c context.Context) ([]*Invitation, error)
+	DeleteInvitation(c context.Context, id primitive.ObjectID) error
+}
+
+type organizationContextKey struct{}
+
+// ContextWithOrganization scopes every repository call made with the returned context
+// to the organization. The delivery layer sets it from the caller's token.
+func ContextWithOrganization(c context.Context, organizationID primitive.ObjectID) context.Context {
+	return context.WithValue(c, organizationContextKey{}, organizationID)
+}
+
+// OrganizationFromContext returns the organization stored by ContextWithOrganization.
+// Repositories serve calls without an organization from no organization at all,
+// unless the context was marked with ContextWithSystemScope.
+func OrganizationFromContext(c context.Context) (primitive.ObjectID, bool) {
+	organizationID, ok := c.Value(organizationContextKey{}).(primitive.ObjectID)
+	return organizationID, ok && !organizationID.IsZero()
+}
+
+type systemScopeContextKey struct{}
+
+// ContextWithSystemScope marks a trusted internal call that works across
+// organizations, such as the overdue check or moving old data into the default
+// organization. An organization set with ContextWithOrganization still takes
+// precedence.
+func ContextWithSystemScope(c context.Context) context.Context {
+	return context.WithValue(c, systemScopeContextKey{}, true)
+}
+
+// IsSystemScope reports whether c was marked with ContextWithSystemScope.
+func IsSystemScope(c context.Context) bool {
+	system, _ := c.Value(systemScopeContextKey{}).(bool)
+	return system
+}
+
+var (
+	ErrOrganizationNotFound  = errors.New("organization not found")
+	ErrOrganizationSlugTaken = errors.New("organization slug already taken")
+	ErrInvitationNotFound    = errors.New("invitation not found")
+	ErrInvalidInvitation     = errors.New("invalid or expired invitation")
+	// ErrNoOrganization is reported for writes made with neither an organization nor
+	// the system scope in the context.
+	ErrNoOrganization = errors.New("no organization in context")
+)
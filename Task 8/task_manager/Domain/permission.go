@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Permission is a single capability checked by the authorization middleware.
+type Permission string
+
+const (
+	PermTasksRead   Permission = "tasks:read"
+	PermTasksCreate Permission = "tasks:create"
+	PermTasksUpdate Permission = "tasks:update"
+	PermTasksDelete Permission = "tasks:delete"
+	PermUsersManage Permission = "users:manage"
+	// PermProjectsManage grants access to every project regardless of membership.
+	PermProjectsManage Permission = "projects:manage"
+	// PermTasksImport allows bulk-loading tasks from CSV or JSON files.
+	PermTasksImport Permission = "tasks:import"
+	// PermTasksRevert allows restoring a task from its history, overwriting later edits.
+	PermTasksRevert Permission = "tasks:revert"
+)
+
+// AllPermissions lists every permission known to the application.
+func AllPermissions() []Permission {
+	return []Permission{PermTasksRead, PermTasksCreate, PermTasksUpdate, PermTasksDelete, PermUsersManage, PermProjectsManage, PermTasksImport, PermTasksRevert}
+}
+
+func (p Permission) IsValid() bool {
+	return slices.Contains(AllPermissions(), p)
+}
+
+// RoleDefinition maps a role name to the set of permissions it grants. Every
+// organization defines its own roles.
+type RoleDefinition struct {
+	Name           UserRole           `json:"name" bson:"name"`
+	Permissions    []Permission       `json:"permissions" bson:"permissions"`
+	OrganizationID primitive.ObjectID `json:"-" bson:"organization_id,omitempty"`
+	// SeededPermissions lists the permissions a built-in role has been granted on
+	// startup so far. Permissions that are built in but not listed are new and get
+	// added; listed ones that an administrator removed stay removed.
+	SeededPermissions []Permission `json:"-" bson:"seeded_permissions,omitempty"`
+}
+
+func NewRoleDefinition(name UserRole, permissions []Permission) (*RoleDefinition, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: role name cannot be empty", ErrValidationFailed)
+	}
+	unique := make([]Permission, 0, len(permissions))
+	for _, p := range permissions {
+		if !p.IsValid() {
+			return nil, fmt.Errorf("%w: unknown permission %q", ErrValidationFailed, p)
+		}
+		if !slices.Contains(unique, p) {
+			unique = append(unique, p)
+		}
+	}
+	return &RoleDefinition{Name: name, Permissions: unique}, nil
+}
+
+func (r *RoleDefinition) Has(permission Permission) bool {
+	return slices.Contains(r.Permissions, permission)
+}
+
+// DefaultRoleDefinitions are seeded on startup when the role store has no entry
+// for the built-in roles, preserving the original Admin/User behaviour, and into every
+// new organization.
+func DefaultRoleDefinitions() []*RoleDefinition {
+	return []*RoleDefinition{
+		{Name: RoleAdmin, Permissions: AllPermissions()},
+		{Name: RoleUser, Permissions: []Permission{PermTasksRead}},
+	}
+}
+
+type RoleRepository interface {
+	GetRole(c context.Context, name UserRole) (*RoleDefinition, error)
+	GetAllRoles(c context.Context) ([]*RoleDefinition, error)
+	UpsertRole(c context.Context, role *RoleDefinition) (*RoleDefinition, error)
+}
+
+var ErrRoleNotFound = errors.New("role not found")
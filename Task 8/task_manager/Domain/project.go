@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ProjectRole string
+
+const (
+	ProjectOwner  ProjectRole = "owner"
+	ProjectEditor ProjectRole = "editor"
+	ProjectViewer ProjectRole = "viewer"
+)
+
+func (role ProjectRole) IsValid() bool {
+	switch role {
+	case ProjectOwner, ProjectEditor, ProjectViewer:
+		return true
+	}
+	return false
+}
+
+// CanEditTasks reports whether members with this role may create, change and delete tasks.
+func (role ProjectRole) CanEditTasks() bool {
+	return role == ProjectOwner || role == ProjectEditor
+}
+
+type ProjectMember struct {
+	UserID string      `json:"user_id" bson:"user_id"`
+	Role   ProjectRole `json:"role" bson:"role"`
+}
+
+// Project groups tasks; only its members can see and change them.
+type Project struct {
+	Id             primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Name           string             `json:"name" bson:"name"`
+	Description    string             `json:"description" bson:"description"`
+	Members        []ProjectMember    `json:"members" bson:"members"`
+	CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+	OrganizationID primitive.ObjectID `json:"-" bson:"organization_id,omitempty"`
+}
+
+func NewProject(name string, description string, ownerID string, now time.Time) (*Project, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: project name cannot be empty", ErrValidationFailed)
+	}
+	if ownerID == "" {
+		return nil, fmt.Errorf("%w: project owner cannot be empty", ErrValidationFailed)
+	}
+	return &Project{
+		Id:          primitive.NilObjectID,
+		Name:        name,
+		Description: description,
+		Members:     []ProjectMember{{UserID: ownerID, Role: ProjectOwner}},
+		CreatedAt:   now,
+	}, nil
+}
+
+func (p *Project) MemberRole(userID string) (ProjectRole, bool) {
+	for _, member := range p.Members {
+		if member.UserID == userID {
+			return member.Role, true
+		}
+	}
+	return "", false
+}
+
+// SetMember adds a member or changes the role of an existing one.
+func (p *Project) SetMember(userID string, role ProjectRole) error {
+	if !role.IsValid() {
+		return fmt.Errorf("%w: invalid project role", ErrValidationFailed)
+	}
+	for i, member := range p.Members {
+		if member.UserID != userID {
+			continue
+		}
+		if member.Role == ProjectOwner && role != ProjectOwner && p.ownerCount() == 1 {
+			return fmt.Errorf("%w: a project must keep at least one owner", ErrValidationFailed)
+		}
+		p.Members[i].Role = role
+		return nil
+	}
+	p.Members = append(p.Members, ProjectMember{UserID: userID, Role: role})
+	return nil
+}
+
+func (p *Project) RemoveMember(userID string) error {
+	idx := slices.IndexFunc(p.Members, func(m ProjectMember) bool { return m.UserID == userID })
+	if idx < 0 {
+		return ErrProjectMemberNotFound
+	}
+	if p.Members[idx].Role == ProjectOwner && p.ownerCount() == 1 {
+		return fmt.Errorf("%w: a project must keep at least one owner", ErrValidationFailed)
+	}
+	p.Members = slices.Delete(p.Members, idx, idx+1)
+	return nil
+}
+
+func (p *Project) ownerCount() int {
+	count := 0
+	for _, member := range p.Members {
+		if member.Role == ProjectOwner {
+			count++
+		}
+	}
+	return count
+}
+
+type ProjectRepository interface {
+	CreateProject(c context.Context, project *Project) (*Project, error)
+	GetProjectById(c context.Context, id primitive.ObjectID) (*Project, error)
+	GetProjectsForMember(c context.Context, userID string) ([]*Project, error)
+	UpdateProject(c context.Context, id primitive.ObjectID, project *Project) (*Project, error)
+}
+
+var (
+	ErrProjectNotFound       = errors.New("project not found")
+	ErrProjectMemberNotFound = errors.New("project member not found")
+	ErrForbidden             = errors.New("access forbidden")
+)
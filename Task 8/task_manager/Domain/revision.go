@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FieldChange records the old and new value of one task field, formatted as text.
+type FieldChange struct {
+	Field string `json:"field" bson:"field"`
+	Old   string `json:"old" bson:"old"`
+	New   string `json:"new" bson:"new"`
+}
+
+// TaskSnapshot holds the editable fields of a task at a point in time.
+type TaskSnapshot struct {
+	Title       string     `json:"title" bson:"title"`
+	Description string     `json:"description" bson:"description"`
+	DueDate     time.Time  `json:"duedate" bson:"duedate"`
+	Status      TaskStatus `json:"status" bson:"status"`
+}
+
+func SnapshotOf(task *Task) TaskSnapshot {
+	return TaskSnapshot{
+		Title:       task.Title,
+		Description: task.Description,
+		DueDate:     task.DueDate,
+		Status:      task.Status,
+	}
+}
+
+// TaskRevision is an immutable record of one change to a task. Snapshot is the state
+// of the task after the change.
+type TaskRevision struct {
+	Id             primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	TaskID         primitive.ObjectID `json:"task_id" bson:"task_id"`
+	Changes        []FieldChange      `json:"changes" bson:"changes"`
+	Snapshot       TaskSnapshot       `json:"snapshot" bson:"snapshot"`
+	EditedBy       string             `json:"edited_by" bson:"edited_by"`
+	CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+	OrganizationID primitive.ObjectID `json:"-" bson:"organization_id,omitempty"`
+}
+
+// DiffTasks lists the editable fields that differ between two versions of a task.
+// A nil before means the task was just created, so every set field is reported.
+func DiffTasks(before, after *Task) []FieldChange {
+	var old TaskSnapshot
+	if before != nil {
+		old = SnapshotOf(before)
+	}
+	updated := SnapshotOf(after)
+
+	changes := []FieldChange{}
+	add := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, FieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+	add("title", old.Title, updated.Title)
+	add("description", old.Description, updated.Description)
+	add("duedate", formatRevisionTime(old.DueDate), formatRevisionTime(updated.DueDate))
+	add("status", string(old.Status), string(updated.Status))
+	return changes
+}
+
+func formatRevisionTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+type TaskRevisionRepository interface {
+	CreateRevision(c context.Context, revision *TaskRevision) (*TaskRevision, error)
+	GetRevisionById(c context.Context, id primitive.ObjectID) (*TaskRevision, error)
+	// GetRevisionsByTask returns a task's revisions, oldest first.
+	GetRevisionsByTask(c context.Context, taskID primitive.ObjectID) ([]*TaskRevision, error)
+}
+
+var ErrRevisionNotFound = errors.New("revision not found")
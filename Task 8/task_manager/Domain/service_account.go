@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RoleService is the role reported for requests authenticated with an API key.
+// Its permissions come from the key's scopes rather than from a role definition.
+const RoleService UserRole = "Service"
+
+// ServiceAccount is a non-human identity used by CI jobs and integrations.
+type ServiceAccount struct {
+	Id             primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Name           string             `json:"name" bson:"name"`
+	Description    string             `json:"description" bson:"description"`
+	CreatedBy      string             `json:"created_by" bson:"created_by"`
+	CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+	OrganizationID primitive.ObjectID `json:"-" bson:"organization_id,omitempty"`
+}
+
+func NewServiceAccount(name string, description string, createdBy string, now time.Time) (*ServiceAccount, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: service account name cannot be empty", ErrValidationFailed)
+	}
+	return &ServiceAccount{
+		Id:          primitive.NilObjectID,
+		Name:        name,
+		Description: description,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+	}, nil
+}
+
+// APIKey is a credential belonging to a service account. Only a hash of the key
+// is stored; the plaintext is shown once when the key is created.
+type APIKey struct {
+	Id               primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	ServiceAccountID primitive.ObjectID `json:"service_account_id" bson:"service_account_id"`
+	Name             string             `json:"name" bson:"name"`
+	Prefix           string             `json:"prefix" bson:"prefix"`
+	KeyHash          string             `json:"-" bson:"key_hash"`
+	Scopes           []Permission       `json:"scopes" bson:"scopes"`
+	CreatedAt        time.Time          `json:"created_at" bson:"created_at"`
+	ExpiresAt        *time.Time         `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	RevokedAt        *time.Time         `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	LastUsedAt       *time.Time         `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	OrganizationID   primitive.ObjectID `json:"-" bson:"organization_id,omitempty"`
+}
+
+func NewAPIKey(serviceAccountID primitive.ObjectID, name string, scopes []Permission, expiresAt *time.Time, now time.Time) (*APIKey, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: API key name cannot be empty", ErrValidationFailed)
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: API key needs at least one scope", ErrValidationFailed)
+	}
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return nil, fmt.Errorf("%w: unknown scope %q", ErrValidationFailed, scope)
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, fmt.Errorf("%w: API key expiry must be in the future", ErrValidationFailed)
+	}
+	return &APIKey{
+		Id:               primitive.NilObjectID,
+		ServiceAccountID: serviceAccountID,
+		Name:             name,
+		Scopes:           scopes,
+		CreatedAt:        now,
+		ExpiresAt:        expiresAt,
+	}, nil
+}
+
+// IsActive reports whether the key can currently be used to authenticate.
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+type ServiceAccountRepository interface {
+	CreateServiceAccount(c context.Context, account *ServiceAccount) (*ServiceAccount, error)
+	GetServiceAccountById(c context.Context, id primitive.ObjectID) (*ServiceAccount, error)
+	GetAllServiceAccounts(c context.Context) ([]*ServiceAccount, error)
+	DeleteServiceAccount(c context.Context, id primitive.ObjectID) error
+}
+
+type APIKeyRepository interface {
+	CreateAPIKey(c context.Context, key *APIKey) (*APIKey, error)
+	GetAPIKeyByHash(c context.Context, keyHash string) (*APIKey, error)
+	GetAPIKeysByServiceAccount(c context.Context, serviceAccountID primitive.ObjectID) ([]*APIKey, error)
+	RevokeAPIKey(c context.Context, id primitive.ObjectID, revokedAt time.Time) error
+	TouchAPIKey(c context.Context, id primitive.ObjectID, usedAt time.Time) error
+}
+
+// APIKeyAuthenticator resolves a raw API key into the same claims a JWT would carry.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(c context.Context, rawKey string) (*Claims, error)
+}
+
+var (
+	ErrServiceAccountNotFound = errors.New("service account not found")
+	ErrAPIKeyNotFound         = errors.New("API key not found")
+	ErrInvalidAPIKey          = errors.New("invalid API key")
+)
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type TaskEventType string
+
+const (
+	TaskCreated TaskEventType = "task.created"
+	TaskUpdated TaskEventType = "task.updated"
+	TaskDeleted TaskEventType = "task.deleted"
+)
+
+// TaskEvent describes a change made by the TaskUseCase. Task holds the task as it was
+// at the time of the event; for updates, Previous holds the task before the change.
+type TaskEvent struct {
+	Type       TaskEventType
+	Task       *Task
+	Previous   *Task
+	OccurredAt time.Time
+}
+
+// TaskEventHandler reacts to task events. Handlers run after the change has been
+// persisted, so they cannot veto it; they report their own failures.
+type TaskEventHandler func(c context.Context, event TaskEvent)
+
+// ErrWatchLagged ends a watch whose receiver could not keep up with the events, so that
+// it does not silently miss some. The receiver can start a new watch and re-read the
+// tasks it cares about.
+var ErrWatchLagged = errors.New("watcher fell behind")
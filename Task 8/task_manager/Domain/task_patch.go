@@ -0,0 +1,400 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PatchField is a field of a partial update. It distinguishes a field that was left
+// out (Set is false) from one that was explicitly cleared with null (Null is true)
+// and from one given a value.
+type PatchField[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// Present returns a PatchField set to value.
+func Present[T any](value T) PatchField[T] {
+	return PatchField[T]{Set: true, Value: value}
+}
+
+// jsonValue returns the value to encode for the field, and false if it was left out.
+func (f PatchField[T]) jsonValue() (any, bool) {
+	if f.Null {
+		return nil, f.Set
+	}
+	return f.Value, f.Set
+}
+
+func (f *PatchField[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if string(data) == "null" {
+		f.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
+
+// TaskPatch is a partial update of the fields of a task that clients may edit.
+// Clearing a required field, such as the title, is a validation error.
+type TaskPatch struct {
+	Title       PatchField[string]
+	Description PatchField[string]
+	DueDate     PatchField[DueDate]
+	Status      PatchField[TaskStatus]
+	Tags        PatchField[[]string]
+}
+
+func (p *TaskPatch) fields() map[string]json.Unmarshaler {
+	return map[string]json.Unmarshaler{
+		"title":       &p.Title,
+		"description": &p.Description,
+		"duedate":     &p.DueDate,
+		"status":      &p.Status,
+		"tags":        &p.Tags,
+	}
+}
+
+// UnmarshalJSON reads a JSON Merge Patch (RFC 7396): members that are left out stay
+// unchanged and null clears a field. Members that are not editable fields are
+// reported in a *ValidationError.
+func (p *TaskPatch) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	if members == nil {
+		return fmt.Errorf("%w: a merge patch must be a JSON object", ErrValidationFailed)
+	}
+
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := p.fields()
+	violations := &ValidationError{}
+	for _, name := range names {
+		field, ok := fields[name]
+		if !ok {
+			violations.Add(name, RuleNotPatchable, name+" cannot be changed with a patch")
+			continue
+		}
+		if err := field.UnmarshalJSON(members[name]); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				typeErr.Field = name
+				return typeErr
+			}
+			var fieldViolations *ValidationError
+			if errors.As(err, &fieldViolations) {
+				violations.Violations = append(violations.Violations, fieldViolations.Violations...)
+				continue
+			}
+			violations.Add(name, RuleFormat, name+" has an invalid format")
+		}
+	}
+	return violations.Err()
+}
+
+// MarshalJSON writes the patch as a JSON Merge Patch, the form UnmarshalJSON reads.
+func (p TaskPatch) MarshalJSON() ([]byte, error) {
+	members := map[string]any{}
+	for name, field := range map[string]interface{ jsonValue() (any, bool) }{
+		"title":       p.Title,
+		"description": p.Description,
+		"duedate":     p.DueDate,
+		"status":      p.Status,
+		"tags":        p.Tags,
+	} {
+		if value, ok := field.jsonValue(); ok {
+			members[name] = value
+		}
+	}
+	return json.Marshal(members)
+}
+
+var (
+	// ErrInvalidPatch reports a JSON Patch that cannot be applied, for example because
+	// a path does not exist.
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrPatchTestFailed reports a JSON Patch "test" operation that did not match.
+	ErrPatchTestFailed = errors.New("patch test failed")
+)
+
+// PatchOperation is one operation of a JSON Patch (RFC 6902).
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// editableTaskFields is the document JSON Patch operations apply to. It holds the
+// fields of TaskPatch under their JSON names.
+type editableTaskFields struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	DueDate     time.Time  `json:"duedate"`
+	Status      TaskStatus `json:"status"`
+	Tags        []string   `json:"tags"`
+}
+
+// JSONPatch applies a JSON Patch to the editable fields of the task and returns the
+// result as a TaskPatch. The task itself is not changed. Removing a field clears it.
+func (t *Task) JSONPatch(operations []PatchOperation) (TaskPatch, error) {
+	fields := editableTaskFields{Title: t.Title, Description: t.Description, DueDate: t.DueDate, Status: t.Status, Tags: t.Tags}
+	if fields.Tags == nil {
+		// Lets clients append with "/tags/-" to a task without tags.
+		fields.Tags = []string{}
+	}
+	var doc any
+	if err := roundTripJSON(fields, &doc); err != nil {
+		return TaskPatch{}, err
+	}
+
+	for i, operation := range operations {
+		var err error
+		doc, err = applyPatchOperation(doc, operation)
+		if errors.Is(err, ErrPatchTestFailed) {
+			return TaskPatch{}, fmt.Errorf("%w: operation %d: %s does not match", ErrPatchTestFailed, i, operation.Path)
+		}
+		if err != nil {
+			return TaskPatch{}, fmt.Errorf("%w: operation %d (%s %s): %v", ErrInvalidPatch, i, operation.Op, operation.Path, err)
+		}
+	}
+
+	result, ok := doc.(map[string]any)
+	if !ok {
+		return TaskPatch{}, fmt.Errorf("%w: the patched task must be a JSON object", ErrInvalidPatch)
+	}
+	var patch TaskPatch
+	for name := range patch.fields() {
+		if _, ok := result[name]; !ok {
+			result[name] = nil
+		}
+	}
+	if err := roundTripJSON(result, &patch); err != nil {
+		return TaskPatch{}, err
+	}
+	return patch, nil
+}
+
+func roundTripJSON(in, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("domain: failed to encode patch document: %w", err)
+	}
+	return json.Unmarshal(data, out)
+}
+
+func applyPatchOperation(doc any, operation PatchOperation) (any, error) {
+	path, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	switch operation.Op {
+	case "add", "replace", "test":
+		if operation.Value == nil {
+			return nil, errors.New("missing value")
+		}
+		if err := json.Unmarshal(operation.Value, &value); err != nil {
+			return nil, errors.New("value is not valid JSON")
+		}
+	}
+
+	switch operation.Op {
+	case "add":
+		return addValue(doc, path, value)
+	case "remove":
+		doc, _, err = removeValue(doc, path)
+		return doc, err
+	case "replace":
+		doc, _, err = removeValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, value)
+	case "move", "copy":
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		if operation.Op == "move" {
+			if len(from) < len(path) && slices.Equal(from, path[:len(from)]) {
+				return nil, errors.New("cannot move a value into one of its children")
+			}
+			doc, value, err = removeValue(doc, from)
+		} else {
+			value, err = getValue(doc, from)
+			if err == nil {
+				// The copy must not share maps or slices with the original.
+				err = roundTripJSON(value, &value)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, value)
+	case "test":
+		current, err := getValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(current, value) {
+			return nil, ErrPatchTestFailed
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown operation %q", operation.Op)
+	}
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%q is not a JSON pointer", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+var errPathNotFound = errors.New("path does not exist")
+
+// arrayIndex parses an array index, which may be at most max.
+func arrayIndex(token string, max int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > max || strconv.Itoa(index) != token {
+		return 0, errPathNotFound
+	}
+	return index, nil
+}
+
+func getValue(node any, path []string) (any, error) {
+	for _, token := range path {
+		switch container := node.(type) {
+		case map[string]any:
+			child, ok := container[token]
+			if !ok {
+				return nil, errPathNotFound
+			}
+			node = child
+		case []any:
+			index, err := arrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = container[index]
+		default:
+			return nil, errPathNotFound
+		}
+	}
+	return node, nil
+}
+
+// addValue adds value at path and returns the updated node. Values added to an array
+// are inserted before the given index, or appended for "-".
+func addValue(node any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	token, rest := path[0], path[1:]
+	switch container := node.(type) {
+	case map[string]any:
+		if len(rest) == 0 {
+			container[token] = value
+			return container, nil
+		}
+		child, ok := container[token]
+		if !ok {
+			return nil, errPathNotFound
+		}
+		updated, err := addValue(child, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = updated
+		return container, nil
+	case []any:
+		if len(rest) == 0 {
+			index := len(container)
+			if token != "-" {
+				var err error
+				if index, err = arrayIndex(token, len(container)); err != nil {
+					return nil, err
+				}
+			}
+			return slices.Insert(container, index, value), nil
+		}
+		index, err := arrayIndex(token, len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := addValue(container[index], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = updated
+		return container, nil
+	default:
+		return nil, errPathNotFound
+	}
+}
+
+// removeValue removes the value at path and returns the updated node and the value.
+func removeValue(node any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, node, nil
+	}
+	token, rest := path[0], path[1:]
+	switch container := node.(type) {
+	case map[string]any:
+		child, ok := container[token]
+		if !ok {
+			return nil, nil, errPathNotFound
+		}
+		if len(rest) == 0 {
+			delete(container, token)
+			return container, child, nil
+		}
+		updated, removed, err := removeValue(child, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		container[token] = updated
+		return container, removed, nil
+	case []any:
+		index, err := arrayIndex(token, len(container)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rest) == 0 {
+			removed := container[index]
+			return slices.Delete(container, index, index+1), removed, nil
+		}
+		updated, removed, err := removeValue(container[index], rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		container[index] = updated
+		return container, removed, nil
+	default:
+		return nil, nil, errPathNotFound
+	}
+}
@@ -0,0 +1,88 @@
+package domain
+
+import "time"
+
+// TaskStatsParams fixes the time windows of a statistics query, so that every
+// repository computes the same numbers for the same moment.
+type TaskStatsParams struct {
+	// Now separates overdue tasks from upcoming ones. A due date at midnight in Location
+	// is a plain date and only overdue once its day has ended (see OverdueAt).
+	Now time.Time
+	// WeekStart and WeekEnd bound "due this week" (start inclusive, end exclusive).
+	WeekStart time.Time
+	WeekEnd   time.Time
+	// From and To bound the completions per day (From inclusive, To exclusive). Both are
+	// midnights in Location, which also decides the calendar day a completion falls on.
+	From     time.Time
+	To       time.Time
+	Location *time.Location
+}
+
+type DailyCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+type TaskStats struct {
+	Total    int                `json:"total"`
+	ByStatus map[TaskStatus]int `json:"by_status"`
+	// Overdue counts unfinished tasks whose due date has passed, with the same rule
+	// as OverdueAt.
+	Overdue int `json:"overdue"`
+	// DueThisWeek counts unfinished tasks due between WeekStart and WeekEnd.
+	DueThisWeek int `json:"due_this_week"`
+	// CompletionsPerDay has one entry for every day from From to To, including days
+	// without completions.
+	CompletionsPerDay []DailyCount `json:"completions_per_day"`
+}
+
+// statsDateFormat is the day key of CompletionsPerDay.
+const statsDateFormat = "2006-01-02"
+
+// ComputeTaskStats is the reference implementation of TaskRepository.GetTaskStats for
+// tasks already loaded into memory.
+func ComputeTaskStats(tasks []*Task, params TaskStatsParams) *TaskStats {
+	completions := map[string]int{}
+	stats := &TaskStats{}
+	for _, task := range tasks {
+		stats.AddToStatus(task.Status, 1)
+		if task.Status != Done {
+			if !params.Now.Before(OverdueAt(task.DueDate, params.Location)) {
+				stats.Overdue++
+			}
+			if !task.DueDate.Before(params.WeekStart) && task.DueDate.Before(params.WeekEnd) {
+				stats.DueThisWeek++
+			}
+		}
+		if task.CompletedAt != nil && !task.CompletedAt.Before(params.From) && task.CompletedAt.Before(params.To) {
+			completions[task.CompletedAt.In(params.Location).Format(statsDateFormat)]++
+		}
+	}
+	stats.SetCompletions(completions, params)
+	return stats
+}
+
+// AddToStatus adds count tasks of the given status to the totals.
+func (s *TaskStats) AddToStatus(status TaskStatus, count int) {
+	s.initByStatus()
+	s.ByStatus[status] += count
+	s.Total += count
+}
+
+// SetCompletions fills CompletionsPerDay from counts keyed by "YYYY-MM-DD", adding
+// zero entries for the days in between.
+func (s *TaskStats) SetCompletions(counts map[string]int, params TaskStatsParams) {
+	s.initByStatus()
+	s.CompletionsPerDay = []DailyCount{}
+	for day := params.From.In(params.Location); day.Before(params.To); day = day.AddDate(0, 0, 1) {
+		key := day.Format(statsDateFormat)
+		s.CompletionsPerDay = append(s.CompletionsPerDay, DailyCount{Date: key, Count: counts[key]})
+	}
+}
+
+// initByStatus lists every status, so that statuses without tasks show up as zero.
+func (s *TaskStats) initByStatus() {
+	if s.ByStatus == nil {
+		s.ByStatus = map[TaskStatus]int{Pending: 0, InProgress: 0, Done: 0}
+	}
+}
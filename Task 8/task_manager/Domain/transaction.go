@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// Transactor runs a unit of work atomically. Repository calls made with the context
+// passed to fn take part in the transaction; if fn returns an error, all of them are
+// undone. Implementations may call fn more than once, e.g. to retry after a transient
+// conflict, so fn must not keep state between calls.
+type Transactor interface {
+	WithinTransaction(c context.Context, fn func(c context.Context) error) error
+}
+
+// NoopTransactor runs fn directly, without a transaction. It is used with stores that
+// cannot roll back, such as the in-memory repositories or a MongoDB server that is not
+// part of a replica set: a failing fn then leaves the writes made before the failure.
+type NoopTransactor struct{}
+
+func (NoopTransactor) WithinTransaction(c context.Context, fn func(c context.Context) error) error {
+	return fn(c)
+}
+
+var ErrTransactionsUnsupported = errors.New("transactions are not supported by the database")
@@ -0,0 +1,56 @@
+package domain
+
+import "strings"
+
+// Rules reported in FieldViolation.Rule. Binding errors use the name of the failed
+// validator tag instead, such as "required" or "oneof".
+const (
+	RuleRequired  = "required"
+	RuleOneOf     = "oneof"
+	RuleNotPast   = "not_past"
+	RuleImmutable = "immutable"
+	RuleMax       = "max"
+	RuleFormat    = "format"
+	// RuleNotPatchable reports a patch that tries to change a field patches cannot change.
+	RuleNotPatchable = "not_patchable"
+)
+
+// FieldViolation describes one invalid field. Field is the JSON name of the field,
+// Rule a short machine-readable name for the check that failed, and Message is
+// meant for people.
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError collects every violation found in an input, so that clients can
+// fix them all at once. It matches ErrValidationFailed with errors.Is.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Add(field, rule, message string) {
+	e.Violations = append(e.Violations, FieldViolation{Field: field, Rule: rule, Message: message})
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidationFailed
+}
+
+// Err returns e if any violation was added and nil otherwise. Returning e directly
+// would produce a non-nil error interface even without violations.
+func (e *ValidationError) Err() error {
+	if len(e.Violations) == 0 {
+		return nil
+	}
+	return e
+}
@@ -2,30 +2,65 @@ package infrastructure
 
 import (
 	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
 	"log"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type AuthMiddleware struct {
 	jwtService domain.JwtService
+	apiKeys    domain.APIKeyAuthenticator
+	sessions   domain.SessionValidator
 }
 
-func NewAuthMiddleware(jwtService domain.JwtService) *AuthMiddleware {
-	return &AuthMiddleware{jwtService: jwtService}
+// NewAuthMiddleware creates the authentication middleware. apiKeys may be nil, in
+// which case the X-API-Key header is not accepted. sessions may be nil, in which case
+// tokens stay valid until they expire, even after their user is removed.
+func NewAuthMiddleware(jwtService domain.JwtService, apiKeys domain.APIKeyAuthenticator, sessions domain.SessionValidator) *AuthMiddleware {
+	return &AuthMiddleware{jwtService: jwtService, apiKeys: apiKeys, sessions: sessions}
 }
 
 // Authenticate is the primary authentication middleware.
-// It verifies the token and stores *all* claims in the context.
+// It verifies the token (or the X-API-Key header of a service account) and stores
+// *all* claims in the context.
 // It does NOT perform any authorization checks itself.
 func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
+	return m.authenticate()
+}
+
+// AuthenticateForEnrollment behaves like Authenticate but additionally accepts the
+// short-lived challenge token issued to users who must enroll in two-factor
+// authentication before they can obtain a regular access token.
+func (m *AuthMiddleware) AuthenticateForEnrollment() gin.HandlerFunc {
+	return m.authenticate(domain.PurposeTwoFactorEnroll)
+}
+
+func (m *AuthMiddleware) authenticate(allowedPurposes ...domain.TokenPurpose) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// Service accounts authenticate with an API key instead of a JWT. Keys are
+		// never valid for the two-factor enrollment routes.
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" && m.apiKeys != nil && len(allowedPurposes) == 0 {
+			claims, err := m.apiKeys.AuthenticateAPIKey(c.Request.Context(), apiKey)
+			if err != nil {
+				log.Printf("AuthMiddleware: API key authentication failed: %v\n", err)
+				AbortWithProblem(c, http.StatusUnauthorized, CodeInvalidAPIKey, domain.ErrInvalidAPIKey.Error())
+				return
+			}
+			setClaims(c, claims)
+			c.Next()
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
 			log.Println("AuthMiddleware: Missing or malformed Authorization header")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Authorization token required"})
+			AbortWithProblem(c, http.StatusUnauthorized, CodeAuthenticationRequired, "Authorization token required")
 			return
 		}
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
@@ -33,19 +68,85 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 		claims, err := m.jwtService.ParseToken(c.Request.Context(), tokenString)
 		if err != nil {
 			log.Printf("AuthMiddleware: Token parsing/validation failed: %v\n", err)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+			AbortWithProblem(c, http.StatusUnauthorized, CodeInvalidToken, err.Error())
 			return
 		}
 
-		// Store relevant claims in context using string literals
-		c.Set("userID", claims.UserId)
-		c.Set("username", claims.Username)
-		c.Set("userRole", claims.Role)
+		// Challenge tokens only prove the password step of a login and must not
+		// grant access unless the route explicitly allows their purpose.
+		if claims.Purpose != "" && !slices.Contains(allowedPurposes, claims.Purpose) {
+			log.Printf("AuthMiddleware: Rejected %q challenge token for user '%s'\n", claims.Purpose, claims.Username)
+			AbortWithProblem(c, http.StatusUnauthorized, CodeInvalidToken, "invalid token")
+			return
+		}
+		if _, ok := claimedOrganization(claims); !ok {
+			log.Printf("AuthMiddleware: Rejected token without an organization for user '%s'\n", claims.Username)
+			AbortWithProblem(c, http.StatusUnauthorized, CodeInvalidToken, errTokenWithoutOrganization)
+			return
+		}
+		if m.sessions != nil {
+			if err := m.sessions.ValidateSession(c.Request.Context(), claims); err != nil {
+				if errors.Is(err, domain.ErrSessionRevoked) {
+					log.Printf("AuthMiddleware: Rejected revoked token for user '%s'\n", claims.Username)
+					AbortWithProblem(c, http.StatusUnauthorized, CodeInvalidToken, errRevokedToken)
+					return
+				}
+				log.Printf("AuthMiddleware: Session validation failed: %v\n", err)
+				AbortWithProblem(c, http.StatusInternalServerError, CodeInternal, "failed to validate session")
+				return
+			}
+		}
 
+		setClaims(c, claims)
 		c.Next() // Proceed to the next handler
 	}
 }
 
+// setClaims stores relevant claims in context using string literals
+func setClaims(c *gin.Context, claims *domain.Claims) {
+	c.Set("userID", claims.UserId)
+	c.Set("username", claims.Username)
+	c.Set("userRole", claims.Role)
+	c.Set("userPermissions", claims.Permissions)
+
+	// Use cases read the caller from the request context rather than from gin.
+	c.Request = c.Request.WithContext(contextWithClaims(c.Request.Context(), claims))
+}
+
+// errTokenWithoutOrganization is reported for tokens issued before organizations existed.
+const errTokenWithoutOrganization = "token has no organization; log in again"
+
+// errRevokedToken is reported for tokens of users who were removed from their organization.
+const errRevokedToken = "token has been revoked"
+
+// claimedOrganization returns the organization the token was issued for.
+func claimedOrganization(claims *domain.Claims) (primitive.ObjectID, bool) {
+	organizationID, err := primitive.ObjectIDFromHex(claims.OrganizationID)
+	return organizationID, err == nil && !organizationID.IsZero()
+}
+
+// contextWithClaims stores the caller described by claims in ctx, scoped to their
+// organization, along with the time zone from the user's profile. A zone already in
+// ctx, taken from a Time-Zone header, takes precedence.
+func contextWithClaims(ctx context.Context, claims *domain.Claims) context.Context {
+	actor := &domain.Actor{
+		UserID:      claims.UserId,
+		Username:    claims.Username,
+		Role:        claims.Role,
+		Permissions: claims.Permissions,
+	}
+	ctx = domain.ContextWithActor(ctx, actor)
+	if organizationID, ok := claimedOrganization(claims); ok {
+		ctx = domain.ContextWithOrganization(ctx, organizationID)
+	}
+	if _, ok := domain.LocationFromContext(ctx); !ok && claims.TimeZone != "" {
+		if location, err := domain.LoadTimeZone(claims.TimeZone); err == nil {
+			ctx = domain.ContextWithLocation(ctx, location)
+		}
+	}
+	return ctx
+}
+
 // AuthorizeAdmin is an authorization middleware.
 // It ASSUMES Authenticate() has already run and set claims in context.
 func (m *AuthMiddleware) AuthorizeAdmin() gin.HandlerFunc {
@@ -54,23 +155,54 @@ func (m *AuthMiddleware) AuthorizeAdmin() gin.HandlerFunc {
 		role, exists := c.Get("userRole")
 		if !exists {
 			log.Println("AuthorizeAdmin: User role not found in context. Authenticate middleware likely missing or failed.")
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "Authentication context missing or invalid"})
+			AbortWithProblem(c, http.StatusInternalServerError, CodeInternal, "Authentication context missing or invalid")
 			return
 		}
 
 		userRole, ok := role.(domain.UserRole) // Type assert to domain.UserRole
 		if !ok {
 			log.Printf("AuthorizeAdmin: Invalid user role type in context: %T\n", role)
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "Invalid user role format"})
+			AbortWithProblem(c, http.StatusInternalServerError, CodeInternal, "Invalid user role format")
 			return
 		}
 
 		if userRole != domain.RoleAdmin {
 			log.Printf("AuthorizeAdmin: User '%s' (ID: %s) attempted to access admin route without Admin role (Role: %s)\n", c.GetString("username"), c.GetString("userID"), userRole)
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "Access forbidden: Admin role required"})
+			AbortWithProblem(c, http.StatusForbidden, CodeForbidden, "Access forbidden: Admin role required")
 			return
 		}
 
 		c.Next() // User is Admin, proceed to the controller
 	}
 }
+
+// RequirePermission is an authorization middleware factory. The request is only
+// allowed through if the authenticated caller holds every listed permission.
+// It ASSUMES Authenticate() has already run and set claims in context.
+func (m *AuthMiddleware) RequirePermission(required ...domain.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("userPermissions")
+		if !exists {
+			log.Println("RequirePermission: Permissions not found in context. Authenticate middleware likely missing or failed.")
+			AbortWithProblem(c, http.StatusInternalServerError, CodeInternal, "Authentication context missing or invalid")
+			return
+		}
+
+		granted, ok := value.([]domain.Permission)
+		if !ok {
+			log.Printf("RequirePermission: Invalid permissions type in context: %T\n", value)
+			AbortWithProblem(c, http.StatusInternalServerError, CodeInternal, "Invalid permissions format")
+			return
+		}
+
+		for _, permission := range required {
+			if !slices.Contains(granted, permission) {
+				log.Printf("RequirePermission: User '%s' (ID: %s) lacks permission '%s'\n", c.GetString("username"), c.GetString("userID"), permission)
+				AbortWithProblem(c, http.StatusForbidden, CodeMissingPermission, "Access forbidden: missing permission "+string(permission))
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -25,9 +25,33 @@ func (m *MockJwtService) ParseToken(c context.Context, token string) (*domain.Cl
 	}
 	return nil, errors.New("ParseTokenFunc not implemented")
 }
-func (m *MockJwtService) GetSignedToken(c context.Context, user *domain.User) (string, error) {
+func (m *MockJwtService) GetSignedToken(c context.Context, user *domain.User, permissions []domain.Permission) (string, error) {
 	return "", errors.New("GetSignedToken not needed for this test")
 }
+func (m *MockJwtService) GetChallengeToken(c context.Context, user *domain.User, purpose domain.TokenPurpose) (string, error) {
+	return "", errors.New("GetChallengeToken not needed for this test")
+}
+
+// --- Mock APIKeyAuthenticator for testing ---
+type MockAPIKeyAuthenticator struct {
+	AuthenticateAPIKeyFunc func(c context.Context, rawKey string) (*domain.Claims, error)
+}
+
+func (m *MockAPIKeyAuthenticator) AuthenticateAPIKey(c context.Context, rawKey string) (*domain.Claims, error) {
+	return m.AuthenticateAPIKeyFunc(c, rawKey)
+}
+
+// --- Mock SessionValidator for testing ---
+type MockSessionValidator struct {
+	ValidateSessionFunc func(c context.Context, claims *domain.Claims) error
+}
+
+func (m *MockSessionValidator) ValidateSession(c context.Context, claims *domain.Claims) error {
+	if m.ValidateSessionFunc != nil {
+		return m.ValidateSessionFunc(c, claims)
+	}
+	return nil
+}
 
 //===========================================================================
 // AuthMiddleware Test Suite
@@ -36,6 +60,8 @@ func (m *MockJwtService) GetSignedToken(c context.Context, user *domain.User) (s
 type AuthMiddlewareSuite struct {
 	suite.Suite
 	mockJwtService *MockJwtService
+	mockAPIKeys    *MockAPIKeyAuthenticator
+	mockSessions   *MockSessionValidator
 	middleware     *infrastructure.AuthMiddleware
 }
 
@@ -50,7 +76,9 @@ func (s *AuthMiddlewareSuite) SetupTest() {
 	gin.SetMode(gin.TestMode)
 
 	s.mockJwtService = &MockJwtService{}
-	s.middleware = infrastructure.NewAuthMiddleware(s.mockJwtService)
+	s.mockAPIKeys = &MockAPIKeyAuthenticator{}
+	s.mockSessions = &MockSessionValidator{}
+	s.middleware = infrastructure.NewAuthMiddleware(s.mockJwtService, s.mockAPIKeys, s.mockSessions)
 }
 
 // Helper function to create a new router, serve a request, and return the recorder
@@ -65,15 +93,21 @@ func (s *AuthMiddlewareSuite) serveRequest(router *gin.Engine, req *http.Request
 func (s *AuthMiddlewareSuite) TestAuthenticate() {
 	// Create a new router for this test method
 	router := gin.New()
-	dummyHandler := func(c *gin.Context) { c.Status(http.StatusOK) }
+	var seenOrganization primitive.ObjectID
+	dummyHandler := func(c *gin.Context) {
+		seenOrganization, _ = domain.OrganizationFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	}
 	router.GET("/test-auth", s.middleware.Authenticate(), dummyHandler)
 
 	s.Run("Success", func() {
 		userID := primitive.NewObjectID()
+		organizationID := primitive.NewObjectID()
 		expectedClaims := &domain.Claims{
-			UserId:   userID.Hex(),
-			Username: "testuser",
-			Role:     domain.RoleUser,
+			UserId:         userID.Hex(),
+			Username:       "testuser",
+			Role:           domain.RoleUser,
+			OrganizationID: organizationID.Hex(),
 		}
 		s.mockJwtService.ParseTokenFunc = func(c context.Context, token string) (*domain.Claims, error) {
 			s.Equal("valid-token", token, "The correct token string should be passed to the service")
@@ -84,6 +118,34 @@ func (s *AuthMiddlewareSuite) TestAuthenticate() {
 
 		recorder := s.serveRequest(router, req)
 		s.Equal(http.StatusOK, recorder.Code, "Request should be allowed to proceed")
+		s.Equal(organizationID, seenOrganization, "Requests should be scoped to the token's organization")
+	})
+
+	s.Run("Failure - Token Without Organization", func() {
+		s.mockJwtService.ParseTokenFunc = func(c context.Context, token string) (*domain.Claims, error) {
+			return &domain.Claims{UserId: primitive.NewObjectID().Hex(), Username: "testuser", Role: domain.RoleUser}, nil
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/test-auth", nil)
+		req.Header.Set("Authorization", "Bearer old-token")
+		recorder := s.serveRequest(router, req)
+		s.Equal(http.StatusUnauthorized, recorder.Code)
+		s.Contains(recorder.Body.String(), "log in again")
+	})
+
+	s.Run("Failure - Revoked Token", func() {
+		s.mockJwtService.ParseTokenFunc = func(c context.Context, token string) (*domain.Claims, error) {
+			return &domain.Claims{UserId: primitive.NewObjectID().Hex(), Username: "removed", Role: domain.RoleUser, OrganizationID: primitive.NewObjectID().Hex()}, nil
+		}
+		s.mockSessions.ValidateSessionFunc = func(c context.Context, claims *domain.Claims) error {
+			s.Equal("removed", claims.Username)
+			return domain.ErrSessionRevoked
+		}
+		defer func() { s.mockSessions.ValidateSessionFunc = nil }()
+		req, _ := http.NewRequest(http.MethodGet, "/test-auth", nil)
+		req.Header.Set("Authorization", "Bearer removed-token")
+		recorder := s.serveRequest(router, req)
+		s.Equal(http.StatusUnauthorized, recorder.Code)
+		s.Contains(recorder.Body.String(), "revoked")
 	})
 
 	s.Run("Failure - No Authorization Header", func() {
@@ -110,6 +172,125 @@ func (s *AuthMiddlewareSuite) TestAuthenticate() {
 		s.Equal(http.StatusUnauthorized, recorder.Code)
 		s.Contains(recorder.Body.String(), "invalid signature")
 	})
+
+	s.Run("Failure - Challenge Token Rejected", func() {
+		s.mockJwtService.ParseTokenFunc = func(c context.Context, token string) (*domain.Claims, error) {
+			return &domain.Claims{UserId: primitive.NewObjectID().Hex(), Purpose: domain.PurposeTwoFactorLogin}, nil
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/test-auth", nil)
+		req.Header.Set("Authorization", "Bearer challenge-token")
+		recorder := s.serveRequest(router, req)
+		s.Equal(http.StatusUnauthorized, recorder.Code)
+	})
+}
+
+// TestTimeZone tests that the Time-Zone header wins over the zone in the token.
+func (s *AuthMiddlewareSuite) TestTimeZone() {
+	router := gin.New()
+	router.Use(infrastructure.TimeZone())
+	router.GET("/zone", s.middleware.Authenticate(), func(c *gin.Context) {
+		location, _ := domain.LocationFromContext(c.Request.Context())
+		c.String(http.StatusOK, location.String())
+	})
+	s.mockJwtService.ParseTokenFunc = func(c context.Context, token string) (*domain.Claims, error) {
+		return &domain.Claims{UserId: primitive.NewObjectID().Hex(), Role: domain.RoleUser, TimeZone: "Asia/Tokyo", OrganizationID: primitive.NewObjectID().Hex()}, nil
+	}
+
+	testCases := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantZone   string
+	}{
+		{name: "Profile Zone", wantStatus: http.StatusOK, wantZone: "Asia/Tokyo"},
+		{name: "Header Overrides Profile", header: "America/Chicago", wantStatus: http.StatusOK, wantZone: "America/Chicago"},
+		{name: "Unknown Zone", header: "Mars/Olympus_Mons", wantStatus: http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			req, _ := http.NewRequest(http.MethodGet, "/zone", nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			if tc.header != "" {
+				req.Header.Set(infrastructure.TimeZoneHeader, tc.header)
+			}
+			recorder := s.serveRequest(router, req)
+			s.Equal(tc.wantStatus, recorder.Code)
+			if tc.wantZone != "" {
+				s.Equal(tc.wantZone, recorder.Body.String())
+			} else {
+				s.Contains(recorder.Body.String(), string(infrastructure.CodeInvalidTimeZone))
+			}
+		})
+	}
+}
+
+// --- Tests for API key authentication ---
+
+func (s *AuthMiddlewareSuite) TestAuthenticate_APIKey() {
+	router := gin.New()
+	var seenRole any
+	var seenPermissions any
+	router.GET("/test-key", s.middleware.Authenticate(), func(c *gin.Context) {
+		seenRole, _ = c.Get("userRole")
+		seenPermissions, _ = c.Get("userPermissions")
+		c.Status(http.StatusOK)
+	})
+
+	s.Run("Success", func() {
+		s.mockAPIKeys.AuthenticateAPIKeyFunc = func(c context.Context, rawKey string) (*domain.Claims, error) {
+			s.Equal("tmk_valid", rawKey)
+			return &domain.Claims{UserId: primitive.NewObjectID().Hex(), Username: "ci-bot", Role: domain.RoleService, Permissions: []domain.Permission{domain.PermTasksRead}}, nil
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/test-key", nil)
+		req.Header.Set("X-API-Key", "tmk_valid")
+
+		recorder := s.serveRequest(router, req)
+		s.Equal(http.StatusOK, recorder.Code)
+		s.Equal(domain.RoleService, seenRole)
+		s.Equal([]domain.Permission{domain.PermTasksRead}, seenPermissions)
+	})
+
+	s.Run("Failure - Invalid Key", func() {
+		s.mockAPIKeys.AuthenticateAPIKeyFunc = func(c context.Context, rawKey string) (*domain.Claims, error) {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/test-key", nil)
+		req.Header.Set("X-API-Key", "tmk_revoked")
+
+		recorder := s.serveRequest(router, req)
+		s.Equal(http.StatusUnauthorized, recorder.Code)
+		s.Contains(recorder.Body.String(), "invalid API key")
+	})
+
+	s.Run("Not Accepted For Enrollment", func() {
+		enrollRouter := gin.New()
+		enrollRouter.GET("/enroll", s.middleware.AuthenticateForEnrollment(), func(c *gin.Context) { c.Status(http.StatusOK) })
+		req, _ := http.NewRequest(http.MethodGet, "/enroll", nil)
+		req.Header.Set("X-API-Key", "tmk_valid")
+
+		s.Equal(http.StatusUnauthorized, s.serveRequest(enrollRouter, req).Code, "Enrollment requires a bearer token")
+	})
+}
+
+// --- Tests for AuthenticateForEnrollment() middleware ---
+
+func (s *AuthMiddlewareSuite) TestAuthenticateForEnrollment() {
+	router := gin.New()
+	dummyHandler := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/test-enroll", s.middleware.AuthenticateForEnrollment(), dummyHandler)
+
+	serveWithPurpose := func(purpose domain.TokenPurpose) int {
+		s.mockJwtService.ParseTokenFunc = func(c context.Context, token string) (*domain.Claims, error) {
+			return &domain.Claims{UserId: primitive.NewObjectID().Hex(), Role: domain.RoleAdmin, Purpose: purpose, OrganizationID: primitive.NewObjectID().Hex()}, nil
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/test-enroll", nil)
+		req.Header.Set("Authorization", "Bearer some-token")
+		return s.serveRequest(router, req).Code
+	}
+
+	s.Equal(http.StatusOK, serveWithPurpose(""), "Regular access tokens should be accepted")
+	s.Equal(http.StatusOK, serveWithPurpose(domain.PurposeTwoFactorEnroll), "Enrollment challenge tokens should be accepted")
+	s.Equal(http.StatusUnauthorized, serveWithPurpose(domain.PurposeTwoFactorLogin), "Login challenge tokens should be rejected")
 }
 
 // --- Tests for AuthorizeAdmin() middleware ---
@@ -160,3 +341,46 @@ func (s *AuthMiddlewareSuite) TestAuthorizeAdmin() {
 		s.Contains(recorder.Body.String(), "Authentication context missing")
 	})
 }
+
+// --- Tests for RequirePermission() middleware ---
+
+func (s *AuthMiddlewareSuite) TestRequirePermission() {
+	dummyHandler := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	createContextSetter := func(permissions []domain.Permission) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			c.Set("userID", primitive.NewObjectID().Hex())
+			c.Set("username", "someone")
+			c.Set("userPermissions", permissions)
+			c.Next()
+		}
+	}
+
+	s.Run("Success - All Permissions Held", func() {
+		router := gin.New()
+		setter := createContextSetter([]domain.Permission{domain.PermTasksRead, domain.PermTasksUpdate})
+		router.GET("/perm", setter, s.middleware.RequirePermission(domain.PermTasksRead, domain.PermTasksUpdate), dummyHandler)
+
+		req, _ := http.NewRequest(http.MethodGet, "/perm", nil)
+		s.Equal(http.StatusOK, s.serveRequest(router, req).Code)
+	})
+
+	s.Run("Failure - Missing One Permission", func() {
+		router := gin.New()
+		setter := createContextSetter([]domain.Permission{domain.PermTasksUpdate})
+		router.GET("/perm", setter, s.middleware.RequirePermission(domain.PermTasksDelete), dummyHandler)
+
+		req, _ := http.NewRequest(http.MethodGet, "/perm", nil)
+		recorder := s.serveRequest(router, req)
+		s.Equal(http.StatusForbidden, recorder.Code)
+		s.Contains(recorder.Body.String(), "tasks:delete")
+	})
+
+	s.Run("Failure - Permissions not in context", func() {
+		router := gin.New()
+		router.GET("/perm", s.middleware.RequirePermission(domain.PermTasksRead), dummyHandler)
+
+		req, _ := http.NewRequest(http.MethodGet, "/perm", nil)
+		s.Equal(http.StatusInternalServerError, s.serveRequest(router, req).Code)
+	})
+}
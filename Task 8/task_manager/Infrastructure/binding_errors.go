@@ -0,0 +1,120 @@
+package infrastructure
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var registerFieldNames sync.Once
+
+// UseJSONFieldNames makes gin's validator report fields by their JSON names, which is
+// what clients send, instead of the Go struct field names. It must be called before
+// the first request is bound, because the validator caches the names per type.
+func UseJSONFieldNames() {
+	registerFieldNames.Do(func() {
+		validate, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			switch name {
+			case "-":
+				return ""
+			case "":
+				return field.Name
+			}
+			return name
+		})
+	})
+}
+
+// AbortWithBindingError reports a request body that gin could not bind. Failed
+// binding rules and values of the wrong JSON type are listed per field, in the same
+// shape as domain validation errors.
+func AbortWithBindingError(c *gin.Context, err error) {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.Is(err, domain.ErrValidationFailed):
+		// Raised by types that validate themselves while decoding, such as domain.TaskPatch.
+		AbortWithError(c, err)
+	case errors.As(err, &validationErrs):
+		violations := &domain.ValidationError{}
+		for _, fieldErr := range validationErrs {
+			field := fieldPath(fieldErr)
+			violations.Add(field, fieldErr.Tag(), bindingRuleMessage(field, fieldErr))
+		}
+		AbortWithFieldErrors(c, http.StatusBadRequest, CodeValidationFailed, violations.Error(), violations.Violations)
+	case errors.As(err, &typeErr):
+		field := typeErr.Field
+		if field == "" {
+			field = "body"
+		}
+		message := fmt.Sprintf("%s must be %s", field, jsonTypeName(typeErr.Type))
+		AbortWithFieldErrors(c, http.StatusBadRequest, CodeInvalidRequestBody, message,
+			[]domain.FieldViolation{{Field: field, Rule: "type", Message: message}})
+	case errors.Is(err, io.EOF):
+		AbortWithProblem(c, http.StatusBadRequest, CodeInvalidRequestBody, "request body is empty")
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		AbortWithProblem(c, http.StatusBadRequest, CodeInvalidRequestBody, "request body is not valid JSON")
+	default:
+		// For example a malformed date, which encoding/json reports without the field.
+		AbortWithProblem(c, http.StatusBadRequest, CodeInvalidRequestBody, err.Error())
+	}
+}
+
+// fieldPath drops the name of the request type from the namespace, leaving a path
+// such as "operations[1].action".
+func fieldPath(fieldErr validator.FieldError) string {
+	_, path, found := strings.Cut(fieldErr.Namespace(), ".")
+	if !found {
+		return fieldErr.Field()
+	}
+	return path
+}
+
+func bindingRuleMessage(field string, fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return field + " is required"
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, fieldErr.Param())
+	case "min":
+		return fmt.Sprintf("%s must be at least %s", field, fieldErr.Param())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s", field, fieldErr.Param())
+	default:
+		return fmt.Sprintf("%s failed the %q rule", field, fieldErr.Tag())
+	}
+}
+
+// jsonTypeName describes the JSON value expected for a Go type.
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,116 @@
+package infrastructure_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+//===========================================================================
+// Binding Error Test Suite
+//===========================================================================
+
+type bindingOperation struct {
+	Action string `json:"action" binding:"required,oneof=create delete"`
+}
+
+type bindingRequest struct {
+	Title      string             `json:"title" binding:"required"`
+	Priority   int                `json:"priority"`
+	Operations []bindingOperation `json:"operations" binding:"dive"`
+}
+
+type BindingErrorSuite struct {
+	suite.Suite
+	router *gin.Engine
+}
+
+func TestBindingErrorSuite(t *testing.T) {
+	suite.Run(t, new(BindingErrorSuite))
+}
+
+func (s *BindingErrorSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+	infrastructure.UseJSONFieldNames()
+	s.router = gin.New()
+	s.router.POST("/bind", func(c *gin.Context) {
+		var req bindingRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			infrastructure.AbortWithBindingError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+	s.router.POST("/patch", func(c *gin.Context) {
+		var patch domain.TaskPatch
+		if err := c.ShouldBindJSON(&patch); err != nil {
+			infrastructure.AbortWithBindingError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+}
+
+func (s *BindingErrorSuite) post(body string) (int, infrastructure.Problem) {
+	return s.postTo("/bind", body)
+}
+
+func (s *BindingErrorSuite) postTo(path, body string) (int, infrastructure.Problem) {
+	req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.router.ServeHTTP(recorder, req)
+
+	var problem infrastructure.Problem
+	if recorder.Code != http.StatusNoContent {
+		s.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &problem))
+	}
+	return recorder.Code, problem
+}
+
+func (s *BindingErrorSuite) TestRulesAreReportedPerField() {
+	status, problem := s.post(`{"operations": [{"action": "create"}, {"action": "archive"}, {}]}`)
+	s.Equal(http.StatusBadRequest, status)
+	s.Equal(infrastructure.CodeValidationFailed, problem.Code)
+	s.Equal([]domain.FieldViolation{
+		{Field: "title", Rule: "required", Message: "title is required"},
+		{Field: "operations[1].action", Rule: "oneof", Message: "operations[1].action must be one of: create delete"},
+		{Field: "operations[2].action", Rule: "required", Message: "operations[2].action is required"},
+	}, problem.Errors)
+}
+
+func (s *BindingErrorSuite) TestMalformedBodies() {
+	status, problem := s.post(`{"title": "ok", "priority": "high"}`)
+	s.Equal(http.StatusBadRequest, status)
+	s.Equal(infrastructure.CodeInvalidRequestBody, problem.Code)
+	s.Equal([]domain.FieldViolation{{Field: "priority", Rule: "type", Message: "priority must be a number"}}, problem.Errors)
+
+	_, problem = s.post(`{"title": `)
+	s.Equal("request body is not valid JSON", problem.Detail)
+	s.Empty(problem.Errors)
+
+	_, problem = s.post(``)
+	s.Equal("request body is empty", problem.Detail)
+
+	status, _ = s.post(`{"title": "ok"}`)
+	s.Equal(http.StatusNoContent, status)
+}
+
+func (s *BindingErrorSuite) TestSelfValidatingTypes() {
+	status, problem := s.postTo("/patch", `{"id": "1", "title": "ok"}`)
+	s.Equal(http.StatusBadRequest, status)
+	s.Equal(infrastructure.CodeValidationFailed, problem.Code)
+	s.Equal([]domain.FieldViolation{{Field: "id", Rule: domain.RuleNotPatchable, Message: "id cannot be changed with a patch"}}, problem.Errors)
+
+	status, problem = s.postTo("/patch", `{"tags": "a"}`)
+	s.Equal(http.StatusBadRequest, status)
+	s.Equal(infrastructure.CodeInvalidRequestBody, problem.Code)
+	s.Equal("tags must be an array", problem.Detail)
+}
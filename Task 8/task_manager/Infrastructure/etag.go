@@ -0,0 +1,47 @@
+package infrastructure
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JSONWithETag sends value as JSON with a strong ETag derived from the body. If the
+// request's If-None-Match already names that ETag, it sends 304 Not Modified without a
+// body instead, so that clients polling a resource only download it when it changed.
+//
+// Responses are marked private and must be revalidated, because they depend on who is
+// asking.
+func JSONWithETag(c *gin.Context, value any) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		AbortWithError(c, err)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// etagMatches implements the weak comparison If-None-Match calls for (RFC 9110,
+// section 13.1.2): the header is "*" or a list of tags, any of which may be weak.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package infrastructure_test
+
+import (
+	"A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+//===========================================================================
+// ETag Test Suite
+//===========================================================================
+
+type ETagSuite struct {
+	suite.Suite
+	router *gin.Engine
+	title  string
+}
+
+func TestETagSuite(t *testing.T) {
+	suite.Run(t, new(ETagSuite))
+}
+
+func (s *ETagSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.title = "Write report"
+	s.router = gin.New()
+	s.router.GET("/tasks/1", func(c *gin.Context) {
+		infrastructure.JSONWithETag(c, gin.H{"title": s.title})
+	})
+}
+
+func (s *ETagSuite) get(ifNoneMatch string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/1", nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	recorder := httptest.NewRecorder()
+	s.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func (s *ETagSuite) TestSendsBodyWithETag() {
+	recorder := s.get("")
+
+	s.Equal(http.StatusOK, recorder.Code)
+	s.JSONEq(`{"title":"Write report"}`, recorder.Body.String())
+	s.Regexp(`^"[A-Za-z0-9_-]+"$`, recorder.Header().Get("ETag"))
+	s.Equal("private, no-cache", recorder.Header().Get("Cache-Control"))
+	s.Equal(recorder.Header().Get("ETag"), s.get("").Header().Get("ETag"), "the same body has the same tag")
+}
+
+func (s *ETagSuite) TestIfNoneMatch() {
+	etag := s.get("").Header().Get("ETag")
+
+	testCases := []struct {
+		name        string
+		ifNoneMatch string
+		status      int
+	}{
+		{"Matching tag", etag, http.StatusNotModified},
+		{"Weak tag", "W/" + etag, http.StatusNotModified},
+		{"One of several tags", `"other", ` + etag, http.StatusNotModified},
+		{"Any tag", "*", http.StatusNotModified},
+		{"Other tag", `"other"`, http.StatusOK},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			recorder := s.get(tc.ifNoneMatch)
+			s.Equal(tc.status, recorder.Code)
+			s.Equal(etag, recorder.Header().Get("ETag"))
+			if tc.status == http.StatusNotModified {
+				s.Empty(recorder.Body.String())
+			}
+		})
+	}
+}
+
+func (s *ETagSuite) TestChangedResourceIsSentAgain() {
+	etag := s.get("").Header().Get("ETag")
+	s.title = "Write the report"
+
+	recorder := s.get(etag)
+
+	s.Equal(http.StatusOK, recorder.Code)
+	s.NotEqual(etag, recorder.Header().Get("ETag"))
+	s.Contains(recorder.Body.String(), "Write the report")
+}
@@ -0,0 +1,69 @@
+package infrastructure
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Ensure GridFSBlobStore implements the domain.BlobStore interface
+var _ domain.BlobStore = (*GridFSBlobStore)(nil)
+
+// GridFSBlobStore keeps blobs in a MongoDB GridFS bucket, using the key as the file ID.
+type GridFSBlobStore struct {
+	bucket *gridfs.Bucket
+}
+
+func NewGridFSBlobStore(db *mongo.Database, bucketName string) (*GridFSBlobStore, error) {
+	bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName(bucketName))
+	if err != nil {
+		return nil, fmt.Errorf("blob store: failed to open GridFS bucket %q: %w", bucketName, err)
+	}
+	return &GridFSBlobStore{bucket: bucket}, nil
+}
+
+func (s *GridFSBlobStore) Put(c context.Context, key string, content io.Reader) (int64, error) {
+	counter := &countingReader{reader: content}
+	if err := s.bucket.UploadFromStreamWithID(key, key, counter); err != nil {
+		return 0, fmt.Errorf("blob store: failed to upload blob %q: %w", key, err)
+	}
+	return counter.count, nil
+}
+
+func (s *GridFSBlobStore) Open(c context.Context, key string) (io.ReadCloser, error) {
+	stream, err := s.bucket.OpenDownloadStream(key)
+	if err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return nil, domain.ErrBlobNotFound
+		}
+		return nil, fmt.Errorf("blob store: failed to open blob %q: %w", key, err)
+	}
+	return stream, nil
+}
+
+func (s *GridFSBlobStore) Delete(c context.Context, key string) error {
+	if err := s.bucket.DeleteContext(c, key); err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return domain.ErrBlobNotFound
+		}
+		return fmt.Errorf("blob store: failed to delete blob %q: %w", key, err)
+	}
+	return nil
+}
+
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
@@ -0,0 +1,200 @@
+package infrastructure
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// gRPC metadata keys are the lower-case names of the matching HTTP headers.
+const (
+	grpcAuthorizationKey = "authorization"
+	grpcAPIKeyKey        = "x-api-key"
+	grpcTimeZoneKey      = "time-zone"
+)
+
+// GRPCAuthInterceptor authenticates gRPC calls the way AuthMiddleware authenticates
+// HTTP requests: with a Bearer JWT in the "authorization" metadata or a service
+// account key in "x-api-key". It also reads the caller's "time-zone".
+type GRPCAuthInterceptor struct {
+	jwtService  domain.JwtService
+	apiKeys     domain.APIKeyAuthenticator
+	sessions    domain.SessionValidator
+	public      map[string]bool
+	permissions map[string][]domain.Permission
+}
+
+// NewGRPCAuthInterceptor creates the interceptor. apiKeys may be nil, in which case
+// the x-api-key metadata is not accepted, and sessions may be nil, in which case tokens
+// are not checked against their user. Every method requires authentication unless it
+// is listed with AllowUnauthenticated.
+func NewGRPCAuthInterceptor(jwtService domain.JwtService, apiKeys domain.APIKeyAuthenticator, sessions domain.SessionValidator) *GRPCAuthInterceptor {
+	return &GRPCAuthInterceptor{
+		jwtService:  jwtService,
+		apiKeys:     apiKeys,
+		sessions:    sessions,
+		public:      make(map[string]bool),
+		permissions: make(map[string][]domain.Permission),
+	}
+}
+
+// AllowUnauthenticated lets calls to the given methods through without credentials.
+// Methods are full gRPC method names such as "/taskmanager.v1.AuthService/Login".
+func (i *GRPCAuthInterceptor) AllowUnauthenticated(methods ...string) {
+	for _, method := range methods {
+		i.public[method] = true
+	}
+}
+
+// RequirePermission only lets callers holding every listed permission call method.
+func (i *GRPCAuthInterceptor) RequirePermission(method string, required ...domain.Permission) {
+	i.permissions[method] = append(i.permissions[method], required...)
+}
+
+// Unary returns the interceptor for unary calls.
+func (i *GRPCAuthInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, _, err := i.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns the interceptor for streaming calls. Streams can outlive the
+// caller's session, so tokens are checked again before every message sent.
+func (i *GRPCAuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, session, err := i.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		stream := &contextStream{ServerStream: ss, ctx: ctx}
+		if session != nil {
+			stream.validate = func() error { return i.validateSession(ctx, session) }
+		}
+		return handler(srv, stream)
+	}
+}
+
+// contextStream replaces the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	// validate, if set, is called before every message sent and ends the stream
+	// when it fails.
+	validate func() error
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *contextStream) SendMsg(m any) error {
+	if s.validate != nil {
+		if err := s.validate(); err != nil {
+			return err
+		}
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// authenticate also returns the claims of a user token whose session must be
+// validated again on later messages, or nil.
+func (i *GRPCAuthInterceptor) authenticate(ctx context.Context, method string) (context.Context, *domain.Claims, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if name := firstMetadata(md, grpcTimeZoneKey); name != "" {
+		location, err := domain.LoadTimeZone(name)
+		if err != nil {
+			return nil, nil, grpcError(codes.InvalidArgument, CodeInvalidTimeZone,
+				grpcTimeZoneKey+" must be an IANA time zone such as Europe/Berlin")
+		}
+		ctx = domain.ContextWithLocation(ctx, location)
+	}
+	if i.public[method] {
+		return ctx, nil, nil
+	}
+
+	claims, session, err := i.claims(ctx, md)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, permission := range i.permissions[method] {
+		if !slices.Contains(claims.Permissions, permission) {
+			log.Printf("GRPCAuthInterceptor: User '%s' (ID: %s) lacks permission '%s' for %s\n", claims.Username, claims.UserId, permission, method)
+			return nil, nil, grpcError(codes.PermissionDenied, CodeMissingPermission, "missing permission "+string(permission))
+		}
+	}
+	ctx = contextWithClaims(ctx, claims)
+	if !session {
+		return ctx, nil, nil
+	}
+	return ctx, claims, nil
+}
+
+// claims authenticates the caller. session reports that the claims come from a user
+// token, which validateSession checks.
+func (i *GRPCAuthInterceptor) claims(ctx context.Context, md metadata.MD) (claims *domain.Claims, session bool, err error) {
+	if apiKey := firstMetadata(md, grpcAPIKeyKey); apiKey != "" && i.apiKeys != nil {
+		claims, err := i.apiKeys.AuthenticateAPIKey(ctx, apiKey)
+		if err != nil {
+			log.Printf("GRPCAuthInterceptor: API key authentication failed: %v\n", err)
+			return nil, false, grpcError(codes.Unauthenticated, CodeInvalidAPIKey, domain.ErrInvalidAPIKey.Error())
+		}
+		return claims, false, nil
+	}
+
+	token, ok := strings.CutPrefix(firstMetadata(md, grpcAuthorizationKey), "Bearer ")
+	if !ok || token == "" {
+		return nil, false, grpcError(codes.Unauthenticated, CodeAuthenticationRequired, "Authorization token required")
+	}
+	claims, err = i.jwtService.ParseToken(ctx, token)
+	if err != nil {
+		log.Printf("GRPCAuthInterceptor: Token parsing/validation failed: %v\n", err)
+		return nil, false, grpcError(codes.Unauthenticated, CodeInvalidToken, err.Error())
+	}
+	// No gRPC method completes a two-factor login, so challenge tokens are never valid.
+	if claims.Purpose != "" {
+		log.Printf("GRPCAuthInterceptor: Rejected %q challenge token for user '%s'\n", claims.Purpose, claims.Username)
+		return nil, false, grpcError(codes.Unauthenticated, CodeInvalidToken, "invalid token")
+	}
+	if _, ok := claimedOrganization(claims); !ok {
+		log.Printf("GRPCAuthInterceptor: Rejected token without an organization for user '%s'\n", claims.Username)
+		return nil, false, grpcError(codes.Unauthenticated, CodeInvalidToken, errTokenWithoutOrganization)
+	}
+	if err := i.validateSession(ctx, claims); err != nil {
+		return nil, false, err
+	}
+	return claims, true, nil
+}
+
+// validateSession checks that the token's user still exists, if sessions are checked.
+func (i *GRPCAuthInterceptor) validateSession(ctx context.Context, claims *domain.Claims) error {
+	if i.sessions == nil {
+		return nil
+	}
+	if err := i.sessions.ValidateSession(ctx, claims); err != nil {
+		if errors.Is(err, domain.ErrSessionRevoked) {
+			log.Printf("GRPCAuthInterceptor: Rejected revoked token for user '%s'\n", claims.Username)
+			return grpcError(codes.Unauthenticated, CodeInvalidToken, errRevokedToken)
+		}
+		log.Printf("GRPCAuthInterceptor: Session validation failed: %v\n", err)
+		return grpcError(codes.Internal, CodeInternal, "failed to validate session")
+	}
+	return nil
+}
+
+func firstMetadata(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+package infrastructure
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// grpcErrorDomain is the domain of the ErrorInfo details attached to gRPC errors.
+const grpcErrorDomain = "task-manager"
+
+// GRPCUnaryErrors converts the errors returned by unary handlers with GRPCStatus.
+func GRPCUnaryErrors() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, GRPCStatus(info.FullMethod, err)
+		}
+		return resp, nil
+	}
+}
+
+// GRPCStreamErrors converts the errors returned by streaming handlers with GRPCStatus.
+func GRPCStreamErrors() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return GRPCStatus(info.FullMethod, err)
+		}
+		return nil
+	}
+}
+
+// GRPCStatus turns an error returned by a use case into a gRPC status error, the way
+// AbortWithError turns it into a problem. The error code travels as the reason of an
+// ErrorInfo detail and invalid fields as a BadRequest detail. Errors that already
+// carry a status are returned unchanged; errors without a mapping are logged and
+// reported as Internal, so their text never reaches the client.
+func GRPCStatus(method string, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	if errors.Is(err, domain.ErrWatchLagged) {
+		return grpcError(codes.Aborted, CodeWatchLagged, err.Error())
+	}
+
+	httpStatus, code, detail, ok := ClassifyError(err)
+	if !ok {
+		log.Printf("grpc %s: %v\n", method, err)
+		return grpcError(codes.Internal, CodeInternal, "An unexpected error occurred")
+	}
+	st := status.New(grpcCode(httpStatus), detail)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: string(code), Domain: grpcErrorDomain}}
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		badRequest := &errdetails.BadRequest{}
+		for _, violation := range validationErr.Violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       violation.Field,
+				Description: violation.Message,
+			})
+		}
+		details = append(details, badRequest)
+	}
+	return withDetails(st, details...).Err()
+}
+
+// grpcError creates a status error carrying code as its ErrorInfo reason.
+func grpcError(grpcCode codes.Code, code ErrorCode, detail string) error {
+	st := status.New(grpcCode, detail)
+	return withDetails(st, &errdetails.ErrorInfo{Reason: string(code), Domain: grpcErrorDomain}).Err()
+}
+
+// withDetails attaches details to st. The details are well-formed messages, so this
+// only fails if they cannot be marshaled, in which case st is returned without them.
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) *status.Status {
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		log.Printf("grpc: failed to attach error details: %v\n", err)
+		return st
+	}
+	return withDetails
+}
+
+// grpcCode picks the gRPC code closest to an HTTP status.
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusUnsupportedMediaType, http.StatusRequestEntityTooLarge:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	default:
+		return codes.Unknown
+	}
+}
@@ -0,0 +1,117 @@
+package infrastructure_test
+
+import (
+	"A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCStatus(t *testing.T) {
+	testCases := []struct {
+		name       string
+		err        error
+		wantCode   codes.Code
+		wantReason infrastructure.ErrorCode
+		wantDetail string
+	}{
+		{
+			name:       "Not Found",
+			err:        fmt.Errorf("usecase: failed to get task: %w", domain.ErrTaskNotFound),
+			wantCode:   codes.NotFound,
+			wantReason: infrastructure.CodeTaskNotFound,
+			wantDetail: "task not found",
+		},
+		{
+			name:       "Conflict",
+			err:        fmt.Errorf("%w: finish the blockers first", domain.ErrTaskBlocked),
+			wantCode:   codes.FailedPrecondition,
+			wantReason: infrastructure.CodeTaskBlocked,
+			wantDetail: "finish the blockers first",
+		},
+		{
+			name:       "Forbidden",
+			err:        domain.ErrForbidden,
+			wantCode:   codes.PermissionDenied,
+			wantReason: infrastructure.CodeForbidden,
+		},
+		{
+			name:       "Too Many Attempts",
+			err:        domain.ErrTooManyTwoFactorAttempts,
+			wantCode:   codes.ResourceExhausted,
+			wantReason: infrastructure.CodeTooManyTwoFactorAttempts,
+		},
+		{
+			name:       "Watch Lagged",
+			err:        domain.ErrWatchLagged,
+			wantCode:   codes.Aborted,
+			wantReason: infrastructure.CodeWatchLagged,
+		},
+		{
+			name:       "Internal Error Text Is Hidden",
+			err:        errors.New("repository: connection refused"),
+			wantCode:   codes.Internal,
+			wantReason: infrastructure.CodeInternal,
+			wantDetail: "An unexpected error occurred",
+		},
+		{
+			name:     "Canceled",
+			err:      fmt.Errorf("usecase: %w", context.Canceled),
+			wantCode: codes.Canceled,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			st := status.Convert(infrastructure.GRPCStatus("/test/Method", tc.err))
+			assert.Equal(t, tc.wantCode, st.Code())
+			if tc.wantDetail != "" {
+				assert.Equal(t, tc.wantDetail, st.Message())
+			}
+			if tc.wantReason != "" {
+				info, ok := grpcErrorInfo(st)
+				if assert.True(t, ok, "an ErrorInfo detail should be attached") {
+					assert.Equal(t, string(tc.wantReason), info.Reason)
+				}
+			}
+		})
+	}
+
+	t.Run("Validation Errors List The Fields", func(t *testing.T) {
+		err := &domain.ValidationError{Violations: []domain.FieldViolation{
+			{Field: "title", Rule: domain.RuleRequired, Message: "title is required"},
+			{Field: "status", Rule: domain.RuleOneOf, Message: "status must be one of Pending, In Progress, Done"},
+		}}
+		st := status.Convert(infrastructure.GRPCStatus("/test/Method", err))
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		var fields []string
+		for _, detail := range st.Details() {
+			if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+				for _, violation := range badRequest.FieldViolations {
+					fields = append(fields, violation.Field)
+				}
+			}
+		}
+		assert.Equal(t, []string{"title", "status"}, fields)
+	})
+
+	t.Run("Status Errors Pass Through", func(t *testing.T) {
+		err := status.Error(codes.Unauthenticated, "Authorization token required")
+		assert.Equal(t, err, infrastructure.GRPCStatus("/test/Method", err))
+	})
+}
+
+func grpcErrorInfo(st *status.Status) (*errdetails.ErrorInfo, bool) {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,133 @@
+package infrastructure
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Ensure ICalRenderer implements the domain.CalendarRenderer interface
+var _ domain.CalendarRenderer = (*ICalRenderer)(nil)
+
+const (
+	icalDateTime = "20060102T150405Z"
+	icalDate     = "20060102"
+	// icalLineLimit is the maximum line length in octets, excluding the line break (RFC 5545, 3.1).
+	icalLineLimit = 75
+)
+
+// ICalRenderer renders tasks as RFC 5545 calendars. Tasks become all-day events on
+// their due date, or to-dos with a DUE time and a STATUS.
+type ICalRenderer struct {
+	productID string
+	uidDomain string
+	now       func() time.Time
+}
+
+// NewICalRenderer creates a renderer. uidDomain makes the item UIDs globally unique,
+// as RFC 5545 requires.
+func NewICalRenderer(productID, uidDomain string) *ICalRenderer {
+	return NewICalRendererWithClock(productID, uidDomain, time.Now)
+}
+
+// NewICalRendererWithClock is like NewICalRenderer but takes the clock used for DTSTAMP.
+func NewICalRendererWithClock(productID, uidDomain string, now func() time.Time) *ICalRenderer {
+	return &ICalRenderer{productID: productID, uidDomain: uidDomain, now: now}
+}
+
+func (r *ICalRenderer) Render(c context.Context, w io.Writer, name string, tasks []*domain.Task, kind domain.CalendarItemKind) error {
+	out := &icalWriter{w: bufio.NewWriter(w)}
+	stamp := r.now().UTC().Format(icalDateTime)
+
+	out.line("BEGIN:VCALENDAR")
+	out.line("VERSION:2.0")
+	out.line("PRODID:" + escapeICalText(r.productID))
+	out.line("CALSCALE:GREGORIAN")
+	out.line("METHOD:PUBLISH")
+	out.line("X-WR-CALNAME:" + escapeICalText(name))
+	for _, task := range tasks {
+		if kind == domain.CalendarTodos {
+			out.line("BEGIN:VTODO")
+		} else {
+			out.line("BEGIN:VEVENT")
+		}
+		out.line("UID:" + task.Id.Hex() + "@" + r.uidDomain)
+		out.line("DTSTAMP:" + stamp)
+		out.line("SUMMARY:" + escapeICalText(task.Title))
+		if task.Description != "" {
+			out.line("DESCRIPTION:" + escapeICalText(task.Description))
+		}
+		due := task.DueDate.UTC()
+		if kind == domain.CalendarTodos {
+			out.line("DUE:" + due.Format(icalDateTime))
+			out.line("STATUS:" + todoStatus(task.Status))
+			if task.Status == domain.Done {
+				out.line("PERCENT-COMPLETE:100")
+			}
+			out.line("END:VTODO")
+		} else {
+			out.line("DTSTART;VALUE=DATE:" + due.Format(icalDate))
+			out.line("DTEND;VALUE=DATE:" + due.AddDate(0, 0, 1).Format(icalDate))
+			out.line("TRANSP:TRANSPARENT")
+			out.line("CATEGORIES:" + escapeICalText(string(task.Status)))
+			out.line("END:VEVENT")
+		}
+	}
+	out.line("END:VCALENDAR")
+
+	if out.err != nil {
+		return out.err
+	}
+	return out.w.Flush()
+}
+
+func todoStatus(status domain.TaskStatus) string {
+	switch status {
+	case domain.InProgress:
+		return "IN-PROCESS"
+	case domain.Done:
+		return "COMPLETED"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
+
+// escapeICalText escapes a TEXT value (RFC 5545, 3.3.11).
+func escapeICalText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(value)
+}
+
+// icalWriter writes content lines with CRLF endings, folding long lines without
+// splitting multi-byte characters. It keeps the first error and ignores later writes.
+type icalWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (iw *icalWriter) line(content string) {
+	if iw.err != nil {
+		return
+	}
+	limit := icalLineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		iw.write(content[:cut] + "\r\n ")
+		content = content[cut:]
+		// Continuation lines start with a space, which counts towards the limit.
+		limit = icalLineLimit - 1
+	}
+	iw.write(content + "\r\n")
+}
+
+func (iw *icalWriter) write(s string) {
+	if iw.err == nil {
+		_, iw.err = iw.w.WriteString(s)
+	}
+}
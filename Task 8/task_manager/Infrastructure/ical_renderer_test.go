@@ -0,0 +1,77 @@
+package infrastructure_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//===========================================================================
+// ICalRenderer Test Suite
+//===========================================================================
+
+type ICalRendererSuite struct {
+	suite.Suite
+	renderer *infrastructure.ICalRenderer
+	task     *domain.Task
+}
+
+func TestICalRendererSuite(t *testing.T) {
+	suite.Run(t, new(ICalRendererSuite))
+}
+
+func (s *ICalRendererSuite) SetupTest() {
+	now := time.Date(2025, 3, 1, 8, 30, 0, 0, time.UTC)
+	s.renderer = infrastructure.NewICalRendererWithClock("-//Task Manager//EN", "tasks.example.com", func() time.Time { return now })
+	s.task = &domain.Task{
+		Id:          primitive.NewObjectID(),
+		Title:       "Ship v2; finally, for real",
+		Description: "line one\nline two",
+		DueDate:     time.Date(2025, 3, 14, 17, 0, 0, 0, time.UTC),
+		Status:      domain.InProgress,
+	}
+}
+
+func (s *ICalRendererSuite) render(kind domain.CalendarItemKind, tasks ...*domain.Task) string {
+	var out bytes.Buffer
+	s.Require().NoError(s.renderer.Render(context.Background(), &out, "alice's tasks", tasks, kind))
+	return out.String()
+}
+
+func (s *ICalRendererSuite) TestEvents() {
+	output := s.render(domain.CalendarEvents, s.task)
+	s.True(strings.HasPrefix(output, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	s.True(strings.HasSuffix(output, "END:VCALENDAR\r\n"))
+	s.Contains(output, "BEGIN:VEVENT\r\nUID:"+s.task.Id.Hex()+"@tasks.example.com\r\nDTSTAMP:20250301T083000Z\r\n")
+	s.Contains(output, `SUMMARY:Ship v2\; finally\, for real`+"\r\n")
+	s.Contains(output, `DESCRIPTION:line one\nline two`+"\r\n")
+	s.Contains(output, "DTSTART;VALUE=DATE:20250314\r\nDTEND;VALUE=DATE:20250315\r\n")
+	s.Contains(output, "CATEGORIES:In progress\r\n")
+	s.NotContains(output, "VTODO")
+}
+
+func (s *ICalRendererSuite) TestTodos() {
+	done := &domain.Task{Id: primitive.NewObjectID(), Title: "Finished", DueDate: s.task.DueDate, Status: domain.Done}
+	output := s.render(domain.CalendarTodos, s.task, done)
+	s.Equal(2, strings.Count(output, "BEGIN:VTODO\r\n"))
+	s.Contains(output, "DUE:20250314T170000Z\r\nSTATUS:IN-PROCESS\r\n")
+	s.Contains(output, "STATUS:COMPLETED\r\nPERCENT-COMPLETE:100\r\n")
+	s.NotContains(output, "VEVENT")
+}
+
+func (s *ICalRendererSuite) TestLongLinesAreFolded() {
+	s.task.Title = strings.Repeat("é", 100)
+	output := s.render(domain.CalendarEvents, s.task)
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n") {
+		s.LessOrEqual(len(line), 75)
+	}
+	unfolded := strings.ReplaceAll(output, "\r\n ", "")
+	s.Contains(unfolded, "SUMMARY:"+s.task.Title+"\r\n")
+}
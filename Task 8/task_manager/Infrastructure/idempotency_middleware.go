@@ -0,0 +1,231 @@
+package infrastructure
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader lets clients retry a POST without repeating its effect.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses that were replayed from a record.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodyBytes matches the largest body any POST route accepts.
+	maxIdempotentBodyBytes = 32 << 20
+	// idempotencyPollInterval is how often a duplicate checks whether the original
+	// request has finished.
+	idempotencyPollInterval = 100 * time.Millisecond
+)
+
+// IdempotencyMiddleware stores the response to POST requests that carry an
+// Idempotency-Key header and replays it for retries with the same key and payload.
+// Keys are scoped to the authenticated user, so it must run after Authenticate.
+type IdempotencyMiddleware struct {
+	store domain.IdempotencyStore
+	// ttl is how long completed responses are replayed.
+	ttl time.Duration
+	// lockTimeout bounds how long an unfinished request holds its key after the server
+	// stops extending it, for example because it crashed before releasing the key.
+	lockTimeout time.Duration
+	// wait is how long a duplicate waits for the original request before giving up
+	// with 409 Conflict.
+	wait time.Duration
+}
+
+func NewIdempotencyMiddleware(store domain.IdempotencyStore, ttl time.Duration) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		store:       store,
+		ttl:         ttl,
+		lockTimeout: time.Minute,
+		wait:        2 * time.Second,
+	}
+}
+
+// SetWait changes how long duplicates of an unfinished request wait for it.
+func (m *IdempotencyMiddleware) SetWait(wait time.Duration) {
+	m.wait = wait
+}
+
+// SetLockTimeout changes how long a reservation lasts without being extended.
+func (m *IdempotencyMiddleware) SetLockTimeout(lockTimeout time.Duration) {
+	m.lockTimeout = lockTimeout
+}
+
+// Handle returns the middleware. A nil *IdempotencyMiddleware passes every request
+// through, which lets routes be set up without idempotency support.
+func (m *IdempotencyMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		userID := c.GetString("userID")
+		if m == nil || key == "" || c.Request.Method != http.MethodPost || userID == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			AbortWithProblem(c, http.StatusBadRequest, CodeBadRequest,
+				IdempotencyKeyHeader+" must be at most "+strconv.Itoa(maxIdempotencyKeyLength)+" characters")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				AbortWithProblem(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "request body is too large")
+				return
+			}
+			AbortWithProblem(c, http.StatusBadRequest, CodeInvalidRequestBody, "failed to read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Request IDs are random, so a new one identifies the reservation as well.
+		record := &domain.IdempotencyRecord{
+			Key:         userID + ":" + key,
+			RequestHash: requestFingerprint(c.Request, body),
+			Owner:       newRequestID(),
+			ExpiresAt:   time.Now().Add(m.lockTimeout),
+		}
+		existing, err := m.reserve(c.Request.Context(), record)
+		switch {
+		case err != nil:
+			AbortWithInternalError(c, err)
+		case existing == nil:
+			m.process(c, record)
+		case existing.RequestHash != record.RequestHash:
+			AbortWithProblem(c, http.StatusUnprocessableEntity, CodeIdempotencyKeyReused,
+				"this "+IdempotencyKeyHeader+" was already used for a different request")
+		case !existing.Completed:
+			c.Header("Retry-After", "1")
+			AbortWithProblem(c, http.StatusConflict, CodeIdempotencyInProgress,
+				"a request with this "+IdempotencyKeyHeader+" is still being processed")
+		default:
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+			c.Abort()
+		}
+	}
+}
+
+// reserve tries to take the key, waiting for an unfinished request with the same
+// payload to complete or give the key up.
+func (m *IdempotencyMiddleware) reserve(c context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	deadline := time.Now().Add(m.wait)
+	for {
+		existing, err := m.store.Reserve(c, record)
+		if err != nil || existing == nil || existing.Completed || existing.RequestHash != record.RequestHash {
+			return existing, err
+		}
+		if time.Now().Add(idempotencyPollInterval).After(deadline) {
+			return existing, nil
+		}
+		select {
+		case <-c.Done():
+			return nil, c.Err()
+		case <-time.After(idempotencyPollInterval):
+		}
+	}
+}
+
+// process runs the handlers while holding the key and stores their response. Server
+// errors and panics release the key instead, since a retry may succeed.
+func (m *IdempotencyMiddleware) process(c *gin.Context, record *domain.IdempotencyRecord) {
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+
+	stored := false
+	stopExtending := m.extend(context.WithoutCancel(c.Request.Context()), *record)
+	defer func() {
+		stopExtending()
+		if stored {
+			return
+		}
+		// The request context may already be canceled, but the key must still be freed.
+		if err := m.store.Release(context.WithoutCancel(c.Request.Context()), record); err != nil {
+			log.Printf("IdempotencyMiddleware: failed to release key: %v", err)
+		}
+	}()
+
+	c.Next()
+
+	if recorder.Status() >= http.StatusInternalServerError {
+		return
+	}
+	record.Completed = true
+	record.StatusCode = recorder.Status()
+	record.ContentType = recorder.Header().Get("Content-Type")
+	record.Body = recorder.body.Bytes()
+	record.ExpiresAt = time.Now().Add(m.ttl)
+	if err := m.store.Complete(context.WithoutCancel(c.Request.Context()), record); err != nil {
+		log.Printf("IdempotencyMiddleware: failed to store response: %v", err)
+		return
+	}
+	stored = true
+}
+
+// extend keeps the reservation of a request whose handlers run longer than lockTimeout,
+// so that a duplicate cannot take the key over while it is still being processed. The
+// returned function stops it.
+func (m *IdempotencyMiddleware) extend(c context.Context, record domain.IdempotencyRecord) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(m.lockTimeout / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				record.ExpiresAt = time.Now().Add(m.lockTimeout)
+				if err := m.store.Extend(c, &record); err != nil {
+					log.Printf("IdempotencyMiddleware: failed to extend key reservation: %v", err)
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+// requestFingerprint identifies the payload of a request, so that a key reused for a
+// different request can be told apart from a retry.
+func requestFingerprint(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder copies everything written to the response.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,252 @@
+package infrastructure_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+// memoryIdempotencyStore is a domain.IdempotencyStore kept in a map.
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]domain.IdempotencyRecord
+}
+
+func (m *memoryIdempotencyStore) Reserve(c context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.records[record.Key]; ok && existing.ExpiresAt.After(time.Now()) {
+		return &existing, nil
+	}
+	m.records[record.Key] = *record
+	return nil, nil
+}
+
+// held reports whether record.Owner holds the unfinished record for its key.
+func (m *memoryIdempotencyStore) held(record *domain.IdempotencyRecord) bool {
+	existing, ok := m.records[record.Key]
+	return ok && !existing.Completed && existing.Owner == record.Owner
+}
+
+func (m *memoryIdempotencyStore) Extend(c context.Context, record *domain.IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.held(record) {
+		return domain.ErrIdempotencyKeyNotHeld
+	}
+	existing := m.records[record.Key]
+	existing.ExpiresAt = record.ExpiresAt
+	m.records[record.Key] = existing
+	return nil
+}
+
+func (m *memoryIdempotencyStore) Complete(c context.Context, record *domain.IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.held(record) {
+		return domain.ErrIdempotencyKeyNotHeld
+	}
+	m.records[record.Key] = *record
+	return nil
+}
+
+func (m *memoryIdempotencyStore) Release(c context.Context, record *domain.IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.held(record) {
+		delete(m.records, record.Key)
+	}
+	return nil
+}
+
+//===========================================================================
+// Idempotency Middleware Test Suite
+//===========================================================================
+
+type IdempotencyMiddlewareSuite struct {
+	suite.Suite
+	store      *memoryIdempotencyStore
+	middleware *infrastructure.IdempotencyMiddleware
+	router     *gin.Engine
+	calls      atomic.Int32
+	// release, when set, blocks the handler until it is closed.
+	release chan struct{}
+}
+
+func TestIdempotencyMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyMiddlewareSuite))
+}
+
+func (s *IdempotencyMiddlewareSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.store = &memoryIdempotencyStore{records: make(map[string]domain.IdempotencyRecord)}
+	s.middleware = infrastructure.NewIdempotencyMiddleware(s.store, time.Hour)
+	s.calls.Store(0)
+	s.release = nil
+
+	s.router = gin.New()
+	s.router.Use(infrastructure.RequestID(), infrastructure.RecoverWithProblem())
+	authenticate := func(c *gin.Context) {
+		c.Set("userID", c.GetHeader("X-Test-User"))
+		c.Next()
+	}
+	s.router.POST("/tasks", authenticate, s.middleware.Handle(), func(c *gin.Context) {
+		call := s.calls.Add(1)
+		if s.release != nil {
+			<-s.release
+		}
+		var body map[string]any
+		if err := c.ShouldBindJSON(&body); err != nil {
+			infrastructure.AbortWithBindingError(c, err)
+			return
+		}
+		if body["fail"] == true {
+			infrastructure.AbortWithInternalError(c, io.ErrUnexpectedEOF)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"call": call, "title": body["title"]})
+	})
+}
+
+func (s *IdempotencyMiddlewareSuite) post(user, key, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Test-User", user)
+	if key != "" {
+		req.Header.Set(infrastructure.IdempotencyKeyHeader, key)
+	}
+	recorder := httptest.NewRecorder()
+	s.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func (s *IdempotencyMiddlewareSuite) TestRetriesAreReplayed() {
+	first := s.post("alice", "key-1", `{"title": "Buy milk"}`)
+	s.Require().Equal(http.StatusCreated, first.Code)
+	s.Empty(first.Header().Get(infrastructure.IdempotentReplayedHeader))
+
+	retry := s.post("alice", "key-1", `{"title": "Buy milk"}`)
+	s.Equal(http.StatusCreated, retry.Code)
+	s.Equal("true", retry.Header().Get(infrastructure.IdempotentReplayedHeader))
+	s.Equal(first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+	s.JSONEq(first.Body.String(), retry.Body.String())
+	s.Equal(int32(1), s.calls.Load())
+
+	// Keys are scoped per user, and requests without a key are never deduplicated.
+	s.Equal(http.StatusCreated, s.post("bob", "key-1", `{"title": "Buy milk"}`).Code)
+	s.Equal(http.StatusCreated, s.post("alice", "", `{"title": "Buy milk"}`).Code)
+	s.Equal(int32(3), s.calls.Load())
+}
+
+func (s *IdempotencyMiddlewareSuite) TestReusedKeyWithDifferentPayload() {
+	s.Require().Equal(http.StatusCreated, s.post("alice", "key-1", `{"title": "Buy milk"}`).Code)
+
+	recorder := s.post("alice", "key-1", `{"title": "Buy bread"}`)
+	s.Equal(http.StatusUnprocessableEntity, recorder.Code)
+	var problem infrastructure.Problem
+	s.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &problem))
+	s.Equal(infrastructure.CodeIdempotencyKeyReused, problem.Code)
+	s.Equal(int32(1), s.calls.Load())
+}
+
+func (s *IdempotencyMiddlewareSuite) TestClientErrorsAreReplayedButServerErrorsAreNot() {
+	s.Equal(http.StatusBadRequest, s.post("alice", "bad", `{"title": `).Code)
+	s.Equal(http.StatusBadRequest, s.post("alice", "bad", `{"title": `).Code)
+	s.Equal(int32(1), s.calls.Load())
+
+	s.Equal(http.StatusInternalServerError, s.post("alice", "fail", `{"fail": true}`).Code)
+	s.Equal(http.StatusInternalServerError, s.post("alice", "fail", `{"fail": true}`).Code)
+	s.Equal(int32(3), s.calls.Load(), "a failed request must not be replayed")
+}
+
+func (s *IdempotencyMiddlewareSuite) TestConcurrentDuplicates() {
+	s.Run("Wait For The Original", func() {
+		s.release = make(chan struct{})
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- s.post("alice", "slow", `{"title": "Slow"}`) }()
+		s.Eventually(func() bool { return s.calls.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+		duplicate := make(chan *httptest.ResponseRecorder)
+		go func() { duplicate <- s.post("alice", "slow", `{"title": "Slow"}`) }()
+		time.Sleep(150 * time.Millisecond)
+		close(s.release)
+
+		s.Equal(http.StatusCreated, (<-done).Code)
+		replayed := <-duplicate
+		s.Equal(http.StatusCreated, replayed.Code)
+		s.Equal("true", replayed.Header().Get(infrastructure.IdempotentReplayedHeader))
+		s.Equal(int32(1), s.calls.Load())
+	})
+
+	s.Run("Give Up With Conflict", func() {
+		s.middleware.SetWait(0)
+		s.release = make(chan struct{})
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- s.post("alice", "stuck", `{"title": "Stuck"}`) }()
+		s.Eventually(func() bool { return s.calls.Load() == 2 }, time.Second, 10*time.Millisecond)
+
+		recorder := s.post("alice", "stuck", `{"title": "Stuck"}`)
+		close(s.release)
+		<-done
+		s.Equal(http.StatusConflict, recorder.Code)
+		s.Equal("1", recorder.Header().Get("Retry-After"))
+		s.Contains(recorder.Body.String(), string(infrastructure.CodeIdempotencyInProgress))
+	})
+}
+
+func (s *IdempotencyMiddlewareSuite) TestSlowRequestsKeepTheirKey() {
+	s.middleware.SetLockTimeout(100 * time.Millisecond)
+	s.middleware.SetWait(0)
+	s.release = make(chan struct{})
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- s.post("alice", "slow", `{"title": "Slow"}`) }()
+	s.Eventually(func() bool { return s.calls.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	// Well past the lock timeout, the reservation is still held.
+	time.Sleep(300 * time.Millisecond)
+	duplicate := make(chan int, 1)
+	go func() { duplicate <- s.post("alice", "slow", `{"title": "Slow"}`).Code }()
+	select {
+	case code := <-duplicate:
+		s.Equal(http.StatusConflict, code)
+	case <-time.After(time.Second):
+		s.Fail("the duplicate took the key over")
+	}
+	close(s.release)
+	s.Equal(http.StatusCreated, (<-done).Code)
+
+	s.Run("Only The Owner Changes The Record", func() {
+		record := &domain.IdempotencyRecord{Key: "alice:other", RequestHash: "hash", Owner: "first", ExpiresAt: time.Now().Add(time.Minute)}
+		_, err := s.store.Reserve(context.Background(), record)
+		s.Require().NoError(err)
+
+		stale := *record
+		stale.Owner = "second"
+		s.ErrorIs(s.store.Complete(context.Background(), &stale), domain.ErrIdempotencyKeyNotHeld)
+		s.Require().NoError(s.store.Release(context.Background(), &stale))
+		s.Contains(s.store.records, record.Key, "a stale request must not free the key")
+	})
+}
+
+func (s *IdempotencyMiddlewareSuite) TestNilMiddlewarePassesThrough() {
+	var middleware *infrastructure.IdempotencyMiddleware
+	router := gin.New()
+	router.POST("/tasks", middleware.Handle(), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	req, _ := http.NewRequest(http.MethodPost, "/tasks", nil)
+	req.Header.Set(infrastructure.IdempotencyKeyHeader, "key-1")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	s.Equal(http.StatusNoContent, recorder.Code)
+}
@@ -21,18 +21,42 @@ func NewJwtService(secretKey string) *MyJwtService {
 	return &MyJwtService{secretKey: secretKey}
 }
 
-func (s *MyJwtService) GetSignedToken(c context.Context, user *domain.User) (string, error) {
+const (
+	accessTokenTTL    = time.Hour * 24
+	challengeTokenTTL = time.Minute * 5
+)
+
+func (s *MyJwtService) GetSignedToken(c context.Context, user *domain.User, permissions []domain.Permission) (string, error) {
+	return s.sign(user, permissions, "", accessTokenTTL)
+}
+
+// GetChallengeToken issues a short-lived token carrying a purpose, used between the
+// password step and the second factor of a login.
+func (s *MyJwtService) GetChallengeToken(c context.Context, user *domain.User, purpose domain.TokenPurpose) (string, error) {
+	if purpose == "" {
+		return "", errors.New("jwt service: challenge token requires a purpose")
+	}
+	return s.sign(user, nil, purpose, challengeTokenTTL)
+}
+
+func (s *MyJwtService) sign(user *domain.User, permissions []domain.Permission, purpose domain.TokenPurpose, ttl time.Duration) (string, error) {
 	// Prepare claims
 	claims := domain.Claims{
-		UserId:   user.Id.Hex(),
-		Role:     user.Role,
-		Username: user.Username,
+		UserId:      user.Id.Hex(),
+		Role:        user.Role,
+		Username:    user.Username,
+		Permissions: permissions,
+		Purpose:     purpose,
+		TimeZone:    user.TimeZone,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 24).Unix(),
+			ExpiresAt: time.Now().Add(ttl).Unix(),
 			IssuedAt:  time.Now().Unix(),
 			Issuer:    "task-manager-app",
 		},
 	}
+	if !user.OrganizationID.IsZero() {
+		claims.OrganizationID = user.OrganizationID.Hex()
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	signedToken, err := token.SignedString([]byte(s.secretKey))
@@ -39,13 +39,15 @@ func (s *JwtServiceSuite) SetupTest() {
 func (s *JwtServiceSuite) TestGetAndParseToken_Success() {
 	// --- Setup ---
 	user := &domain.User{
-		Id:       primitive.NewObjectID(),
-		Username: "testuser",
-		Role:     domain.RoleUser,
+		Id:             primitive.NewObjectID(),
+		Username:       "testuser",
+		Role:           domain.RoleUser,
+		OrganizationID: primitive.NewObjectID(),
 	}
 
 	// --- Execution: Generate Token ---
-	tokenString, err := s.jwtService.GetSignedToken(context.Background(), user)
+	permissions := []domain.Permission{domain.PermTasksRead, domain.PermTasksUpdate}
+	tokenString, err := s.jwtService.GetSignedToken(context.Background(), user, permissions)
 
 	// --- Assertion: Generation ---
 	s.Require().NoError(err, "Token generation should not fail")
@@ -61,6 +63,8 @@ func (s *JwtServiceSuite) TestGetAndParseToken_Success() {
 	s.Equal(user.Id.Hex(), claims.UserId, "User ID in claims should match original")
 	s.Equal(user.Username, claims.Username, "Username in claims should match original")
 	s.Equal(user.Role, claims.Role, "Role in claims should match original")
+	s.Equal(permissions, claims.Permissions, "Permissions in claims should match original")
+	s.Equal(user.OrganizationID.Hex(), claims.OrganizationID, "Organization in claims should match original")
 	s.Greater(claims.ExpiresAt, time.Now().Unix(), "Token should expire in the future")
 }
 
@@ -71,7 +75,7 @@ func (s *JwtServiceSuite) TestParseToken_Failure() {
 		// Create a token with a DIFFERENT secret key
 		otherService := infrastructure.NewJwtService(s.differentSecretKey)
 		user := &domain.User{Id: primitive.NewObjectID(), Username: "user"}
-		tokenString, err := otherService.GetSignedToken(context.Background(), user)
+		tokenString, err := otherService.GetSignedToken(context.Background(), user, nil)
 		s.Require().NoError(err)
 
 		// --- Execution & Assertion ---
@@ -125,3 +129,34 @@ func (s *JwtServiceSuite) TestParseToken_Failure() {
 		s.Equal("invalid token", err.Error())
 	})
 }
+
+// TestGetChallengeToken tests that challenge tokens carry their purpose and a short expiry.
+func (s *JwtServiceSuite) TestGetChallengeToken() {
+	user := &domain.User{Id: primitive.NewObjectID(), Username: "challenged", Role: domain.RoleAdmin}
+
+	s.Run("Success", func() {
+		tokenString, err := s.jwtService.GetChallengeToken(context.Background(), user, domain.PurposeTwoFactorLogin)
+		s.Require().NoError(err)
+
+		claims, err := s.jwtService.ParseToken(context.Background(), tokenString)
+		s.Require().NoError(err)
+		s.Equal(domain.PurposeTwoFactorLogin, claims.Purpose)
+		s.Empty(claims.Permissions, "Challenge tokens must not carry permissions")
+		s.Equal(user.Id.Hex(), claims.UserId)
+		s.LessOrEqual(claims.ExpiresAt, time.Now().Add(10*time.Minute).Unix(), "Challenge tokens should be short-lived")
+	})
+
+	s.Run("Failure - Missing Purpose", func() {
+		_, err := s.jwtService.GetChallengeToken(context.Background(), user, "")
+		s.Require().Error(err)
+	})
+
+	s.Run("Access Tokens Have No Purpose", func() {
+		tokenString, err := s.jwtService.GetSignedToken(context.Background(), user, nil)
+		s.Require().NoError(err)
+
+		claims, err := s.jwtService.ParseToken(context.Background(), tokenString)
+		s.Require().NoError(err)
+		s.Empty(claims.Purpose)
+	})
+}
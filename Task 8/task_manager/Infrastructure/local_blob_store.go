@@ -0,0 +1,91 @@
+package infrastructure
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Ensure LocalBlobStore implements the domain.BlobStore interface
+var _ domain.BlobStore = (*LocalBlobStore)(nil)
+
+// LocalBlobStore keeps blobs as files in a directory on the local filesystem.
+type LocalBlobStore struct {
+	root string
+}
+
+func NewLocalBlobStore(root string) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("blob store: failed to create directory %q: %w", root, err)
+	}
+	return &LocalBlobStore{root: root}, nil
+}
+
+// Put writes the content to a temporary file first so that readers never see a
+// partially written blob.
+func (s *LocalBlobStore) Put(c context.Context, key string, content io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	tmp, err := os.CreateTemp(s.root, ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("blob store: failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	written, err := io.Copy(tmp, content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("blob store: failed to write blob %q: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("blob store: failed to store blob %q: %w", key, err)
+	}
+	return written, nil
+}
+
+func (s *LocalBlobStore) Open(c context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, domain.ErrBlobNotFound
+		}
+		return nil, fmt.Errorf("blob store: failed to open blob %q: %w", key, err)
+	}
+	return file, nil
+}
+
+func (s *LocalBlobStore) Delete(c context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return domain.ErrBlobNotFound
+		}
+		return fmt.Errorf("blob store: failed to delete blob %q: %w", key, err)
+	}
+	return nil
+}
+
+// path maps a key to a file inside the root, rejecting keys that could escape it.
+func (s *LocalBlobStore) path(key string) (string, error) {
+	if key == "" || key != filepath.Base(key) || strings.HasPrefix(key, ".") {
+		return "", fmt.Errorf("blob store: invalid key %q", key)
+	}
+	return filepath.Join(s.root, key), nil
+}
@@ -0,0 +1,60 @@
+package infrastructure_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+//===========================================================================
+// LocalBlobStore Test Suite
+//===========================================================================
+
+type LocalBlobStoreSuite struct {
+	suite.Suite
+	store *infrastructure.LocalBlobStore
+	ctx   context.Context
+}
+
+// TestLocalBlobStoreSuite is the entry point for the test suite
+func TestLocalBlobStoreSuite(t *testing.T) {
+	suite.Run(t, new(LocalBlobStoreSuite))
+}
+
+// SetupTest gives every test its own empty directory
+func (s *LocalBlobStoreSuite) SetupTest() {
+	store, err := infrastructure.NewLocalBlobStore(s.T().TempDir())
+	s.Require().NoError(err)
+	s.store = store
+	s.ctx = context.Background()
+}
+
+func (s *LocalBlobStoreSuite) TestPutOpenDelete() {
+	written, err := s.store.Put(s.ctx, "blob-1", strings.NewReader("hello"))
+	s.Require().NoError(err)
+	s.Equal(int64(5), written)
+
+	reader, err := s.store.Open(s.ctx, "blob-1")
+	s.Require().NoError(err)
+	content, err := io.ReadAll(reader)
+	reader.Close()
+	s.Require().NoError(err)
+	s.Equal("hello", string(content))
+
+	s.Require().NoError(s.store.Delete(s.ctx, "blob-1"))
+	_, err = s.store.Open(s.ctx, "blob-1")
+	s.ErrorIs(err, domain.ErrBlobNotFound)
+	s.ErrorIs(s.store.Delete(s.ctx, "blob-1"), domain.ErrBlobNotFound)
+}
+
+func (s *LocalBlobStoreSuite) TestRejectsUnsafeKeys() {
+	for _, key := range []string{"", "../escape", "nested/key", ".hidden"} {
+		_, err := s.store.Put(s.ctx, key, strings.NewReader("x"))
+		s.Error(err, "key %q should be rejected", key)
+	}
+}
@@ -0,0 +1,42 @@
+package infrastructure
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OperatorTokenHeader carries the operator token of the deployment.
+const OperatorTokenHeader = "X-Operator-Token"
+
+// OperatorMiddleware admits the operators of the whole deployment, who hold its
+// operator token. Administrators of an organization are not operators: users:manage
+// only reaches their own organization.
+type OperatorMiddleware struct {
+	token string
+}
+
+// NewOperatorMiddleware creates the middleware. An empty token turns operator routes
+// off, since no request can present it.
+func NewOperatorMiddleware(token string) *OperatorMiddleware {
+	return &OperatorMiddleware{token: token}
+}
+
+// RequireOperator rejects requests without the operator token.
+func (m *OperatorMiddleware) RequireOperator() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(OperatorTokenHeader)
+		if token == "" {
+			AbortWithProblem(c, http.StatusUnauthorized, CodeAuthenticationRequired, OperatorTokenHeader+" required")
+			return
+		}
+		if m.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(m.token)) != 1 {
+			log.Println("OperatorMiddleware: Rejected an invalid operator token")
+			AbortWithProblem(c, http.StatusForbidden, CodeForbidden, "invalid operator token")
+			return
+		}
+		c.Next()
+	}
+}
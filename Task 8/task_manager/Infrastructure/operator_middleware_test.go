@@ -0,0 +1,49 @@
+package infrastructure_test
+
+import (
+	"A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+//===========================================================================
+// OperatorMiddleware Test Suite
+//===========================================================================
+
+type OperatorMiddlewareSuite struct {
+	suite.Suite
+}
+
+func TestOperatorMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(OperatorMiddlewareSuite))
+}
+
+func (s *OperatorMiddlewareSuite) request(middleware *infrastructure.OperatorMiddleware, token string) int {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/operator", middleware.RequireOperator(), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	req, _ := http.NewRequest(http.MethodGet, "/operator", nil)
+	if token != "" {
+		req.Header.Set(infrastructure.OperatorTokenHeader, token)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder.Code
+}
+
+func (s *OperatorMiddlewareSuite) TestRequireOperator() {
+	middleware := infrastructure.NewOperatorMiddleware("operator-secret")
+	s.Equal(http.StatusNoContent, s.request(middleware, "operator-secret"))
+	s.Equal(http.StatusUnauthorized, s.request(middleware, ""))
+	s.Equal(http.StatusForbidden, s.request(middleware, "guess"))
+
+	s.Run("Without A Token Nobody Is An Operator", func() {
+		disabled := infrastructure.NewOperatorMiddleware("")
+		s.Equal(http.StatusUnauthorized, s.request(disabled, ""))
+		s.Equal(http.StatusForbidden, s.request(disabled, "anything"))
+	})
+}
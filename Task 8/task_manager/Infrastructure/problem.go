@@ -0,0 +1,259 @@
+package infrastructure
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of error responses (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// ErrorCode identifies a kind of error. Codes are part of the API: clients switch on
+// them, so existing codes must never change meaning.
+type ErrorCode string
+
+const (
+	CodeBadRequest         ErrorCode = "bad_request"
+	CodeInvalidRequestBody ErrorCode = "invalid_request_body"
+	CodeValidationFailed   ErrorCode = "validation_failed"
+	CodePayloadTooLarge    ErrorCode = "payload_too_large"
+	CodeRouteNotFound      ErrorCode = "route_not_found"
+	CodeInternal           ErrorCode = "internal_error"
+	CodeNotImplemented     ErrorCode = "not_implemented"
+	CodeInvalidTimeZone    ErrorCode = "invalid_time_zone"
+
+	CodeAuthenticationRequired ErrorCode = "authentication_required"
+	CodeInvalidToken           ErrorCode = "invalid_token"
+	CodeInvalidAPIKey          ErrorCode = "invalid_api_key"
+	CodeInvalidCredentials     ErrorCode = "invalid_credentials"
+	CodeForbidden              ErrorCode = "forbidden"
+	CodeMissingPermission      ErrorCode = "missing_permission"
+
+	CodeInvalidTwoFactorCode       ErrorCode = "invalid_two_factor_code"
+	CodeTwoFactorRequired          ErrorCode = "two_factor_required"
+	CodeTwoFactorAlreadyEnabled    ErrorCode = "two_factor_already_enabled"
+	CodeTwoFactorNotEnabled        ErrorCode = "two_factor_not_enabled"
+	CodeTwoFactorEnrollmentMissing ErrorCode = "two_factor_enrollment_missing"
+	CodeTooManyTwoFactorAttempts   ErrorCode = "too_many_two_factor_attempts"
+
+	CodeUserNotFound           ErrorCode = "user_not_found"
+	CodeUsernameTaken          ErrorCode = "username_taken"
+	CodeOrganizationNotFound   ErrorCode = "organization_not_found"
+	CodeOrganizationSlugTaken  ErrorCode = "organization_slug_taken"
+	CodeInvitationNotFound     ErrorCode = "invitation_not_found"
+	CodeInvalidInvitation      ErrorCode = "invalid_invitation"
+	CodeRoleNotFound           ErrorCode = "role_not_found"
+	CodeServiceAccountNotFound ErrorCode = "service_account_not_found"
+	CodeAPIKeyNotFound         ErrorCode = "api_key_not_found"
+	CodeTaskNotFound           ErrorCode = "task_not_found"
+	CodeTaskBlocked            ErrorCode = "task_blocked"
+	CodeDependencyCycle        ErrorCode = "dependency_cycle"
+	CodeProjectNotFound        ErrorCode = "project_not_found"
+	CodeProjectMemberNotFound  ErrorCode = "project_member_not_found"
+	CodeAttachmentNotFound     ErrorCode = "attachment_not_found"
+	CodeAttachmentTooLarge     ErrorCode = "attachment_too_large"
+	CodeUnsupportedContentType ErrorCode = "unsupported_content_type"
+	CodeRevisionNotFound       ErrorCode = "revision_not_found"
+	CodeFeedNotFound           ErrorCode = "calendar_feed_not_found"
+	CodeNotificationNotFound   ErrorCode = "notification_not_found"
+	CodeTransactionsDisabled   ErrorCode = "transactions_unsupported"
+	CodeBulkRolledBack         ErrorCode = "bulk_rolled_back"
+	CodeImportUnreadable       ErrorCode = "import_unreadable"
+	CodeInvalidPatch           ErrorCode = "invalid_patch"
+	CodePatchTestFailed        ErrorCode = "patch_test_failed"
+
+	CodeIdempotencyKeyReused  ErrorCode = "idempotency_key_reused"
+	CodeIdempotencyInProgress ErrorCode = "idempotency_request_in_progress"
+
+	CodeWatchLagged ErrorCode = "watch_lagged"
+
+	CodeQueryTooDeep    ErrorCode = "query_too_deep"
+	CodeQueryTooComplex ErrorCode = "query_too_complex"
+)
+
+// Problem is an RFC 7807 problem details object. Code and RequestID are extension
+// members; Errors lists the offending fields of invalid requests.
+type Problem struct {
+	Type      string                  `json:"type"`
+	Title     string                  `json:"title"`
+	Status    int                     `json:"status"`
+	Detail    string                  `json:"detail,omitempty"`
+	Instance  string                  `json:"instance,omitempty"`
+	Code      ErrorCode               `json:"code"`
+	RequestID string                  `json:"request_id,omitempty"`
+	Errors    []domain.FieldViolation `json:"errors,omitempty"`
+}
+
+// problemTypePrefix makes the type URI of a problem from its code.
+const problemTypePrefix = "urn:task-manager:problem:"
+
+// errorMappings translates domain errors into responses. Errors are matched with
+// errors.Is in order, so errors that may wrap others (such as ErrValidationFailed)
+// come last.
+var errorMappings = []struct {
+	err    error
+	status int
+	code   ErrorCode
+}{
+	{domain.ErrInvalidCredentials, http.StatusUnauthorized, CodeInvalidCredentials},
+	{domain.ErrInvalidAPIKey, http.StatusUnauthorized, CodeInvalidAPIKey},
+	{domain.ErrInvalidTwoFactorCode, http.StatusUnauthorized, CodeInvalidTwoFactorCode},
+	{domain.ErrTwoFactorRequired, http.StatusForbidden, CodeTwoFactorRequired},
+	{domain.ErrTwoFactorAlreadyEnabled, http.StatusConflict, CodeTwoFactorAlreadyEnabled},
+	{domain.ErrTwoFactorNotEnabled, http.StatusConflict, CodeTwoFactorNotEnabled},
+	{domain.ErrTwoFactorEnrollmentMissing, http.StatusConflict, CodeTwoFactorEnrollmentMissing},
+	{domain.ErrTooManyTwoFactorAttempts, http.StatusTooManyRequests, CodeTooManyTwoFactorAttempts},
+
+	{domain.ErrUserNotFound, http.StatusNotFound, CodeUserNotFound},
+	{domain.ErrUsernameTaken, http.StatusConflict, CodeUsernameTaken},
+	{domain.ErrOrganizationNotFound, http.StatusNotFound, CodeOrganizationNotFound},
+	{domain.ErrOrganizationSlugTaken, http.StatusConflict, CodeOrganizationSlugTaken},
+	{domain.ErrInvitationNotFound, http.StatusNotFound, CodeInvitationNotFound},
+	{domain.ErrInvalidInvitation, http.StatusBadRequest, CodeInvalidInvitation},
+	{domain.ErrRoleNotFound, http.StatusNotFound, CodeRoleNotFound},
+	{domain.ErrServiceAccountNotFound, http.StatusNotFound, CodeServiceAccountNotFound},
+	{domain.ErrAPIKeyNotFound, http.StatusNotFound, CodeAPIKeyNotFound},
+	{domain.ErrProjectNotFound, http.StatusNotFound, CodeProjectNotFound},
+	{domain.ErrProjectMemberNotFound, http.StatusNotFound, CodeProjectMemberNotFound},
+	{domain.ErrTaskNotFound, http.StatusNotFound, CodeTaskNotFound},
+	{domain.ErrTaskBlocked, http.StatusConflict, CodeTaskBlocked},
+	{domain.ErrDependencyCycle, http.StatusConflict, CodeDependencyCycle},
+	{domain.ErrAttachmentNotFound, http.StatusNotFound, CodeAttachmentNotFound},
+	{domain.ErrAttachmentTooLarge, http.StatusRequestEntityTooLarge, CodeAttachmentTooLarge},
+	{domain.ErrUnsupportedContentType, http.StatusUnsupportedMediaType, CodeUnsupportedContentType},
+	{domain.ErrRevisionNotFound, http.StatusNotFound, CodeRevisionNotFound},
+	{domain.ErrInvalidFeedToken, http.StatusNotFound, CodeFeedNotFound},
+	{domain.ErrNotificationNotFound, http.StatusNotFound, CodeNotificationNotFound},
+	{domain.ErrTransactionsUnsupported, http.StatusNotImplemented, CodeTransactionsDisabled},
+	{domain.ErrInvalidPatch, http.StatusUnprocessableEntity, CodeInvalidPatch},
+	{domain.ErrPatchTestFailed, http.StatusConflict, CodePatchTestFailed},
+
+	{domain.ErrForbidden, http.StatusForbidden, CodeForbidden},
+	{domain.ErrValidationFailed, http.StatusBadRequest, CodeValidationFailed},
+}
+
+// AbortWithError responds with the problem matching err. A *domain.ValidationError
+// lists its violations in Errors, and JSON values of the wrong type are reported as
+// binding errors. Errors without a mapping are logged and reported as internal errors,
+// so their text never reaches the client.
+func AbortWithError(c *gin.Context, err error) {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		AbortWithFieldErrors(c, http.StatusBadRequest, CodeValidationFailed, validationErr.Error(), validationErr.Violations)
+		return
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		AbortWithBindingError(c, typeErr)
+		return
+	}
+	if status, code, detail, ok := ClassifyError(err); ok {
+		AbortWithProblem(c, status, code, detail)
+		return
+	}
+	AbortWithInternalError(c, err)
+}
+
+// ClassifyError returns the HTTP status, code and client-safe detail of a domain
+// error. ok is false for errors without a mapping, which are internal errors. Other
+// transports, such as the gRPC server, use it to report errors like the REST API does.
+func ClassifyError(err error) (status int, code ErrorCode, detail string, ok bool) {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusBadRequest, CodeValidationFailed, validationErr.Error(), true
+	}
+	for _, mapping := range errorMappings {
+		if errors.Is(err, mapping.err) {
+			return mapping.status, mapping.code, publicDetail(err, mapping.err), true
+		}
+	}
+	return 0, "", "", false
+}
+
+// ClassifyRequestError is ClassifyError for errors reported as part of a larger
+// response. Errors without a mapping are logged and described as internal errors.
+func ClassifyRequestError(c *gin.Context, err error) (status int, code ErrorCode, detail string) {
+	if status, code, detail, ok := ClassifyError(err); ok {
+		return status, code, detail
+	}
+	log.Printf("request %s: %v\n", RequestIDFromContext(c), err)
+	return http.StatusInternalServerError, CodeInternal, internalErrorDetail
+}
+
+// internalErrorDetail is all clients learn about an internal error.
+const internalErrorDetail = "An unexpected error occurred"
+
+// AbortWithInternalError logs err and responds with a generic 500 problem. The request
+// ID in the response links it to the log line.
+func AbortWithInternalError(c *gin.Context, err error) {
+	log.Printf("request %s: %v\n", RequestIDFromContext(c), err)
+	AbortWithProblem(c, http.StatusInternalServerError, CodeInternal, internalErrorDetail)
+}
+
+// AbortWithProblem responds with a problem of the given status and code.
+func AbortWithProblem(c *gin.Context, status int, code ErrorCode, detail string) {
+	AbortWithFieldErrors(c, status, code, detail, nil)
+}
+
+// AbortWithFieldErrors is like AbortWithProblem but also lists the invalid fields.
+func AbortWithFieldErrors(c *gin.Context, status int, code ErrorCode, detail string, fields []domain.FieldViolation) {
+	problem := NewProblem(c, status, code, detail)
+	problem.Errors = fields
+	AbortWithProblemBody(c, status, problem)
+}
+
+// NewProblem fills in a problem for the current request. Handlers that need extension
+// members embed it in their own type and send that with AbortWithProblemBody.
+func NewProblem(c *gin.Context, status int, code ErrorCode, detail string) Problem {
+	return Problem{
+		Type:      problemTypePrefix + string(code),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		Code:      code,
+		RequestID: RequestIDFromContext(c),
+	}
+}
+
+// AbortWithProblemBody sends body, a Problem or a type embedding one, as problem+json.
+func AbortWithProblemBody(c *gin.Context, status int, body any) {
+	// gin keeps a Content-Type that is already set instead of its JSON default.
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(status, body)
+}
+
+// NoRouteHandler reports unknown routes as problems instead of gin's plain-text 404.
+func NoRouteHandler(c *gin.Context) {
+	AbortWithProblem(c, http.StatusNotFound, CodeRouteNotFound, "no route matches "+c.Request.Method+" "+c.Request.URL.Path)
+}
+
+// RecoverWithProblem turns panics into internal error problems.
+func RecoverWithProblem() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		log.Printf("request %s: panic: %v\n", RequestIDFromContext(c), recovered)
+		AbortWithProblem(c, http.StatusInternalServerError, CodeInternal, internalErrorDetail)
+	})
+}
+
+// publicDetail strips the wrapping added on the way up, such as "usecase: failed to
+// ...", by keeping only what follows the matched sentinel. Errors that add nothing
+// after the sentinel are described by the sentinel alone.
+func publicDetail(err, sentinel error) string {
+	message := err.Error()
+	marker := sentinel.Error()
+	index := strings.Index(message, marker)
+	if index < 0 {
+		return marker
+	}
+	if rest, ok := strings.CutPrefix(message[index+len(marker):], ": "); ok && rest != "" {
+		return rest
+	}
+	return marker
+}
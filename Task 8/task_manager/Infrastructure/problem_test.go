@@ -0,0 +1,161 @@
+package infrastructure_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+//===========================================================================
+// Problem Details Test Suite
+//===========================================================================
+
+type ProblemSuite struct {
+	suite.Suite
+}
+
+func TestProblemSuite(t *testing.T) {
+	suite.Run(t, new(ProblemSuite))
+}
+
+func (s *ProblemSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serve runs handler behind the request ID middleware and decodes the problem it sends.
+func (s *ProblemSuite) serve(handler gin.HandlerFunc, header http.Header) (*httptest.ResponseRecorder, infrastructure.Problem) {
+	router := gin.New()
+	router.Use(infrastructure.RequestID(), infrastructure.RecoverWithProblem())
+	router.NoRoute(infrastructure.NoRouteHandler)
+	router.GET("/tasks/:id", handler)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/42", nil)
+	for name, values := range header {
+		req.Header[http.CanonicalHeaderKey(name)] = values
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	var problem infrastructure.Problem
+	s.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &problem))
+	return recorder, problem
+}
+
+func (s *ProblemSuite) TestDomainErrors() {
+	testCases := []struct {
+		name   string
+		err    error
+		status int
+		code   infrastructure.ErrorCode
+		detail string
+	}{
+		{"Not Found", fmt.Errorf("usecase: failed to get task: %w", domain.ErrTaskNotFound), http.StatusNotFound, infrastructure.CodeTaskNotFound, "task not found"},
+		{"Validation", fmt.Errorf("%w: invalid task ID format", domain.ErrValidationFailed), http.StatusBadRequest, infrastructure.CodeValidationFailed, "invalid task ID format"},
+		{"Wrapped Twice", fmt.Errorf("usecase: failed to revert: %w", fmt.Errorf("%w: missing permission tasks:update", domain.ErrForbidden)), http.StatusForbidden, infrastructure.CodeForbidden, "missing permission tasks:update"},
+		{"Specific Before Generic", fmt.Errorf("%w: %w", domain.ErrValidationFailed, domain.ErrTaskBlocked), http.StatusConflict, infrastructure.CodeTaskBlocked, "task is blocked by unfinished tasks"},
+		{"Username Taken", domain.ErrUsernameTaken, http.StatusConflict, infrastructure.CodeUsernameTaken, "username already taken"},
+		{"Invalid Credentials", domain.ErrInvalidCredentials, http.StatusUnauthorized, infrastructure.CodeInvalidCredentials, "invalid credentials"},
+		{"Invalid Patch", fmt.Errorf("%w: operation 0 (remove /x): path does not exist", domain.ErrInvalidPatch), http.StatusUnprocessableEntity, infrastructure.CodeInvalidPatch, "operation 0 (remove /x): path does not exist"},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			recorder, problem := s.serve(func(c *gin.Context) { infrastructure.AbortWithError(c, tc.err) }, nil)
+			s.Equal(tc.status, recorder.Code)
+			s.Equal(infrastructure.ProblemContentType, recorder.Header().Get("Content-Type"))
+			s.Equal(tc.status, problem.Status)
+			s.Equal(tc.code, problem.Code)
+			s.Equal("urn:task-manager:problem:"+string(tc.code), problem.Type)
+			s.Equal(http.StatusText(tc.status), problem.Title)
+			s.Equal(tc.detail, problem.Detail)
+			s.Equal("/tasks/42", problem.Instance)
+		})
+	}
+}
+
+func (s *ProblemSuite) TestValidationErrorsListFields() {
+	violations := &domain.ValidationError{}
+	violations.Add("title", domain.RuleRequired, "task title cannot be empty")
+	violations.Add("duedate", domain.RuleNotPast, "task due date cannot be in the past")
+
+	recorder, problem := s.serve(func(c *gin.Context) {
+		infrastructure.AbortWithError(c, fmt.Errorf("usecase: failed to create new user: %w", violations))
+	}, nil)
+	s.Equal(http.StatusBadRequest, recorder.Code)
+	s.Equal(infrastructure.CodeValidationFailed, problem.Code)
+	s.Equal("task title cannot be empty; task due date cannot be in the past", problem.Detail)
+	s.Equal(violations.Violations, problem.Errors)
+}
+
+func (s *ProblemSuite) TestUnknownErrorsAreHidden() {
+	recorder, problem := s.serve(func(c *gin.Context) {
+		infrastructure.AbortWithError(c, errors.New("repository: connection refused to 10.0.0.3"))
+	}, nil)
+	s.Equal(http.StatusInternalServerError, recorder.Code)
+	s.Equal(infrastructure.CodeInternal, problem.Code)
+	s.NotContains(recorder.Body.String(), "10.0.0.3")
+}
+
+func (s *ProblemSuite) TestClassifyRequestError() {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodPost, "/tasks/bulk", nil)
+
+	status, code, detail := infrastructure.ClassifyRequestError(c, fmt.Errorf("usecase: failed to update: %w", domain.ErrTaskNotFound))
+	s.Equal(http.StatusNotFound, status)
+	s.Equal(infrastructure.CodeTaskNotFound, code)
+	s.NotContains(detail, "usecase")
+
+	status, code, detail = infrastructure.ClassifyRequestError(c, errors.New("repository: connection refused to 10.0.0.3"))
+	s.Equal(http.StatusInternalServerError, status)
+	s.Equal(infrastructure.CodeInternal, code)
+	s.NotContains(detail, "10.0.0.3")
+}
+
+func (s *ProblemSuite) TestPanicsAndUnknownRoutes() {
+	recorder, problem := s.serve(func(c *gin.Context) { panic("boom") }, nil)
+	s.Equal(http.StatusInternalServerError, recorder.Code)
+	s.Equal(infrastructure.CodeInternal, problem.Code)
+	s.NotEmpty(problem.RequestID)
+
+	router := gin.New()
+	router.NoRoute(infrastructure.NoRouteHandler)
+	req, _ := http.NewRequest(http.MethodGet, "/nowhere", nil)
+	notFound := httptest.NewRecorder()
+	router.ServeHTTP(notFound, req)
+	s.Equal(http.StatusNotFound, notFound.Code)
+	s.Contains(notFound.Body.String(), `"code":"route_not_found"`)
+}
+
+func (s *ProblemSuite) TestRequestID() {
+	s.Run("Generated", func() {
+		recorder, problem := s.serve(func(c *gin.Context) { infrastructure.AbortWithError(c, domain.ErrTaskNotFound) }, nil)
+		id := recorder.Header().Get(infrastructure.RequestIDHeader)
+		s.Len(id, 32)
+		s.Equal(id, problem.RequestID)
+	})
+
+	s.Run("Propagated From The Client", func() {
+		header := http.Header{infrastructure.RequestIDHeader: {"trace-1234"}}
+		recorder, problem := s.serve(func(c *gin.Context) {
+			// Use cases only see the request context.
+			s.Equal("trace-1234", infrastructure.RequestIDFromContext(c.Request.Context()))
+			infrastructure.AbortWithError(c, domain.ErrTaskNotFound)
+		}, header)
+		s.Equal("trace-1234", recorder.Header().Get(infrastructure.RequestIDHeader))
+		s.Equal("trace-1234", problem.RequestID)
+	})
+
+	s.Run("Malformed Client IDs Are Replaced", func() {
+		header := http.Header{infrastructure.RequestIDHeader: {"bad id\nwith newline" + strings.Repeat("x", 200)}}
+		recorder, _ := s.serve(func(c *gin.Context) { infrastructure.AbortWithError(c, domain.ErrTaskNotFound) }, header)
+		s.Len(recorder.Header().Get(infrastructure.RequestIDHeader), 32)
+	})
+}
@@ -0,0 +1,66 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds IDs supplied by clients, which end up in logs.
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestID assigns every request an ID, reusing the client's X-Request-ID when it is
+// well formed so that calls can be traced across services. The ID is echoed in the
+// response header, stored in the request context and included in error responses.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set("requestID", id)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestIDKey{}, id))
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// RequestIDFromContext returns the ID assigned by RequestID, or "" outside a request.
+// It accepts both a *gin.Context and the request context derived from it.
+func RequestIDFromContext(c context.Context) string {
+	if gc, ok := c.(*gin.Context); ok {
+		return gc.GetString("requestID")
+	}
+	id, _ := c.Value(requestIDKey{}).(string)
+	return id
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	raw := make([]byte, 16)
+	// crypto/rand only fails if the system's entropy source is broken.
+	if _, err := rand.Read(raw); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(raw)
+}
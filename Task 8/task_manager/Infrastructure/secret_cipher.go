@@ -0,0 +1,63 @@
+package infrastructure
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// Ensure AESSecretCipher implements the domain.SecretCipher interface
+var _ domain.SecretCipher = (*AESSecretCipher)(nil)
+
+// AESSecretCipher encrypts secrets with AES-256-GCM. The key is derived from
+// the configured passphrase with SHA-256 so any non-empty string can be used.
+type AESSecretCipher struct {
+	aead cipher.AEAD
+}
+
+func NewAESSecretCipher(passphrase string) (*AESSecretCipher, error) {
+	if passphrase == "" {
+		return nil, errors.New("secret cipher: passphrase cannot be empty")
+	}
+	key := sha256.Sum256([]byte(passphrase))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("secret cipher: failed to create block cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("secret cipher: failed to create GCM: %w", err)
+	}
+	return &AESSecretCipher{aead: aead}, nil
+}
+
+func (s *AESSecretCipher) Encrypt(c context.Context, plaintext string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("secret cipher: failed to generate nonce: %w", err)
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *AESSecretCipher) Decrypt(c context.Context, ciphertext string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("secret cipher: malformed ciphertext: %w", err)
+	}
+	nonceSize := s.aead.NonceSize()
+	if len(raw) < nonceSize {
+		return "", errors.New("secret cipher: ciphertext too short")
+	}
+	plaintext, err := s.aead.Open(nil, raw[:nonceSize], raw[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("secret cipher: failed to decrypt: %w", err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,75 @@
+package infrastructure_test
+
+import (
+	"A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+//===========================================================================
+// AESSecretCipher Test Suite
+//===========================================================================
+
+type AESSecretCipherSuite struct {
+	suite.Suite
+	cipher *infrastructure.AESSecretCipher
+}
+
+// TestAESSecretCipherSuite is the entry point for the test suite
+func TestAESSecretCipherSuite(t *testing.T) {
+	suite.Run(t, new(AESSecretCipherSuite))
+}
+
+// SetupTest runs before each test method
+func (s *AESSecretCipherSuite) SetupTest() {
+	cipher, err := infrastructure.NewAESSecretCipher("test-passphrase")
+	s.Require().NoError(err)
+	s.cipher = cipher
+}
+
+// TestEncryptDecrypt tests the full round-trip of encrypting and decrypting a secret.
+func (s *AESSecretCipherSuite) TestEncryptDecrypt() {
+	ctx := context.Background()
+	plaintext := "JBSWY3DPEHPK3PXP"
+
+	ciphertext, err := s.cipher.Encrypt(ctx, plaintext)
+	s.Require().NoError(err)
+	s.NotEqual(plaintext, ciphertext)
+
+	again, err := s.cipher.Encrypt(ctx, plaintext)
+	s.Require().NoError(err)
+	s.NotEqual(ciphertext, again, "Each encryption should use a fresh nonce")
+
+	decrypted, err := s.cipher.Decrypt(ctx, ciphertext)
+	s.Require().NoError(err)
+	s.Equal(plaintext, decrypted)
+}
+
+// TestDecrypt_Failure tests tampered and foreign ciphertexts.
+func (s *AESSecretCipherSuite) TestDecrypt_Failure() {
+	ctx := context.Background()
+
+	s.Run("Different Key", func() {
+		other, err := infrastructure.NewAESSecretCipher("another-passphrase")
+		s.Require().NoError(err)
+		ciphertext, err := other.Encrypt(ctx, "secret")
+		s.Require().NoError(err)
+
+		_, err = s.cipher.Decrypt(ctx, ciphertext)
+		s.Error(err)
+	})
+
+	s.Run("Malformed", func() {
+		_, err := s.cipher.Decrypt(ctx, "%%%")
+		s.Error(err)
+		_, err = s.cipher.Decrypt(ctx, "c2hvcnQ=")
+		s.Error(err)
+	})
+
+	s.Run("Empty Passphrase", func() {
+		_, err := infrastructure.NewAESSecretCipher("")
+		s.Error(err)
+	})
+}
@@ -0,0 +1,32 @@
+package infrastructure
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeZoneHeader names the IANA time zone of the client, e.g. "Europe/Berlin".
+const TimeZoneHeader = "Time-Zone"
+
+// TimeZone stores the zone from the Time-Zone header in the request context, where use
+// cases read it to decide what "today" is. Requests without the header fall back to
+// the zone in the user's profile once Authenticate runs, and to UTC otherwise.
+func TimeZone() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.GetHeader(TimeZoneHeader)
+		if name == "" {
+			c.Next()
+			return
+		}
+		location, err := domain.LoadTimeZone(name)
+		if err != nil {
+			AbortWithProblem(c, http.StatusBadRequest, CodeInvalidTimeZone,
+				TimeZoneHeader+" must be an IANA time zone such as Europe/Berlin")
+			return
+		}
+		c.Request = c.Request.WithContext(domain.ContextWithLocation(c.Request.Context(), location))
+		c.Next()
+	}
+}
@@ -0,0 +1,94 @@
+package infrastructure_test
+
+import (
+	"A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"context"
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+//===========================================================================
+// TOTPService Test Suite
+//===========================================================================
+
+type TOTPServiceSuite struct {
+	suite.Suite
+	now     time.Time
+	service *infrastructure.TOTPService
+	// secret is the RFC 6238 SHA-1 test seed "12345678901234567890" in base32.
+	secret string
+}
+
+// TestTOTPServiceSuite is the entry point for the test suite
+func TestTOTPServiceSuite(t *testing.T) {
+	suite.Run(t, new(TOTPServiceSuite))
+}
+
+// SetupTest runs before each test method
+func (s *TOTPServiceSuite) SetupTest() {
+	s.now = time.Unix(1111111109, 0)
+	s.service = infrastructure.NewTOTPServiceWithClock("TaskManager", func() time.Time { return s.now })
+	s.secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+}
+
+// TestGenerateTOTPCode checks the HOTP output against the RFC 6238 test vectors (last 6 digits).
+func (s *TOTPServiceSuite) TestGenerateTOTPCode() {
+	key := []byte("12345678901234567890")
+	s.Equal("287082", infrastructure.GenerateTOTPCode(key, 59/30))
+	s.Equal("081804", infrastructure.GenerateTOTPCode(key, 1111111109/30))
+	s.Equal("005924", infrastructure.GenerateTOTPCode(key, 1234567890/30))
+}
+
+// TestValidate tests code validation including the clock-skew window.
+func (s *TOTPServiceSuite) TestValidate() {
+	ctx := context.Background()
+
+	s.Run("Success - Current Period", func() {
+		step, ok := s.service.Validate(ctx, "081804", s.secret)
+		s.True(ok)
+		s.Equal(int64(1111111109/30), step)
+	})
+
+	s.Run("Success - Previous Period Within Skew", func() {
+		s.now = time.Unix(1111111109+30, 0)
+		defer func() { s.now = time.Unix(1111111109, 0) }()
+		step, ok := s.service.Validate(ctx, "081804", s.secret)
+		s.True(ok)
+		s.Equal(int64(1111111109/30), step, "The step should be the code's period, not the current one")
+	})
+
+	s.Run("Failure - Outside Skew", func() {
+		s.now = time.Unix(1111111109+120, 0)
+		defer func() { s.now = time.Unix(1111111109, 0) }()
+		_, ok := s.service.Validate(ctx, "081804", s.secret)
+		s.False(ok)
+	})
+
+	s.Run("Failure - Wrong Code", func() {
+		_, ok := s.service.Validate(ctx, "000000", s.secret)
+		s.False(ok)
+	})
+
+	s.Run("Failure - Malformed Input", func() {
+		_, ok := s.service.Validate(ctx, "12345", s.secret)
+		s.False(ok)
+		_, ok = s.service.Validate(ctx, "081804", "not base32!")
+		s.False(ok)
+	})
+}
+
+// TestGenerateSecretAndURI tests secret generation and the otpauth provisioning URI.
+func (s *TOTPServiceSuite) TestGenerateSecretAndURI() {
+	secret, err := s.service.GenerateSecret(context.Background())
+	s.Require().NoError(err)
+	s.Len(secret, 32, "20 random bytes encode to 32 base32 characters")
+
+	uri := s.service.ProvisioningURI(context.Background(), "alice", secret)
+	s.True(strings.HasPrefix(uri, "otpauth://totp/TaskManager:alice?"))
+	s.Contains(uri, "secret="+secret)
+	s.Contains(uri, "issuer=TaskManager")
+}
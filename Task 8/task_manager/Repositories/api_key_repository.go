@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Ensure APIKeyRepo implements the domain.APIKeyRepository interface
+var _ domain.APIKeyRepository = (*APIKeyRepo)(nil)
+
+type APIKeyRepo struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDBAPIKeyRepository(col *mongo.Collection) *APIKeyRepo {
+	return &APIKeyRepo{
+		collection: col,
+	}
+}
+
+func (kr *APIKeyRepo) CreateAPIKey(c context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	organizationID, err := inOrganization(c, key.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	key.OrganizationID = organizationID
+	result, err := kr.collection.InsertOne(c, key)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to insert API key: %w", err)
+	}
+
+	insertedID, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("repository: inserted ID is not of type ObjectID: %T", result.InsertedID)
+	}
+	key.Id = insertedID
+
+	return key, nil
+}
+
+func (kr *APIKeyRepo) GetAPIKeyByHash(c context.Context, keyHash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := kr.collection.FindOne(c, scoped(c, bson.M{"key_hash": keyHash})).Decode(&key)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to find API key by hash: %w", err)
+	}
+	return &key, nil
+}
+
+func (kr *APIKeyRepo) GetAPIKeysByServiceAccount(c context.Context, serviceAccountID primitive.ObjectID) ([]*domain.APIKey, error) {
+	cursor, err := kr.collection.Find(c, scoped(c, bson.M{"service_account_id": serviceAccountID}))
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to retrieve API keys cursor: %w", err)
+	}
+	defer cursor.Close(c)
+
+	keys := []*domain.APIKey{}
+	if err = cursor.All(c, &keys); err != nil {
+		return nil, fmt.Errorf("repository: failed to decode API keys from cursor: %w", err)
+	}
+	return keys, nil
+}
+
+func (kr *APIKeyRepo) RevokeAPIKey(c context.Context, id primitive.ObjectID, revokedAt time.Time) error {
+	res, err := kr.collection.UpdateOne(c, scoped(c, bson.M{"_id": id}), bson.M{"$set": bson.M{"revoked_at": revokedAt}})
+	if err != nil {
+		return fmt.Errorf("repository: failed to revoke API key '%s': %w", id.Hex(), err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (kr *APIKeyRepo) TouchAPIKey(c context.Context, id primitive.ObjectID, usedAt time.Time) error {
+	_, err := kr.collection.UpdateOne(c, scoped(c, bson.M{"_id": id}), bson.M{"$set": bson.M{"last_used_at": usedAt}})
+	if err != nil {
+		return fmt.Errorf("repository: failed to record API key usage '%s': %w", id.Hex(), err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package repositories_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"A2SV_ProjectPhase/Task8/TaskManager/Repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+//===========================================================================
+// APIKeyRepo Integration Test Suite
+//===========================================================================
+
+type APIKeyRepoSuite struct {
+	suite.Suite
+	coll *mongo.Collection
+	repo domain.APIKeyRepository
+}
+
+// TestAPIKeyRepoSuite is the entry point for the test suite
+func TestAPIKeyRepoSuite(t *testing.T) {
+	if testMongoClient == nil {
+		t.Skip("Skipping integration tests: MongoDB connection not available.")
+	}
+	suite.Run(t, new(APIKeyRepoSuite))
+}
+
+// SetupSuite runs once for the entire suite.
+func (s *APIKeyRepoSuite) SetupSuite() {
+	s.coll = testMongoClient.Database("test_learning_phase").Collection("apikey8")
+}
+
+// SetupTest runs before EACH test method.
+func (s *APIKeyRepoSuite) SetupTest() {
+	_, err := s.coll.DeleteMany(context.Background(), bson.D{})
+	s.Require().NoError(err, "Failed to clean API key collection before test")
+	s.repo = repositories.NewMongoDBAPIKeyRepository(s.coll)
+}
+
+// TestCreateAndFind tests storing a key and finding it by hash and by account.
+func (s *APIKeyRepoSuite) TestCreateAndFind() {
+	ctx := organizationContext()
+	accountID := primitive.NewObjectID()
+	key := &domain.APIKey{ServiceAccountID: accountID, Name: "ci", KeyHash: "abc123", Scopes: []domain.Permission{domain.PermTasksRead}}
+
+	created, err := s.repo.CreateAPIKey(ctx, key)
+	s.Require().NoError(err)
+	s.False(created.Id.IsZero())
+
+	found, err := s.repo.GetAPIKeyByHash(ctx, "abc123")
+	s.Require().NoError(err)
+	s.Equal(created.Id, found.Id)
+
+	keys, err := s.repo.GetAPIKeysByServiceAccount(ctx, accountID)
+	s.Require().NoError(err)
+	s.Len(keys, 1)
+
+	_, err = s.repo.GetAPIKeyByHash(ctx, "missing")
+	s.ErrorIs(err, domain.ErrAPIKeyNotFound)
+}
+
+// TestRevokeAPIKey tests revoking keys.
+func (s *APIKeyRepoSuite) TestRevokeAPIKey() {
+	ctx := organizationContext()
+	created, err := s.repo.CreateAPIKey(ctx, &domain.APIKey{Name: "ci", KeyHash: "to-revoke"})
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.repo.RevokeAPIKey(ctx, created.Id, time.Now()))
+	found, err := s.repo.GetAPIKeyByHash(ctx, "to-revoke")
+	s.Require().NoError(err)
+	s.NotNil(found.RevokedAt)
+
+	s.ErrorIs(s.repo.RevokeAPIKey(ctx, primitive.NewObjectID(), time.Now()), domain.ErrAPIKeyNotFound)
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Ensure AttachmentRepo implements the domain.AttachmentRepository interface
+var _ domain.AttachmentRepository = (*AttachmentRepo)(nil)
+
+type AttachmentRepo struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDBAttachmentRepository(col *mongo.Collection) *AttachmentRepo {
+	return &AttachmentRepo{
+		collection: col,
+	}
+}
+
+func (ar *AttachmentRepo) CreateAttachment(c context.Context, attachment *domain.Attachment) (*domain.Attachment, error) {
+	organizationID, err := inOrganization(c, attachment.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	attachment.OrganizationID = organizationID
+	result, err := ar.collection.InsertOne(c, attachment)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to insert attachment: %w", err)
+	}
+
+	insertedID, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("repository: inserted ID is not of type ObjectID: %T", result.InsertedID)
+	}
+	attachment.Id = insertedID
+
+	return attachment, nil
+}
+
+func (ar *AttachmentRepo) GetAttachmentById(c context.Context, id primitive.ObjectID) (*domain.Attachment, error) {
+	var attachment domain.Attachment
+	err := ar.collection.FindOne(c, scoped(c, bson.M{"_id": id})).Decode(&attachment)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to find attachment by ID '%s': %w", id.Hex(), err)
+	}
+	return &attachment, nil
+}
+
+func (ar *AttachmentRepo) GetAttachmentsByTask(c context.Context, taskID primitive.ObjectID) ([]*domain.Attachment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := ar.collection.Find(c, scoped(c, bson.M{"task_id": taskID}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to retrieve attachments cursor: %w", err)
+	}
+	defer cursor.Close(c)
+
+	attachments := []*domain.Attachment{}
+	if err = cursor.All(c, &attachments); err != nil {
+		return nil, fmt.Errorf("repository: failed to decode attachments from cursor: %w", err)
+	}
+	return attachments, nil
+}
+
+func (ar *AttachmentRepo) DeleteAttachment(c context.Context, id primitive.ObjectID) error {
+	res, err := ar.collection.DeleteOne(c, scoped(c, bson.M{"_id": id}))
+	if err != nil {
+		return fmt.Errorf("repository: failed to delete attachment by ID '%s': %w", id.Hex(), err)
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrAttachmentNotFound
+	}
+	return nil
+}
+
+func (ar *AttachmentRepo) DeleteAttachmentsByTask(c context.Context, taskID primitive.ObjectID) error {
+	if _, err := ar.collection.DeleteMany(c, scoped(c, bson.M{"task_id": taskID})); err != nil {
+		return fmt.Errorf("repository: failed to delete attachments of task '%s': %w", taskID.Hex(), err)
+	}
+	return nil
+}
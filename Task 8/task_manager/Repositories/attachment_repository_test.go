@@ -0,0 +1,72 @@
+package repositories_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"A2SV_ProjectPhase/Task8/TaskManager/Repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+//===========================================================================
+// AttachmentRepo Integration Test Suite
+//===========================================================================
+
+type AttachmentRepoSuite struct {
+	suite.Suite
+	coll *mongo.Collection
+	repo domain.AttachmentRepository
+}
+
+// TestAttachmentRepoSuite is the entry point for the test suite
+func TestAttachmentRepoSuite(t *testing.T) {
+	if testMongoClient == nil {
+		t.Skip("Skipping integration tests: MongoDB connection not available.")
+	}
+	suite.Run(t, new(AttachmentRepoSuite))
+}
+
+// SetupSuite runs once for the entire suite.
+func (s *AttachmentRepoSuite) SetupSuite() {
+	s.coll = testMongoClient.Database("test_learning_phase").Collection("attachment8")
+}
+
+// SetupTest runs before EACH test method.
+func (s *AttachmentRepoSuite) SetupTest() {
+	_, err := s.coll.DeleteMany(context.Background(), bson.D{})
+	s.Require().NoError(err, "Failed to clean attachment collection before test")
+	s.repo = repositories.NewMongoDBAttachmentRepository(s.coll)
+}
+
+// TestAttachmentLifecycle tests creating, listing and deleting attachments.
+func (s *AttachmentRepoSuite) TestAttachmentLifecycle() {
+	ctx := organizationContext()
+	taskID := primitive.NewObjectID()
+	for _, name := range []string{"spec.pdf", "screenshot.png"} {
+		_, err := s.repo.CreateAttachment(ctx, &domain.Attachment{TaskID: taskID, FileName: name, StorageKey: name, CreatedAt: time.Now()})
+		s.Require().NoError(err)
+	}
+	other, err := s.repo.CreateAttachment(ctx, &domain.Attachment{TaskID: primitive.NewObjectID(), FileName: "other.txt"})
+	s.Require().NoError(err)
+
+	attachments, err := s.repo.GetAttachmentsByTask(ctx, taskID)
+	s.Require().NoError(err)
+	s.Require().Len(attachments, 2)
+	s.Equal("spec.pdf", attachments[0].StorageKey, "storage key is persisted even though it is hidden from JSON")
+
+	s.Require().NoError(s.repo.DeleteAttachment(ctx, attachments[0].Id))
+	s.ErrorIs(s.repo.DeleteAttachment(ctx, attachments[0].Id), domain.ErrAttachmentNotFound)
+
+	s.Require().NoError(s.repo.DeleteAttachmentsByTask(ctx, taskID))
+	attachments, err = s.repo.GetAttachmentsByTask(ctx, taskID)
+	s.Require().NoError(err)
+	s.Empty(attachments)
+
+	_, err = s.repo.GetAttachmentById(ctx, other.Id)
+	s.NoError(err, "attachments of other tasks are untouched")
+}
@@ -0,0 +1,359 @@
+package repositories
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/sync/singleflight"
+)
+
+// Ensure CachedTaskRepo implements the domain.TaskRepository interface
+var _ domain.TaskRepository = (*CachedTaskRepo)(nil)
+
+// TaskCacheOptions configures a CachedTaskRepo. Zero values select the defaults.
+type TaskCacheOptions struct {
+	// MaxEntries bounds the number of cached tasks and task lists together; the least
+	// recently used entry is evicted first.
+	MaxEntries int
+	// TTL is how long an entry is served before it is read again from the backend.
+	TTL   time.Duration
+	Clock domain.Clock
+}
+
+const (
+	DefaultTaskCacheEntries = 1000
+	DefaultTaskCacheTTL     = 30 * time.Second
+)
+
+// TaskCacheStats counts cache lookups since the repository was created.
+type TaskCacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+}
+
+// CachedTaskRepo wraps another TaskRepository and caches GetTaskById and GetAllTasks
+// in memory. Every write through the repository drops the written task and all cached
+// lists, since any list may have included it. Concurrent misses for the same key share
+// one backend read. Entries are kept per organization, so one organization never sees
+// another's cached tasks.
+//
+// The cache only sees writes made through it, so all writers in a process must share
+// one CachedTaskRepo, and writes by other processes show up after at most the TTL.
+// Transactions must be run through WrapTransactor.
+type CachedTaskRepo struct {
+	next  domain.TaskRepository
+	ttl   time.Duration
+	max   int
+	clock domain.Clock
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // front is most recently used
+	// generation changes on every invalidation; reads that started before it changed
+	// must not store what they read.
+	generation uint64
+	// transactions counts running transactions. While any runs, nothing is stored,
+	// because reads may see writes that are later rolled back.
+	transactions int
+
+	loads singleflight.Group
+
+	hits, misses, evictions atomic.Uint64
+}
+
+type cacheEntry struct {
+	key     string
+	value   any // *domain.Task or []*domain.Task
+	expires time.Time
+}
+
+func NewCachedTaskRepository(next domain.TaskRepository, opts TaskCacheOptions) *CachedTaskRepo {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = DefaultTaskCacheEntries
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTaskCacheTTL
+	}
+	if opts.Clock == nil {
+		opts.Clock = domain.SystemClock{}
+	}
+	return &CachedTaskRepo{
+		next:    next,
+		ttl:     opts.TTL,
+		max:     opts.MaxEntries,
+		clock:   opts.Clock,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Stats returns the cache counters and the current number of entries.
+func (r *CachedTaskRepo) Stats() TaskCacheStats {
+	r.mu.Lock()
+	entries := r.lru.Len()
+	r.mu.Unlock()
+	return TaskCacheStats{
+		Hits:      r.hits.Load(),
+		Misses:    r.misses.Load(),
+		Evictions: r.evictions.Load(),
+		Entries:   entries,
+	}
+}
+
+// --- Reads ---
+
+func (r *CachedTaskRepo) GetTaskById(c context.Context, id primitive.ObjectID) (*domain.Task, error) {
+	value, err := r.load(c, "task:"+id.Hex(), func(c context.Context) (any, error) {
+		return r.next.GetTaskById(c, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyTask(value.(*domain.Task)), nil
+}
+
+func (r *CachedTaskRepo) GetAllTasks(c context.Context, filter domain.TaskFilter) ([]*domain.Task, error) {
+	key, err := json.Marshal(filter)
+	if err != nil {
+		return r.next.GetAllTasks(c, filter)
+	}
+	value, err := r.load(c, "list:"+string(key), func(c context.Context) (any, error) {
+		return r.next.GetAllTasks(c, filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	cached := value.([]*domain.Task)
+	tasks := make([]*domain.Task, len(cached))
+	for i, task := range cached {
+		tasks[i] = copyTask(task)
+	}
+	return tasks, nil
+}
+
+// StreamTasks is not cached: it serves exports, which read every task once.
+func (r *CachedTaskRepo) StreamTasks(c context.Context, filter domain.TaskFilter, fn func(task *domain.Task) error) error {
+	return r.next.StreamTasks(c, filter, fn)
+}
+
+func (r *CachedTaskRepo) GetTaskByExternalID(c context.Context, externalID string) (*domain.Task, error) {
+	return r.next.GetTaskByExternalID(c, externalID)
+}
+
+func (r *CachedTaskRepo) GetTaskStats(c context.Context, filter domain.TaskFilter, params domain.TaskStatsParams) (*domain.TaskStats, error) {
+	return r.next.GetTaskStats(c, filter, params)
+}
+
+// cacheKey prefixes key with the caller's organization, or with "system" for trusted
+// internal calls, which read across organizations. Other calls see nothing and are
+// not cached, which ok reports.
+func cacheKey(c context.Context, key string) (string, bool) {
+	if organizationID, ok := domain.OrganizationFromContext(c); ok {
+		return organizationID.Hex() + "/" + key, true
+	}
+	if domain.IsSystemScope(c) {
+		return "system/" + key, true
+	}
+	return "", false
+}
+
+// load returns the cached value for key in the caller's organization or reads it with
+// fetch. The value it returns is shared and must be copied before it is handed out.
+func (r *CachedTaskRepo) load(c context.Context, key string, fetch func(c context.Context) (any, error)) (any, error) {
+	// Inside a transaction, reads must see the transaction's own writes, so they
+	// bypass the cache and are never shared with other callers.
+	if inTransaction(c) {
+		return fetch(c)
+	}
+	key, ok := cacheKey(c, key)
+	if !ok {
+		return fetch(c)
+	}
+	if value, ok := r.get(key); ok {
+		r.hits.Add(1)
+		return value, nil
+	}
+	r.misses.Add(1)
+
+	// Callers that arrive after an invalidation start a new read rather than joining
+	// one that may have read the old data.
+	r.mu.Lock()
+	generation := r.generation
+	r.mu.Unlock()
+	value, err, _ := r.loads.Do(fmt.Sprintf("%s@%d", key, generation), func() (any, error) {
+		// The read is shared by every caller waiting for key, so one caller giving up
+		// must not fail it for the others.
+		value, err := fetch(context.WithoutCancel(c))
+		if err == nil {
+			r.put(key, value, generation)
+		}
+		return value, err
+	})
+	return value, err
+}
+
+func (r *CachedTaskRepo) get(key string) (any, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	element, ok := r.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if !r.clock.Now().Before(entry.expires) {
+		r.remove(element)
+		return nil, false
+	}
+	r.lru.MoveToFront(element)
+	return entry.value, true
+}
+
+func (r *CachedTaskRepo) put(key string, value any, generation uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if generation != r.generation || r.transactions > 0 {
+		return
+	}
+	entry := &cacheEntry{key: key, value: value, expires: r.clock.Now().Add(r.ttl)}
+	if element, ok := r.entries[key]; ok {
+		element.Value = entry
+		r.lru.MoveToFront(element)
+		return
+	}
+	r.entries[key] = r.lru.PushFront(entry)
+	for r.lru.Len() > r.max {
+		r.remove(r.lru.Back())
+		r.evictions.Add(1)
+	}
+}
+
+// remove deletes an entry; the caller holds r.mu.
+func (r *CachedTaskRepo) remove(element *list.Element) {
+	r.lru.Remove(element)
+	delete(r.entries, element.Value.(*cacheEntry).key)
+}
+
+// --- Writes ---
+
+// invalidate drops the given tasks and every cached list, in every organization, or
+// everything when no IDs are given.
+func (r *CachedTaskRepo) invalidate(ids ...primitive.ObjectID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.generation++
+	if len(ids) == 0 {
+		r.entries = make(map[string]*list.Element)
+		r.lru.Init()
+		return
+	}
+	dropped := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		dropped["task:"+id.Hex()] = true
+	}
+	for key, element := range r.entries {
+		_, key, _ = strings.Cut(key, "/")
+		if dropped[key] || strings.HasPrefix(key, "list:") {
+			r.remove(element)
+		}
+	}
+}
+
+// The cache is invalidated even when a write fails, since the backend may have
+// applied part of it.
+
+func (r *CachedTaskRepo) CreateTask(c context.Context, task *domain.Task) (*domain.Task, error) {
+	created, err := r.next.CreateTask(c, task)
+	r.invalidate(task.Id)
+	return created, err
+}
+
+func (r *CachedTaskRepo) UpdateTask(c context.Context, id primitive.ObjectID, task *domain.Task) (*domain.Task, error) {
+	defer r.invalidate(id)
+	return r.next.UpdateTask(c, id, task)
+}
+
+func (r *CachedTaskRepo) DeleteTask(c context.Context, id primitive.ObjectID) error {
+	defer r.invalidate(id)
+	return r.next.DeleteTask(c, id)
+}
+
+func (r *CachedTaskRepo) AddBlocker(c context.Context, taskID, blockerID primitive.ObjectID) error {
+	defer r.invalidate(taskID)
+	return r.next.AddBlocker(c, taskID, blockerID)
+}
+
+func (r *CachedTaskRepo) RemoveBlocker(c context.Context, taskID, blockerID primitive.ObjectID) error {
+	defer r.invalidate(taskID)
+	return r.next.RemoveBlocker(c, taskID, blockerID)
+}
+
+func (r *CachedTaskRepo) AddWatcher(c context.Context, taskID primitive.ObjectID, userID string) error {
+	defer r.invalidate(taskID)
+	return r.next.AddWatcher(c, taskID, userID)
+}
+
+func (r *CachedTaskRepo) RemoveWatcher(c context.Context, taskID primitive.ObjectID, userID string) error {
+	defer r.invalidate(taskID)
+	return r.next.RemoveWatcher(c, taskID, userID)
+}
+
+// RemoveBlockerFromAll may change any number of tasks, so it clears the whole cache.
+func (r *CachedTaskRepo) RemoveBlockerFromAll(c context.Context, blockerID primitive.ObjectID) error {
+	defer r.invalidate()
+	return r.next.RemoveBlockerFromAll(c, blockerID)
+}
+
+// RemoveWatcherFromAll may change any number of tasks, so it clears the whole cache.
+func (r *CachedTaskRepo) RemoveWatcherFromAll(c context.Context, userID string) error {
+	defer r.invalidate()
+	return r.next.RemoveWatcherFromAll(c, userID)
+}
+
+// --- Transactions ---
+
+type transactionKey struct{}
+
+func inTransaction(c context.Context) bool {
+	return c.Value(transactionKey{}) != nil
+}
+
+// WrapTransactor returns a Transactor that runs transactions with next and keeps the
+// cache consistent with them: reads inside a transaction bypass the cache, nothing is
+// cached while a transaction runs, and the whole cache is dropped when it ends, since
+// its writes only become visible (or are undone) then.
+func (r *CachedTaskRepo) WrapTransactor(next domain.Transactor) domain.Transactor {
+	return &cachedTaskTransactor{repo: r, next: next}
+}
+
+type cachedTaskTransactor struct {
+	repo *CachedTaskRepo
+	next domain.Transactor
+}
+
+func (t *cachedTaskTransactor) WithinTransaction(c context.Context, fn func(c context.Context) error) error {
+	if inTransaction(c) {
+		return t.next.WithinTransaction(c, fn)
+	}
+	t.repo.mu.Lock()
+	t.repo.transactions++
+	t.repo.mu.Unlock()
+	defer func() {
+		t.repo.mu.Lock()
+		t.repo.transactions--
+		t.repo.mu.Unlock()
+		t.repo.invalidate()
+	}()
+	return t.next.WithinTransaction(c, func(txCtx context.Context) error {
+		return fn(context.WithValue(txCtx, transactionKey{}, true))
+	})
+}
@@ -0,0 +1,356 @@
+package repositories_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	repositories "A2SV_ProjectPhase/Task8/TaskManager/Repositories"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// countingTaskRepo counts backend reads and can hold GetTaskById until released.
+type countingTaskRepo struct {
+	*repositories.InMemoryTaskRepo
+	gets, lists atomic.Int32
+	// block, if set, is waited on by GetTaskById after it has read the task.
+	block chan struct{}
+}
+
+func (r *countingTaskRepo) GetTaskById(c context.Context, id primitive.ObjectID) (*domain.Task, error) {
+	r.gets.Add(1)
+	task, err := r.InMemoryTaskRepo.GetTaskById(c, id)
+	if r.block != nil {
+		<-r.block
+	}
+	return task, err
+}
+
+func (r *countingTaskRepo) GetAllTasks(c context.Context, filter domain.TaskFilter) ([]*domain.Task, error) {
+	r.lists.Add(1)
+	return r.InMemoryTaskRepo.GetAllTasks(c, filter)
+}
+
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// passthroughTransactor runs fn directly; the cache only cares when transactions start and end.
+type passthroughTransactor struct{}
+
+func (passthroughTransactor) WithinTransaction(c context.Context, fn func(c context.Context) error) error {
+	return fn(c)
+}
+
+//===========================================================================
+// Cached Task Repository Test Suite
+//===========================================================================
+
+type CachedTaskRepoSuite struct {
+	suite.Suite
+	backend *countingTaskRepo
+	clock   *manualClock
+	repo    *repositories.CachedTaskRepo
+	ctx     context.Context
+}
+
+// The cache does not need MongoDB, but runs with the package's other tests.
+func TestCachedTaskRepoSuite(t *testing.T) {
+	suite.Run(t, new(CachedTaskRepoSuite))
+}
+
+func (s *CachedTaskRepoSuite) SetupTest() {
+	s.backend = &countingTaskRepo{InMemoryTaskRepo: repositories.NewInMemoryTaskRepository()}
+	s.clock = &manualClock{now: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)}
+	s.repo = s.newRepo(10)
+	s.ctx = domain.ContextWithOrganization(context.Background(), primitive.NewObjectID())
+}
+
+func (s *CachedTaskRepoSuite) newRepo(maxEntries int) *repositories.CachedTaskRepo {
+	return repositories.NewCachedTaskRepository(s.backend, repositories.TaskCacheOptions{
+		MaxEntries: maxEntries,
+		TTL:        time.Minute,
+		Clock:      s.clock,
+	})
+}
+
+func (s *CachedTaskRepoSuite) createTask(title string) *domain.Task {
+	task, err := s.repo.CreateTask(s.ctx, &domain.Task{Title: title, Status: domain.Pending})
+	s.Require().NoError(err)
+	return task
+}
+
+func (s *CachedTaskRepoSuite) TestRepeatedReadsHitTheCache() {
+	task := s.createTask("Cached")
+
+	for range 3 {
+		found, err := s.repo.GetTaskById(s.ctx, task.Id)
+		s.Require().NoError(err)
+		s.Equal("Cached", found.Title)
+	}
+	for range 2 {
+		tasks, err := s.repo.GetAllTasks(s.ctx, domain.TaskFilter{})
+		s.Require().NoError(err)
+		s.Len(tasks, 1)
+	}
+
+	s.Equal(int32(1), s.backend.gets.Load())
+	s.Equal(int32(1), s.backend.lists.Load())
+	s.Equal(repositories.TaskCacheStats{Hits: 3, Misses: 2, Entries: 2}, s.repo.Stats())
+}
+
+func (s *CachedTaskRepoSuite) TestFiltersAreCachedSeparately() {
+	s.createTask("Pending")
+
+	pending, err := s.repo.GetAllTasks(s.ctx, domain.TaskFilter{Statuses: []domain.TaskStatus{domain.Pending}})
+	s.Require().NoError(err)
+	done, err := s.repo.GetAllTasks(s.ctx, domain.TaskFilter{Statuses: []domain.TaskStatus{domain.Done}})
+	s.Require().NoError(err)
+
+	s.Len(pending, 1)
+	s.Empty(done)
+	s.Equal(int32(2), s.backend.lists.Load())
+}
+
+func (s *CachedTaskRepoSuite) TestCallersCannotChangeCachedTasks() {
+	task := s.createTask("Original")
+
+	found, err := s.repo.GetTaskById(s.ctx, task.Id)
+	s.Require().NoError(err)
+	found.Title = "Changed by the caller"
+	found, err = s.repo.GetTaskById(s.ctx, task.Id)
+	s.Require().NoError(err)
+
+	s.Equal("Original", found.Title)
+}
+
+func (s *CachedTaskRepoSuite) TestWritesInvalidate() {
+	task := s.createTask("Original")
+	other := s.createTask("Other")
+	read := func() (*domain.Task, []*domain.Task) {
+		found, err := s.repo.GetTaskById(s.ctx, task.Id)
+		s.Require().NoError(err)
+		tasks, err := s.repo.GetAllTasks(s.ctx, domain.TaskFilter{})
+		s.Require().NoError(err)
+		return found, tasks
+	}
+	read()
+
+	s.Run("Create", func() {
+		s.createTask("New")
+		_, tasks := read()
+		s.Len(tasks, 3)
+	})
+	s.Run("Update", func() {
+		_, err := s.repo.UpdateTask(s.ctx, task.Id, &domain.Task{Id: task.Id, Title: "Updated", Status: domain.Pending})
+		s.Require().NoError(err)
+		found, tasks := read()
+		s.Equal("Updated", found.Title)
+		s.Contains([]string{tasks[0].Title, tasks[1].Title, tasks[2].Title}, "Updated")
+	})
+	s.Run("Blockers", func() {
+		s.Require().NoError(s.repo.AddBlocker(s.ctx, task.Id, other.Id))
+		found, _ := read()
+		s.Equal([]primitive.ObjectID{other.Id}, found.BlockedBy)
+
+		s.Require().NoError(s.repo.RemoveBlockerFromAll(s.ctx, other.Id))
+		found, _ = read()
+		s.Empty(found.BlockedBy)
+	})
+	s.Run("Delete", func() {
+		s.Require().NoError(s.repo.DeleteTask(s.ctx, task.Id))
+		_, err := s.repo.GetTaskById(s.ctx, task.Id)
+		s.ErrorIs(err, domain.ErrTaskNotFound)
+		tasks, err := s.repo.GetAllTasks(s.ctx, domain.TaskFilter{})
+		s.Require().NoError(err)
+		s.Len(tasks, 2)
+	})
+}
+
+func (s *CachedTaskRepoSuite) TestOrganizationsDoNotShareEntries() {
+	acme := domain.ContextWithOrganization(s.ctx, primitive.NewObjectID())
+	globex := domain.ContextWithOrganization(s.ctx, primitive.NewObjectID())
+	task, err := s.repo.CreateTask(acme, &domain.Task{Title: "Acme only", Status: domain.Pending})
+	s.Require().NoError(err)
+
+	found, err := s.repo.GetTaskById(acme, task.Id)
+	s.Require().NoError(err)
+	s.Equal("Acme only", found.Title)
+	tasks, err := s.repo.GetAllTasks(acme, domain.TaskFilter{})
+	s.Require().NoError(err)
+	s.Len(tasks, 1)
+
+	_, err = s.repo.GetTaskById(globex, task.Id)
+	s.ErrorIs(err, domain.ErrTaskNotFound)
+	tasks, err = s.repo.GetAllTasks(globex, domain.TaskFilter{})
+	s.Require().NoError(err)
+	s.Empty(tasks)
+
+	_, err = s.repo.UpdateTask(acme, task.Id, &domain.Task{Id: task.Id, Title: "Renamed", Status: domain.Pending})
+	s.Require().NoError(err)
+	found, err = s.repo.GetTaskById(acme, task.Id)
+	s.Require().NoError(err)
+	s.Equal("Renamed", found.Title)
+}
+
+func (s *CachedTaskRepoSuite) TestCallsWithoutOrganizationSeeNothing() {
+	task := s.createTask("Scoped")
+
+	_, err := s.repo.GetTaskById(context.Background(), task.Id)
+	s.ErrorIs(err, domain.ErrTaskNotFound)
+	tasks, err := s.repo.GetAllTasks(context.Background(), domain.TaskFilter{})
+	s.Require().NoError(err)
+	s.Empty(tasks)
+	_, err = s.repo.CreateTask(context.Background(), &domain.Task{Title: "Nowhere", Status: domain.Pending})
+	s.ErrorIs(err, domain.ErrNoOrganization)
+
+	system := domain.ContextWithSystemScope(context.Background())
+	tasks, err = s.repo.GetAllTasks(system, domain.TaskFilter{})
+	s.Require().NoError(err)
+	s.Len(tasks, 1, "The system scope sees every organization")
+}
+
+func (s *CachedTaskRepoSuite) TestEntriesExpire() {
+	task := s.createTask("Expiring")
+	_, err := s.repo.GetTaskById(s.ctx, task.Id)
+	s.Require().NoError(err)
+
+	s.clock.Advance(59 * time.Second)
+	_, err = s.repo.GetTaskById(s.ctx, task.Id)
+	s.Require().NoError(err)
+	s.Equal(int32(1), s.backend.gets.Load(), "still fresh")
+
+	s.clock.Advance(time.Second)
+	_, err = s.repo.GetTaskById(s.ctx, task.Id)
+	s.Require().NoError(err)
+	s.Equal(int32(2), s.backend.gets.Load(), "read again after the TTL")
+}
+
+func (s *CachedTaskRepoSuite) TestLeastRecentlyUsedEntryIsEvicted() {
+	s.repo = s.newRepo(2)
+	first, second, third := s.createTask("First"), s.createTask("Second"), s.createTask("Third")
+	get := func(task *domain.Task) {
+		_, err := s.repo.GetTaskById(s.ctx, task.Id)
+		s.Require().NoError(err)
+	}
+
+	get(first)
+	get(second)
+	get(first) // second is now the least recently used
+	get(third)
+	s.Equal(int32(3), s.backend.gets.Load())
+
+	get(first)
+	s.Equal(int32(3), s.backend.gets.Load(), "first stayed cached")
+	get(second)
+	s.Equal(int32(4), s.backend.gets.Load(), "second was evicted")
+	s.Equal(uint64(2), s.repo.Stats().Evictions)
+	s.Equal(2, s.repo.Stats().Entries)
+}
+
+func (s *CachedTaskRepoSuite) TestConcurrentMissesShareOneRead() {
+	task := s.createTask("Popular")
+	s.backend.block = make(chan struct{})
+
+	var wg sync.WaitGroup
+	titles := make([]string, 5)
+	for i := range titles {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found, err := s.repo.GetTaskById(s.ctx, task.Id)
+			if err == nil {
+				titles[i] = found.Title
+			}
+		}()
+	}
+	s.Eventually(func() bool { return s.repo.Stats().Misses == 5 }, time.Second, time.Millisecond)
+	close(s.backend.block)
+	wg.Wait()
+
+	s.Equal(int32(1), s.backend.gets.Load())
+	s.Equal([]string{"Popular", "Popular", "Popular", "Popular", "Popular"}, titles)
+}
+
+func (s *CachedTaskRepoSuite) TestReadRacingAWriteIsNotCached() {
+	task := s.createTask("Before")
+	s.backend.block = make(chan struct{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = s.repo.GetTaskById(s.ctx, task.Id)
+	}()
+	s.Eventually(func() bool { return s.backend.gets.Load() == 1 }, time.Second, time.Millisecond)
+
+	// The read above has seen "Before"; the update lands before it finishes.
+	_, err := s.repo.UpdateTask(s.ctx, task.Id, &domain.Task{Id: task.Id, Title: "After", Status: domain.Pending})
+	s.Require().NoError(err)
+	close(s.backend.block)
+	<-done
+
+	found, err := s.repo.GetTaskById(s.ctx, task.Id)
+	s.Require().NoError(err)
+	s.Equal("After", found.Title)
+}
+
+func (s *CachedTaskRepoSuite) TestErrorsAreNotCached() {
+	id := primitive.NewObjectID()
+
+	for range 2 {
+		_, err := s.repo.GetTaskById(s.ctx, id)
+		s.ErrorIs(err, domain.ErrTaskNotFound)
+	}
+
+	s.Equal(int32(2), s.backend.gets.Load())
+	s.Zero(s.repo.Stats().Entries)
+}
+
+func (s *CachedTaskRepoSuite) TestTransactions() {
+	task := s.createTask("Original")
+	_, err := s.repo.GetTaskById(s.ctx, task.Id)
+	s.Require().NoError(err)
+	transactor := s.repo.WrapTransactor(passthroughTransactor{})
+	rollback := errors.New("rollback")
+
+	err = transactor.WithinTransaction(s.ctx, func(txCtx context.Context) error {
+		_, err := s.repo.UpdateTask(txCtx, task.Id, &domain.Task{Id: task.Id, Title: "In transaction", Status: domain.Pending})
+		s.Require().NoError(err)
+
+		found, err := s.repo.GetTaskById(txCtx, task.Id)
+		s.Require().NoError(err)
+		s.Equal("In transaction", found.Title, "the transaction sees its own write")
+
+		_, err = s.repo.GetTaskById(s.ctx, task.Id)
+		s.Require().NoError(err)
+		s.Zero(s.repo.Stats().Entries, "nothing is cached while a transaction runs")
+		return rollback
+	})
+	s.ErrorIs(err, rollback)
+
+	// The fake transactor cannot undo the write; the point is that the cache is empty
+	// afterwards, so the next read sees whatever the database kept.
+	s.Zero(s.repo.Stats().Entries)
+	gets := s.backend.gets.Load()
+	_, err = s.repo.GetTaskById(s.ctx, task.Id)
+	s.Require().NoError(err)
+	s.Equal(gets+1, s.backend.gets.Load())
+}
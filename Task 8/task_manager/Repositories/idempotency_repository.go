@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Ensure IdempotencyRepo implements the domain.IdempotencyStore interface
+var _ domain.IdempotencyStore = (*IdempotencyRepo)(nil)
+
+// IdempotencyRepo stores idempotency records keyed by their scoped key, so the unique
+// _id index makes Reserve atomic across server instances.
+type IdempotencyRepo struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDBIdempotencyRepository(col *mongo.Collection) *IdempotencyRepo {
+	return &IdempotencyRepo{
+		collection: col,
+	}
+}
+
+// EnsureIndexes creates the TTL index that lets MongoDB delete expired records.
+func (ir *IdempotencyRepo) EnsureIndexes(c context.Context) error {
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+	if _, err := ir.collection.Indexes().CreateOne(c, indexModel); err != nil {
+		return fmt.Errorf("repository: failed to create idempotency TTL index: %w", err)
+	}
+	return nil
+}
+
+func (ir *IdempotencyRepo) Reserve(c context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	_, err := ir.collection.InsertOne(c, record)
+	if err == nil {
+		return nil, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("repository: failed to reserve idempotency key: %w", err)
+	}
+
+	// MongoDB removes expired documents only about once a minute, so an expired
+	// record may still be present. Take it over if so.
+	filter := bson.M{"_id": record.Key, "expires_at": bson.M{"$lte": time.Now()}}
+	err = ir.collection.FindOneAndReplace(c, filter, record).Err()
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("repository: failed to replace expired idempotency record: %w", err)
+	}
+
+	var existing domain.IdempotencyRecord
+	err = ir.collection.FindOne(c, bson.M{"_id": record.Key}).Decode(&existing)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// Released or expired in the meantime; report it as held so the caller retries.
+		return &domain.IdempotencyRecord{Key: record.Key, RequestHash: record.RequestHash}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to find idempotency record '%s': %w", record.Key, err)
+	}
+	return &existing, nil
+}
+
+func (ir *IdempotencyRepo) Extend(c context.Context, record *domain.IdempotencyRecord) error {
+	filter := bson.M{"_id": record.Key, "owner": record.Owner, "completed": false}
+	update := bson.M{"$set": bson.M{"expires_at": record.ExpiresAt}}
+	result, err := ir.collection.UpdateOne(c, filter, update)
+	if err != nil {
+		return fmt.Errorf("repository: failed to extend idempotency record '%s': %w", record.Key, err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrIdempotencyKeyNotHeld
+	}
+	return nil
+}
+
+func (ir *IdempotencyRepo) Complete(c context.Context, record *domain.IdempotencyRecord) error {
+	filter := bson.M{"_id": record.Key, "owner": record.Owner, "completed": false}
+	result, err := ir.collection.ReplaceOne(c, filter, record)
+	if err != nil {
+		return fmt.Errorf("repository: failed to complete idempotency record '%s': %w", record.Key, err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrIdempotencyKeyNotHeld
+	}
+	return nil
+}
+
+func (ir *IdempotencyRepo) Release(c context.Context, record *domain.IdempotencyRecord) error {
+	_, err := ir.collection.DeleteOne(c, bson.M{"_id": record.Key, "owner": record.Owner, "completed": false})
+	if err != nil {
+		return fmt.Errorf("repository: failed to release idempotency record '%s': %w", record.Key, err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package repositories_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"A2SV_ProjectPhase/Task8/TaskManager/Repositories"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+//===========================================================================
+// IdempotencyRepo Integration Test Suite
+//===========================================================================
+
+type IdempotencyRepoSuite struct {
+	suite.Suite
+	coll *mongo.Collection
+	repo *repositories.IdempotencyRepo
+}
+
+// TestIdempotencyRepoSuite is the entry point for the test suite
+func TestIdempotencyRepoSuite(t *testing.T) {
+	if testMongoClient == nil {
+		t.Skip("Skipping integration tests: MongoDB connection not available.")
+	}
+	suite.Run(t, new(IdempotencyRepoSuite))
+}
+
+// SetupSuite runs once for the entire suite.
+func (s *IdempotencyRepoSuite) SetupSuite() {
+	s.coll = testMongoClient.Database("test_learning_phase").Collection("idempotency8")
+}
+
+// SetupTest runs before EACH test method.
+func (s *IdempotencyRepoSuite) SetupTest() {
+	_, err := s.coll.DeleteMany(context.Background(), bson.D{})
+	s.Require().NoError(err, "Failed to clean idempotency collection before test")
+	s.repo = repositories.NewMongoDBIdempotencyRepository(s.coll)
+	s.Require().NoError(s.repo.EnsureIndexes(context.Background()))
+}
+
+// TestReserveCompleteAndRelease tests the lifecycle of a key.
+func (s *IdempotencyRepoSuite) TestReserveCompleteAndRelease() {
+	ctx := context.Background()
+	record := &domain.IdempotencyRecord{Key: "user1:key1", RequestHash: "hash", Owner: "request-1", ExpiresAt: time.Now().Add(time.Minute)}
+
+	existing, err := s.repo.Reserve(ctx, record)
+	s.Require().NoError(err)
+	s.Nil(existing, "the first reservation should hold the key")
+
+	existing, err = s.repo.Reserve(ctx, &domain.IdempotencyRecord{Key: "user1:key1", RequestHash: "other", ExpiresAt: time.Now().Add(time.Minute)})
+	s.Require().NoError(err)
+	s.Require().NotNil(existing)
+	s.False(existing.Completed)
+	s.Equal("hash", existing.RequestHash)
+
+	extended := *record
+	extended.ExpiresAt = time.Now().Add(2 * time.Minute)
+	s.Require().NoError(s.repo.Extend(ctx, &extended))
+	stale := extended
+	stale.Owner = "request-2"
+	s.ErrorIs(s.repo.Extend(ctx, &stale), domain.ErrIdempotencyKeyNotHeld)
+	s.ErrorIs(s.repo.Complete(ctx, &stale), domain.ErrIdempotencyKeyNotHeld)
+	s.Require().NoError(s.repo.Release(ctx, &stale))
+
+	completed := *record
+	completed.Completed = true
+	completed.StatusCode = http.StatusCreated
+	completed.ContentType = "application/json"
+	completed.Body = []byte(`{"id":"1"}`)
+	completed.ExpiresAt = time.Now().Add(time.Hour)
+	s.Require().NoError(s.repo.Complete(ctx, &completed))
+
+	existing, err = s.repo.Reserve(ctx, record)
+	s.Require().NoError(err)
+	s.Require().NotNil(existing)
+	s.True(existing.Completed)
+	s.Equal(http.StatusCreated, existing.StatusCode)
+	s.Equal(`{"id":"1"}`, string(existing.Body))
+
+	// Completed records are kept until they expire.
+	s.Require().NoError(s.repo.Release(ctx, record))
+	s.ErrorIs(s.repo.Extend(ctx, record), domain.ErrIdempotencyKeyNotHeld)
+	existing, err = s.repo.Reserve(ctx, record)
+	s.Require().NoError(err)
+	s.NotNil(existing)
+
+	// Released records free the key.
+	other := &domain.IdempotencyRecord{Key: "user2:key1", RequestHash: "hash", Owner: "request-3", ExpiresAt: time.Now().Add(time.Minute)}
+	_, err = s.repo.Reserve(ctx, other)
+	s.Require().NoError(err)
+	s.Require().NoError(s.repo.Release(ctx, other))
+	existing, err = s.repo.Reserve(ctx, other)
+	s.Require().NoError(err)
+	s.Nil(existing)
+}
+
+// TestExpiredRecordsAreReplaced tests that a record past its expiry does not block the key
+// before MongoDB's TTL monitor removes it.
+func (s *IdempotencyRepoSuite) TestExpiredRecordsAreReplaced() {
+	ctx := context.Background()
+	expired := &domain.IdempotencyRecord{Key: "user1:key1", RequestHash: "old", Completed: true, ExpiresAt: time.Now().Add(-time.Minute)}
+	_, err := s.coll.InsertOne(ctx, expired)
+	s.Require().NoError(err)
+
+	existing, err := s.repo.Reserve(ctx, &domain.IdempotencyRecord{Key: "user1:key1", RequestHash: "new", ExpiresAt: time.Now().Add(time.Minute)})
+	s.Require().NoError(err)
+	s.Nil(existing)
+
+	var stored domain.IdempotencyRecord
+	s.Require().NoError(s.coll.FindOne(ctx, bson.M{"_id": "user1:key1"}).Decode(&stored))
+	s.Equal("new", stored.RequestHash)
+	s.False(stored.Completed)
+}
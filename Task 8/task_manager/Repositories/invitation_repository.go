@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Ensure InvitationRepo implements the domain.InvitationRepository interface
+var _ domain.InvitationRepository = (*InvitationRepo)(nil)
+
+type InvitationRepo struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDBInvitationRepository(col *mongo.Collection) *InvitationRepo {
+	return &InvitationRepo{
+		collection: col,
+	}
+}
+
+// EnsureIndexes creates the TTL index that lets MongoDB delete expired invitations.
+func (ir *InvitationRepo) EnsureIndexes(c context.Context) error {
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+	if _, err := ir.collection.Indexes().CreateOne(c, indexModel); err != nil {
+		return fmt.Errorf("repository: failed to create invitation TTL index: %w", err)
+	}
+	return nil
+}
+
+func (ir *InvitationRepo) CreateInvitation(c context.Context, invitation *domain.Invitation) (*domain.Invitation, error) {
+	organizationID, err := inOrganization(c, invitation.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	invitation.OrganizationID = organizationID
+	result, err := ir.collection.InsertOne(c, invitation)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to insert invitation: %w", err)
+	}
+
+	insertedID, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("repository: inserted ID is not of type ObjectID: %T", result.InsertedID)
+	}
+	invitation.Id = insertedID
+
+	return invitation, nil
+}
+
+func (ir *InvitationRepo) GetInvitationByTokenHash(c context.Context, tokenHash string) (*domain.Invitation, error) {
+	var invitation domain.Invitation
+	err := ir.collection.FindOne(c, bson.M{"token_hash": tokenHash}).Decode(&invitation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrInvitationNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to find invitation by token: %w", err)
+	}
+	return &invitation, nil
+}
+
+func (ir *InvitationRepo) GetAllInvitations(c context.Context) ([]*domain.Invitation, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := ir.collection.Find(c, scoped(c, bson.M{}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to retrieve invitations cursor: %w", err)
+	}
+	defer cursor.Close(c)
+
+	invitations := []*domain.Invitation{}
+	if err = cursor.All(c, &invitations); err != nil {
+		return nil, fmt.Errorf("repository: failed to decode invitations from cursor: %w", err)
+	}
+	return invitations, nil
+}
+
+func (ir *InvitationRepo) DeleteInvitation(c context.Context, id primitive.ObjectID) error {
+	res, err := ir.collection.DeleteOne(c, scoped(c, bson.M{"_id": id}))
+	if err != nil {
+		return fmt.Errorf("repository: failed to delete invitation by ID '%s': %w", id.Hex(), err)
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrInvitationNotFound
+	}
+	return nil
+}
@@ -1,18 +1,34 @@
 package repositories_test
 
 import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
 	"context"
 	"log"
 	"os"
 	"testing"
 
 	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var testMongoClient *mongo.Client
 
+// testOrganizationID is the organization of the documents the tests create.
+var testOrganizationID = primitive.NewObjectID()
+
+// organizationContext is the context of a caller in testOrganizationID.
+func organizationContext() context.Context {
+	return domain.ContextWithOrganization(context.Background(), testOrganizationID)
+}
+
+// systemContext is the context of a trusted internal call, which sees the documents
+// the tests insert straight into a collection without an organization.
+func systemContext() context.Context {
+	return domain.ContextWithSystemScope(context.Background())
+}
+
 // TestMain is a special function that Go's test runner executes before any other
 // tests in this package. It's the perfect place for global setup and teardown.
 func TestMain(m *testing.M) {
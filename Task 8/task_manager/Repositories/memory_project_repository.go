@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"slices"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Ensure InMemoryProjectRepo implements the domain.ProjectRepository interface
+var _ domain.ProjectRepository = (*InMemoryProjectRepo)(nil)
+
+// InMemoryProjectRepo keeps projects in memory, for the same uses as InMemoryTaskRepo.
+type InMemoryProjectRepo struct {
+	mu       sync.RWMutex
+	projects map[primitive.ObjectID]*domain.Project
+	order    []primitive.ObjectID
+}
+
+func NewInMemoryProjectRepository() *InMemoryProjectRepo {
+	return &InMemoryProjectRepo{
+		projects: map[primitive.ObjectID]*domain.Project{},
+	}
+}
+
+// copyProject keeps callers from changing stored projects through shared pointers.
+func copyProject(project *domain.Project) *domain.Project {
+	duplicate := *project
+	duplicate.Members = slices.Clone(project.Members)
+	return &duplicate
+}
+
+func (r *InMemoryProjectRepo) CreateProject(c context.Context, project *domain.Project) (*domain.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if project.Id.IsZero() {
+		project.Id = primitive.NewObjectID()
+	}
+	organizationID, err := inOrganization(c, project.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	project.OrganizationID = organizationID
+	r.projects[project.Id] = copyProject(project)
+	r.order = append(r.order, project.Id)
+	return project, nil
+}
+
+func (r *InMemoryProjectRepo) GetProjectById(c context.Context, id primitive.ObjectID) (*domain.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	project, ok := r.projects[id]
+	if !ok || !inScope(c, project.OrganizationID) {
+		return nil, domain.ErrProjectNotFound
+	}
+	return copyProject(project), nil
+}
+
+func (r *InMemoryProjectRepo) GetProjectsForMember(c context.Context, userID string) ([]*domain.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	projects := []*domain.Project{}
+	for _, id := range r.order {
+		project := r.projects[id]
+		if _, ok := project.MemberRole(userID); ok && inScope(c, project.OrganizationID) {
+			projects = append(projects, copyProject(project))
+		}
+	}
+	return projects, nil
+}
+
+// UpdateProject changes the same fields as ProjectRepo.UpdateProject.
+func (r *InMemoryProjectRepo) UpdateProject(c context.Context, id primitive.ObjectID, updatedProject *domain.Project) (*domain.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	project, ok := r.projects[id]
+	if !ok || !inScope(c, project.OrganizationID) {
+		return nil, domain.ErrProjectNotFound
+	}
+	update := copyProject(updatedProject)
+	project.Name = update.Name
+	project.Description = update.Description
+	project.Members = update.Members
+	return copyProject(project), nil
+}
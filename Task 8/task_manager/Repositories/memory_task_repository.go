@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"slices"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Ensure InMemoryTaskRepo implements the domain.TaskRepository interface
+var _ domain.TaskRepository = (*InMemoryTaskRepo)(nil)
+
+// InMemoryTaskRepo keeps tasks in memory. It is meant for local development and tests
+// that do not need MongoDB, and follows the same filter and statistics semantics as
+// TaskRepo through domain.TaskFilter.Matches and domain.ComputeTaskStats.
+type InMemoryTaskRepo struct {
+	mu    sync.RWMutex
+	tasks map[primitive.ObjectID]*domain.Task
+	// order keeps tasks in insertion order, which matches the _id order of TaskRepo.
+	order []primitive.ObjectID
+}
+
+func NewInMemoryTaskRepository() *InMemoryTaskRepo {
+	return &InMemoryTaskRepo{
+		tasks: map[primitive.ObjectID]*domain.Task{},
+	}
+}
+
+// copyTask keeps callers from changing stored tasks through shared pointers.
+func copyTask(task *domain.Task) *domain.Task {
+	duplicate := *task
+	duplicate.BlockedBy = slices.Clone(task.BlockedBy)
+	duplicate.Tags = slices.Clone(task.Tags)
+	duplicate.Watchers = slices.Clone(task.Watchers)
+	if task.CompletedAt != nil {
+		completedAt := *task.CompletedAt
+		duplicate.CompletedAt = &completedAt
+	}
+	return &duplicate
+}
+
+func (r *InMemoryTaskRepo) CreateTask(c context.Context, task *domain.Task) (*domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if task.Id.IsZero() {
+		task.Id = primitive.NewObjectID()
+	}
+	organizationID, err := inOrganization(c, task.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	task.OrganizationID = organizationID
+	r.tasks[task.Id] = copyTask(task)
+	r.order = append(r.order, task.Id)
+	return task, nil
+}
+
+func (r *InMemoryTaskRepo) GetTaskById(c context.Context, id primitive.ObjectID) (*domain.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	task, ok := r.get(c, id)
+	if !ok {
+		return nil, domain.ErrTaskNotFound
+	}
+	return copyTask(task), nil
+}
+
+func (r *InMemoryTaskRepo) GetAllTasks(c context.Context, filter domain.TaskFilter) ([]*domain.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.matching(c, filter), nil
+}
+
+func (r *InMemoryTaskRepo) StreamTasks(c context.Context, filter domain.TaskFilter, fn func(task *domain.Task) error) error {
+	// The matches are copied first so that fn may call back into the repository.
+	tasks, _ := r.GetAllTasks(c, filter)
+	for _, task := range tasks {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryTaskRepo) GetTaskByExternalID(c context.Context, externalID string) (*domain.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, id := range r.order {
+		if task := r.tasks[id]; task.ExternalID == externalID && visible(c, task) {
+			return copyTask(task), nil
+		}
+	}
+	return nil, domain.ErrTaskNotFound
+}
+
+func (r *InMemoryTaskRepo) GetTaskStats(c context.Context, filter domain.TaskFilter, params domain.TaskStatsParams) (*domain.TaskStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return domain.ComputeTaskStats(r.matching(c, filter), params), nil
+}
+
+// UpdateTask changes the same fields as TaskRepo.UpdateTask.
+func (r *InMemoryTaskRepo) UpdateTask(c context.Context, id primitive.ObjectID, updatedTask *domain.Task) (*domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.get(c, id)
+	if !ok {
+		return nil, domain.ErrTaskNotFound
+	}
+	update := copyTask(updatedTask)
+	task.Title = update.Title
+	task.Description = update.Description
+	task.DueDate = update.DueDate
+	task.Status = update.Status
+	task.Tags = update.Tags
+	task.CompletedAt = update.CompletedAt
+	return copyTask(task), nil
+}
+
+func (r *InMemoryTaskRepo) DeleteTask(c context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.get(c, id); !ok {
+		return domain.ErrTaskNotFound
+	}
+	delete(r.tasks, id)
+	r.order = slices.DeleteFunc(r.order, func(other primitive.ObjectID) bool { return other == id })
+	return nil
+}
+
+func (r *InMemoryTaskRepo) AddBlocker(c context.Context, taskID, blockerID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.get(c, taskID)
+	if !ok {
+		return domain.ErrTaskNotFound
+	}
+	if !slices.Contains(task.BlockedBy, blockerID) {
+		task.BlockedBy = append(task.BlockedBy, blockerID)
+	}
+	return nil
+}
+
+func (r *InMemoryTaskRepo) RemoveBlocker(c context.Context, taskID, blockerID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.get(c, taskID)
+	if !ok {
+		return domain.ErrTaskNotFound
+	}
+	task.BlockedBy = slices.DeleteFunc(task.BlockedBy, func(id primitive.ObjectID) bool { return id == blockerID })
+	return nil
+}
+
+func (r *InMemoryTaskRepo) RemoveBlockerFromAll(c context.Context, blockerID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, task := range r.tasks {
+		if !visible(c, task) {
+			continue
+		}
+		task.BlockedBy = slices.DeleteFunc(task.BlockedBy, func(id primitive.ObjectID) bool { return id == blockerID })
+	}
+	return nil
+}
+
+func (r *InMemoryTaskRepo) AddWatcher(c context.Context, taskID primitive.ObjectID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.get(c, taskID)
+	if !ok {
+		return domain.ErrTaskNotFound
+	}
+	if !slices.Contains(task.Watchers, userID) {
+		task.Watchers = append(task.Watchers, userID)
+	}
+	return nil
+}
+
+func (r *InMemoryTaskRepo) RemoveWatcher(c context.Context, taskID primitive.ObjectID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.get(c, taskID)
+	if !ok {
+		return domain.ErrTaskNotFound
+	}
+	task.Watchers = slices.DeleteFunc(task.Watchers, func(id string) bool { return id == userID })
+	return nil
+}
+
+func (r *InMemoryTaskRepo) RemoveWatcherFromAll(c context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, task := range r.tasks {
+		if !visible(c, task) {
+			continue
+		}
+		task.Watchers = slices.DeleteFunc(task.Watchers, func(id string) bool { return id == userID })
+	}
+	return nil
+}
+
+// get returns the stored task if the caller may see it. The caller must hold the lock.
+func (r *InMemoryTaskRepo) get(c context.Context, id primitive.ObjectID) (*domain.Task, bool) {
+	task, ok := r.tasks[id]
+	if !ok || !visible(c, task) {
+		return nil, false
+	}
+	return task, true
+}
+
+// visible reports whether the task belongs to the caller's organization.
+func visible(c context.Context, task *domain.Task) bool {
+	return inScope(c, task.OrganizationID)
+}
+
+// matching returns copies of the tasks matching the filter. The caller must hold the lock.
+func (r *InMemoryTaskRepo) matching(c context.Context, filter domain.TaskFilter) []*domain.Task {
+	tasks := []*domain.Task{}
+	for _, id := range r.order {
+		if task := r.tasks[id]; visible(c, task) && filter.Matches(task) {
+			tasks = append(tasks, copyTask(task))
+		}
+	}
+	return tasks
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Ensure MongoTransactor implements the domain.Transactor interface
+var _ domain.Transactor = (*MongoTransactor)(nil)
+
+// MongoTransactor runs functions in MongoDB multi-document transactions. Transactions
+// need a replica set or sharded cluster; a standalone server reports
+// domain.ErrTransactionsUnsupported.
+type MongoTransactor struct {
+	client *mongo.Client
+}
+
+func NewMongoTransactor(client *mongo.Client) *MongoTransactor {
+	return &MongoTransactor{
+		client: client,
+	}
+}
+
+// WithinTransaction may call fn more than once if the transaction hits a transient
+// error, so fn must not keep state between calls. Called with the context of a running
+// transaction, it joins that transaction instead of starting another one.
+func (t *MongoTransactor) WithinTransaction(c context.Context, fn func(c context.Context) error) error {
+	if mongo.SessionFromContext(c) != nil {
+		return fn(c)
+	}
+	session, err := t.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("repository: failed to start session: %w", err)
+	}
+	defer session.EndSession(c)
+
+	_, err = session.WithTransaction(c, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	if err != nil {
+		var cmdErr mongo.CommandError
+		// IllegalOperation: "Transaction numbers are only allowed on a replica set member or mongos"
+		if errors.As(err, &cmdErr) && cmdErr.Code == 20 {
+			return fmt.Errorf("%w: %s", domain.ErrTransactionsUnsupported, cmdErr.Message)
+		}
+		return err
+	}
+	return nil
+}
+
+// SupportsTransactions reports whether the server the client is connected to can run
+// transactions, i.e. whether it is a replica set member or a mongos router.
+func SupportsTransactions(c context.Context, client *mongo.Client) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(c, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, fmt.Errorf("repository: failed to query server topology: %w", err)
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
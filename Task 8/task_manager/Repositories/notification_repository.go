@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Ensure NotificationRepo implements the domain.NotificationRepository interface
+var _ domain.NotificationRepository = (*NotificationRepo)(nil)
+
+type NotificationRepo struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDBNotificationRepository(col *mongo.Collection) *NotificationRepo {
+	return &NotificationRepo{
+		collection: col,
+	}
+}
+
+// EnsureIndexes creates the index inboxes are read with and the unique index on keys.
+func (nr *NotificationRepo) EnsureIndexes(c context.Context) error {
+	indexModels := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
+	}
+	if _, err := nr.collection.Indexes().CreateMany(c, indexModels); err != nil {
+		return fmt.Errorf("repository: failed to create notification indexes: %w", err)
+	}
+	return nil
+}
+
+func (nr *NotificationRepo) CreateNotification(c context.Context, notification *domain.Notification) (*domain.Notification, error) {
+	organizationID, err := inOrganization(c, notification.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	notification.OrganizationID = organizationID
+	result, err := nr.collection.InsertOne(c, notification)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, domain.ErrNotificationExists
+		}
+		return nil, fmt.Errorf("repository: failed to insert notification: %w", err)
+	}
+
+	insertedID, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("repository: inserted ID is not of type ObjectID: %T", result.InsertedID)
+	}
+	notification.Id = insertedID
+
+	return notification, nil
+}
+
+func (nr *NotificationRepo) GetNotifications(c context.Context, userID string, query domain.NotificationQuery) ([]*domain.Notification, int64, error) {
+	filter := scoped(c, bson.M{"user_id": userID})
+	if query.UnreadOnly {
+		filter["read_at"] = bson.M{"$exists": false}
+	}
+	total, err := nr.collection.CountDocuments(c, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("repository: failed to count notifications: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(query.Offset)).
+		SetLimit(int64(query.Limit))
+	cursor, err := nr.collection.Find(c, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("repository: failed to retrieve notifications cursor: %w", err)
+	}
+	defer cursor.Close(c)
+
+	notifications := []*domain.Notification{}
+	if err = cursor.All(c, &notifications); err != nil {
+		return nil, 0, fmt.Errorf("repository: failed to decode notifications from cursor: %w", err)
+	}
+	return notifications, total, nil
+}
+
+func (nr *NotificationRepo) CountUnread(c context.Context, userID string) (int64, error) {
+	count, err := nr.collection.CountDocuments(c, scoped(c, bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}}))
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+func (nr *NotificationRepo) MarkRead(c context.Context, userID string, id primitive.ObjectID, at time.Time) error {
+	filter := scoped(c, bson.M{"_id": id, "user_id": userID})
+	// $min keeps the first read time when a notification is marked read twice.
+	res, err := nr.collection.UpdateOne(c, filter, bson.M{"$min": bson.M{"read_at": at}})
+	if err != nil {
+		return fmt.Errorf("repository: failed to mark notification '%s' read: %w", id.Hex(), err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (nr *NotificationRepo) MarkAllRead(c context.Context, userID string, at time.Time) (int64, error) {
+	filter := scoped(c, bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}})
+	res, err := nr.collection.UpdateMany(c, filter, bson.M{"$set": bson.M{"read_at": at}})
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to mark notifications read: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
@@ -0,0 +1,117 @@
+package repositories_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"A2SV_ProjectPhase/Task8/TaskManager/Repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+//===========================================================================
+// NotificationRepo Integration Test Suite
+//===========================================================================
+
+type NotificationRepoSuite struct {
+	suite.Suite
+	coll *mongo.Collection
+	repo *repositories.NotificationRepo
+}
+
+// TestNotificationRepoSuite is the entry point for the test suite
+func TestNotificationRepoSuite(t *testing.T) {
+	if testMongoClient == nil {
+		t.Skip("Skipping integration tests: MongoDB connection not available.")
+	}
+	suite.Run(t, new(NotificationRepoSuite))
+}
+
+// SetupSuite runs once for the entire suite.
+func (s *NotificationRepoSuite) SetupSuite() {
+	s.coll = testMongoClient.Database("test_learning_phase").Collection("notification8")
+}
+
+// SetupTest runs before EACH test method.
+func (s *NotificationRepoSuite) SetupTest() {
+	_, err := s.coll.DeleteMany(context.Background(), bson.D{})
+	s.Require().NoError(err, "Failed to clean notification collection before test")
+	s.repo = repositories.NewMongoDBNotificationRepository(s.coll)
+	s.Require().NoError(s.repo.EnsureIndexes(context.Background()))
+}
+
+// TestInbox tests paging through an inbox and marking notifications read.
+func (s *NotificationRepoSuite) TestInbox() {
+	ctx := domain.ContextWithOrganization(context.Background(), primitive.NewObjectID())
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	for i, title := range []string{"First", "Second", "Third"} {
+		_, err := s.repo.CreateNotification(ctx, &domain.Notification{
+			UserID:    "alice",
+			Type:      domain.NotificationTaskUpdated,
+			TaskID:    primitive.NewObjectID(),
+			TaskTitle: title,
+			CreatedAt: now.Add(time.Duration(i) * time.Second),
+		})
+		s.Require().NoError(err)
+	}
+	_, err := s.repo.CreateNotification(ctx, &domain.Notification{UserID: "bob", Type: domain.NotificationTaskDeleted, CreatedAt: now})
+	s.Require().NoError(err)
+
+	page, total, err := s.repo.GetNotifications(ctx, "alice", domain.NotificationQuery{Limit: 2})
+	s.Require().NoError(err)
+	s.Equal(int64(3), total)
+	s.Require().Len(page, 2)
+	s.Equal("Third", page[0].TaskTitle, "Newest first")
+	s.Equal("Second", page[1].TaskTitle)
+
+	page, _, err = s.repo.GetNotifications(ctx, "alice", domain.NotificationQuery{Limit: 2, Offset: 2})
+	s.Require().NoError(err)
+	s.Require().Len(page, 1)
+	s.Equal("First", page[0].TaskTitle)
+
+	s.Require().NoError(s.repo.MarkRead(ctx, "alice", page[0].Id, now))
+	s.Require().NoError(s.repo.MarkRead(ctx, "alice", page[0].Id, now.Add(time.Hour)))
+	s.ErrorIs(s.repo.MarkRead(ctx, "bob", page[0].Id, now), domain.ErrNotificationNotFound, "Users can only mark their own notifications")
+	unread, err := s.repo.CountUnread(ctx, "alice")
+	s.Require().NoError(err)
+	s.Equal(int64(2), unread)
+	page, total, err = s.repo.GetNotifications(ctx, "alice", domain.NotificationQuery{UnreadOnly: true, Limit: 10})
+	s.Require().NoError(err)
+	s.Equal(int64(2), total)
+	s.Len(page, 2)
+
+	marked, err := s.repo.MarkAllRead(ctx, "alice", now.Add(2*time.Hour))
+	s.Require().NoError(err)
+	s.Equal(int64(2), marked)
+	page, _, err = s.repo.GetNotifications(ctx, "alice", domain.NotificationQuery{Limit: 10})
+	s.Require().NoError(err)
+	s.Require().Len(page, 3)
+	s.Equal(now, page[2].ReadAt.UTC(), "Marking again keeps the first read time")
+
+	unread, err = s.repo.CountUnread(ctx, "bob")
+	s.Require().NoError(err)
+	s.Equal(int64(1), unread)
+	unread, err = s.repo.CountUnread(domain.ContextWithOrganization(context.Background(), primitive.NewObjectID()), "bob")
+	s.Require().NoError(err)
+	s.Zero(unread, "Other organizations do not see the notifications")
+}
+
+// TestKeysAreUnique tests that a keyed notification is only stored once.
+func (s *NotificationRepoSuite) TestKeysAreUnique() {
+	ctx := organizationContext()
+	notification := func() *domain.Notification {
+		return &domain.Notification{UserID: "alice", Type: domain.NotificationTaskOverdue, Key: "task.overdue:1", CreatedAt: time.Now()}
+	}
+	_, err := s.repo.CreateNotification(ctx, notification())
+	s.Require().NoError(err)
+
+	_, err = s.repo.CreateNotification(ctx, notification())
+	s.ErrorIs(err, domain.ErrNotificationExists)
+
+	_, err = s.repo.CreateNotification(ctx, &domain.Notification{UserID: "alice", Type: domain.NotificationTaskUpdated, CreatedAt: time.Now()})
+	s.NoError(err, "Notifications without a key are never duplicates")
+}
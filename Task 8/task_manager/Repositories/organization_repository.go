@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Ensure OrganizationRepo implements the domain.OrganizationRepository interface
+var _ domain.OrganizationRepository = (*OrganizationRepo)(nil)
+
+// OrganizationRepo stores the organizations themselves, which are not scoped: anyone
+// logging in may name any organization.
+type OrganizationRepo struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDBOrganizationRepository(col *mongo.Collection) *OrganizationRepo {
+	return &OrganizationRepo{
+		collection: col,
+	}
+}
+
+// EnsureIndexes creates the unique index on slugs.
+func (or *OrganizationRepo) EnsureIndexes(c context.Context) error {
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "slug", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := or.collection.Indexes().CreateOne(c, indexModel); err != nil {
+		return fmt.Errorf("repository: failed to create unique organization slug index: %w", err)
+	}
+	return nil
+}
+
+func (or *OrganizationRepo) CreateOrganization(c context.Context, organization *domain.Organization) (*domain.Organization, error) {
+	result, err := or.collection.InsertOne(c, organization)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, domain.ErrOrganizationSlugTaken
+		}
+		return nil, fmt.Errorf("repository: failed to insert organization: %w", err)
+	}
+
+	insertedID, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("repository: inserted ID is not of type ObjectID: %T", result.InsertedID)
+	}
+	organization.Id = insertedID
+
+	return organization, nil
+}
+
+func (or *OrganizationRepo) GetOrganizationById(c context.Context, id primitive.ObjectID) (*domain.Organization, error) {
+	var organization domain.Organization
+	err := or.collection.FindOne(c, bson.M{"_id": id}).Decode(&organization)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to find organization by ID '%s': %w", id.Hex(), err)
+	}
+	return &organization, nil
+}
+
+func (or *OrganizationRepo) GetOrganizationBySlug(c context.Context, slug string) (*domain.Organization, error) {
+	var organization domain.Organization
+	err := or.collection.FindOne(c, bson.M{"slug": slug}).Decode(&organization)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to find organization '%s': %w", slug, err)
+	}
+	return &organization, nil
+}
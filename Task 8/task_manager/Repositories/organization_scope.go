@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Every repository keeps organizations apart the same way: queries go through scoped,
+// and new documents take their organization from inOrganization. Calls without an
+// organization in the context see nothing, unless they are trusted internal calls
+// marked with domain.ContextWithSystemScope, which see every organization.
+
+// scoped restricts filter to the caller's organization. Without an organization or
+// the system scope, it matches no documents.
+func scoped(c context.Context, filter bson.M) bson.M {
+	if organizationID, ok := domain.OrganizationFromContext(c); ok {
+		filter["organization_id"] = organizationID
+	} else if !domain.IsSystemScope(c) {
+		filter["organization_id"] = bson.M{"$in": bson.A{}}
+	}
+	return filter
+}
+
+// inScope is scoped for the in-memory repositories: it reports whether a document of
+// the given organization is visible to the caller.
+func inScope(c context.Context, organizationID primitive.ObjectID) bool {
+	if callerOrganizationID, ok := domain.OrganizationFromContext(c); ok {
+		return organizationID == callerOrganizationID
+	}
+	return domain.IsSystemScope(c)
+}
+
+// inOrganization returns the organization a new document belongs to: the caller's, or
+// current for trusted internal calls, which set it themselves.
+func inOrganization(c context.Context, current primitive.ObjectID) (primitive.ObjectID, error) {
+	if organizationID, ok := domain.OrganizationFromContext(c); ok {
+		return organizationID, nil
+	}
+	if domain.IsSystemScope(c) && !current.IsZero() {
+		return current, nil
+	}
+	return primitive.NilObjectID, fmt.Errorf("repository: %w", domain.ErrNoOrganization)
+}
+
+// AdoptUnscopedDocuments moves documents written before organizations existed into the
+// given organization. It only touches documents without an organization, so it is safe
+// to run on every start.
+func AdoptUnscopedDocuments(c context.Context, organizationID primitive.ObjectID, collections ...*mongo.Collection) error {
+	if !domain.IsSystemScope(c) {
+		return fmt.Errorf("repository: moving documents between organizations requires the system scope: %w", domain.ErrForbidden)
+	}
+	for _, collection := range collections {
+		filter := bson.M{"organization_id": bson.M{"$exists": false}}
+		update := bson.M{"$set": bson.M{"organization_id": organizationID}}
+		if _, err := collection.UpdateMany(c, filter, update); err != nil {
+			return fmt.Errorf("repository: failed to move %s into organization '%s': %w", collection.Name(), organizationID.Hex(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Ensure ProjectRepo implements the domain.ProjectRepository interface
+var _ domain.ProjectRepository = (*ProjectRepo)(nil)
+
+type ProjectRepo struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDBProjectRepository(col *mongo.Collection) *ProjectRepo {
+	return &ProjectRepo{
+		collection: col,
+	}
+}
+
+func (pr *ProjectRepo) CreateProject(c context.Context, project *domain.Project) (*domain.Project, error) {
+	organizationID, err := inOrganization(c, project.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	project.OrganizationID = organizationID
+	result, err := pr.collection.InsertOne(c, project)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to insert project: %w", err)
+	}
+
+	insertedID, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("repository: inserted ID is not of type ObjectID: %T", result.InsertedID)
+	}
+	project.Id = insertedID
+
+	return project, nil
+}
+
+func (pr *ProjectRepo) GetProjectById(c context.Context, id primitive.ObjectID) (*domain.Project, error) {
+	var project domain.Project
+	err := pr.collection.FindOne(c, scoped(c, bson.M{"_id": id})).Decode(&project)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to find project by ID '%s': %w", id.Hex(), err)
+	}
+	return &project, nil
+}
+
+func (pr *ProjectRepo) GetProjectsForMember(c context.Context, userID string) ([]*domain.Project, error) {
+	cursor, err := pr.collection.Find(c, scoped(c, bson.M{"members.user_id": userID}))
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to retrieve projects cursor: %w", err)
+	}
+	defer cursor.Close(c)
+
+	projects := []*domain.Project{}
+	if err = cursor.All(c, &projects); err != nil {
+		return nil, fmt.Errorf("repository: failed to decode projects from cursor: %w", err)
+	}
+	return projects, nil
+}
+
+func (pr *ProjectRepo) UpdateProject(c context.Context, id primitive.ObjectID, project *domain.Project) (*domain.Project, error) {
+	updateDoc := bson.M{"$set": bson.M{
+		"name":        project.Name,
+		"description": project.Description,
+		"members":     project.Members,
+	}}
+
+	var result domain.Project
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := pr.collection.FindOneAndUpdate(c, scoped(c, bson.M{"_id": id}), updateDoc, opts).Decode(&result)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to update project by ID '%s': %w", id.Hex(), err)
+	}
+	return &result, nil
+}
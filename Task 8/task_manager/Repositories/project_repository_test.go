@@ -0,0 +1,90 @@
+package repositories_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"A2SV_ProjectPhase/Task8/TaskManager/Repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+//===========================================================================
+// ProjectRepo Integration Test Suite
+//===========================================================================
+
+type ProjectRepoSuite struct {
+	suite.Suite
+	coll *mongo.Collection
+	repo domain.ProjectRepository
+}
+
+// TestProjectRepoSuite is the entry point for the test suite
+func TestProjectRepoSuite(t *testing.T) {
+	if testMongoClient == nil {
+		t.Skip("Skipping integration tests: MongoDB connection not available.")
+	}
+	suite.Run(t, new(ProjectRepoSuite))
+}
+
+// SetupSuite runs once for the entire suite.
+func (s *ProjectRepoSuite) SetupSuite() {
+	s.coll = testMongoClient.Database("test_learning_phase").Collection("project8")
+}
+
+// SetupTest runs before EACH test method.
+func (s *ProjectRepoSuite) SetupTest() {
+	_, err := s.coll.DeleteMany(context.Background(), bson.D{})
+	s.Require().NoError(err, "Failed to clean project collection before test")
+	s.repo = repositories.NewMongoDBProjectRepository(s.coll)
+}
+
+// TestCreateAndFindForMember tests storing a project and finding it by membership.
+func (s *ProjectRepoSuite) TestCreateAndFindForMember() {
+	ctx := organizationContext()
+	project, err := domain.NewProject("Website", "", "owner-1", time.Now())
+	s.Require().NoError(err)
+
+	created, err := s.repo.CreateProject(ctx, project)
+	s.Require().NoError(err)
+	s.False(created.Id.IsZero())
+
+	found, err := s.repo.GetProjectById(ctx, created.Id)
+	s.Require().NoError(err)
+	s.Equal("Website", found.Name)
+
+	projects, err := s.repo.GetProjectsForMember(ctx, "owner-1")
+	s.Require().NoError(err)
+	s.Len(projects, 1)
+
+	projects, err = s.repo.GetProjectsForMember(ctx, "stranger")
+	s.Require().NoError(err)
+	s.Empty(projects)
+
+	_, err = s.repo.GetProjectById(ctx, primitive.NewObjectID())
+	s.ErrorIs(err, domain.ErrProjectNotFound)
+}
+
+// TestUpdateProject tests persisting membership changes.
+func (s *ProjectRepoSuite) TestUpdateProject() {
+	ctx := organizationContext()
+	project, _ := domain.NewProject("Website", "", "owner-1", time.Now())
+	created, err := s.repo.CreateProject(ctx, project)
+	s.Require().NoError(err)
+
+	s.Require().NoError(created.SetMember("editor-1", domain.ProjectEditor))
+	updated, err := s.repo.UpdateProject(ctx, created.Id, created)
+	s.Require().NoError(err)
+	s.Len(updated.Members, 2)
+
+	projects, err := s.repo.GetProjectsForMember(ctx, "editor-1")
+	s.Require().NoError(err)
+	s.Len(projects, 1)
+
+	_, err = s.repo.UpdateProject(ctx, primitive.NewObjectID(), created)
+	s.ErrorIs(err, domain.ErrProjectNotFound)
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Ensure RevisionRepo implements the domain.TaskRevisionRepository interface
+var _ domain.TaskRevisionRepository = (*RevisionRepo)(nil)
+
+// RevisionRepo stores task revisions. Revisions are never modified, so there is no update method.
+type RevisionRepo struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDBRevisionRepository(col *mongo.Collection) *RevisionRepo {
+	return &RevisionRepo{
+		collection: col,
+	}
+}
+
+func (rr *RevisionRepo) CreateRevision(c context.Context, revision *domain.TaskRevision) (*domain.TaskRevision, error) {
+	organizationID, err := inOrganization(c, revision.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	revision.OrganizationID = organizationID
+	result, err := rr.collection.InsertOne(c, revision)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to insert revision: %w", err)
+	}
+
+	insertedID, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("repository: inserted ID is not of type ObjectID: %T", result.InsertedID)
+	}
+	revision.Id = insertedID
+
+	return revision, nil
+}
+
+func (rr *RevisionRepo) GetRevisionById(c context.Context, id primitive.ObjectID) (*domain.TaskRevision, error) {
+	var revision domain.TaskRevision
+	err := rr.collection.FindOne(c, scoped(c, bson.M{"_id": id})).Decode(&revision)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrRevisionNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to find revision by ID '%s': %w", id.Hex(), err)
+	}
+	return &revision, nil
+}
+
+func (rr *RevisionRepo) GetRevisionsByTask(c context.Context, taskID primitive.ObjectID) ([]*domain.TaskRevision, error) {
+	// ObjectIDs start with a timestamp, so sorting by _id breaks ties between revisions
+	// created within the same millisecond.
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := rr.collection.Find(c, scoped(c, bson.M{"task_id": taskID}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to retrieve revisions cursor: %w", err)
+	}
+	defer cursor.Close(c)
+
+	revisions := []*domain.TaskRevision{}
+	if err = cursor.All(c, &revisions); err != nil {
+		return nil, fmt.Errorf("repository: failed to decode revisions from cursor: %w", err)
+	}
+	return revisions, nil
+}
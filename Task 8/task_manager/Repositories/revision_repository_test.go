@@ -0,0 +1,69 @@
+package repositories_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"A2SV_ProjectPhase/Task8/TaskManager/Repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+//===========================================================================
+// RevisionRepo Integration Test Suite
+//===========================================================================
+
+type RevisionRepoSuite struct {
+	suite.Suite
+	coll *mongo.Collection
+	repo domain.TaskRevisionRepository
+}
+
+// TestRevisionRepoSuite is the entry point for the test suite
+func TestRevisionRepoSuite(t *testing.T) {
+	if testMongoClient == nil {
+		t.Skip("Skipping integration tests: MongoDB connection not available.")
+	}
+	suite.Run(t, new(RevisionRepoSuite))
+}
+
+// SetupSuite runs once for the entire suite.
+func (s *RevisionRepoSuite) SetupSuite() {
+	s.coll = testMongoClient.Database("test_learning_phase").Collection("revision8")
+}
+
+// SetupTest runs before EACH test method.
+func (s *RevisionRepoSuite) SetupTest() {
+	_, err := s.coll.DeleteMany(context.Background(), bson.D{})
+	s.Require().NoError(err, "Failed to clean revision collection before test")
+	s.repo = repositories.NewMongoDBRevisionRepository(s.coll)
+}
+
+// TestRevisionLifecycle tests storing, listing in order and deleting revisions.
+func (s *RevisionRepoSuite) TestRevisionLifecycle() {
+	ctx := organizationContext()
+	taskID := primitive.NewObjectID()
+	now := time.Now().Truncate(time.Millisecond)
+	for i, title := range []string{"First", "Second", "Third"} {
+		_, err := s.repo.CreateRevision(ctx, &domain.TaskRevision{
+			TaskID:    taskID,
+			Snapshot:  domain.TaskSnapshot{Title: title},
+			CreatedAt: now.Add(time.Duration(i) * time.Second),
+		})
+		s.Require().NoError(err)
+	}
+
+	revisions, err := s.repo.GetRevisionsByTask(ctx, taskID)
+	s.Require().NoError(err)
+	s.Require().Len(revisions, 3)
+	s.Equal("First", revisions[0].Snapshot.Title)
+	s.Equal("Third", revisions[2].Snapshot.Title)
+
+	found, err := s.repo.GetRevisionById(ctx, revisions[1].Id)
+	s.Require().NoError(err)
+	s.Equal("Second", found.Snapshot.Title)
+}
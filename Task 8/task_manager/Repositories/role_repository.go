@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Ensure RoleRepo implements the domain.RoleRepository interface
+var _ domain.RoleRepository = (*RoleRepo)(nil)
+
+type RoleRepo struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDBRoleRepository(col *mongo.Collection) *RoleRepo {
+	return &RoleRepo{
+		collection: col,
+	}
+}
+
+// EnsureIndexes creates the unique index on role names within an organization.
+func (rr *RoleRepo) EnsureIndexes(c context.Context) error {
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "organization_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := rr.collection.Indexes().CreateOne(c, indexModel); err != nil {
+		return fmt.Errorf("repository: failed to create unique role name index: %w", err)
+	}
+	return nil
+}
+
+// AdoptUnscopedRoles moves role definitions written before organizations existed into
+// the given organization. Those used the role name as their _id, which is copied into
+// the name field.
+func (rr *RoleRepo) AdoptUnscopedRoles(c context.Context, organizationID primitive.ObjectID) error {
+	if !domain.IsSystemScope(c) {
+		return fmt.Errorf("repository: moving roles between organizations requires the system scope: %w", domain.ErrForbidden)
+	}
+	filter := bson.M{"organization_id": bson.M{"$exists": false}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{"organization_id": organizationID, "name": "$_id"}}}}
+	if _, err := rr.collection.UpdateMany(c, filter, update); err != nil {
+		return fmt.Errorf("repository: failed to move roles into organization '%s': %w", organizationID.Hex(), err)
+	}
+	return nil
+}
+
+func (rr *RoleRepo) GetRole(c context.Context, name domain.UserRole) (*domain.RoleDefinition, error) {
+	var role domain.RoleDefinition
+	err := rr.collection.FindOne(c, scoped(c, bson.M{"name": name})).Decode(&role)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to find role '%s': %w", name, err)
+	}
+	return &role, nil
+}
+
+func (rr *RoleRepo) GetAllRoles(c context.Context) ([]*domain.RoleDefinition, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := rr.collection.Find(c, scoped(c, bson.M{}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to retrieve roles cursor: %w", err)
+	}
+	defer cursor.Close(c)
+
+	roles := []*domain.RoleDefinition{}
+	if err = cursor.All(c, &roles); err != nil {
+		return nil, fmt.Errorf("repository: failed to decode roles from cursor: %w", err)
+	}
+	return roles, nil
+}
+
+func (rr *RoleRepo) UpsertRole(c context.Context, role *domain.RoleDefinition) (*domain.RoleDefinition, error) {
+	// The filter's fields are written into upserted documents, so new definitions get
+	// the name and organization.
+	organizationID, err := inOrganization(c, role.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M{"name": role.Name, "organization_id": organizationID}
+	set := bson.M{"permissions": role.Permissions}
+	if role.SeededPermissions != nil {
+		set["seeded_permissions"] = role.SeededPermissions
+	}
+	update := bson.M{"$set": set}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var result domain.RoleDefinition
+	err = rr.collection.FindOneAndUpdate(c, filter, update, opts).Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to upsert role '%s': %w", role.Name, err)
+	}
+	return &result, nil
+}
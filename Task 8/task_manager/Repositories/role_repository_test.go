@@ -0,0 +1,75 @@
+package repositories_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"A2SV_ProjectPhase/Task8/TaskManager/Repositories"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+//===========================================================================
+// RoleRepo Integration Test Suite
+//===========================================================================
+
+type RoleRepoSuite struct {
+	suite.Suite
+	coll *mongo.Collection
+	repo domain.RoleRepository
+}
+
+// TestRoleRepoSuite is the entry point for the test suite
+func TestRoleRepoSuite(t *testing.T) {
+	if testMongoClient == nil {
+		t.Skip("Skipping integration tests: MongoDB connection not available.")
+	}
+	suite.Run(t, new(RoleRepoSuite))
+}
+
+// SetupSuite runs once for the entire suite.
+func (s *RoleRepoSuite) SetupSuite() {
+	s.coll = testMongoClient.Database("test_learning_phase").Collection("role8")
+}
+
+// SetupTest runs before EACH test method.
+func (s *RoleRepoSuite) SetupTest() {
+	_, err := s.coll.DeleteMany(context.Background(), bson.D{})
+	s.Require().NoError(err, "Failed to clean role collection before test")
+	s.repo = repositories.NewMongoDBRoleRepository(s.coll)
+}
+
+// TestUpsertAndGetRole tests creating, replacing and reading role definitions.
+func (s *RoleRepoSuite) TestUpsertAndGetRole() {
+	ctx := organizationContext()
+
+	_, err := s.repo.UpsertRole(ctx, &domain.RoleDefinition{Name: "Editor", Permissions: []domain.Permission{domain.PermTasksRead}})
+	s.Require().NoError(err)
+
+	updated, err := s.repo.UpsertRole(ctx, &domain.RoleDefinition{Name: "Editor", Permissions: []domain.Permission{domain.PermTasksRead, domain.PermTasksUpdate}})
+	s.Require().NoError(err)
+	s.Equal([]domain.Permission{domain.PermTasksRead, domain.PermTasksUpdate}, updated.Permissions)
+
+	found, err := s.repo.GetRole(ctx, "Editor")
+	s.Require().NoError(err)
+	s.True(found.Has(domain.PermTasksUpdate))
+
+	all, err := s.repo.GetAllRoles(ctx)
+	s.Require().NoError(err)
+	s.Len(all, 1, "Upserting an existing role must not create a duplicate")
+
+	// Seeded permissions are only written when they are set.
+	_, err = s.repo.UpsertRole(ctx, &domain.RoleDefinition{Name: "Editor", Permissions: []domain.Permission{domain.PermTasksRead}, SeededPermissions: []domain.Permission{domain.PermTasksRead}})
+	s.Require().NoError(err)
+	updated, err = s.repo.UpsertRole(ctx, &domain.RoleDefinition{Name: "Editor", Permissions: []domain.Permission{domain.PermTasksUpdate}})
+	s.Require().NoError(err)
+	s.Equal([]domain.Permission{domain.PermTasksRead}, updated.SeededPermissions)
+}
+
+// TestGetRole_NotFound tests looking up an undefined role.
+func (s *RoleRepoSuite) TestGetRole_NotFound() {
+	_, err := s.repo.GetRole(organizationContext(), "Ghost")
+	s.ErrorIs(err, domain.ErrRoleNotFound)
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Ensure ServiceAccountRepo implements the domain.ServiceAccountRepository interface
+var _ domain.ServiceAccountRepository = (*ServiceAccountRepo)(nil)
+
+type ServiceAccountRepo struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDBServiceAccountRepository(col *mongo.Collection) *ServiceAccountRepo {
+	return &ServiceAccountRepo{
+		collection: col,
+	}
+}
+
+func (sr *ServiceAccountRepo) CreateServiceAccount(c context.Context, account *domain.ServiceAccount) (*domain.ServiceAccount, error) {
+	organizationID, err := inOrganization(c, account.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	account.OrganizationID = organizationID
+	result, err := sr.collection.InsertOne(c, account)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to insert service account: %w", err)
+	}
+
+	insertedID, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("repository: inserted ID is not of type ObjectID: %T", result.InsertedID)
+	}
+	account.Id = insertedID
+
+	return account, nil
+}
+
+func (sr *ServiceAccountRepo) GetServiceAccountById(c context.Context, id primitive.ObjectID) (*domain.ServiceAccount, error) {
+	var account domain.ServiceAccount
+	err := sr.collection.FindOne(c, scoped(c, bson.M{"_id": id})).Decode(&account)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrServiceAccountNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to find service account by ID '%s': %w", id.Hex(), err)
+	}
+	return &account, nil
+}
+
+func (sr *ServiceAccountRepo) GetAllServiceAccounts(c context.Context) ([]*domain.ServiceAccount, error) {
+	cursor, err := sr.collection.Find(c, scoped(c, bson.M{}))
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to retrieve service accounts cursor: %w", err)
+	}
+	defer cursor.Close(c)
+
+	accounts := []*domain.ServiceAccount{}
+	if err = cursor.All(c, &accounts); err != nil {
+		return nil, fmt.Errorf("repository: failed to decode service accounts from cursor: %w", err)
+	}
+	return accounts, nil
+}
+
+func (sr *ServiceAccountRepo) DeleteServiceAccount(c context.Context, id primitive.ObjectID) error {
+	res, err := sr.collection.DeleteOne(c, scoped(c, bson.M{"_id": id}))
+	if err != nil {
+		return fmt.Errorf("repository: failed to delete service account by ID '%s': %w", id.Hex(), err)
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrServiceAccountNotFound
+	}
+	return nil
+}
@@ -26,6 +26,11 @@ func NewMongoDBTaskRepository(col *mongo.Collection) *TaskRepo {
 }
 
 func (tr *TaskRepo) CreateTask(c context.Context, task *domain.Task) (*domain.Task, error) {
+	organizationID, err := inOrganization(c, task.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	task.OrganizationID = organizationID
 	result, err := tr.collection.InsertOne(c, task)
 	if err != nil {
 		return nil, fmt.Errorf("repository: failed to insert task: %w", err)
@@ -42,7 +47,7 @@ func (tr *TaskRepo) CreateTask(c context.Context, task *domain.Task) (*domain.Ta
 
 func (tr *TaskRepo) GetTaskById(c context.Context, id primitive.ObjectID) (*domain.Task, error) {
 	var task domain.Task
-	filter := bson.M{"_id": id}
+	filter := scoped(c, bson.M{"_id": id})
 	err := tr.collection.FindOne(c, filter).Decode(&task)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
@@ -53,8 +58,8 @@ func (tr *TaskRepo) GetTaskById(c context.Context, id primitive.ObjectID) (*doma
 	return &task, nil
 }
 
-func (tr *TaskRepo) GetAllTasks(c context.Context) ([]*domain.Task, error) {
-	cursor, err := tr.collection.Find(c, bson.D{})
+func (tr *TaskRepo) GetAllTasks(c context.Context, filter domain.TaskFilter) ([]*domain.Task, error) {
+	cursor, err := tr.collection.Find(c, scoped(c, taskFilterToBSON(filter)))
 	if err != nil {
 		return nil, fmt.Errorf("repository: failed to retrieve tasks cursor: %w", err)
 	}
@@ -67,15 +72,52 @@ func (tr *TaskRepo) GetAllTasks(c context.Context) ([]*domain.Task, error) {
 	return tasks, nil
 }
 
+func (tr *TaskRepo) StreamTasks(c context.Context, filter domain.TaskFilter, fn func(task *domain.Task) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := tr.collection.Find(c, scoped(c, taskFilterToBSON(filter)), opts)
+	if err != nil {
+		return fmt.Errorf("repository: failed to retrieve tasks cursor: %w", err)
+	}
+	defer cursor.Close(c)
+
+	for cursor.Next(c) {
+		var task domain.Task
+		if err := cursor.Decode(&task); err != nil {
+			return fmt.Errorf("repository: failed to decode task from cursor: %w", err)
+		}
+		if err := fn(&task); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("repository: failed to iterate tasks: %w", err)
+	}
+	return nil
+}
+
+func (tr *TaskRepo) GetTaskByExternalID(c context.Context, externalID string) (*domain.Task, error) {
+	var task domain.Task
+	err := tr.collection.FindOne(c, scoped(c, bson.M{"external_id": externalID})).Decode(&task)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to find task by external ID '%s': %w", externalID, err)
+	}
+	return &task, nil
+}
+
 func (tr *TaskRepo) UpdateTask(c context.Context, id primitive.ObjectID, updatedTask *domain.Task) (*domain.Task, error) {
 	updateDoc := bson.M{"$set": bson.M{
-		"title":       updatedTask.Title,
-		"description": updatedTask.Description,
-		"duedate":     updatedTask.DueDate,
-		"status":      updatedTask.Status,
+		"title":        updatedTask.Title,
+		"description":  updatedTask.Description,
+		"duedate":      updatedTask.DueDate,
+		"status":       updatedTask.Status,
+		"tags":         updatedTask.Tags,
+		"completed_at": updatedTask.CompletedAt,
 	}}
 
-	filter := bson.M{"_id": id}
+	filter := scoped(c, bson.M{"_id": id})
 	var result domain.Task
 
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After) // Get the document AFTER the update
@@ -91,7 +133,7 @@ func (tr *TaskRepo) UpdateTask(c context.Context, id primitive.ObjectID, updated
 }
 
 func (tr *TaskRepo) DeleteTask(c context.Context, id primitive.ObjectID) error {
-	filter := bson.M{"_id": id}
+	filter := scoped(c, bson.M{"_id": id})
 	res, err := tr.collection.DeleteOne(c, filter)
 	if err != nil {
 		return fmt.Errorf("repository: failed to delete task by ID '%s': %w", id.Hex(), err)
@@ -101,3 +143,191 @@ func (tr *TaskRepo) DeleteTask(c context.Context, id primitive.ObjectID) error {
 	}
 	return nil
 }
+
+func (tr *TaskRepo) AddBlocker(c context.Context, taskID, blockerID primitive.ObjectID) error {
+	res, err := tr.collection.UpdateOne(c, scoped(c, bson.M{"_id": taskID}), bson.M{"$addToSet": bson.M{"blocked_by": blockerID}})
+	if err != nil {
+		return fmt.Errorf("repository: failed to add blocker to task '%s': %w", taskID.Hex(), err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+func (tr *TaskRepo) RemoveBlocker(c context.Context, taskID, blockerID primitive.ObjectID) error {
+	res, err := tr.collection.UpdateOne(c, scoped(c, bson.M{"_id": taskID}), bson.M{"$pull": bson.M{"blocked_by": blockerID}})
+	if err != nil {
+		return fmt.Errorf("repository: failed to remove blocker from task '%s': %w", taskID.Hex(), err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+func (tr *TaskRepo) RemoveBlockerFromAll(c context.Context, blockerID primitive.ObjectID) error {
+	_, err := tr.collection.UpdateMany(c, scoped(c, bson.M{"blocked_by": blockerID}), bson.M{"$pull": bson.M{"blocked_by": blockerID}})
+	if err != nil {
+		return fmt.Errorf("repository: failed to unlink blocker '%s': %w", blockerID.Hex(), err)
+	}
+	return nil
+}
+
+func (tr *TaskRepo) AddWatcher(c context.Context, taskID primitive.ObjectID, userID string) error {
+	res, err := tr.collection.UpdateOne(c, scoped(c, bson.M{"_id": taskID}), bson.M{"$addToSet": bson.M{"watchers": userID}})
+	if err != nil {
+		return fmt.Errorf("repository: failed to add watcher to task '%s': %w", taskID.Hex(), err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+func (tr *TaskRepo) RemoveWatcher(c context.Context, taskID primitive.ObjectID, userID string) error {
+	res, err := tr.collection.UpdateOne(c, scoped(c, bson.M{"_id": taskID}), bson.M{"$pull": bson.M{"watchers": userID}})
+	if err != nil {
+		return fmt.Errorf("repository: failed to remove watcher from task '%s': %w", taskID.Hex(), err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+func (tr *TaskRepo) RemoveWatcherFromAll(c context.Context, userID string) error {
+	_, err := tr.collection.UpdateMany(c, scoped(c, bson.M{"watchers": userID}), bson.M{"$pull": bson.M{"watchers": userID}})
+	if err != nil {
+		return fmt.Errorf("repository: failed to remove watcher '%s' from tasks: %w", userID, err)
+	}
+	return nil
+}
+
+// GetTaskStats computes the statistics in a single aggregation, using one $facet
+// branch per figure. The results match domain.ComputeTaskStats.
+func (tr *TaskRepo) GetTaskStats(c context.Context, filter domain.TaskFilter, params domain.TaskStatsParams) (*domain.TaskStats, error) {
+	unfinished := bson.M{"$ne": domain.Done}
+	// domain.OverdueAt keeps a plain date due until its day ends. The only such date
+	// that has started but not yet ended is today's midnight, so leave that one out.
+	today := domain.StartOfDay(params.Now.In(params.Location))
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: scoped(c, taskFilterToBSON(filter))}},
+		{{Key: "$facet", Value: bson.M{
+			"by_status": bson.A{
+				bson.M{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}},
+			},
+			"overdue": bson.A{
+				bson.M{"$match": bson.M{"status": unfinished, "duedate": bson.M{"$lte": params.Now, "$ne": today}}},
+				bson.M{"$count": "count"},
+			},
+			"due_this_week": bson.A{
+				bson.M{"$match": bson.M{"status": unfinished, "duedate": bson.M{"$gte": params.WeekStart, "$lt": params.WeekEnd}}},
+				bson.M{"$count": "count"},
+			},
+			"completions": bson.A{
+				bson.M{"$match": bson.M{"completed_at": bson.M{"$gte": params.From, "$lt": params.To}}},
+				bson.M{"$group": bson.M{
+					"_id": bson.M{"$dateToString": bson.M{
+						"format":   "%Y-%m-%d",
+						"date":     "$completed_at",
+						"timezone": params.Location.String(),
+					}},
+					"count": bson.M{"$sum": 1},
+				}},
+			},
+		}}},
+	}
+
+	cursor, err := tr.collection.Aggregate(c, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to aggregate task stats: %w", err)
+	}
+	defer cursor.Close(c)
+
+	type bucket struct {
+		Key   string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	var results []struct {
+		ByStatus    []bucket `bson:"by_status"`
+		Overdue     []bucket `bson:"overdue"`
+		DueThisWeek []bucket `bson:"due_this_week"`
+		Completions []bucket `bson:"completions"`
+	}
+	if err := cursor.All(c, &results); err != nil {
+		return nil, fmt.Errorf("repository: failed to decode task stats: %w", err)
+	}
+
+	stats := &domain.TaskStats{}
+	completions := map[string]int{}
+	if len(results) == 1 {
+		result := results[0]
+		for _, b := range result.ByStatus {
+			stats.AddToStatus(domain.TaskStatus(b.Key), b.Count)
+		}
+		if len(result.Overdue) == 1 {
+			stats.Overdue = result.Overdue[0].Count
+		}
+		if len(result.DueThisWeek) == 1 {
+			stats.DueThisWeek = result.DueThisWeek[0].Count
+		}
+		for _, b := range result.Completions {
+			completions[b.Key] = b.Count
+		}
+	}
+	stats.SetCompletions(completions, params)
+	return stats, nil
+}
+
+// taskFilterToBSON translates a domain.TaskFilter into a MongoDB query document.
+func taskFilterToBSON(filter domain.TaskFilter) bson.M {
+	query := bson.M{}
+	if !filter.ProjectID.IsZero() {
+		query["project_id"] = filter.ProjectID
+	}
+	if len(filter.IDs) > 0 {
+		query["_id"] = bson.M{"$in": filter.IDs}
+	}
+	if len(filter.BlockedByAny) > 0 {
+		query["blocked_by"] = bson.M{"$in": filter.BlockedByAny}
+	}
+	if len(filter.Statuses) > 0 {
+		query["status"] = bson.M{"$in": filter.Statuses}
+	}
+	if filter.DueBefore != nil || filter.DueAfter != nil {
+		dueDate := bson.M{}
+		if filter.DueBefore != nil {
+			dueDate["$lt"] = *filter.DueBefore
+		}
+		if filter.DueAfter != nil {
+			dueDate["$gt"] = *filter.DueAfter
+		}
+		query["duedate"] = dueDate
+	}
+	if filter.OwnerID != "" {
+		query["owner_id"] = filter.OwnerID
+	}
+	if filter.Tag != "" {
+		query["tags"] = filter.Tag
+	}
+	if !filter.AfterID.IsZero() {
+		id, _ := query["_id"].(bson.M)
+		if id == nil {
+			id = bson.M{}
+		}
+		id["$gt"] = filter.AfterID
+		query["_id"] = id
+	}
+	if filter.Restricted {
+		visible := filter.VisibleProjects
+		if visible == nil {
+			visible = []primitive.ObjectID{}
+		}
+		query["$or"] = bson.A{
+			bson.M{"project_id": bson.M{"$exists": false}},
+			bson.M{"project_id": bson.M{"$in": visible}},
+		}
+	}
+	return query
+}
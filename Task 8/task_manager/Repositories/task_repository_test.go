@@ -4,6 +4,7 @@ import (
 	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
 	"A2SV_ProjectPhase/Task8/TaskManager/Repositories"
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -61,7 +62,7 @@ func (s *TaskRepoSuite) TestCreateTask() {
 		Status:      domain.Pending,
 	}
 
-	createdTask, err := s.repo.CreateTask(context.Background(), taskToCreate)
+	createdTask, err := s.repo.CreateTask(organizationContext(), taskToCreate)
 
 	s.Require().NoError(err, "CreateTask should not return an error")
 	s.Require().NotNil(createdTask)
@@ -78,7 +79,7 @@ func (s *TaskRepoSuite) TestGetTaskById() {
 		s.Require().NoError(err, "Failed to seed database for test")
 
 		// Execution
-		foundTask, err := s.repo.GetTaskById(context.Background(), taskToFind.Id)
+		foundTask, err := s.repo.GetTaskById(systemContext(), taskToFind.Id)
 
 		// Assertion
 		s.Require().NoError(err)
@@ -90,7 +91,7 @@ func (s *TaskRepoSuite) TestGetTaskById() {
 		nonExistentID := primitive.NewObjectID()
 
 		// Execution
-		_, err := s.repo.GetTaskById(context.Background(), nonExistentID)
+		_, err := s.repo.GetTaskById(systemContext(), nonExistentID)
 
 		// Assertion
 		s.Require().Error(err)
@@ -109,13 +110,168 @@ func (s *TaskRepoSuite) TestGetAllTasks() {
 	s.Require().NoError(err)
 
 	// Execution
-	allTasks, err := s.repo.GetAllTasks(context.Background())
+	allTasks, err := s.repo.GetAllTasks(systemContext(), domain.TaskFilter{})
 
 	// Assertion
 	s.Require().NoError(err)
 	s.Len(allTasks, 2, "Expected to retrieve 2 tasks")
 }
 
+// TestGetAllTasksWithProjectFilter tests project scoping and membership restriction.
+func (s *TaskRepoSuite) TestGetAllTasksWithProjectFilter() {
+	visibleProject := primitive.NewObjectID()
+	hiddenProject := primitive.NewObjectID()
+	tasksToInsert := []interface{}{
+		&domain.Task{Id: primitive.NewObjectID(), Title: "Global"},
+		&domain.Task{Id: primitive.NewObjectID(), Title: "Visible", ProjectID: visibleProject},
+		&domain.Task{Id: primitive.NewObjectID(), Title: "Hidden", ProjectID: hiddenProject},
+	}
+	_, err := s.coll.InsertMany(context.Background(), tasksToInsert)
+	s.Require().NoError(err)
+
+	s.Run("Restricted to member projects", func() {
+		tasks, err := s.repo.GetAllTasks(systemContext(), domain.TaskFilter{Restricted: true, VisibleProjects: []primitive.ObjectID{visibleProject}})
+		s.Require().NoError(err)
+		s.Len(tasks, 2)
+	})
+
+	s.Run("Restricted without projects", func() {
+		tasks, err := s.repo.GetAllTasks(systemContext(), domain.TaskFilter{Restricted: true})
+		s.Require().NoError(err)
+		s.Require().Len(tasks, 1)
+		s.Equal("Global", tasks[0].Title)
+	})
+
+	s.Run("Single project", func() {
+		tasks, err := s.repo.GetAllTasks(systemContext(), domain.TaskFilter{ProjectID: hiddenProject})
+		s.Require().NoError(err)
+		s.Require().Len(tasks, 1)
+		s.Equal("Hidden", tasks[0].Title)
+	})
+}
+
+// TestGetAllTasksWithStatusAndDueDateFilter tests the filters used by bulk updates.
+func (s *TaskRepoSuite) TestGetAllTasksWithStatusAndDueDateFilter() {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	tasksToInsert := []interface{}{
+		&domain.Task{Id: primitive.NewObjectID(), Title: "Overdue", Status: domain.Pending, DueDate: now.Add(-time.Hour)},
+		&domain.Task{Id: primitive.NewObjectID(), Title: "Upcoming", Status: domain.InProgress, DueDate: now.Add(time.Hour)},
+		&domain.Task{Id: primitive.NewObjectID(), Title: "Finished", Status: domain.Done, DueDate: now.Add(-time.Hour)},
+	}
+	_, err := s.coll.InsertMany(context.Background(), tasksToInsert)
+	s.Require().NoError(err)
+
+	tasks, err := s.repo.GetAllTasks(systemContext(), domain.TaskFilter{Statuses: []domain.TaskStatus{domain.Pending, domain.InProgress}})
+	s.Require().NoError(err)
+	s.Len(tasks, 2)
+
+	tasks, err = s.repo.GetAllTasks(systemContext(), domain.TaskFilter{Statuses: []domain.TaskStatus{domain.Pending, domain.InProgress}, DueBefore: &now})
+	s.Require().NoError(err)
+	s.Require().Len(tasks, 1)
+	s.Equal("Overdue", tasks[0].Title)
+
+	tasks, err = s.repo.GetAllTasks(systemContext(), domain.TaskFilter{DueAfter: &now})
+	s.Require().NoError(err)
+	s.Require().Len(tasks, 1)
+	s.Equal("Upcoming", tasks[0].Title)
+}
+
+// TestStreamTasksAndExternalID tests streaming and lookups by external ID.
+func (s *TaskRepoSuite) TestStreamTasksAndExternalID() {
+	tasksToInsert := []interface{}{
+		&domain.Task{Id: primitive.NewObjectID(), Title: "Imported", Status: domain.Pending, ExternalID: "JIRA-7"},
+		&domain.Task{Id: primitive.NewObjectID(), Title: "Local", Status: domain.Done},
+	}
+	_, err := s.coll.InsertMany(context.Background(), tasksToInsert)
+	s.Require().NoError(err)
+
+	var titles []string
+	err = s.repo.StreamTasks(systemContext(), domain.TaskFilter{}, func(task *domain.Task) error {
+		titles = append(titles, task.Title)
+		return nil
+	})
+	s.Require().NoError(err)
+	s.Equal([]string{"Imported", "Local"}, titles)
+
+	titles = nil
+	first := tasksToInsert[0].(*domain.Task)
+	err = s.repo.StreamTasks(systemContext(), domain.TaskFilter{AfterID: first.Id}, func(task *domain.Task) error {
+		titles = append(titles, task.Title)
+		return nil
+	})
+	s.Require().NoError(err)
+	s.Equal([]string{"Local"}, titles, "AfterID should continue after the given task")
+
+	stop := errors.New("stop")
+	err = s.repo.StreamTasks(systemContext(), domain.TaskFilter{}, func(task *domain.Task) error { return stop })
+	s.ErrorIs(err, stop)
+
+	task, err := s.repo.GetTaskByExternalID(systemContext(), "JIRA-7")
+	s.Require().NoError(err)
+	s.Equal("Imported", task.Title)
+
+	_, err = s.repo.GetTaskByExternalID(systemContext(), "JIRA-8")
+	s.ErrorIs(err, domain.ErrTaskNotFound)
+}
+
+// TestGetTaskStats tests that the aggregation agrees with domain.ComputeTaskStats.
+func (s *TaskRepoSuite) TestGetTaskStats() {
+	now := time.Date(2025, 3, 12, 15, 0, 0, 0, time.UTC)
+	weekStart := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	completed := time.Date(2025, 3, 11, 23, 30, 0, 0, time.UTC)
+	params := domain.TaskStatsParams{
+		Now:       now,
+		WeekStart: weekStart,
+		WeekEnd:   weekStart.AddDate(0, 0, 7),
+		From:      weekStart.AddDate(0, 0, -3),
+		To:        weekStart.AddDate(0, 0, 3),
+		Location:  time.UTC,
+	}
+	tasks := []*domain.Task{
+		{Id: primitive.NewObjectID(), Title: "Overdue", Status: domain.Pending, DueDate: now.Add(-48 * time.Hour), OwnerID: "alice", Tags: []string{"ui"}},
+		{Id: primitive.NewObjectID(), Title: "This week", Status: domain.InProgress, DueDate: now.Add(24 * time.Hour), OwnerID: "alice"},
+		{Id: primitive.NewObjectID(), Title: "Done", Status: domain.Done, DueDate: now.Add(-24 * time.Hour), OwnerID: "bob", Tags: []string{"ui"}, CompletedAt: &completed},
+		{Id: primitive.NewObjectID(), Title: "Later", Status: domain.Pending, DueDate: now.AddDate(0, 1, 0), OwnerID: "bob"},
+		{Id: primitive.NewObjectID(), Title: "Due today", Status: domain.Pending, DueDate: domain.StartOfDay(now), OwnerID: "carol"},
+	}
+	for _, task := range tasks {
+		_, err := s.coll.InsertOne(context.Background(), task)
+		s.Require().NoError(err)
+	}
+
+	s.Run("All Tasks", func() {
+		stats, err := s.repo.GetTaskStats(systemContext(), domain.TaskFilter{}, params)
+		s.Require().NoError(err)
+		s.Equal(domain.ComputeTaskStats(tasks, params), stats)
+		s.Equal(1, stats.Overdue, "a task due today is not overdue yet")
+		s.Equal(2, stats.DueThisWeek)
+		s.Equal(domain.DailyCount{Date: "2025-03-11", Count: 1}, stats.CompletionsPerDay[4])
+	})
+
+	s.Run("Filtered By Owner And Tag", func() {
+		stats, err := s.repo.GetTaskStats(systemContext(), domain.TaskFilter{OwnerID: "alice"}, params)
+		s.Require().NoError(err)
+		s.Equal(2, stats.Total)
+
+		stats, err = s.repo.GetTaskStats(systemContext(), domain.TaskFilter{Tag: "ui"}, params)
+		s.Require().NoError(err)
+		s.Equal(2, stats.Total)
+		s.Equal(1, stats.ByStatus[domain.Done])
+	})
+
+	s.Run("Completions Follow The Location", func() {
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		s.Require().NoError(err)
+		local := params
+		local.Location = tokyo
+		local.From = time.Date(2025, 3, 10, 0, 0, 0, 0, tokyo)
+		local.To = time.Date(2025, 3, 14, 0, 0, 0, 0, tokyo)
+		stats, err := s.repo.GetTaskStats(systemContext(), domain.TaskFilter{}, local)
+		s.Require().NoError(err)
+		s.Equal(domain.DailyCount{Date: "2025-03-12", Count: 1}, stats.CompletionsPerDay[2])
+	})
+}
+
 // TestUpdateTask tests the update functionality.
 func (s *TaskRepoSuite) TestUpdateTask() {
 	// Setup: Seed the database
@@ -132,7 +288,7 @@ func (s *TaskRepoSuite) TestUpdateTask() {
 		Title:  "Updated Title",
 		Status: domain.InProgress,
 	}
-	updatedTask, err := s.repo.UpdateTask(context.Background(), originalTask.Id, taskWithUpdates)
+	updatedTask, err := s.repo.UpdateTask(systemContext(), originalTask.Id, taskWithUpdates)
 
 	// Assertion
 	s.Require().NoError(err)
@@ -149,12 +305,68 @@ func (s *TaskRepoSuite) TestDeleteTask() {
 	s.Require().NoError(err)
 
 	// Execution
-	err = s.repo.DeleteTask(context.Background(), taskToDelete.Id)
+	err = s.repo.DeleteTask(systemContext(), taskToDelete.Id)
 
 	// Assertion
 	s.Require().NoError(err)
 
 	// Verification: Ensure it's actually gone by trying to get it
-	_, err = s.repo.GetTaskById(context.Background(), taskToDelete.Id)
+	_, err = s.repo.GetTaskById(systemContext(), taskToDelete.Id)
 	s.ErrorIs(err, domain.ErrTaskNotFound, "Task should not be found after deletion")
 }
+
+// TestBlockerLinks tests adding, removing and bulk-unlinking blockers.
+func (s *TaskRepoSuite) TestBlockerLinks() {
+	ctx := systemContext()
+	blocker := &domain.Task{Id: primitive.NewObjectID(), Title: "Blocker"}
+	blocked := &domain.Task{Id: primitive.NewObjectID(), Title: "Blocked"}
+	_, err := s.coll.InsertMany(ctx, []interface{}{blocker, blocked})
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.repo.AddBlocker(ctx, blocked.Id, blocker.Id))
+	s.Require().NoError(s.repo.AddBlocker(ctx, blocked.Id, blocker.Id), "adding a link twice is a no-op")
+	found, err := s.repo.GetTaskById(ctx, blocked.Id)
+	s.Require().NoError(err)
+	s.Equal([]primitive.ObjectID{blocker.Id}, found.BlockedBy)
+
+	dependents, err := s.repo.GetAllTasks(ctx, domain.TaskFilter{BlockedByAny: []primitive.ObjectID{blocker.Id}})
+	s.Require().NoError(err)
+	s.Len(dependents, 1)
+
+	s.Require().NoError(s.repo.RemoveBlocker(ctx, blocked.Id, blocker.Id))
+	found, _ = s.repo.GetTaskById(ctx, blocked.Id)
+	s.Empty(found.BlockedBy)
+
+	s.Require().NoError(s.repo.AddBlocker(ctx, blocked.Id, blocker.Id))
+	s.Require().NoError(s.repo.RemoveBlockerFromAll(ctx, blocker.Id))
+	found, _ = s.repo.GetTaskById(ctx, blocked.Id)
+	s.Empty(found.BlockedBy)
+
+	s.ErrorIs(s.repo.AddBlocker(ctx, primitive.NewObjectID(), blocker.Id), domain.ErrTaskNotFound)
+}
+
+// TestWatchers tests adding and removing watchers.
+func (s *TaskRepoSuite) TestWatchers() {
+	ctx := systemContext()
+	task := &domain.Task{Id: primitive.NewObjectID(), Title: "Watched"}
+	_, err := s.coll.InsertOne(ctx, task)
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.repo.AddWatcher(ctx, task.Id, "alice"))
+	s.Require().NoError(s.repo.AddWatcher(ctx, task.Id, "bob"))
+	s.Require().NoError(s.repo.AddWatcher(ctx, task.Id, "alice"), "watching twice is a no-op")
+	found, err := s.repo.GetTaskById(ctx, task.Id)
+	s.Require().NoError(err)
+	s.Equal([]string{"alice", "bob"}, found.Watchers)
+
+	s.Require().NoError(s.repo.RemoveWatcher(ctx, task.Id, "alice"))
+	found, _ = s.repo.GetTaskById(ctx, task.Id)
+	s.Equal([]string{"bob"}, found.Watchers)
+
+	s.Require().NoError(s.repo.RemoveWatcherFromAll(ctx, "bob"))
+	found, _ = s.repo.GetTaskById(ctx, task.Id)
+	s.Empty(found.Watchers)
+
+	s.ErrorIs(s.repo.AddWatcher(ctx, primitive.NewObjectID(), "alice"), domain.ErrTaskNotFound)
+	s.ErrorIs(s.repo.RemoveWatcher(ctx, primitive.NewObjectID(), "alice"), domain.ErrTaskNotFound)
+}
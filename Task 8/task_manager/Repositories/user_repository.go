@@ -3,11 +3,14 @@ package repositories
 import (
 	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserRepo struct {
@@ -22,7 +25,37 @@ func NewMongoDBUserRepository(col *mongo.Collection) *UserRepo {
 
 var _ domain.UserRepository = (*UserRepo)(nil)
 
+// EnsureIndexes creates the unique index on usernames within an organization, which
+// makes CreateUser report domain.ErrUsernameTaken even when two registrations of a name
+// race each other. It drops the index of earlier versions, which kept usernames unique
+// across all organizations.
+func (ur *UserRepo) EnsureIndexes(c context.Context) error {
+	if _, err := ur.collection.Indexes().DropOne(c, "username_1"); err != nil && !isIndexNotFound(err) {
+		return fmt.Errorf("repository: failed to drop global username index: %w", err)
+	}
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "organization_id", Value: 1}, {Key: "username", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := ur.collection.Indexes().CreateOne(c, indexModel); err != nil {
+		return fmt.Errorf("repository: failed to create unique username index: %w", err)
+	}
+	return nil
+}
+
+// isIndexNotFound reports whether dropping an index failed because it, or its
+// collection, does not exist.
+func isIndexNotFound(err error) bool {
+	var commandErr mongo.CommandError
+	return errors.As(err, &commandErr) && (commandErr.Name == "IndexNotFound" || commandErr.Name == "NamespaceNotFound")
+}
+
 func (ur *UserRepo) CreateUser(c context.Context, user *domain.User) (*domain.User, error) {
+	organizationID, err := inOrganization(c, user.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	user.OrganizationID = organizationID
 	result, err := ur.collection.InsertOne(c, user)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
@@ -42,7 +75,7 @@ func (ur *UserRepo) CreateUser(c context.Context, user *domain.User) (*domain.Us
 
 func (ur *UserRepo) GetUserByUsername(c context.Context, username string) (*domain.User, error) {
 	var user domain.User
-	filter := bson.M{"username": username}
+	filter := scoped(c, bson.M{"username": username})
 	err := ur.collection.FindOne(c, filter).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -52,3 +85,137 @@ func (ur *UserRepo) GetUserByUsername(c context.Context, username string) (*doma
 	}
 	return &user, nil
 }
+
+func (ur *UserRepo) GetUserById(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+	var user domain.User
+	filter := scoped(c, bson.M{"_id": id})
+	err := ur.collection.FindOne(c, filter).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to find user by ID '%s': %w", id.Hex(), err)
+	}
+	return &user, nil
+}
+
+func (ur *UserRepo) GetUserByCalendarTokenHash(c context.Context, tokenHash string) (*domain.User, error) {
+	// Users without a feed store an empty hash, which must never match.
+	if tokenHash == "" {
+		return nil, domain.ErrUserNotFound
+	}
+	var user domain.User
+	filter := bson.M{"calendar_token_hash": tokenHash}
+	err := ur.collection.FindOne(c, filter).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to find user by calendar token: %w", err)
+	}
+	return &user, nil
+}
+
+func (ur *UserRepo) GetAllUsers(c context.Context) ([]*domain.User, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "username", Value: 1}})
+	cursor, err := ur.collection.Find(c, scoped(c, bson.M{}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to retrieve users cursor: %w", err)
+	}
+	defer cursor.Close(c)
+
+	users := []*domain.User{}
+	if err = cursor.All(c, &users); err != nil {
+		return nil, fmt.Errorf("repository: failed to decode users from cursor: %w", err)
+	}
+	return users, nil
+}
+
+func (ur *UserRepo) UpdateUser(c context.Context, id primitive.ObjectID, updatedUser *domain.User) (*domain.User, error) {
+	updateDoc := bson.M{"$set": bson.M{
+		"username":                  updatedUser.Username,
+		"password":                  updatedUser.PasswordHash,
+		"role":                      updatedUser.Role,
+		"two_factor_enabled":        updatedUser.TwoFactorEnabled,
+		"two_factor_secret":         updatedUser.TwoFactorSecret,
+		"pending_two_factor_secret": updatedUser.PendingTwoFactorSecret,
+		"recovery_codes":            updatedUser.RecoveryCodeHashes,
+		"calendar_token_hash":       updatedUser.CalendarTokenHash,
+		"time_zone":                 updatedUser.TimeZone,
+	}}
+
+	filter := scoped(c, bson.M{"_id": id})
+	var result domain.User
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := ur.collection.FindOneAndUpdate(c, filter, updateDoc, opts).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrUserNotFound
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, domain.ErrUsernameTaken
+		}
+		return nil, fmt.Errorf("repository: failed to update user by ID '%s': %w", id.Hex(), err)
+	}
+	return &result, nil
+}
+
+func (ur *UserRepo) DeleteUser(c context.Context, id primitive.ObjectID) error {
+	res, err := ur.collection.DeleteOne(c, scoped(c, bson.M{"_id": id}))
+	if err != nil {
+		return fmt.Errorf("repository: failed to delete user by ID '%s': %w", id.Hex(), err)
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (ur *UserRepo) RecordTwoFactorStep(c context.Context, id primitive.ObjectID, step int64) (bool, error) {
+	// Users who never used a code have no last_two_factor_step, which $lt does not match.
+	filter := scoped(c, bson.M{"_id": id, "$or": bson.A{
+		bson.M{"last_two_factor_step": bson.M{"$lt": step}},
+		bson.M{"last_two_factor_step": bson.M{"$exists": false}},
+	}})
+	res, err := ur.collection.UpdateOne(c, filter, bson.M{"$set": bson.M{"last_two_factor_step": step}})
+	if err != nil {
+		return false, fmt.Errorf("repository: failed to record two-factor step for user '%s': %w", id.Hex(), err)
+	}
+	return res.MatchedCount == 1, nil
+}
+
+func (ur *UserRepo) ConsumeRecoveryCode(c context.Context, id primitive.ObjectID, hash string) (bool, error) {
+	filter := scoped(c, bson.M{"_id": id, "recovery_codes": hash})
+	res, err := ur.collection.UpdateOne(c, filter, bson.M{"$pull": bson.M{"recovery_codes": hash}})
+	if err != nil {
+		return false, fmt.Errorf("repository: failed to consume recovery code of user '%s': %w", id.Hex(), err)
+	}
+	return res.MatchedCount == 1, nil
+}
+
+func (ur *UserRepo) RecordTwoFactorAttempt(c context.Context, id primitive.ObjectID, at time.Time) (int, *time.Time, error) {
+	update := bson.M{
+		"$inc": bson.M{"two_factor_failures": 1},
+		"$set": bson.M{"last_two_factor_failure": at},
+	}
+	// The document from before the update tells concurrent attempts apart.
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	var previous domain.User
+	err := ur.collection.FindOneAndUpdate(c, scoped(c, bson.M{"_id": id}), update, opts).Decode(&previous)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil, domain.ErrUserNotFound
+		}
+		return 0, nil, fmt.Errorf("repository: failed to record two-factor attempt for user '%s': %w", id.Hex(), err)
+	}
+	return previous.TwoFactorFailures, previous.LastTwoFactorFailure, nil
+}
+
+func (ur *UserRepo) ResetTwoFactorFailures(c context.Context, id primitive.ObjectID) error {
+	update := bson.M{"$unset": bson.M{"two_factor_failures": "", "last_two_factor_failure": ""}}
+	if _, err := ur.collection.UpdateOne(c, scoped(c, bson.M{"_id": id}), update); err != nil {
+		return fmt.Errorf("repository: failed to reset two-factor failures for user '%s': %w", id.Hex(), err)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"A2SV_ProjectPhase/Task8/TaskManager/Repositories"
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson"
@@ -61,7 +62,7 @@ func (s *UserRepoSuite) SetupTest() {
 func (s *UserRepoSuite) TestCreateUser() {
 	s.Run("Success", func() {
 		user := &domain.User{Username: "testuser", PasswordHash: "hash"}
-		createdUser, err := s.repo.CreateUser(context.Background(), user)
+		createdUser, err := s.repo.CreateUser(organizationContext(), user)
 
 		s.Require().NoError(err)
 		s.Require().NotNil(createdUser)
@@ -72,12 +73,12 @@ func (s *UserRepoSuite) TestCreateUser() {
 	s.Run("Failure - Duplicate Username", func() {
 		// First user (seeded)
 		user1 := &domain.User{Username: "duplicate", PasswordHash: "hash1"}
-		_, err := s.repo.CreateUser(context.Background(), user1)
+		_, err := s.repo.CreateUser(organizationContext(), user1)
 		s.Require().NoError(err, "Seeding the first user should succeed")
 
 		// Attempt to create a second user with the same username
 		user2 := &domain.User{Username: "duplicate", PasswordHash: "hash2"}
-		_, err = s.repo.CreateUser(context.Background(), user2)
+		_, err = s.repo.CreateUser(organizationContext(), user2)
 
 		// Assert that we get the correct domain-level error
 		s.Require().Error(err)
@@ -98,7 +99,7 @@ func (s *UserRepoSuite) TestGetUserByUsername() {
 		s.Require().NoError(err, "Failed to seed database for test")
 
 		// Execution
-		foundUser, err := s.repo.GetUserByUsername(context.Background(), "findme")
+		foundUser, err := s.repo.GetUserByUsername(systemContext(), "findme")
 
 		// Assertion
 		s.Require().NoError(err)
@@ -110,10 +111,134 @@ func (s *UserRepoSuite) TestGetUserByUsername() {
 		// No seeding needed, collection is clean from SetupTest.
 
 		// Execution
-		_, err := s.repo.GetUserByUsername(context.Background(), "nonexistentuser")
+		_, err := s.repo.GetUserByUsername(systemContext(), "nonexistentuser")
 
 		// Assertion
 		s.Require().Error(err)
 		s.ErrorIs(err, domain.ErrUserNotFound)
 	})
 }
+
+// TestGetUserById tests finding a user by their ID.
+func (s *UserRepoSuite) TestGetUserById() {
+	s.Run("Success - User Found", func() {
+		userToFind := &domain.User{Id: primitive.NewObjectID(), Username: "byid"}
+		_, err := s.coll.InsertOne(context.Background(), userToFind)
+		s.Require().NoError(err, "Failed to seed database for test")
+
+		foundUser, err := s.repo.GetUserById(systemContext(), userToFind.Id)
+
+		s.Require().NoError(err)
+		s.Equal("byid", foundUser.Username)
+	})
+
+	s.Run("Failure - User Not Found", func() {
+		_, err := s.repo.GetUserById(systemContext(), primitive.NewObjectID())
+
+		s.Require().Error(err)
+		s.ErrorIs(err, domain.ErrUserNotFound)
+	})
+}
+
+// TestGetUserByCalendarTokenHash tests the feed token lookup.
+func (s *UserRepoSuite) TestGetUserByCalendarTokenHash() {
+	withFeed := &domain.User{Id: primitive.NewObjectID(), Username: "withfeed", CalendarTokenHash: "feedhash"}
+	withoutFeed := &domain.User{Id: primitive.NewObjectID(), Username: "withoutfeed"}
+	_, err := s.coll.InsertMany(context.Background(), []interface{}{withFeed, withoutFeed})
+	s.Require().NoError(err, "Failed to seed database for test")
+
+	foundUser, err := s.repo.GetUserByCalendarTokenHash(systemContext(), "feedhash")
+	s.Require().NoError(err)
+	s.Equal("withfeed", foundUser.Username)
+
+	// Revoking stores an empty hash, which must not match users without a feed.
+	withFeed.CalendarTokenHash = ""
+	_, err = s.repo.UpdateUser(systemContext(), withFeed.Id, withFeed)
+	s.Require().NoError(err)
+	_, err = s.repo.GetUserByCalendarTokenHash(systemContext(), "feedhash")
+	s.ErrorIs(err, domain.ErrUserNotFound)
+	_, err = s.repo.GetUserByCalendarTokenHash(systemContext(), "")
+	s.ErrorIs(err, domain.ErrUserNotFound)
+}
+
+// TestUpdateUser tests persisting changes to an existing user.
+func (s *UserRepoSuite) TestUpdateUser() {
+	s.Run("Success", func() {
+		user := &domain.User{Id: primitive.NewObjectID(), Username: "updateme", PasswordHash: "hash", Role: domain.RoleUser}
+		_, err := s.coll.InsertOne(context.Background(), user)
+		s.Require().NoError(err, "Failed to seed database for test")
+
+		user.TwoFactorEnabled = true
+		user.TwoFactorSecret = "encrypted-secret"
+		user.RecoveryCodeHashes = []string{"h1", "h2"}
+		updatedUser, err := s.repo.UpdateUser(systemContext(), user.Id, user)
+
+		s.Require().NoError(err)
+		s.True(updatedUser.TwoFactorEnabled)
+		s.Equal("encrypted-secret", updatedUser.TwoFactorSecret)
+		s.Len(updatedUser.RecoveryCodeHashes, 2)
+	})
+
+	s.Run("Failure - User Not Found", func() {
+		_, err := s.repo.UpdateUser(systemContext(), primitive.NewObjectID(), &domain.User{Username: "ghost"})
+
+		s.Require().Error(err)
+		s.ErrorIs(err, domain.ErrUserNotFound)
+	})
+}
+
+func (s *UserRepoSuite) TestTwoFactorAttempts() {
+	user := &domain.User{Id: primitive.NewObjectID(), Username: "totp", PasswordHash: "hash", Role: domain.RoleUser}
+	_, err := s.coll.InsertOne(context.Background(), user)
+	s.Require().NoError(err, "Failed to seed database for test")
+
+	s.Run("Steps Are Used Once", func() {
+		recorded, err := s.repo.RecordTwoFactorStep(systemContext(), user.Id, 100)
+		s.Require().NoError(err)
+		s.True(recorded)
+
+		recorded, err = s.repo.RecordTwoFactorStep(systemContext(), user.Id, 100)
+		s.Require().NoError(err)
+		s.False(recorded, "A step should not be accepted twice")
+		recorded, err = s.repo.RecordTwoFactorStep(systemContext(), user.Id, 99)
+		s.Require().NoError(err)
+		s.False(recorded, "Earlier steps should not be accepted after a later one")
+	})
+
+	s.Run("Recovery Codes Are Used Once", func() {
+		_, err := s.coll.UpdateOne(context.Background(), bson.M{"_id": user.Id}, bson.M{"$set": bson.M{"recovery_codes": []string{"a", "b"}}})
+		s.Require().NoError(err)
+
+		consumed, err := s.repo.ConsumeRecoveryCode(systemContext(), user.Id, "a")
+		s.Require().NoError(err)
+		s.True(consumed)
+
+		consumed, err = s.repo.ConsumeRecoveryCode(systemContext(), user.Id, "a")
+		s.Require().NoError(err)
+		s.False(consumed, "A recovery code should not be accepted twice")
+
+		stored, err := s.repo.GetUserById(systemContext(), user.Id)
+		s.Require().NoError(err)
+		s.Equal([]string{"b"}, stored.RecoveryCodeHashes)
+	})
+
+	s.Run("Attempts Are Counted Until Reset", func() {
+		first := time.Now().UTC().Truncate(time.Millisecond)
+		failures, lastFailure, err := s.repo.RecordTwoFactorAttempt(systemContext(), user.Id, first)
+		s.Require().NoError(err)
+		s.Zero(failures)
+		s.Nil(lastFailure)
+
+		failures, lastFailure, err = s.repo.RecordTwoFactorAttempt(systemContext(), user.Id, first.Add(time.Second))
+		s.Require().NoError(err)
+		s.Equal(1, failures, "The previous attempt should be reported")
+		s.Require().NotNil(lastFailure)
+		s.True(first.Equal(*lastFailure))
+
+		s.Require().NoError(s.repo.ResetTwoFactorFailures(systemContext(), user.Id))
+		stored, err := s.repo.GetUserById(systemContext(), user.Id)
+		s.Require().NoError(err)
+		s.Zero(stored.TwoFactorFailures)
+		s.Nil(stored.LastTwoFactorFailure)
+	})
+}
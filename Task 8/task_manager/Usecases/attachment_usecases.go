@@ -0,0 +1,195 @@
+package usecases
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AttachmentUseCase struct {
+	attachmentRepo domain.AttachmentRepository
+	blobStore      domain.BlobStore
+	tasks          *TaskUseCase
+	policy         domain.AttachmentPolicy
+}
+
+// NewAttachmentUseCase creates the use case and hooks it into task deletions so that
+// attachments of deleted tasks are cleaned up.
+func NewAttachmentUseCase(attachmentRepo domain.AttachmentRepository, blobStore domain.BlobStore, tasks *TaskUseCase, policy domain.AttachmentPolicy) *AttachmentUseCase {
+	uc := &AttachmentUseCase{
+		attachmentRepo: attachmentRepo,
+		blobStore:      blobStore,
+		tasks:          tasks,
+		policy:         policy,
+	}
+	tasks.OnDelete(uc.deleteTaskAttachments)
+	return uc
+}
+
+// MaxSize is the largest accepted attachment in bytes.
+func (uc *AttachmentUseCase) MaxSize() int64 {
+	return uc.policy.MaxSize
+}
+
+// Upload stores the content and records its metadata. size is the size declared by the
+// client; the content is also cut off at the limit in case the declaration was wrong.
+// The content type is checked both as declared and as detected from the content.
+func (uc *AttachmentUseCase) Upload(c context.Context, taskID, fileName, contentType string, size int64, content io.Reader) (*domain.Attachment, error) {
+	task, err := uc.tasks.GetTaskForEdit(c, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.policy.Check(contentType, size); err != nil {
+		return nil, err
+	}
+	fileName = filepath.Base(fileName)
+	if fileName == "." || fileName == string(filepath.Separator) {
+		return nil, fmt.Errorf("%w: attachment file name cannot be empty", domain.ErrValidationFailed)
+	}
+
+	// DetectContentType looks at no more than the first 512 bytes.
+	head := make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("usecase: failed to read attachment: %w", err)
+	}
+	head = head[:n]
+	contentType, err = uc.policy.ContentType(contentType, http.DetectContentType(head))
+	if err != nil {
+		return nil, err
+	}
+	content = io.MultiReader(bytes.NewReader(head), content)
+
+	attachment := &domain.Attachment{
+		Id:          primitive.NewObjectID(),
+		TaskID:      task.Id,
+		FileName:    fileName,
+		ContentType: contentType,
+		CreatedAt:   uc.tasks.clock.Now(),
+	}
+	attachment.StorageKey = attachment.Id.Hex()
+	if actor, ok := domain.ActorFromContext(c); ok {
+		attachment.UploadedBy = actor.UserID
+	}
+
+	written, err := uc.blobStore.Put(c, attachment.StorageKey, io.LimitReader(content, uc.policy.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to store attachment: %w", err)
+	}
+	if written > uc.policy.MaxSize {
+		uc.deleteBlob(c, attachment.StorageKey)
+		return nil, fmt.Errorf("%w: exceeds the limit of %d bytes", domain.ErrAttachmentTooLarge, uc.policy.MaxSize)
+	}
+	attachment.Size = written
+
+	saved, err := uc.attachmentRepo.CreateAttachment(c, attachment)
+	if err != nil {
+		uc.deleteBlob(c, attachment.StorageKey)
+		return nil, fmt.Errorf("usecase: failed to save attachment: %w", err)
+	}
+	return saved, nil
+}
+
+func (uc *AttachmentUseCase) GetAttachments(c context.Context, taskID string) ([]*domain.Attachment, error) {
+	task, err := uc.tasks.GetTaskByID(c, taskID)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := uc.attachmentRepo.GetAttachmentsByTask(c, task.Id)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to get attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// Download returns the attachment's metadata and content. The caller must close the content.
+func (uc *AttachmentUseCase) Download(c context.Context, taskID, attachmentID string) (*domain.Attachment, io.ReadCloser, error) {
+	task, err := uc.tasks.GetTaskByID(c, taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	attachment, err := uc.getAttachment(c, task, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := uc.blobStore.Open(c, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, domain.ErrBlobNotFound) {
+			return nil, nil, domain.ErrAttachmentNotFound
+		}
+		return nil, nil, fmt.Errorf("usecase: failed to open attachment: %w", err)
+	}
+	return attachment, content, nil
+}
+
+func (uc *AttachmentUseCase) Delete(c context.Context, taskID, attachmentID string) error {
+	task, err := uc.tasks.GetTaskForEdit(c, taskID)
+	if err != nil {
+		return err
+	}
+	attachment, err := uc.getAttachment(c, task, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err := uc.attachmentRepo.DeleteAttachment(c, attachment.Id); err != nil {
+		if errors.Is(err, domain.ErrAttachmentNotFound) {
+			return domain.ErrAttachmentNotFound
+		}
+		return fmt.Errorf("usecase: failed to delete attachment: %w", err)
+	}
+	uc.deleteBlob(c, attachment.StorageKey)
+	return nil
+}
+
+// getAttachment loads an attachment and checks that it belongs to the task.
+func (uc *AttachmentUseCase) getAttachment(c context.Context, task *domain.Task, attachmentID string) (*domain.Attachment, error) {
+	objectID, err := primitive.ObjectIDFromHex(attachmentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid attachment ID format", domain.ErrValidationFailed)
+	}
+	attachment, err := uc.attachmentRepo.GetAttachmentById(c, objectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAttachmentNotFound) {
+			return nil, domain.ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("usecase: failed to get attachment: %w", err)
+	}
+	if attachment.TaskID != task.Id {
+		return nil, domain.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// deleteTaskAttachments deletes the attachment records of a task in the task's unit of
+// work. Blobs cannot be restored, so they are only deleted once the deletion commits.
+func (uc *AttachmentUseCase) deleteTaskAttachments(c context.Context, task *domain.Task) error {
+	attachments, err := uc.attachmentRepo.GetAttachmentsByTask(c, task.Id)
+	if err != nil {
+		return fmt.Errorf("usecase: failed to list attachments of task %s: %w", task.Id.Hex(), err)
+	}
+	if err := uc.attachmentRepo.DeleteAttachmentsByTask(c, task.Id); err != nil {
+		return fmt.Errorf("usecase: failed to delete attachments of task %s: %w", task.Id.Hex(), err)
+	}
+	afterCommit(c, func(c context.Context) {
+		for _, attachment := range attachments {
+			uc.deleteBlob(c, attachment.StorageKey)
+		}
+	})
+	return nil
+}
+
+// deleteBlob removes a blob whose metadata is gone or was never saved. Failures only
+// leave an orphaned blob behind, so they are logged rather than returned.
+func (uc *AttachmentUseCase) deleteBlob(c context.Context, key string) {
+	if err := uc.blobStore.Delete(c, key); err != nil && !errors.Is(err, domain.ErrBlobNotFound) {
+		log.Printf("attachments: failed to delete blob %s: %v", key, err)
+	}
+}
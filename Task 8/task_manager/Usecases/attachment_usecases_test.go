@@ -0,0 +1,227 @@
+package usecases_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// --- In-memory mocks for attachments ---
+type MockBlobStore struct {
+	Blobs map[string][]byte
+}
+
+func (m *MockBlobStore) Put(c context.Context, key string, content io.Reader) (int64, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return 0, err
+	}
+	m.Blobs[key] = data
+	return int64(len(data)), nil
+}
+func (m *MockBlobStore) Open(c context.Context, key string) (io.ReadCloser, error) {
+	data, ok := m.Blobs[key]
+	if !ok {
+		return nil, domain.ErrBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+func (m *MockBlobStore) Delete(c context.Context, key string) error {
+	if _, ok := m.Blobs[key]; !ok {
+		return domain.ErrBlobNotFound
+	}
+	delete(m.Blobs, key)
+	return nil
+}
+
+type MockAttachmentRepository struct {
+	Attachments map[primitive.ObjectID]*domain.Attachment
+}
+
+func (m *MockAttachmentRepository) CreateAttachment(c context.Context, attachment *domain.Attachment) (*domain.Attachment, error) {
+	m.Attachments[attachment.Id] = attachment
+	return attachment, nil
+}
+func (m *MockAttachmentRepository) GetAttachmentById(c context.Context, id primitive.ObjectID) (*domain.Attachment, error) {
+	attachment, ok := m.Attachments[id]
+	if !ok {
+		return nil, domain.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+func (m *MockAttachmentRepository) GetAttachmentsByTask(c context.Context, taskID primitive.ObjectID) ([]*domain.Attachment, error) {
+	attachments := []*domain.Attachment{}
+	for _, attachment := range m.Attachments {
+		if attachment.TaskID == taskID {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return attachments, nil
+}
+func (m *MockAttachmentRepository) DeleteAttachment(c context.Context, id primitive.ObjectID) error {
+	if _, ok := m.Attachments[id]; !ok {
+		return domain.ErrAttachmentNotFound
+	}
+	delete(m.Attachments, id)
+	return nil
+}
+func (m *MockAttachmentRepository) DeleteAttachmentsByTask(c context.Context, taskID primitive.ObjectID) error {
+	for id, attachment := range m.Attachments {
+		if attachment.TaskID == taskID {
+			delete(m.Attachments, id)
+		}
+	}
+	return nil
+}
+
+//===========================================================================
+// AttachmentUseCase Test Suite
+//===========================================================================
+
+type AttachmentUseCaseSuite struct {
+	suite.Suite
+	task      *domain.Task
+	blobs     *MockBlobStore
+	repo      *MockAttachmentRepository
+	taskUC    *usecases.TaskUseCase
+	useCase   *usecases.AttachmentUseCase
+	ctx       context.Context
+	textPlain string
+}
+
+func TestAttachmentUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(AttachmentUseCaseSuite))
+}
+
+func (s *AttachmentUseCaseSuite) SetupTest() {
+	s.task = &domain.Task{Id: primitive.NewObjectID(), Title: "Task"}
+	taskRepo := newInMemoryTaskRepository(s.task)
+	taskRepo.DeleteTaskFunc = func(c context.Context, id primitive.ObjectID) error { return nil }
+	taskRepo.RemoveBlockerFromAllFunc = func(c context.Context, blockerID primitive.ObjectID) error { return nil }
+
+	s.blobs = &MockBlobStore{Blobs: map[string][]byte{}}
+	s.repo = &MockAttachmentRepository{Attachments: map[primitive.ObjectID]*domain.Attachment{}}
+	s.taskUC = usecases.NewTaskUseCase(taskRepo, NewMockProjectRepository())
+	s.useCase = usecases.NewAttachmentUseCase(s.repo, s.blobs, s.taskUC, domain.AttachmentPolicy{MaxSize: 10, AllowedContentTypes: []string{"text/plain", "image/*"}})
+	s.ctx = context.Background()
+	s.textPlain = "text/plain; charset=utf-8"
+}
+
+func (s *AttachmentUseCaseSuite) upload(content string) *domain.Attachment {
+	attachment, err := s.useCase.Upload(s.ctx, s.task.Id.Hex(), "notes.txt", s.textPlain, int64(len(content)), strings.NewReader(content))
+	s.Require().NoError(err)
+	return attachment
+}
+
+func (s *AttachmentUseCaseSuite) TestUploadAndDownload() {
+	attachment := s.upload("hello")
+	s.Equal(int64(5), attachment.Size)
+	s.Equal("notes.txt", attachment.FileName)
+
+	meta, content, err := s.useCase.Download(s.ctx, s.task.Id.Hex(), attachment.Id.Hex())
+	s.Require().NoError(err)
+	defer content.Close()
+	data, _ := io.ReadAll(content)
+	s.Equal("hello", string(data))
+	s.Equal(attachment.Id, meta.Id)
+
+	attachments, err := s.useCase.GetAttachments(s.ctx, s.task.Id.Hex())
+	s.Require().NoError(err)
+	s.Len(attachments, 1)
+}
+
+func (s *AttachmentUseCaseSuite) TestUploadLimits() {
+	s.Run("Declared Size Too Large", func() {
+		s.SetupTest()
+		_, err := s.useCase.Upload(s.ctx, s.task.Id.Hex(), "big.txt", s.textPlain, 11, strings.NewReader("0123456789a"))
+		s.ErrorIs(err, domain.ErrAttachmentTooLarge)
+	})
+
+	s.Run("Actual Size Too Large", func() {
+		s.SetupTest()
+		_, err := s.useCase.Upload(s.ctx, s.task.Id.Hex(), "big.txt", s.textPlain, 1, strings.NewReader("0123456789a"))
+		s.ErrorIs(err, domain.ErrAttachmentTooLarge)
+		s.Empty(s.blobs.Blobs, "the oversized blob should be removed")
+	})
+
+	s.Run("Content Type Not Allowed", func() {
+		s.SetupTest()
+		_, err := s.useCase.Upload(s.ctx, s.task.Id.Hex(), "run.exe", "application/x-msdownload", 1, strings.NewReader("x"))
+		s.ErrorIs(err, domain.ErrUnsupportedContentType)
+	})
+
+	s.Run("Wildcard Content Type", func() {
+		s.SetupTest()
+		_, err := s.useCase.Upload(s.ctx, s.task.Id.Hex(), "shot.png", "image/png", 8, strings.NewReader("\x89PNG\r\n\x1a\n"))
+		s.NoError(err)
+	})
+
+	s.Run("Content Does Not Match Declared Type", func() {
+		s.SetupTest()
+		_, err := s.useCase.Upload(s.ctx, s.task.Id.Hex(), "shot.png", "image/png", 7, strings.NewReader("<html>x"))
+		s.ErrorIs(err, domain.ErrUnsupportedContentType, "HTML labelled as an image is rejected")
+		s.Empty(s.blobs.Blobs)
+	})
+
+	s.Run("Detected Type Is Stored", func() {
+		s.SetupTest()
+		attachment, err := s.useCase.Upload(s.ctx, s.task.Id.Hex(), "shot.png", "image/jpeg", 8, strings.NewReader("\x89PNG\r\n\x1a\n"))
+		s.Require().NoError(err)
+		s.Equal("image/png", attachment.ContentType)
+	})
+
+	s.Run("Unknown Task", func() {
+		s.SetupTest()
+		_, err := s.useCase.Upload(s.ctx, primitive.NewObjectID().Hex(), "notes.txt", s.textPlain, 1, strings.NewReader("x"))
+		s.ErrorIs(err, domain.ErrTaskNotFound)
+	})
+}
+
+func (s *AttachmentUseCaseSuite) TestDelete() {
+	attachment := s.upload("hello")
+	s.Require().NoError(s.useCase.Delete(s.ctx, s.task.Id.Hex(), attachment.Id.Hex()))
+	s.Empty(s.blobs.Blobs)
+	s.ErrorIs(s.useCase.Delete(s.ctx, s.task.Id.Hex(), attachment.Id.Hex()), domain.ErrAttachmentNotFound)
+}
+
+func (s *AttachmentUseCaseSuite) TestAttachmentOfOtherTaskIsNotFound() {
+	attachment := s.upload("hello")
+	attachment.TaskID = primitive.NewObjectID()
+	_, _, err := s.useCase.Download(s.ctx, s.task.Id.Hex(), attachment.Id.Hex())
+	s.ErrorIs(err, domain.ErrAttachmentNotFound)
+}
+
+func (s *AttachmentUseCaseSuite) TestTaskDeletionRemovesAttachments() {
+	s.upload("one")
+	s.upload("two")
+
+	s.Require().NoError(s.taskUC.DeleteTask(s.ctx, s.task.Id.Hex()))
+	s.Empty(s.blobs.Blobs)
+	s.Empty(s.repo.Attachments)
+}
+
+func (s *AttachmentUseCaseSuite) TestFailedTaskDeletionKeepsAttachments() {
+	s.upload("one")
+	transactor := &FakeTransactor{}
+	s.taskUC.SetTransactor(transactor)
+	var events []domain.TaskEventType
+	s.taskUC.Subscribe(func(c context.Context, event domain.TaskEvent) {
+		events = append(events, event.Type)
+	})
+	hookErr := errors.New("hook failed")
+	s.taskUC.OnDelete(func(c context.Context, task *domain.Task) error { return hookErr })
+
+	err := s.taskUC.DeleteTask(s.ctx, s.task.Id.Hex())
+	s.ErrorIs(err, hookErr)
+	s.Equal(1, transactor.Calls)
+	s.Len(s.blobs.Blobs, 1, "blobs are only deleted once the transaction commits")
+	s.Empty(events, "no event is published for a rolled back deletion")
+}
@@ -0,0 +1,120 @@
+package usecases
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const calendarTokenRandomBytes = 32
+
+// CalendarUseCase serves per-user iCalendar feeds. Calendar apps cannot send an
+// Authorization header, so each user gets a secret token that is part of the feed
+// URL instead. Only its hash is stored.
+type CalendarUseCase struct {
+	userRepo domain.UserRepository
+	roleRepo domain.RoleRepository
+	tasks    *TaskUseCase
+	renderer domain.CalendarRenderer
+}
+
+func NewCalendarUseCase(userRepo domain.UserRepository, roleRepo domain.RoleRepository, tasks *TaskUseCase, renderer domain.CalendarRenderer) *CalendarUseCase {
+	return &CalendarUseCase{
+		userRepo: userRepo,
+		roleRepo: roleRepo,
+		tasks:    tasks,
+		renderer: renderer,
+	}
+}
+
+// RotateFeedToken issues a new feed token for the user, replacing and thereby
+// revoking the previous one. The plaintext token cannot be retrieved again.
+func (uc *CalendarUseCase) RotateFeedToken(c context.Context, userID string) (string, error) {
+	user, err := uc.getUser(c, userID)
+	if err != nil {
+		return "", err
+	}
+
+	raw := make([]byte, calendarTokenRandomBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("usecase: failed to generate calendar token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	user.CalendarTokenHash = hashSecretToken(token)
+
+	if _, err := uc.userRepo.UpdateUser(c, user.Id, user); err != nil {
+		return "", fmt.Errorf("usecase: failed to save calendar token: %w", err)
+	}
+	return token, nil
+}
+
+// RevokeFeedToken disables the user's feed until a new token is issued.
+func (uc *CalendarUseCase) RevokeFeedToken(c context.Context, userID string) error {
+	user, err := uc.getUser(c, userID)
+	if err != nil {
+		return err
+	}
+	user.CalendarTokenHash = ""
+	if _, err := uc.userRepo.UpdateUser(c, user.Id, user); err != nil {
+		return fmt.Errorf("usecase: failed to revoke calendar token: %w", err)
+	}
+	return nil
+}
+
+// RenderFeed writes the calendar of the token's owner. The owner's current role decides
+// what the feed contains, exactly as if they had called GET /tasks.
+func (uc *CalendarUseCase) RenderFeed(c context.Context, token string, kind domain.CalendarItemKind, w io.Writer) error {
+	if !kind.IsValid() {
+		return fmt.Errorf("%w: unknown calendar item kind %q", domain.ErrValidationFailed, kind)
+	}
+	if token == "" {
+		return domain.ErrInvalidFeedToken
+	}
+	user, err := uc.userRepo.GetUserByCalendarTokenHash(c, hashSecretToken(token))
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return domain.ErrInvalidFeedToken
+		}
+		return fmt.Errorf("usecase: failed to look up calendar token: %w", err)
+	}
+	if user.OrganizationID.IsZero() {
+		return domain.ErrInvalidFeedToken
+	}
+
+	// The feed URL is unauthenticated, so the owner's organization comes from the owner.
+	c = domain.ContextWithOrganization(c, user.OrganizationID)
+	actor, err := userActor(c, uc.roleRepo, user)
+	if err != nil {
+		return err
+	}
+	if !actor.Has(domain.PermTasksRead) {
+		return fmt.Errorf("%w: missing permission %s", domain.ErrForbidden, domain.PermTasksRead)
+	}
+
+	tasks, err := uc.tasks.GetAllTasks(domain.ContextWithActor(c, actor))
+	if err != nil {
+		return err
+	}
+	return uc.renderer.Render(c, w, user.Username+"'s tasks", tasks, kind)
+}
+
+func (uc *CalendarUseCase) getUser(c context.Context, userID string) (*domain.User, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid user ID format", domain.ErrValidationFailed)
+	}
+	user, err := uc.userRepo.GetUserById(c, objectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("usecase: failed to get user by ID: %w", err)
+	}
+	return user, nil
+}
@@ -0,0 +1,128 @@
+package usecases_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockCalendarRenderer records what it was asked to render.
+type MockCalendarRenderer struct {
+	Name  string
+	Tasks []*domain.Task
+	Kind  domain.CalendarItemKind
+}
+
+func (m *MockCalendarRenderer) Render(c context.Context, w io.Writer, name string, tasks []*domain.Task, kind domain.CalendarItemKind) error {
+	m.Name, m.Tasks, m.Kind = name, tasks, kind
+	_, err := io.WriteString(w, "BEGIN:VCALENDAR")
+	return err
+}
+
+//===========================================================================
+// CalendarUseCase Test Suite
+//===========================================================================
+
+type CalendarUseCaseSuite struct {
+	suite.Suite
+	user         *domain.User
+	mockUserRepo *MockUserRepository
+	mockRoleRepo *MockRoleRepository
+	renderer     *MockCalendarRenderer
+	taskFilter   domain.TaskFilter
+	useCase      *usecases.CalendarUseCase
+	ctx          context.Context
+}
+
+func TestCalendarUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(CalendarUseCaseSuite))
+}
+
+func (s *CalendarUseCaseSuite) SetupTest() {
+	s.user = &domain.User{Id: primitive.NewObjectID(), OrganizationID: primitive.NewObjectID(), Username: "alice", Role: domain.RoleUser}
+	s.mockUserRepo = &MockUserRepository{
+		GetUserByIdFunc: func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			if id != s.user.Id {
+				return nil, domain.ErrUserNotFound
+			}
+			return s.user, nil
+		},
+		UpdateUserFunc: func(c context.Context, id primitive.ObjectID, user *domain.User) (*domain.User, error) {
+			s.user = user
+			return user, nil
+		},
+		GetUserByCalendarTokenHashFunc: func(c context.Context, tokenHash string) (*domain.User, error) {
+			if tokenHash == "" || tokenHash != s.user.CalendarTokenHash {
+				return nil, domain.ErrUserNotFound
+			}
+			return s.user, nil
+		},
+	}
+	s.mockRoleRepo = NewMockRoleRepository(domain.DefaultRoleDefinitions()...)
+	task := &domain.Task{Id: primitive.NewObjectID(), Title: "Due soon", Status: domain.Pending, DueDate: time.Now().Add(24 * time.Hour)}
+	taskRepo := newInMemoryTaskRepository(task)
+	listTasks := taskRepo.GetAllTasksFunc
+	taskRepo.GetAllTasksFunc = func(c context.Context, filter domain.TaskFilter) ([]*domain.Task, error) {
+		s.taskFilter = filter
+		return listTasks(c, filter)
+	}
+	s.renderer = &MockCalendarRenderer{}
+	taskUC := usecases.NewTaskUseCase(taskRepo, NewMockProjectRepository())
+	s.useCase = usecases.NewCalendarUseCase(s.mockUserRepo, s.mockRoleRepo, taskUC, s.renderer)
+	s.ctx = context.Background()
+}
+
+func (s *CalendarUseCaseSuite) TestFeed() {
+	s.Run("Rotated Token Works", func() {
+		s.SetupTest()
+		token, err := s.useCase.RotateFeedToken(s.ctx, s.user.Id.Hex())
+		s.Require().NoError(err)
+		s.NotEmpty(token)
+		s.NotEqual(token, s.user.CalendarTokenHash, "only the hash is stored")
+
+		var out strings.Builder
+		s.Require().NoError(s.useCase.RenderFeed(s.ctx, token, domain.CalendarTodos, &out))
+		s.Equal("BEGIN:VCALENDAR", out.String())
+		s.Len(s.renderer.Tasks, 1)
+		s.Equal(domain.CalendarTodos, s.renderer.Kind)
+		s.True(s.taskFilter.Restricted, "the feed applies the owner's project visibility")
+	})
+
+	s.Run("Rotating Replaces The Old Token", func() {
+		s.SetupTest()
+		first, _ := s.useCase.RotateFeedToken(s.ctx, s.user.Id.Hex())
+		second, err := s.useCase.RotateFeedToken(s.ctx, s.user.Id.Hex())
+		s.Require().NoError(err)
+		s.NotEqual(first, second)
+		s.ErrorIs(s.useCase.RenderFeed(s.ctx, first, domain.CalendarEvents, io.Discard), domain.ErrInvalidFeedToken)
+		s.NoError(s.useCase.RenderFeed(s.ctx, second, domain.CalendarEvents, io.Discard))
+	})
+
+	s.Run("Revoked Token", func() {
+		s.SetupTest()
+		token, _ := s.useCase.RotateFeedToken(s.ctx, s.user.Id.Hex())
+		s.Require().NoError(s.useCase.RevokeFeedToken(s.ctx, s.user.Id.Hex()))
+		s.ErrorIs(s.useCase.RenderFeed(s.ctx, token, domain.CalendarEvents, io.Discard), domain.ErrInvalidFeedToken)
+		s.ErrorIs(s.useCase.RenderFeed(s.ctx, "", domain.CalendarEvents, io.Discard), domain.ErrInvalidFeedToken)
+	})
+
+	s.Run("Role Without Read Permission", func() {
+		s.SetupTest()
+		token, _ := s.useCase.RotateFeedToken(s.ctx, s.user.Id.Hex())
+		s.mockRoleRepo.Roles[domain.RoleUser] = &domain.RoleDefinition{Name: domain.RoleUser}
+		s.ErrorIs(s.useCase.RenderFeed(s.ctx, token, domain.CalendarEvents, io.Discard), domain.ErrForbidden)
+	})
+
+	s.Run("Unknown Item Kind", func() {
+		s.SetupTest()
+		token, _ := s.useCase.RotateFeedToken(s.ctx, s.user.Id.Hex())
+		s.ErrorIs(s.useCase.RenderFeed(s.ctx, token, "journal", io.Discard), domain.ErrValidationFailed)
+	})
+}
@@ -0,0 +1,320 @@
+package usecases
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationUseCase fills users' inboxes. It learns about changes from task events and
+// finds overdue tasks with NotifyOverdueTasks, which the server runs periodically.
+type NotificationUseCase struct {
+	notificationRepo domain.NotificationRepository
+	userRepo         domain.UserRepository
+	roleRepo         domain.RoleRepository
+	tasks            *TaskUseCase
+	clock            domain.Clock
+}
+
+// NewNotificationUseCase creates the use case and subscribes it to task events, so that
+// owners and watchers are told when someone else updates or deletes a task, and users
+// are told when they are mentioned in one. roleRepo resolves what recipients may see.
+func NewNotificationUseCase(notificationRepo domain.NotificationRepository, userRepo domain.UserRepository, roleRepo domain.RoleRepository, tasks *TaskUseCase) *NotificationUseCase {
+	uc := &NotificationUseCase{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		roleRepo:         roleRepo,
+		tasks:            tasks,
+		clock:            domain.SystemClock{},
+	}
+	tasks.Subscribe(uc.handleTaskEvent)
+	return uc
+}
+
+// SetClock replaces the system clock, which decides when tasks are overdue and stamps
+// read times.
+func (uc *NotificationUseCase) SetClock(clock domain.Clock) {
+	uc.clock = clock
+}
+
+// GetNotifications returns a page of the user's inbox, newest first. A zero limit means
+// DefaultNotificationPageSize.
+func (uc *NotificationUseCase) GetNotifications(c context.Context, userID string, query domain.NotificationQuery) (*domain.NotificationPage, error) {
+	if query.Limit == 0 {
+		query.Limit = domain.DefaultNotificationPageSize
+	}
+	violations := &domain.ValidationError{}
+	if query.Limit < 0 || query.Limit > domain.MaxNotificationPageSize {
+		violations.Add("limit", domain.RuleMax, fmt.Sprintf("limit must be between 1 and %d", domain.MaxNotificationPageSize))
+	}
+	if query.Offset < 0 {
+		violations.Add("offset", domain.RuleFormat, "offset cannot be negative")
+	}
+	if err := violations.Err(); err != nil {
+		return nil, err
+	}
+
+	notifications, total, err := uc.notificationRepo.GetNotifications(c, userID, query)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to get notifications: %w", err)
+	}
+	unread, err := uc.notificationRepo.CountUnread(c, userID)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to count unread notifications: %w", err)
+	}
+	return &domain.NotificationPage{
+		Notifications: notifications,
+		Total:         total,
+		Unread:        unread,
+		Limit:         query.Limit,
+		Offset:        query.Offset,
+	}, nil
+}
+
+// MarkRead marks one of the user's notifications read.
+func (uc *NotificationUseCase) MarkRead(c context.Context, userID, notificationID string) error {
+	objectID, err := primitive.ObjectIDFromHex(notificationID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid notification ID format", domain.ErrValidationFailed)
+	}
+	if err := uc.notificationRepo.MarkRead(c, userID, objectID, uc.clock.Now()); err != nil {
+		if errors.Is(err, domain.ErrNotificationNotFound) {
+			return err
+		}
+		return fmt.Errorf("usecase: failed to mark notification read: %w", err)
+	}
+	return nil
+}
+
+// MarkAllRead marks every notification of the user read and returns how many were unread.
+func (uc *NotificationUseCase) MarkAllRead(c context.Context, userID string) (int64, error) {
+	marked, err := uc.notificationRepo.MarkAllRead(c, userID, uc.clock.Now())
+	if err != nil {
+		return 0, fmt.Errorf("usecase: failed to mark notifications read: %w", err)
+	}
+	return marked, nil
+}
+
+func (uc *NotificationUseCase) GetPreferences(c context.Context, userID string) (domain.NotificationPreferences, error) {
+	user, err := uc.getUser(c, userID)
+	if err != nil {
+		return nil, err
+	}
+	return user.NotificationPreferences(), nil
+}
+
+// SetPreferences turns the named notification types on or off and leaves the others as
+// they are. It returns the resulting preferences.
+func (uc *NotificationUseCase) SetPreferences(c context.Context, userID string, preferences domain.NotificationPreferences) (domain.NotificationPreferences, error) {
+	user, err := uc.getUser(c, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := user.SetNotificationPreferences(preferences); err != nil {
+		return nil, err
+	}
+	if _, err := uc.userRepo.UpdateUser(c, user.Id, user); err != nil {
+		return nil, fmt.Errorf("usecase: failed to save notification preferences: %w", err)
+	}
+	return user.NotificationPreferences(), nil
+}
+
+// NotifyOverdueTasks tells the owners of unfinished tasks whose due date has passed. It
+// covers every organization when c has the system scope. Plain due dates are overdue
+// once their day has ended in the owner's time zone (see domain.OverdueAt). Each task is
+// reported once per due date, however often this runs, so moving the due date and
+// missing it again is reported again. It returns how many notifications were created.
+func (uc *NotificationUseCase) NotifyOverdueTasks(c context.Context) (int, error) {
+	now := uc.clock.Now()
+	filter := domain.TaskFilter{Statuses: []domain.TaskStatus{domain.Pending, domain.InProgress}, DueBefore: &now}
+	created := 0
+	err := uc.tasks.taskRepo.StreamTasks(c, filter, func(task *domain.Task) error {
+		c := domain.ContextWithOrganization(c, task.OrganizationID)
+		owner, ok := uc.recipient(c, task.OwnerID, task)
+		if !ok || now.Before(domain.OverdueAt(task.DueDate, userLocation(owner))) {
+			return c.Err()
+		}
+		notification := &domain.Notification{
+			Type:           domain.NotificationTaskOverdue,
+			TaskID:         task.Id,
+			TaskTitle:      task.Title,
+			CreatedAt:      now,
+			Key:            fmt.Sprintf("%s:%s:%d", domain.NotificationTaskOverdue, task.Id.Hex(), task.DueDate.Unix()),
+			OrganizationID: task.OrganizationID,
+		}
+		if uc.deliver(c, owner, notification) {
+			created++
+		}
+		return c.Err()
+	})
+	if err != nil {
+		return created, fmt.Errorf("usecase: failed to look for overdue tasks: %w", err)
+	}
+	return created, nil
+}
+
+// userLocation returns the time zone from the user's profile, or UTC without one.
+func userLocation(user *domain.User) *time.Location {
+	if user.TimeZone == "" {
+		return time.UTC
+	}
+	location, err := domain.LoadTimeZone(user.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+func (uc *NotificationUseCase) handleTaskEvent(c context.Context, event domain.TaskEvent) {
+	var changes []domain.FieldChange
+	switch event.Type {
+	case domain.TaskCreated:
+	case domain.TaskUpdated:
+		changes = domain.DiffTasks(event.Previous, event.Task)
+	case domain.TaskDeleted:
+	default:
+		return
+	}
+
+	actorID := actorUserID(c)
+	for _, recipient := range uc.recipients(event.Task) {
+		// People are not told about their own changes.
+		if recipient == actorID {
+			continue
+		}
+		notification := &domain.Notification{
+			TaskID:    event.Task.Id,
+			TaskTitle: event.Task.Title,
+			ActorID:   actorID,
+			CreatedAt: event.OccurredAt,
+		}
+		switch {
+		case event.Type == domain.TaskDeleted:
+			notification.Type = domain.NotificationTaskDeleted
+		case event.Task.IsWatchedBy(recipient) && (event.Previous == nil || !event.Previous.IsWatchedBy(recipient)):
+			// Only mentions make someone else a watcher.
+			notification.Type = domain.NotificationTaskMentioned
+		case len(changes) > 0:
+			notification.Type = domain.NotificationTaskUpdated
+			notification.Changes = changes
+		default:
+			continue
+		}
+		uc.notify(c, recipient, event.Task, notification)
+	}
+}
+
+// recipients returns the IDs of the users who hear about changes to the task: its
+// owner and its watchers. Those who can no longer see the task are left out later, by
+// recipient.
+func (uc *NotificationUseCase) recipients(task *domain.Task) []string {
+	recipients := []string{}
+	if task.OwnerID != "" {
+		recipients = append(recipients, task.OwnerID)
+	}
+	for _, watcher := range task.Watchers {
+		if !slices.Contains(recipients, watcher) {
+			recipients = append(recipients, watcher)
+		}
+	}
+	return recipients
+}
+
+// notify delivers a notification about task to the user unless they cannot see the
+// task or turned the notification's type off. It reports whether the notification was
+// stored. Failures are logged rather than returned, since the change that caused the
+// notification has already been made.
+func (uc *NotificationUseCase) notify(c context.Context, userID string, task *domain.Task, notification *domain.Notification) bool {
+	user, ok := uc.recipient(c, userID, task)
+	if !ok {
+		return false
+	}
+	return uc.deliver(c, user, notification)
+}
+
+// recipient looks up the user with an inbox that a notification about task for userID
+// goes to. Users who cannot read the task by the rules of GetTaskByID, such as members
+// removed from its project, have none.
+func (uc *NotificationUseCase) recipient(c context.Context, userID string, task *domain.Task) (*domain.User, bool) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, false
+	}
+	user, err := uc.userRepo.GetUserById(c, objectID)
+	if err != nil {
+		// Tasks created with an API key are owned by a service account, which has
+		// no inbox.
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			log.Printf("notifications: failed to look up user %s: %v", userID, err)
+		}
+		return nil, false
+	}
+
+	actor, err := userActor(c, uc.roleRepo, user)
+	if err != nil {
+		log.Printf("notifications: %v", err)
+		return nil, false
+	}
+	if !actor.Has(domain.PermTasksRead) {
+		return nil, false
+	}
+	if err := uc.tasks.authorizeTask(domain.ContextWithActor(c, actor), task, false); err != nil {
+		if !errors.Is(err, domain.ErrTaskNotFound) {
+			log.Printf("notifications: failed to check whether user %s can see task %s: %v", userID, task.Id.Hex(), err)
+		}
+		return nil, false
+	}
+	return user, true
+}
+
+// deliver stores the notification for user unless they turned its type off.
+func (uc *NotificationUseCase) deliver(c context.Context, user *domain.User, notification *domain.Notification) bool {
+	if !user.WantsNotification(notification.Type) {
+		return false
+	}
+	notification.UserID = user.Id.Hex()
+	if _, err := uc.notificationRepo.CreateNotification(c, notification); err != nil {
+		if !errors.Is(err, domain.ErrNotificationExists) {
+			log.Printf("notifications: failed to notify user %s about task %s: %v", notification.UserID, notification.TaskID.Hex(), err)
+		}
+		return false
+	}
+	return true
+}
+
+// RunOverdueChecks calls NotifyOverdueTasks every interval until c is done.
+func (uc *NotificationUseCase) RunOverdueChecks(c context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := uc.NotifyOverdueTasks(c); err != nil && c.Err() == nil {
+			log.Printf("notifications: %v", err)
+		}
+		select {
+		case <-c.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (uc *NotificationUseCase) getUser(c context.Context, userID string) (*domain.User, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid user ID format", domain.ErrValidationFailed)
+	}
+	user, err := uc.userRepo.GetUserById(c, objectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("usecase: failed to get user by ID: %w", err)
+	}
+	return user, nil
+}
@@ -0,0 +1,312 @@
+package usecases_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// --- In-memory mock for notifications ---
+type MockNotificationRepository struct {
+	Notifications []*domain.Notification
+}
+
+func (m *MockNotificationRepository) CreateNotification(c context.Context, notification *domain.Notification) (*domain.Notification, error) {
+	for _, existing := range m.Notifications {
+		if notification.Key != "" && existing.Key == notification.Key {
+			return nil, domain.ErrNotificationExists
+		}
+	}
+	notification.Id = primitive.NewObjectID()
+	m.Notifications = append(m.Notifications, notification)
+	return notification, nil
+}
+func (m *MockNotificationRepository) GetNotifications(c context.Context, userID string, query domain.NotificationQuery) ([]*domain.Notification, int64, error) {
+	selected := []*domain.Notification{}
+	for i := len(m.Notifications) - 1; i >= 0; i-- {
+		notification := m.Notifications[i]
+		if notification.UserID == userID && (!query.UnreadOnly || notification.ReadAt == nil) {
+			selected = append(selected, notification)
+		}
+	}
+	total := int64(len(selected))
+	selected = selected[min(query.Offset, len(selected)):]
+	return selected[:min(query.Limit, len(selected))], total, nil
+}
+func (m *MockNotificationRepository) CountUnread(c context.Context, userID string) (int64, error) {
+	unread, _, err := m.GetNotifications(c, userID, domain.NotificationQuery{UnreadOnly: true, Limit: len(m.Notifications)})
+	return int64(len(unread)), err
+}
+func (m *MockNotificationRepository) MarkRead(c context.Context, userID string, id primitive.ObjectID, at time.Time) error {
+	for _, notification := range m.Notifications {
+		if notification.Id == id && notification.UserID == userID {
+			if notification.ReadAt == nil {
+				notification.ReadAt = &at
+			}
+			return nil
+		}
+	}
+	return domain.ErrNotificationNotFound
+}
+func (m *MockNotificationRepository) MarkAllRead(c context.Context, userID string, at time.Time) (int64, error) {
+	var marked int64
+	for _, notification := range m.Notifications {
+		if notification.UserID == userID && notification.ReadAt == nil {
+			notification.ReadAt = &at
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+//===========================================================================
+// NotificationUseCase Test Suite
+//===========================================================================
+
+type NotificationUseCaseSuite struct {
+	suite.Suite
+	owner         *domain.User
+	task          *domain.Task
+	projects      *MockProjectRepository
+	roles         *MockRoleRepository
+	notifications *MockNotificationRepository
+	now           time.Time
+	taskUC        *usecases.TaskUseCase
+	useCase       *usecases.NotificationUseCase
+	ownerCtx      context.Context
+	editorCtx     context.Context
+}
+
+func TestNotificationUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(NotificationUseCaseSuite))
+}
+
+func (s *NotificationUseCaseSuite) SetupTest() {
+	s.now = time.Date(2026, time.May, 4, 9, 0, 0, 0, time.UTC)
+	s.owner = &domain.User{Id: primitive.NewObjectID(), Username: "owner", Role: domain.RoleUser}
+	s.task = &domain.Task{Id: primitive.NewObjectID(), Title: "Write report", Status: domain.Pending, DueDate: s.now.Add(48 * time.Hour), OwnerID: s.owner.Id.Hex()}
+	taskRepo := newInMemoryTaskRepository(s.task)
+	taskRepo.UpdateTaskFunc = func(c context.Context, id primitive.ObjectID, task *domain.Task) (*domain.Task, error) {
+		*s.task = *task
+		return task, nil
+	}
+	taskRepo.DeleteTaskFunc = func(c context.Context, id primitive.ObjectID) error { return nil }
+	taskRepo.RemoveBlockerFromAllFunc = func(c context.Context, blockerID primitive.ObjectID) error { return nil }
+	taskRepo.GetAllTasksFunc = func(c context.Context, filter domain.TaskFilter) ([]*domain.Task, error) {
+		if filter.Matches(s.task) {
+			return []*domain.Task{s.task}, nil
+		}
+		return []*domain.Task{}, nil
+	}
+	userRepo := &MockUserRepository{
+		GetUserByIdFunc: func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			if id != s.owner.Id {
+				return nil, domain.ErrUserNotFound
+			}
+			return s.owner, nil
+		},
+		UpdateUserFunc: func(c context.Context, id primitive.ObjectID, user *domain.User) (*domain.User, error) {
+			return user, nil
+		},
+	}
+
+	s.notifications = &MockNotificationRepository{}
+	s.projects = NewMockProjectRepository()
+	s.roles = NewMockRoleRepository(domain.DefaultRoleDefinitions()...)
+	s.taskUC = usecases.NewTaskUseCase(taskRepo, s.projects)
+	s.useCase = usecases.NewNotificationUseCase(s.notifications, userRepo, s.roles, s.taskUC)
+	s.useCase.SetClock(fixedClock(s.now))
+	s.ownerCtx = domain.ContextWithActor(context.Background(), &domain.Actor{UserID: s.owner.Id.Hex()})
+	s.editorCtx = domain.ContextWithActor(context.Background(), &domain.Actor{UserID: "editor-1"})
+}
+
+func (s *NotificationUseCaseSuite) TestOwnersHearAboutOtherPeoplesChanges() {
+	inProgress := domain.InProgress
+	_, err := s.taskUC.UpdateTask(s.editorCtx, s.task.Id.Hex(), nil, nil, nil, &inProgress)
+	s.Require().NoError(err)
+
+	s.Require().Len(s.notifications.Notifications, 1)
+	notification := s.notifications.Notifications[0]
+	s.Equal(s.owner.Id.Hex(), notification.UserID)
+	s.Equal(domain.NotificationTaskUpdated, notification.Type)
+	s.Equal("editor-1", notification.ActorID)
+	s.Equal([]domain.FieldChange{{Field: "status", Old: "Pending", New: "In progress"}}, notification.Changes)
+
+	title := "Write the report"
+	_, err = s.taskUC.UpdateTask(s.ownerCtx, s.task.Id.Hex(), &title, nil, nil, nil)
+	s.Require().NoError(err)
+	s.Len(s.notifications.Notifications, 1, "Owners are not told about their own changes")
+
+	s.Require().NoError(s.taskUC.DeleteTask(s.editorCtx, s.task.Id.Hex()))
+	s.Require().Len(s.notifications.Notifications, 2)
+	s.Equal(domain.NotificationTaskDeleted, s.notifications.Notifications[1].Type)
+	s.Equal("Write the report", s.notifications.Notifications[1].TaskTitle)
+}
+
+func (s *NotificationUseCaseSuite) TestMentionedUsersWatchTheTask() {
+	watcher := &domain.User{Id: primitive.NewObjectID(), Username: "watcher", Role: domain.RoleUser}
+	userRepo := &MockUserRepository{
+		GetUserByUsernameFunc: func(c context.Context, username string) (*domain.User, error) {
+			if username != watcher.Username {
+				return nil, domain.ErrUserNotFound
+			}
+			return watcher, nil
+		},
+		GetUserByIdFunc: func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			if id != watcher.Id {
+				return nil, domain.ErrUserNotFound
+			}
+			return watcher, nil
+		},
+	}
+	s.taskUC.SetUserRepository(userRepo)
+	s.useCase = usecases.NewNotificationUseCase(s.notifications, userRepo, s.roles, s.taskUC)
+
+	description := "@watcher can you check this?"
+	_, err := s.taskUC.UpdateTask(s.ownerCtx, s.task.Id.Hex(), nil, &description, nil, nil)
+	s.Require().NoError(err)
+	s.Require().Len(s.notifications.Notifications, 1)
+	s.Equal(watcher.Id.Hex(), s.notifications.Notifications[0].UserID)
+	s.Equal(domain.NotificationTaskMentioned, s.notifications.Notifications[0].Type)
+	s.Empty(s.notifications.Notifications[0].Changes)
+
+	inProgress := domain.InProgress
+	_, err = s.taskUC.UpdateTask(s.ownerCtx, s.task.Id.Hex(), nil, nil, nil, &inProgress)
+	s.Require().NoError(err)
+	s.Require().Len(s.notifications.Notifications, 2)
+	s.Equal(domain.NotificationTaskUpdated, s.notifications.Notifications[1].Type, "Watchers hear about later changes")
+}
+
+func (s *NotificationUseCaseSuite) TestOnlyPeopleWhoCanSeeTheTaskHearAboutIt() {
+	project := &domain.Project{Id: primitive.NewObjectID(), Name: "Website", Members: []domain.ProjectMember{{UserID: "editor-1", Role: domain.ProjectOwner}}}
+	s.projects.Projects[project.Id] = project
+	s.task.ProjectID = project.Id
+	s.editorCtx = domain.ContextWithActor(context.Background(), &domain.Actor{UserID: "editor-1", Permissions: []domain.Permission{domain.PermTasksRead}})
+
+	inProgress := domain.InProgress
+	_, err := s.taskUC.UpdateTask(s.editorCtx, s.task.Id.Hex(), nil, nil, nil, &inProgress)
+	s.Require().NoError(err)
+	s.Empty(s.notifications.Notifications, "The owner left the project and cannot see the task anymore")
+
+	s.useCase.SetClock(fixedClock(s.now.Add(72 * time.Hour)))
+	created, err := s.useCase.NotifyOverdueTasks(domain.ContextWithSystemScope(context.Background()))
+	s.Require().NoError(err)
+	s.Zero(created)
+
+	s.Require().NoError(project.SetMember(s.owner.Id.Hex(), domain.ProjectViewer))
+	created, err = s.useCase.NotifyOverdueTasks(domain.ContextWithSystemScope(context.Background()))
+	s.Require().NoError(err)
+	s.Equal(1, created, "Members of the project hear about its tasks")
+}
+
+func (s *NotificationUseCaseSuite) TestMutedTypesAreNotDelivered() {
+	preferences, err := s.useCase.SetPreferences(s.ownerCtx, s.owner.Id.Hex(), domain.NotificationPreferences{domain.NotificationTaskUpdated: false})
+	s.Require().NoError(err)
+	s.False(preferences[domain.NotificationTaskUpdated])
+	s.True(preferences[domain.NotificationTaskDeleted], "Types that are not named keep their setting")
+
+	inProgress := domain.InProgress
+	_, err = s.taskUC.UpdateTask(s.editorCtx, s.task.Id.Hex(), nil, nil, nil, &inProgress)
+	s.Require().NoError(err)
+	s.Empty(s.notifications.Notifications)
+
+	_, err = s.useCase.SetPreferences(s.ownerCtx, s.owner.Id.Hex(), domain.NotificationPreferences{"task.commented": true})
+	s.ErrorIs(err, domain.ErrValidationFailed)
+}
+
+func (s *NotificationUseCaseSuite) TestNotifyOverdueTasks() {
+	created, err := s.useCase.NotifyOverdueTasks(domain.ContextWithSystemScope(context.Background()))
+	s.Require().NoError(err)
+	s.Zero(created, "The task is not due yet")
+
+	s.useCase.SetClock(fixedClock(s.now.Add(72 * time.Hour)))
+	created, err = s.useCase.NotifyOverdueTasks(domain.ContextWithSystemScope(context.Background()))
+	s.Require().NoError(err)
+	s.Equal(1, created)
+	s.Equal(domain.NotificationTaskOverdue, s.notifications.Notifications[0].Type)
+	s.Empty(s.notifications.Notifications[0].ActorID)
+
+	created, err = s.useCase.NotifyOverdueTasks(domain.ContextWithSystemScope(context.Background()))
+	s.Require().NoError(err)
+	s.Zero(created, "Each due date is only reported once")
+
+	s.task.DueDate = s.now.Add(96 * time.Hour)
+	s.useCase.SetClock(fixedClock(s.now.Add(120 * time.Hour)))
+	created, err = s.useCase.NotifyOverdueTasks(domain.ContextWithSystemScope(context.Background()))
+	s.Require().NoError(err)
+	s.Equal(1, created, "Missing a new due date is reported again")
+
+	s.task.Status = domain.Done
+	s.useCase.SetClock(fixedClock(s.now.Add(240 * time.Hour)))
+	s.task.DueDate = s.now.Add(200 * time.Hour)
+	created, err = s.useCase.NotifyOverdueTasks(domain.ContextWithSystemScope(context.Background()))
+	s.Require().NoError(err)
+	s.Zero(created, "Finished tasks are never overdue")
+}
+
+func (s *NotificationUseCaseSuite) TestNotifyOverdueTasks_PlainDates() {
+	// A plain due date of May 10 for an owner in Los Angeles is stored as midnight there.
+	s.owner.TimeZone = "America/Los_Angeles"
+	losAngeles, err := time.LoadLocation(s.owner.TimeZone)
+	s.Require().NoError(err)
+	s.task.DueDate = domain.DueDate{Time: time.Date(2026, time.May, 10, 0, 0, 0, 0, time.UTC), DateOnly: true}.In(losAngeles)
+	system := domain.ContextWithSystemScope(context.Background())
+
+	for _, now := range []time.Time{
+		time.Date(2026, time.May, 10, 20, 0, 0, 0, time.UTC), // 13:00 on May 10 in Los Angeles
+		time.Date(2026, time.May, 11, 6, 59, 0, 0, time.UTC), // 23:59 on May 10 in Los Angeles
+	} {
+		s.useCase.SetClock(fixedClock(now))
+		created, err := s.useCase.NotifyOverdueTasks(system)
+		s.Require().NoError(err)
+		s.Zero(created, "The task is due all day in the owner's time zone")
+	}
+
+	s.useCase.SetClock(fixedClock(time.Date(2026, time.May, 11, 7, 0, 0, 0, time.UTC)))
+	created, err := s.useCase.NotifyOverdueTasks(system)
+	s.Require().NoError(err)
+	s.Equal(1, created, "The task is overdue once May 10 has ended in Los Angeles")
+}
+
+func (s *NotificationUseCaseSuite) TestInbox() {
+	for _, title := range []string{"First", "Second", "Third"} {
+		_, err := s.taskUC.UpdateTask(s.editorCtx, s.task.Id.Hex(), &title, nil, nil, nil)
+		s.Require().NoError(err)
+	}
+	ownerID := s.owner.Id.Hex()
+
+	page, err := s.useCase.GetNotifications(s.ownerCtx, ownerID, domain.NotificationQuery{Limit: 2})
+	s.Require().NoError(err)
+	s.Equal(int64(3), page.Total)
+	s.Equal(int64(3), page.Unread)
+	s.Require().Len(page.Notifications, 2)
+	s.Equal("Third", page.Notifications[0].TaskTitle)
+
+	s.Require().NoError(s.useCase.MarkRead(s.ownerCtx, ownerID, page.Notifications[0].Id.Hex()))
+	s.ErrorIs(s.useCase.MarkRead(s.editorCtx, "editor-1", page.Notifications[1].Id.Hex()), domain.ErrNotificationNotFound)
+	page, err = s.useCase.GetNotifications(s.ownerCtx, ownerID, domain.NotificationQuery{})
+	s.Require().NoError(err)
+	s.Equal(domain.DefaultNotificationPageSize, page.Limit)
+	s.Equal(int64(2), page.Unread)
+	s.Equal(s.now, *page.Notifications[0].ReadAt)
+
+	marked, err := s.useCase.MarkAllRead(s.ownerCtx, ownerID)
+	s.Require().NoError(err)
+	s.Equal(int64(2), marked)
+	page, err = s.useCase.GetNotifications(s.ownerCtx, ownerID, domain.NotificationQuery{UnreadOnly: true})
+	s.Require().NoError(err)
+	s.Zero(page.Total)
+
+	s.Run("Failure - Invalid Page", func() {
+		_, err := s.useCase.GetNotifications(s.ownerCtx, ownerID, domain.NotificationQuery{Limit: domain.MaxNotificationPageSize + 1, Offset: -1})
+
+		var validationErr *domain.ValidationError
+		s.Require().ErrorAs(err, &validationErr)
+		s.Len(validationErr.Violations, 2)
+	})
+}
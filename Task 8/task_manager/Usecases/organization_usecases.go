@@ -0,0 +1,227 @@
+package usecases
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const invitationRandomBytes = 32
+
+type OrganizationUseCase struct {
+	orgRepo        domain.OrganizationRepository
+	invitationRepo domain.InvitationRepository
+	users          *UserUseCase
+	roles          *RoleUseCase
+	transactor     domain.Transactor
+	clock          domain.Clock
+	removalHooks   []MemberRemovalHook
+}
+
+// MemberRemovalHook removes data that belongs to a member being removed from the
+// organization. It runs in the same unit of work as the removal, and an error rolls
+// the removal back.
+type MemberRemovalHook func(c context.Context, user *domain.User) error
+
+func NewOrganizationUseCase(orgRepo domain.OrganizationRepository, invitationRepo domain.InvitationRepository, users *UserUseCase, roles *RoleUseCase) *OrganizationUseCase {
+	return &OrganizationUseCase{
+		orgRepo:        orgRepo,
+		invitationRepo: invitationRepo,
+		users:          users,
+		roles:          roles,
+		transactor:     domain.NoopTransactor{},
+		clock:          domain.SystemClock{},
+	}
+}
+
+// SetTransactor makes creating an organization and accepting an invitation run in a
+// transaction. Without one, they run without a transaction.
+func (uc *OrganizationUseCase) SetTransactor(transactor domain.Transactor) {
+	uc.transactor = transactor
+}
+
+// SetClock replaces the system clock, which decides when invitations expire.
+func (uc *OrganizationUseCase) SetClock(clock domain.Clock) {
+	uc.clock = clock
+}
+
+// OnMemberRemoved registers a hook that removes data belonging to removed members.
+func (uc *OrganizationUseCase) OnMemberRemoved(hook MemberRemovalHook) {
+	uc.removalHooks = append(uc.removalHooks, hook)
+}
+
+// CreateOrganization creates an organization with the default roles and its first
+// member, an administrator with the given credentials.
+func (uc *OrganizationUseCase) CreateOrganization(c context.Context, name, slug, username, password string) (*domain.Organization, *domain.User, error) {
+	organization, err := domain.NewOrganization(name, slug, uc.clock.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var admin *domain.User
+	err = runUnitOfWork(c, uc.transactor, func(c context.Context) error {
+		organization, err = uc.orgRepo.CreateOrganization(c, organization)
+		if err != nil {
+			if errors.Is(err, domain.ErrOrganizationSlugTaken) {
+				return err
+			}
+			return fmt.Errorf("usecase: failed to save organization: %w", err)
+		}
+		if err := uc.roles.EnsureDefaultRoles(domain.ContextWithOrganization(c, organization.Id)); err != nil {
+			return err
+		}
+		admin, err = uc.users.registerUser(c, organization.Id, username, password, domain.RoleAdmin)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return organization, admin, nil
+}
+
+// GetOrganization returns the caller's organization.
+func (uc *OrganizationUseCase) GetOrganization(c context.Context) (*domain.Organization, error) {
+	organizationID, ok := domain.OrganizationFromContext(c)
+	if !ok {
+		return nil, domain.ErrOrganizationNotFound
+	}
+	organization, err := uc.orgRepo.GetOrganizationById(c, organizationID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrganizationNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("usecase: failed to get organization: %w", err)
+	}
+	return organization, nil
+}
+
+// Invite creates an invitation to the caller's organization for a member with the
+// given role, which must be defined there. The returned token is not stored anywhere
+// and cannot be retrieved again.
+func (uc *OrganizationUseCase) Invite(c context.Context, role domain.UserRole, createdBy string) (string, *domain.Invitation, error) {
+	invitation, err := domain.NewInvitation(role, createdBy, uc.clock.Now())
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := uc.users.roleRepo.GetRole(c, role); err != nil {
+		if errors.Is(err, domain.ErrRoleNotFound) {
+			return "", nil, fmt.Errorf("%w: role %q is not defined", domain.ErrValidationFailed, role)
+		}
+		return "", nil, fmt.Errorf("usecase: failed to look up role: %w", err)
+	}
+
+	raw := make([]byte, invitationRandomBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("usecase: failed to generate invitation token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	invitation.TokenHash = hashSecretToken(token)
+
+	savedInvitation, err := uc.invitationRepo.CreateInvitation(c, invitation)
+	if err != nil {
+		return "", nil, fmt.Errorf("usecase: failed to save invitation: %w", err)
+	}
+	return token, savedInvitation, nil
+}
+
+func (uc *OrganizationUseCase) GetInvitations(c context.Context) ([]*domain.Invitation, error) {
+	invitations, err := uc.invitationRepo.GetAllInvitations(c)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to get invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+func (uc *OrganizationUseCase) RevokeInvitation(c context.Context, invitationID string) error {
+	objectID, err := primitive.ObjectIDFromHex(invitationID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid invitation ID format", domain.ErrValidationFailed)
+	}
+	if err := uc.invitationRepo.DeleteInvitation(c, objectID); err != nil {
+		if errors.Is(err, domain.ErrInvitationNotFound) {
+			return err
+		}
+		return fmt.Errorf("usecase: failed to revoke invitation: %w", err)
+	}
+	return nil
+}
+
+// RegisterWithInvitation creates an account in the organization that issued the
+// invitation, with the role it names. Each invitation can be accepted once; it is
+// consumed in the same unit of work that creates the account, so a failed registration
+// leaves it usable.
+func (uc *OrganizationUseCase) RegisterWithInvitation(c context.Context, token, username, password string) (*domain.User, error) {
+	if token == "" {
+		return nil, domain.ErrInvalidInvitation
+	}
+	invitation, err := uc.invitationRepo.GetInvitationByTokenHash(c, hashSecretToken(token))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvitationNotFound) {
+			return nil, domain.ErrInvalidInvitation
+		}
+		return nil, fmt.Errorf("usecase: failed to look up invitation: %w", err)
+	}
+	if !invitation.IsActive(uc.clock.Now()) || invitation.OrganizationID.IsZero() {
+		return nil, domain.ErrInvalidInvitation
+	}
+
+	var user *domain.User
+	err = runUnitOfWork(domain.ContextWithOrganization(c, invitation.OrganizationID), uc.transactor, func(c context.Context) error {
+		if err := uc.invitationRepo.DeleteInvitation(c, invitation.Id); err != nil {
+			if errors.Is(err, domain.ErrInvitationNotFound) {
+				// Someone else accepted it first.
+				return domain.ErrInvalidInvitation
+			}
+			return fmt.Errorf("usecase: failed to consume invitation: %w", err)
+		}
+		user, err = uc.users.registerUser(c, invitation.OrganizationID, username, password, invitation.Role)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetMembers lists the users of the caller's organization.
+func (uc *OrganizationUseCase) GetMembers(c context.Context) ([]*domain.User, error) {
+	users, err := uc.users.userRepo.GetAllUsers(c)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to get members: %w", err)
+	}
+	return users, nil
+}
+
+// RemoveMember deletes a user from the caller's organization, together with their
+// project memberships, the tasks they watch and the service accounts they created, as
+// registered with OnMemberRemoved. Tokens already issued to them are rejected from then
+// on (see ValidateSession). Callers cannot remove themselves, so an organization always
+// keeps the member who manages it.
+func (uc *OrganizationUseCase) RemoveMember(c context.Context, userID string) error {
+	if actor, ok := domain.ActorFromContext(c); ok && actor.UserID == userID {
+		return fmt.Errorf("%w: you cannot remove yourself from the organization", domain.ErrValidationFailed)
+	}
+	return runUnitOfWork(c, uc.transactor, func(c context.Context) error {
+		user, err := uc.users.getUser(c, userID)
+		if err != nil {
+			return err
+		}
+		if err := uc.users.userRepo.DeleteUser(c, user.Id); err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) {
+				return err
+			}
+			return fmt.Errorf("usecase: failed to remove member: %w", err)
+		}
+		for _, hook := range uc.removalHooks {
+			if err := hook(c, user); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
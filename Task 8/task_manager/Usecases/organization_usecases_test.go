@@ -0,0 +1,274 @@
+package usecases_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockOrganizationRepository keeps organizations in a map keyed by slug.
+type MockOrganizationRepository struct {
+	Organizations map[string]*domain.Organization
+}
+
+func NewMockOrganizationRepository(organizations ...*domain.Organization) *MockOrganizationRepository {
+	m := &MockOrganizationRepository{Organizations: map[string]*domain.Organization{}}
+	for _, organization := range organizations {
+		m.Organizations[organization.Slug] = organization
+	}
+	return m
+}
+
+func (m *MockOrganizationRepository) CreateOrganization(c context.Context, organization *domain.Organization) (*domain.Organization, error) {
+	if _, ok := m.Organizations[organization.Slug]; ok {
+		return nil, domain.ErrOrganizationSlugTaken
+	}
+	organization.Id = primitive.NewObjectID()
+	m.Organizations[organization.Slug] = organization
+	return organization, nil
+}
+func (m *MockOrganizationRepository) GetOrganizationById(c context.Context, id primitive.ObjectID) (*domain.Organization, error) {
+	for _, organization := range m.Organizations {
+		if organization.Id == id {
+			return organization, nil
+		}
+	}
+	return nil, domain.ErrOrganizationNotFound
+}
+func (m *MockOrganizationRepository) GetOrganizationBySlug(c context.Context, slug string) (*domain.Organization, error) {
+	organization, ok := m.Organizations[slug]
+	if !ok {
+		return nil, domain.ErrOrganizationNotFound
+	}
+	return organization, nil
+}
+
+// MockInvitationRepository keeps invitations in a map and scopes them like the real
+// repository does.
+type MockInvitationRepository struct {
+	Invitations map[primitive.ObjectID]*domain.Invitation
+}
+
+func (m *MockInvitationRepository) CreateInvitation(c context.Context, invitation *domain.Invitation) (*domain.Invitation, error) {
+	invitation.Id = primitive.NewObjectID()
+	invitation.OrganizationID, _ = domain.OrganizationFromContext(c)
+	m.Invitations[invitation.Id] = invitation
+	return invitation, nil
+}
+func (m *MockInvitationRepository) GetInvitationByTokenHash(c context.Context, tokenHash string) (*domain.Invitation, error) {
+	for _, invitation := range m.Invitations {
+		if invitation.TokenHash == tokenHash {
+			return invitation, nil
+		}
+	}
+	return nil, domain.ErrInvitationNotFound
+}
+func (m *MockInvitationRepository) GetAllInvitations(c context.Context) ([]*domain.Invitation, error) {
+	organizationID, _ := domain.OrganizationFromContext(c)
+	invitations := []*domain.Invitation{}
+	for _, invitation := range m.Invitations {
+		if invitation.OrganizationID == organizationID {
+			invitations = append(invitations, invitation)
+		}
+	}
+	return invitations, nil
+}
+func (m *MockInvitationRepository) DeleteInvitation(c context.Context, id primitive.ObjectID) error {
+	organizationID, _ := domain.OrganizationFromContext(c)
+	invitation, ok := m.Invitations[id]
+	if !ok || invitation.OrganizationID != organizationID {
+		return domain.ErrInvitationNotFound
+	}
+	delete(m.Invitations, id)
+	return nil
+}
+
+//===========================================================================
+// OrganizationUseCase Test Suite
+//===========================================================================
+
+type OrganizationUseCaseSuite struct {
+	suite.Suite
+	orgRepo        *MockOrganizationRepository
+	invitationRepo *MockInvitationRepository
+	roleRepo       *MockRoleRepository
+	userRepo       *MockUserRepository
+	created        []*domain.User
+	now            time.Time
+	useCase        *usecases.OrganizationUseCase
+	ctx            context.Context
+}
+
+func TestOrganizationUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(OrganizationUseCaseSuite))
+}
+
+func (s *OrganizationUseCaseSuite) SetupTest() {
+	s.orgRepo = NewMockOrganizationRepository()
+	s.invitationRepo = &MockInvitationRepository{Invitations: map[primitive.ObjectID]*domain.Invitation{}}
+	s.roleRepo = NewMockRoleRepository(domain.DefaultRoleDefinitions()...)
+	s.created = nil
+	s.userRepo = &MockUserRepository{
+		GetUserByUsernameFunc: func(c context.Context, username string) (*domain.User, error) {
+			return nil, domain.ErrUserNotFound
+		},
+		CreateUserFunc: func(c context.Context, user *domain.User) (*domain.User, error) {
+			user.Id = primitive.NewObjectID()
+			s.created = append(s.created, user)
+			return user, nil
+		},
+	}
+	passwords := &MockPasswordService{HashFunc: func(c context.Context, password string) (string, error) {
+		return "hash:" + password, nil
+	}}
+	users := usecases.NewUserUseCase(s.userRepo, s.roleRepo, s.orgRepo, &MockJwtService{}, passwords, nil, nil)
+
+	s.now = time.Date(2026, time.May, 4, 9, 0, 0, 0, time.UTC)
+	s.useCase = usecases.NewOrganizationUseCase(s.orgRepo, s.invitationRepo, users, usecases.NewRoleUseCase(s.roleRepo))
+	s.useCase.SetClock(fixedClock(s.now))
+	s.ctx = context.Background()
+}
+
+// inOrganization returns a context for an administrator of the organization.
+func (s *OrganizationUseCaseSuite) inOrganization(organizationID primitive.ObjectID, userID string) context.Context {
+	c := domain.ContextWithOrganization(s.ctx, organizationID)
+	return domain.ContextWithActor(c, &domain.Actor{UserID: userID, Username: "admin", Role: domain.RoleAdmin})
+}
+
+func (s *OrganizationUseCaseSuite) TestCreateOrganization() {
+	s.Run("Success", func() {
+		s.roleRepo.Roles = map[domain.UserRole]*domain.RoleDefinition{}
+
+		organization, admin, err := s.useCase.CreateOrganization(s.ctx, "Acme", "acme", "wile", "s3cret")
+
+		s.Require().NoError(err)
+		s.Equal("acme", organization.Slug)
+		s.Equal(s.now, organization.CreatedAt)
+		s.Equal(organization.Id, admin.OrganizationID)
+		s.Equal(domain.RoleAdmin, admin.Role, "The first member administers the organization")
+		s.Contains(s.roleRepo.Roles, domain.RoleAdmin, "The default roles should be seeded")
+		s.Contains(s.roleRepo.Roles, domain.RoleUser)
+	})
+
+	s.Run("Failure - Slug Taken", func() {
+		_, _, err := s.useCase.CreateOrganization(s.ctx, "Acme Again", "acme", "road", "runner")
+
+		s.ErrorIs(err, domain.ErrOrganizationSlugTaken)
+	})
+
+	s.Run("Failure - Invalid Slug", func() {
+		_, _, err := s.useCase.CreateOrganization(s.ctx, "Acme", "-Acme-", "wile", "s3cret")
+
+		s.ErrorIs(err, domain.ErrValidationFailed)
+	})
+}
+
+func (s *OrganizationUseCaseSuite) TestInvitations() {
+	acme, _, err := s.useCase.CreateOrganization(s.ctx, "Acme", "acme", "wile", "s3cret")
+	s.Require().NoError(err)
+	c := s.inOrganization(acme.Id, s.created[0].Id.Hex())
+
+	s.Run("Failure - Undefined Role", func() {
+		_, _, err := s.useCase.Invite(c, "Auditor", "wile")
+
+		s.ErrorIs(err, domain.ErrValidationFailed)
+	})
+
+	token, invitation, err := s.useCase.Invite(c, domain.RoleUser, "wile")
+	s.Require().NoError(err)
+	s.NotEmpty(token)
+	s.NotEqual(token, invitation.TokenHash, "Only a hash of the token may be stored")
+	s.Equal(acme.Id, invitation.OrganizationID)
+	s.Equal(s.now.Add(domain.InvitationTTL), invitation.ExpiresAt)
+
+	invitations, err := s.useCase.GetInvitations(c)
+	s.Require().NoError(err)
+	s.Len(invitations, 1)
+	others, err := s.useCase.GetInvitations(s.inOrganization(primitive.NewObjectID(), "someone"))
+	s.Require().NoError(err)
+	s.Empty(others, "Invitations are only visible to their organization")
+
+	s.Run("Register", func() {
+		user, err := s.useCase.RegisterWithInvitation(s.ctx, token, "road", "runner")
+
+		s.Require().NoError(err)
+		s.Equal(acme.Id, user.OrganizationID)
+		s.Equal(domain.RoleUser, user.Role)
+		s.Empty(s.invitationRepo.Invitations, "The invitation should be consumed")
+	})
+
+	s.Run("Failure - Reused Token", func() {
+		_, err := s.useCase.RegisterWithInvitation(s.ctx, token, "coyote", "acme")
+
+		s.ErrorIs(err, domain.ErrInvalidInvitation)
+	})
+
+	s.Run("Failure - Expired", func() {
+		token, _, err := s.useCase.Invite(c, domain.RoleUser, "wile")
+		s.Require().NoError(err)
+		s.useCase.SetClock(fixedClock(s.now.Add(domain.InvitationTTL)))
+		defer s.useCase.SetClock(fixedClock(s.now))
+
+		_, err = s.useCase.RegisterWithInvitation(s.ctx, token, "coyote", "acme")
+
+		s.ErrorIs(err, domain.ErrInvalidInvitation)
+	})
+
+	s.Run("Revoke", func() {
+		_, invitation, err := s.useCase.Invite(c, domain.RoleUser, "wile")
+		s.Require().NoError(err)
+
+		s.ErrorIs(s.useCase.RevokeInvitation(s.inOrganization(primitive.NewObjectID(), "someone"), invitation.Id.Hex()), domain.ErrInvitationNotFound,
+			"Other organizations cannot revoke the invitation")
+		s.NoError(s.useCase.RevokeInvitation(c, invitation.Id.Hex()))
+	})
+}
+
+func (s *OrganizationUseCaseSuite) TestRemoveMember() {
+	adminID := primitive.NewObjectID()
+	memberID := primitive.NewObjectID()
+	var deleted primitive.ObjectID
+	var cleanedUp []string
+	s.userRepo.GetUserByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+		if id != memberID && id != adminID {
+			return nil, domain.ErrUserNotFound
+		}
+		return &domain.User{Id: id, Username: "road"}, nil
+	}
+	s.userRepo.DeleteUserFunc = func(c context.Context, id primitive.ObjectID) error {
+		deleted = id
+		return nil
+	}
+	s.useCase.OnMemberRemoved(func(c context.Context, user *domain.User) error {
+		cleanedUp = append(cleanedUp, user.Username)
+		return nil
+	})
+	c := s.inOrganization(primitive.NewObjectID(), adminID.Hex())
+
+	s.ErrorIs(s.useCase.RemoveMember(c, adminID.Hex()), domain.ErrValidationFailed, "Admins cannot remove themselves")
+	s.True(deleted.IsZero())
+	s.ErrorIs(s.useCase.RemoveMember(c, primitive.NewObjectID().Hex()), domain.ErrUserNotFound)
+
+	s.Require().NoError(s.useCase.RemoveMember(c, memberID.Hex()))
+	s.Equal(memberID, deleted)
+	s.Equal([]string{"road"}, cleanedUp, "Hooks should clean up after the removed member")
+
+	s.Run("Failing Hooks Roll Back", func() {
+		transactor := &FakeTransactor{}
+		s.useCase.SetTransactor(transactor)
+		s.useCase.OnMemberRemoved(func(c context.Context, user *domain.User) error {
+			return fmt.Errorf("%w: still owns a project", domain.ErrValidationFailed)
+		})
+
+		err := s.useCase.RemoveMember(c, memberID.Hex())
+
+		s.ErrorIs(err, domain.ErrValidationFailed)
+		s.Equal(1, transactor.Calls)
+	})
+}
@@ -0,0 +1,192 @@
+package usecases
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ProjectUseCase struct {
+	projectRepo domain.ProjectRepository
+	userRepo    domain.UserRepository
+	clock       domain.Clock
+}
+
+func NewProjectUseCase(projectRepo domain.ProjectRepository, userRepo domain.UserRepository) *ProjectUseCase {
+	return &ProjectUseCase{
+		projectRepo: projectRepo,
+		userRepo:    userRepo,
+		clock:       domain.SystemClock{},
+	}
+}
+
+// SetClock replaces the system clock used for creation times.
+func (uc *ProjectUseCase) SetClock(clock domain.Clock) {
+	uc.clock = clock
+}
+
+// CreateProject creates a project owned by the caller.
+func (uc *ProjectUseCase) CreateProject(c context.Context, name, description string) (*domain.Project, error) {
+	actor, ok := domain.ActorFromContext(c)
+	if !ok {
+		return nil, fmt.Errorf("%w: projects must be created by a user", domain.ErrForbidden)
+	}
+	project, err := domain.NewProject(name, description, actor.UserID, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	savedProject, err := uc.projectRepo.CreateProject(c, project)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to save project: %w", err)
+	}
+	return savedProject, nil
+}
+
+// GetMyProjects lists the projects the caller is a member of.
+func (uc *ProjectUseCase) GetMyProjects(c context.Context) ([]*domain.Project, error) {
+	actor, ok := domain.ActorFromContext(c)
+	if !ok {
+		return []*domain.Project{}, nil
+	}
+	projects, err := uc.projectRepo.GetProjectsForMember(c, actor.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to get projects: %w", err)
+	}
+	return projects, nil
+}
+
+func (uc *ProjectUseCase) GetProject(c context.Context, projectID string) (*domain.Project, error) {
+	project, err := uc.getProject(c, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := callerProjectRole(c, project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// SetMember adds a user to the project or changes their role. Only owners may manage members.
+func (uc *ProjectUseCase) SetMember(c context.Context, projectID, userID string, role domain.ProjectRole) (*domain.Project, error) {
+	project, err := uc.getOwnedProject(c, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid user ID format", domain.ErrValidationFailed)
+	}
+	if _, err := uc.userRepo.GetUserById(c, userObjectID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("usecase: failed to look up user: %w", err)
+	}
+
+	if err := project.SetMember(userID, role); err != nil {
+		return nil, err
+	}
+	return uc.saveProject(c, project)
+}
+
+// RemoveMember removes a user from the project. Owners may remove anyone; members may remove themselves.
+func (uc *ProjectUseCase) RemoveMember(c context.Context, projectID, userID string) (*domain.Project, error) {
+	project, err := uc.getProject(c, projectID)
+	if err != nil {
+		return nil, err
+	}
+	role, err := callerProjectRole(c, project)
+	if err != nil {
+		return nil, err
+	}
+	actor, hasActor := domain.ActorFromContext(c)
+	leaving := hasActor && actor.UserID == userID
+	if role != domain.ProjectOwner && !leaving {
+		return nil, fmt.Errorf("%w: only project owners can remove members", domain.ErrForbidden)
+	}
+
+	if err := project.RemoveMember(userID); err != nil {
+		return nil, err
+	}
+	return uc.saveProject(c, project)
+}
+
+// RemoveFromAllProjects is a MemberRemovalHook that takes a removed member out of
+// every project. It fails while they are the only owner of a project, which must be
+// handed over first.
+func (uc *ProjectUseCase) RemoveFromAllProjects(c context.Context, user *domain.User) error {
+	userID := user.Id.Hex()
+	projects, err := uc.projectRepo.GetProjectsForMember(c, userID)
+	if err != nil {
+		return fmt.Errorf("usecase: failed to get projects: %w", err)
+	}
+	for _, project := range projects {
+		if err := project.RemoveMember(userID); err != nil {
+			return fmt.Errorf("project %q: %w", project.Name, err)
+		}
+		if _, err := uc.saveProject(c, project); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *ProjectUseCase) getOwnedProject(c context.Context, projectID string) (*domain.Project, error) {
+	project, err := uc.getProject(c, projectID)
+	if err != nil {
+		return nil, err
+	}
+	role, err := callerProjectRole(c, project)
+	if err != nil {
+		return nil, err
+	}
+	if role != domain.ProjectOwner {
+		return nil, fmt.Errorf("%w: only project owners can manage members", domain.ErrForbidden)
+	}
+	return project, nil
+}
+
+func (uc *ProjectUseCase) getProject(c context.Context, projectID string) (*domain.Project, error) {
+	objectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid project ID format", domain.ErrValidationFailed)
+	}
+	project, err := uc.projectRepo.GetProjectById(c, objectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrProjectNotFound) {
+			return nil, domain.ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("usecase: failed to get project: %w", err)
+	}
+	return project, nil
+}
+
+func (uc *ProjectUseCase) saveProject(c context.Context, project *domain.Project) (*domain.Project, error) {
+	updated, err := uc.projectRepo.UpdateProject(c, project.Id, project)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to update project: %w", err)
+	}
+	return updated, nil
+}
+
+// callerProjectRole returns the caller's role in an already loaded project. Callers
+// with projects:manage, such as domain.SystemActor, act as owners; non-members get
+// ErrProjectNotFound so that the project's existence is not revealed.
+func callerProjectRole(c context.Context, project *domain.Project) (domain.ProjectRole, error) {
+	actor, ok := domain.ActorFromContext(c)
+	if !ok {
+		return "", errNoActor
+	}
+	if actor.Has(domain.PermProjectsManage) {
+		return domain.ProjectOwner, nil
+	}
+	role, isMember := project.MemberRole(actor.UserID)
+	if !isMember {
+		return "", domain.ErrProjectNotFound
+	}
+	return role, nil
+}
@@ -0,0 +1,221 @@
+package usecases_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// --- In-memory mock for projects ---
+type MockProjectRepository struct {
+	Projects map[primitive.ObjectID]*domain.Project
+}
+
+func NewMockProjectRepository() *MockProjectRepository {
+	return &MockProjectRepository{Projects: map[primitive.ObjectID]*domain.Project{}}
+}
+
+func (m *MockProjectRepository) CreateProject(c context.Context, project *domain.Project) (*domain.Project, error) {
+	project.Id = primitive.NewObjectID()
+	m.Projects[project.Id] = project
+	return project, nil
+}
+func (m *MockProjectRepository) GetProjectById(c context.Context, id primitive.ObjectID) (*domain.Project, error) {
+	project, ok := m.Projects[id]
+	if !ok {
+		return nil, domain.ErrProjectNotFound
+	}
+	return project, nil
+}
+func (m *MockProjectRepository) GetProjectsForMember(c context.Context, userID string) ([]*domain.Project, error) {
+	projects := []*domain.Project{}
+	for _, project := range m.Projects {
+		if _, ok := project.MemberRole(userID); ok {
+			projects = append(projects, project)
+		}
+	}
+	return projects, nil
+}
+func (m *MockProjectRepository) UpdateProject(c context.Context, id primitive.ObjectID, project *domain.Project) (*domain.Project, error) {
+	if _, ok := m.Projects[id]; !ok {
+		return nil, domain.ErrProjectNotFound
+	}
+	m.Projects[id] = project
+	return project, nil
+}
+
+// actorContext returns a context carrying a caller with the given ID and permissions.
+func actorContext(userID string, permissions ...domain.Permission) context.Context {
+	return domain.ContextWithActor(context.Background(), &domain.Actor{UserID: userID, Permissions: permissions})
+}
+
+//===========================================================================
+// ProjectUseCase Test Suite
+//===========================================================================
+
+type ProjectUseCaseSuite struct {
+	suite.Suite
+	mockProjectRepo *MockProjectRepository
+	mockUserRepo    *MockUserRepository
+	useCase         *usecases.ProjectUseCase
+	ownerID         string
+	memberID        string
+}
+
+// TestProjectUseCaseSuite is the entry point for the test suite
+func TestProjectUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(ProjectUseCaseSuite))
+}
+
+func (s *ProjectUseCaseSuite) SetupTest() {
+	s.mockProjectRepo = NewMockProjectRepository()
+	s.ownerID = primitive.NewObjectID().Hex()
+	s.memberID = primitive.NewObjectID().Hex()
+	s.mockUserRepo = &MockUserRepository{
+		GetUserByIdFunc: func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			if id.Hex() == s.memberID || id.Hex() == s.ownerID {
+				return &domain.User{Id: id}, nil
+			}
+			return nil, domain.ErrUserNotFound
+		},
+	}
+	s.useCase = usecases.NewProjectUseCase(s.mockProjectRepo, s.mockUserRepo)
+}
+
+func (s *ProjectUseCaseSuite) createProject() *domain.Project {
+	project, err := s.useCase.CreateProject(actorContext(s.ownerID), "Website", "Redesign")
+	s.Require().NoError(err)
+	return project
+}
+
+func (s *ProjectUseCaseSuite) TestCreateProject() {
+	s.Run("Caller Becomes Owner", func() {
+		s.SetupTest()
+		project := s.createProject()
+		role, ok := project.MemberRole(s.ownerID)
+		s.True(ok)
+		s.Equal(domain.ProjectOwner, role)
+	})
+
+	s.Run("Requires Caller", func() {
+		s.SetupTest()
+		_, err := s.useCase.CreateProject(context.Background(), "Website", "")
+		s.ErrorIs(err, domain.ErrForbidden)
+	})
+}
+
+func (s *ProjectUseCaseSuite) TestGetProject() {
+	s.Run("Non Member Gets Not Found", func() {
+		s.SetupTest()
+		project := s.createProject()
+		_, err := s.useCase.GetProject(actorContext(s.memberID), project.Id.Hex())
+		s.ErrorIs(err, domain.ErrProjectNotFound)
+	})
+
+	s.Run("Projects Manager Can Read Any Project", func() {
+		s.SetupTest()
+		project := s.createProject()
+		_, err := s.useCase.GetProject(actorContext("admin", domain.PermProjectsManage), project.Id.Hex())
+		s.NoError(err)
+	})
+}
+
+func (s *ProjectUseCaseSuite) TestSetMember() {
+	s.Run("Owner Adds Member", func() {
+		s.SetupTest()
+		project := s.createProject()
+		updated, err := s.useCase.SetMember(actorContext(s.ownerID), project.Id.Hex(), s.memberID, domain.ProjectViewer)
+		s.Require().NoError(err)
+		role, ok := updated.MemberRole(s.memberID)
+		s.True(ok)
+		s.Equal(domain.ProjectViewer, role)
+
+		projects, err := s.useCase.GetMyProjects(actorContext(s.memberID))
+		s.Require().NoError(err)
+		s.Len(projects, 1)
+	})
+
+	s.Run("Editor Cannot Manage Members", func() {
+		s.SetupTest()
+		project := s.createProject()
+		_, err := s.useCase.SetMember(actorContext(s.ownerID), project.Id.Hex(), s.memberID, domain.ProjectEditor)
+		s.Require().NoError(err)
+
+		_, err = s.useCase.SetMember(actorContext(s.memberID), project.Id.Hex(), s.ownerID, domain.ProjectViewer)
+		s.ErrorIs(err, domain.ErrForbidden)
+	})
+
+	s.Run("Unknown User", func() {
+		s.SetupTest()
+		project := s.createProject()
+		_, err := s.useCase.SetMember(actorContext(s.ownerID), project.Id.Hex(), primitive.NewObjectID().Hex(), domain.ProjectViewer)
+		s.ErrorIs(err, domain.ErrUserNotFound)
+	})
+
+	s.Run("Last Owner Cannot Be Demoted", func() {
+		s.SetupTest()
+		project := s.createProject()
+		_, err := s.useCase.SetMember(actorContext(s.ownerID), project.Id.Hex(), s.ownerID, domain.ProjectEditor)
+		s.ErrorIs(err, domain.ErrValidationFailed)
+	})
+}
+
+func (s *ProjectUseCaseSuite) TestRemoveMember() {
+	s.Run("Member Can Leave", func() {
+		s.SetupTest()
+		project := s.createProject()
+		_, err := s.useCase.SetMember(actorContext(s.ownerID), project.Id.Hex(), s.memberID, domain.ProjectViewer)
+		s.Require().NoError(err)
+
+		updated, err := s.useCase.RemoveMember(actorContext(s.memberID), project.Id.Hex(), s.memberID)
+		s.Require().NoError(err)
+		_, ok := updated.MemberRole(s.memberID)
+		s.False(ok)
+	})
+
+	s.Run("Viewer Cannot Remove Others", func() {
+		s.SetupTest()
+		project := s.createProject()
+		_, err := s.useCase.SetMember(actorContext(s.ownerID), project.Id.Hex(), s.memberID, domain.ProjectViewer)
+		s.Require().NoError(err)
+
+		_, err = s.useCase.RemoveMember(actorContext(s.memberID), project.Id.Hex(), s.ownerID)
+		s.ErrorIs(err, domain.ErrForbidden)
+	})
+}
+
+func (s *ProjectUseCaseSuite) TestRemoveFromAllProjects() {
+	s.Run("Success", func() {
+		s.SetupTest()
+		project := s.createProject()
+		_, err := s.useCase.SetMember(actorContext(s.ownerID), project.Id.Hex(), s.memberID, domain.ProjectEditor)
+		s.Require().NoError(err)
+
+		s.Require().NoError(s.useCase.RemoveFromAllProjects(context.Background(), &domain.User{Id: primitive.NewObjectID()}))
+		s.Require().NoError(s.useCase.RemoveFromAllProjects(context.Background(), &domain.User{Id: mustObjectID(s.memberID)}))
+
+		_, ok := s.mockProjectRepo.Projects[project.Id].MemberRole(s.memberID)
+		s.False(ok)
+	})
+
+	s.Run("Failure - Only Owner", func() {
+		s.SetupTest()
+		s.createProject()
+
+		err := s.useCase.RemoveFromAllProjects(context.Background(), &domain.User{Id: mustObjectID(s.ownerID)})
+
+		s.ErrorIs(err, domain.ErrValidationFailed, "Projects must be handed over before their only owner leaves")
+	})
+}
+
+func mustObjectID(hex string) primitive.ObjectID {
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
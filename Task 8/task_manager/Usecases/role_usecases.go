@@ -0,0 +1,125 @@
+package usecases
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+type RoleUseCase struct {
+	roleRepo domain.RoleRepository
+}
+
+func NewRoleUseCase(roleRepo domain.RoleRepository) *RoleUseCase {
+	return &RoleUseCase{
+		roleRepo: roleRepo,
+	}
+}
+
+// EnsureDefaultRoles seeds the built-in roles that are missing from the caller's
+// organization and grants existing built-in roles the built-in permissions added since
+// they were seeded. Other customisations, including removed permissions, survive
+// restarts.
+func (uc *RoleUseCase) EnsureDefaultRoles(c context.Context) error {
+	for _, role := range domain.DefaultRoleDefinitions() {
+		existing, err := uc.roleRepo.GetRole(c, role.Name)
+		if errors.Is(err, domain.ErrRoleNotFound) {
+			role.SeededPermissions = role.Permissions
+			if _, err := uc.roleRepo.UpsertRole(c, role); err != nil {
+				return fmt.Errorf("usecase: failed to seed role %q: %w", role.Name, err)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("usecase: failed to look up role %q: %w", role.Name, err)
+		}
+		if err := uc.grantNewPermissions(c, existing, role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpgradeDefaultRoles grants the built-in roles of every organization visible to c the
+// built-in permissions added since they were seeded. It runs on startup with the
+// system scope, so that existing organizations get new permissions too.
+func (uc *RoleUseCase) UpgradeDefaultRoles(c context.Context) error {
+	builtIn := map[domain.UserRole]*domain.RoleDefinition{}
+	for _, role := range domain.DefaultRoleDefinitions() {
+		builtIn[role.Name] = role
+	}
+	roles, err := uc.roleRepo.GetAllRoles(c)
+	if err != nil {
+		return fmt.Errorf("usecase: failed to get roles: %w", err)
+	}
+	for _, role := range roles {
+		if defaults, ok := builtIn[role.Name]; ok {
+			if err := uc.grantNewPermissions(c, role, defaults); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// grantNewPermissions adds the permissions of the built-in role that role has not been
+// seeded with yet.
+func (uc *RoleUseCase) grantNewPermissions(c context.Context, role, defaults *domain.RoleDefinition) error {
+	changed := false
+	for _, permission := range defaults.Permissions {
+		if slices.Contains(role.SeededPermissions, permission) {
+			continue
+		}
+		changed = true
+		if !role.Has(permission) {
+			role.Permissions = append(role.Permissions, permission)
+		}
+	}
+	if !changed {
+		return nil
+	}
+	role.SeededPermissions = defaults.Permissions
+	if _, err := uc.roleRepo.UpsertRole(c, role); err != nil {
+		return fmt.Errorf("usecase: failed to update role %q: %w", role.Name, err)
+	}
+	return nil
+}
+
+// GetAllRoles handles fetching all role definitions.
+func (uc *RoleUseCase) GetAllRoles(c context.Context) ([]*domain.RoleDefinition, error) {
+	roles, err := uc.roleRepo.GetAllRoles(c)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to get all roles: %w", err)
+	}
+	return roles, nil
+}
+
+// DefineRole creates or replaces the permission set of a role.
+func (uc *RoleUseCase) DefineRole(c context.Context, name domain.UserRole, permissions []domain.Permission) (*domain.RoleDefinition, error) {
+	role, err := domain.NewRoleDefinition(name, permissions)
+	if err != nil {
+		return nil, err
+	}
+	savedRole, err := uc.roleRepo.UpsertRole(c, role)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to save role: %w", err)
+	}
+	return savedRole, nil
+}
+
+// userActor describes the user as a caller, with the permissions of their role, for
+// checks made on their behalf outside of their own requests. A role that is not
+// defined grants nothing.
+func userActor(c context.Context, roleRepo domain.RoleRepository, user *domain.User) (*domain.Actor, error) {
+	actor := &domain.Actor{UserID: user.Id.Hex(), Username: user.Username, Role: user.Role}
+	role, err := roleRepo.GetRole(c, user.Role)
+	switch {
+	case err == nil:
+		actor.Permissions = role.Permissions
+	case !errors.Is(err, domain.ErrRoleNotFound):
+		return nil, fmt.Errorf("usecase: failed to resolve permissions: %w", err)
+	}
+	return actor, nil
+}
@@ -0,0 +1,165 @@
+package usecases_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// MockRoleRepository keeps role definitions in a map, which is enough for use case tests.
+type MockRoleRepository struct {
+	Roles      map[domain.UserRole]*domain.RoleDefinition
+	GetRoleErr error
+}
+
+func NewMockRoleRepository(roles ...*domain.RoleDefinition) *MockRoleRepository {
+	m := &MockRoleRepository{Roles: map[domain.UserRole]*domain.RoleDefinition{}}
+	for _, role := range roles {
+		m.Roles[role.Name] = role
+	}
+	return m
+}
+
+func (m *MockRoleRepository) GetRole(c context.Context, name domain.UserRole) (*domain.RoleDefinition, error) {
+	if m.GetRoleErr != nil {
+		return nil, m.GetRoleErr
+	}
+	role, ok := m.Roles[name]
+	if !ok {
+		return nil, domain.ErrRoleNotFound
+	}
+	return role, nil
+}
+func (m *MockRoleRepository) GetAllRoles(c context.Context) ([]*domain.RoleDefinition, error) {
+	roles := make([]*domain.RoleDefinition, 0, len(m.Roles))
+	for _, role := range m.Roles {
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+func (m *MockRoleRepository) UpsertRole(c context.Context, role *domain.RoleDefinition) (*domain.RoleDefinition, error) {
+	m.Roles[role.Name] = role
+	return role, nil
+}
+
+//===========================================================================
+// RoleUseCase Test Suite
+//===========================================================================
+
+type RoleUseCaseSuite struct {
+	suite.Suite
+	mockRepo *MockRoleRepository
+	useCase  *usecases.RoleUseCase
+	ctx      context.Context
+}
+
+// TestRoleUseCaseSuite is the entry point for the test suite
+func TestRoleUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(RoleUseCaseSuite))
+}
+
+// SetupTest runs before each test method in the suite.
+func (s *RoleUseCaseSuite) SetupTest() {
+	s.mockRepo = NewMockRoleRepository()
+	s.useCase = usecases.NewRoleUseCase(s.mockRepo)
+	s.ctx = context.Background()
+}
+
+func (s *RoleUseCaseSuite) TestEnsureDefaultRoles() {
+	s.Run("Seeds Missing Roles", func() {
+		s.SetupTest()
+		s.Require().NoError(s.useCase.EnsureDefaultRoles(s.ctx))
+
+		s.Contains(s.mockRepo.Roles, domain.RoleAdmin)
+		s.Contains(s.mockRepo.Roles, domain.RoleUser)
+		s.True(s.mockRepo.Roles[domain.RoleAdmin].Has(domain.PermTasksDelete))
+		s.False(s.mockRepo.Roles[domain.RoleUser].Has(domain.PermTasksCreate))
+	})
+
+	s.Run("Keeps Customised Roles", func() {
+		s.SetupTest()
+		custom := &domain.RoleDefinition{Name: domain.RoleUser, Permissions: []domain.Permission{domain.PermTasksRead, domain.PermTasksUpdate}}
+		s.mockRepo.Roles[domain.RoleUser] = custom
+
+		s.Require().NoError(s.useCase.EnsureDefaultRoles(s.ctx))
+
+		s.Same(custom, s.mockRepo.Roles[domain.RoleUser])
+		s.Equal([]domain.Permission{domain.PermTasksRead, domain.PermTasksUpdate}, custom.Permissions)
+	})
+
+	s.Run("Adds New Built-In Permissions", func() {
+		s.SetupTest()
+		seeded := []domain.Permission{domain.PermTasksRead, domain.PermTasksCreate, domain.PermTasksUpdate, domain.PermTasksDelete, domain.PermUsersManage, domain.PermProjectsManage}
+		// The administrator removed tasks:delete after the role was seeded.
+		s.mockRepo.Roles[domain.RoleAdmin] = &domain.RoleDefinition{
+			Name:              domain.RoleAdmin,
+			Permissions:       []domain.Permission{domain.PermTasksRead, domain.PermTasksCreate, domain.PermTasksUpdate, domain.PermUsersManage, domain.PermProjectsManage},
+			SeededPermissions: seeded,
+		}
+
+		s.Require().NoError(s.useCase.EnsureDefaultRoles(s.ctx))
+
+		admin := s.mockRepo.Roles[domain.RoleAdmin]
+		s.True(admin.Has(domain.PermTasksImport))
+		s.True(admin.Has(domain.PermTasksRevert))
+		s.False(admin.Has(domain.PermTasksDelete), "a removed permission stays removed")
+		s.ElementsMatch(domain.AllPermissions(), admin.SeededPermissions)
+	})
+
+	s.Run("Roles Seeded Before Tracking Get Every Built-In Permission", func() {
+		s.SetupTest()
+		s.mockRepo.Roles[domain.RoleAdmin] = &domain.RoleDefinition{Name: domain.RoleAdmin, Permissions: []domain.Permission{domain.PermTasksRead}}
+
+		s.Require().NoError(s.useCase.EnsureDefaultRoles(s.ctx))
+
+		s.ElementsMatch(domain.AllPermissions(), s.mockRepo.Roles[domain.RoleAdmin].Permissions)
+	})
+
+	s.Run("Repository Error", func() {
+		s.SetupTest()
+		s.mockRepo.GetRoleErr = errors.New("db down")
+
+		s.Error(s.useCase.EnsureDefaultRoles(s.ctx))
+	})
+}
+
+func (s *RoleUseCaseSuite) TestUpgradeDefaultRoles() {
+	s.mockRepo.Roles[domain.RoleAdmin] = &domain.RoleDefinition{Name: domain.RoleAdmin, Permissions: []domain.Permission{domain.PermTasksRead}, SeededPermissions: []domain.Permission{domain.PermTasksRead}}
+	custom := &domain.RoleDefinition{Name: "Editor", Permissions: []domain.Permission{domain.PermTasksUpdate}}
+	s.mockRepo.Roles["Editor"] = custom
+
+	s.Require().NoError(s.useCase.UpgradeDefaultRoles(s.ctx))
+
+	s.ElementsMatch(domain.AllPermissions(), s.mockRepo.Roles[domain.RoleAdmin].Permissions)
+	s.Equal([]domain.Permission{domain.PermTasksUpdate}, custom.Permissions, "custom roles are not touched")
+	s.NotContains(s.mockRepo.Roles, domain.RoleUser, "missing roles are only seeded per organization")
+}
+
+func (s *RoleUseCaseSuite) TestDefineRole() {
+	s.Run("Success", func() {
+		s.SetupTest()
+		role, err := s.useCase.DefineRole(s.ctx, "Editor", []domain.Permission{domain.PermTasksRead, domain.PermTasksUpdate, domain.PermTasksRead})
+
+		s.Require().NoError(err)
+		s.Equal([]domain.Permission{domain.PermTasksRead, domain.PermTasksUpdate}, role.Permissions, "Duplicates should be removed")
+		s.Contains(s.mockRepo.Roles, domain.UserRole("Editor"))
+	})
+
+	s.Run("Unknown Permission", func() {
+		s.SetupTest()
+		_, err := s.useCase.DefineRole(s.ctx, "Editor", []domain.Permission{"tasks:explode"})
+
+		s.ErrorIs(err, domain.ErrValidationFailed)
+	})
+
+	s.Run("Empty Name", func() {
+		s.SetupTest()
+		_, err := s.useCase.DefineRole(s.ctx, "", nil)
+
+		s.ErrorIs(err, domain.ErrValidationFailed)
+	})
+}
@@ -0,0 +1,235 @@
+package usecases
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	apiKeyPrefix      = "tmk_"
+	apiKeyRandomBytes = 32
+	// apiKeyDisplayLen is how much of the key is kept in clear text so that
+	// administrators can tell keys apart in listings.
+	apiKeyDisplayLen = 12
+)
+
+// Ensure ServiceAccountUseCase can be used by the authentication middleware
+var _ domain.APIKeyAuthenticator = (*ServiceAccountUseCase)(nil)
+
+type ServiceAccountUseCase struct {
+	accountRepo domain.ServiceAccountRepository
+	keyRepo     domain.APIKeyRepository
+	clock       domain.Clock
+}
+
+func NewServiceAccountUseCase(accountRepo domain.ServiceAccountRepository, keyRepo domain.APIKeyRepository) *ServiceAccountUseCase {
+	return &ServiceAccountUseCase{
+		accountRepo: accountRepo,
+		keyRepo:     keyRepo,
+		clock:       domain.SystemClock{},
+	}
+}
+
+// SetClock replaces the system clock, which decides when keys expire.
+func (uc *ServiceAccountUseCase) SetClock(clock domain.Clock) {
+	uc.clock = clock
+}
+
+func (uc *ServiceAccountUseCase) CreateServiceAccount(c context.Context, name, description, createdBy string) (*domain.ServiceAccount, error) {
+	account, err := domain.NewServiceAccount(name, description, createdBy, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	savedAccount, err := uc.accountRepo.CreateServiceAccount(c, account)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to save service account: %w", err)
+	}
+	return savedAccount, nil
+}
+
+func (uc *ServiceAccountUseCase) GetAllServiceAccounts(c context.Context) ([]*domain.ServiceAccount, error) {
+	accounts, err := uc.accountRepo.GetAllServiceAccounts(c)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to get service accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+// CreateAPIKey issues a new key for the service account. The returned plaintext key
+// is not stored anywhere and cannot be retrieved again.
+func (uc *ServiceAccountUseCase) CreateAPIKey(c context.Context, accountID, name string, scopes []domain.Permission, expiresAt *time.Time) (string, *domain.APIKey, error) {
+	account, err := uc.getServiceAccount(c, accountID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	key, err := domain.NewAPIKey(account.Id, name, scopes, expiresAt, uc.clock.Now())
+	if err != nil {
+		return "", nil, err
+	}
+
+	raw := make([]byte, apiKeyRandomBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("usecase: failed to generate API key: %w", err)
+	}
+	plaintext := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	key.Prefix = plaintext[:apiKeyDisplayLen]
+	key.KeyHash = hashSecretToken(plaintext)
+
+	savedKey, err := uc.keyRepo.CreateAPIKey(c, key)
+	if err != nil {
+		return "", nil, fmt.Errorf("usecase: failed to save API key: %w", err)
+	}
+	return plaintext, savedKey, nil
+}
+
+func (uc *ServiceAccountUseCase) GetAPIKeys(c context.Context, accountID string) ([]*domain.APIKey, error) {
+	account, err := uc.getServiceAccount(c, accountID)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := uc.keyRepo.GetAPIKeysByServiceAccount(c, account.Id)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to get API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey immediately disables a key. Revoked keys are kept for auditing.
+func (uc *ServiceAccountUseCase) RevokeAPIKey(c context.Context, accountID, keyID string) error {
+	account, err := uc.getServiceAccount(c, accountID)
+	if err != nil {
+		return err
+	}
+	keyObjectID, err := primitive.ObjectIDFromHex(keyID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid API key ID format", domain.ErrValidationFailed)
+	}
+
+	keys, err := uc.keyRepo.GetAPIKeysByServiceAccount(c, account.Id)
+	if err != nil {
+		return fmt.Errorf("usecase: failed to get API keys: %w", err)
+	}
+	for _, key := range keys {
+		if key.Id != keyObjectID {
+			continue
+		}
+		if err := uc.keyRepo.RevokeAPIKey(c, key.Id, uc.clock.Now()); err != nil {
+			if errors.Is(err, domain.ErrAPIKeyNotFound) {
+				return domain.ErrAPIKeyNotFound
+			}
+			return fmt.Errorf("usecase: failed to revoke API key: %w", err)
+		}
+		return nil
+	}
+	return domain.ErrAPIKeyNotFound
+}
+
+// DeleteServiceAccountsCreatedBy is a MemberRemovalHook that deletes the service
+// accounts a removed member created. Their keys are revoked rather than deleted, like
+// any revoked key, so that they remain available for auditing.
+func (uc *ServiceAccountUseCase) DeleteServiceAccountsCreatedBy(c context.Context, user *domain.User) error {
+	accounts, err := uc.accountRepo.GetAllServiceAccounts(c)
+	if err != nil {
+		return fmt.Errorf("usecase: failed to get service accounts: %w", err)
+	}
+	now := uc.clock.Now()
+	for _, account := range accounts {
+		if account.CreatedBy != user.Username {
+			continue
+		}
+		keys, err := uc.keyRepo.GetAPIKeysByServiceAccount(c, account.Id)
+		if err != nil {
+			return fmt.Errorf("usecase: failed to get API keys: %w", err)
+		}
+		for _, key := range keys {
+			if key.RevokedAt != nil {
+				continue
+			}
+			if err := uc.keyRepo.RevokeAPIKey(c, key.Id, now); err != nil {
+				return fmt.Errorf("usecase: failed to revoke API key: %w", err)
+			}
+		}
+		if err := uc.accountRepo.DeleteServiceAccount(c, account.Id); err != nil {
+			return fmt.Errorf("usecase: failed to delete service account: %w", err)
+		}
+	}
+	return nil
+}
+
+// AuthenticateAPIKey implements domain.APIKeyAuthenticator.
+func (uc *ServiceAccountUseCase) AuthenticateAPIKey(c context.Context, rawKey string) (*domain.Claims, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	// The key is all the caller has, so it is looked up in every organization, and the
+	// rest of the request runs in the key's.
+	key, err := uc.keyRepo.GetAPIKeyByHash(domain.ContextWithSystemScope(c), hashSecretToken(rawKey))
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("usecase: failed to look up API key: %w", err)
+	}
+	now := uc.clock.Now()
+	if !key.IsActive(now) || key.OrganizationID.IsZero() {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	c = domain.ContextWithOrganization(c, key.OrganizationID)
+
+	account, err := uc.accountRepo.GetServiceAccountById(c, key.ServiceAccountID)
+	if err != nil {
+		if errors.Is(err, domain.ErrServiceAccountNotFound) {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("usecase: failed to look up service account: %w", err)
+	}
+
+	// Usage tracking is best effort and must not fail the request.
+	if err := uc.keyRepo.TouchAPIKey(c, key.Id, now); err != nil {
+		log.Printf("usecase: failed to record usage of API key %s: %v\n", key.Prefix, err)
+	}
+
+	return &domain.Claims{
+		UserId:         account.Id.Hex(),
+		Username:       account.Name,
+		Role:           domain.RoleService,
+		Permissions:    key.Scopes,
+		OrganizationID: account.OrganizationID.Hex(),
+	}, nil
+}
+
+func (uc *ServiceAccountUseCase) getServiceAccount(c context.Context, accountID string) (*domain.ServiceAccount, error) {
+	objectID, err := primitive.ObjectIDFromHex(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid service account ID format", domain.ErrValidationFailed)
+	}
+	account, err := uc.accountRepo.GetServiceAccountById(c, objectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrServiceAccountNotFound) {
+			return nil, domain.ErrServiceAccountNotFound
+		}
+		return nil, fmt.Errorf("usecase: failed to get service account: %w", err)
+	}
+	return account, nil
+}
+
+// hashSecretToken hashes API keys and calendar feed tokens with a plain SHA-256 digest:
+// they carry 256 bits of randomness, so a slow password hash adds nothing and would make
+// every request expensive.
+func hashSecretToken(rawToken string) string {
+	sum := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(sum[:])
+}
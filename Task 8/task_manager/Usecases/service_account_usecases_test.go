@@ -0,0 +1,236 @@
+package usecases_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// --- In-memory mocks for service accounts and API keys ---
+type MockServiceAccountRepository struct {
+	Accounts map[primitive.ObjectID]*domain.ServiceAccount
+}
+
+func (m *MockServiceAccountRepository) CreateServiceAccount(c context.Context, account *domain.ServiceAccount) (*domain.ServiceAccount, error) {
+	account.Id = primitive.NewObjectID()
+	account.OrganizationID, _ = domain.OrganizationFromContext(c)
+	m.Accounts[account.Id] = account
+	return account, nil
+}
+func (m *MockServiceAccountRepository) GetServiceAccountById(c context.Context, id primitive.ObjectID) (*domain.ServiceAccount, error) {
+	account, ok := m.Accounts[id]
+	if !ok {
+		return nil, domain.ErrServiceAccountNotFound
+	}
+	return account, nil
+}
+func (m *MockServiceAccountRepository) GetAllServiceAccounts(c context.Context) ([]*domain.ServiceAccount, error) {
+	accounts := []*domain.ServiceAccount{}
+	for _, account := range m.Accounts {
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+func (m *MockServiceAccountRepository) DeleteServiceAccount(c context.Context, id primitive.ObjectID) error {
+	if _, ok := m.Accounts[id]; !ok {
+		return domain.ErrServiceAccountNotFound
+	}
+	delete(m.Accounts, id)
+	return nil
+}
+
+type MockAPIKeyRepository struct {
+	Keys map[primitive.ObjectID]*domain.APIKey
+}
+
+func (m *MockAPIKeyRepository) CreateAPIKey(c context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	key.Id = primitive.NewObjectID()
+	key.OrganizationID, _ = domain.OrganizationFromContext(c)
+	m.Keys[key.Id] = key
+	return key, nil
+}
+func (m *MockAPIKeyRepository) GetAPIKeyByHash(c context.Context, keyHash string) (*domain.APIKey, error) {
+	for _, key := range m.Keys {
+		if key.KeyHash == keyHash {
+			return key, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+func (m *MockAPIKeyRepository) GetAPIKeysByServiceAccount(c context.Context, serviceAccountID primitive.ObjectID) ([]*domain.APIKey, error) {
+	keys := []*domain.APIKey{}
+	for _, key := range m.Keys {
+		if key.ServiceAccountID == serviceAccountID {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+func (m *MockAPIKeyRepository) RevokeAPIKey(c context.Context, id primitive.ObjectID, revokedAt time.Time) error {
+	key, ok := m.Keys[id]
+	if !ok {
+		return domain.ErrAPIKeyNotFound
+	}
+	key.RevokedAt = &revokedAt
+	return nil
+}
+func (m *MockAPIKeyRepository) TouchAPIKey(c context.Context, id primitive.ObjectID, usedAt time.Time) error {
+	if key, ok := m.Keys[id]; ok {
+		key.LastUsedAt = &usedAt
+	}
+	return nil
+}
+
+//===========================================================================
+// ServiceAccountUseCase Test Suite
+//===========================================================================
+
+type ServiceAccountUseCaseSuite struct {
+	suite.Suite
+	accounts *MockServiceAccountRepository
+	keys     *MockAPIKeyRepository
+	useCase  *usecases.ServiceAccountUseCase
+	ctx      context.Context
+}
+
+// TestServiceAccountUseCaseSuite is the entry point for the test suite
+func TestServiceAccountUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(ServiceAccountUseCaseSuite))
+}
+
+// SetupTest runs before each test method in the suite.
+func (s *ServiceAccountUseCaseSuite) SetupTest() {
+	s.accounts = &MockServiceAccountRepository{Accounts: map[primitive.ObjectID]*domain.ServiceAccount{}}
+	s.keys = &MockAPIKeyRepository{Keys: map[primitive.ObjectID]*domain.APIKey{}}
+	s.useCase = usecases.NewServiceAccountUseCase(s.accounts, s.keys)
+	s.ctx = domain.ContextWithOrganization(context.Background(), primitive.NewObjectID())
+}
+
+func (s *ServiceAccountUseCaseSuite) createAccount() *domain.ServiceAccount {
+	account, err := s.useCase.CreateServiceAccount(s.ctx, "ci", "pipeline", "admin")
+	s.Require().NoError(err)
+	return account
+}
+
+func (s *ServiceAccountUseCaseSuite) TestCreateServiceAccount_Validation() {
+	_, err := s.useCase.CreateServiceAccount(s.ctx, "", "", "admin")
+	s.ErrorIs(err, domain.ErrValidationFailed)
+}
+
+func (s *ServiceAccountUseCaseSuite) TestCreateAPIKey() {
+	s.Run("Only A Hash Is Stored", func() {
+		s.SetupTest()
+		account := s.createAccount()
+
+		plaintext, key, err := s.useCase.CreateAPIKey(s.ctx, account.Id.Hex(), "deploy", []domain.Permission{domain.PermTasksRead}, nil)
+
+		s.Require().NoError(err)
+		s.True(strings.HasPrefix(plaintext, "tmk_"))
+		s.True(strings.HasPrefix(plaintext, key.Prefix))
+		s.NotEqual(plaintext, key.KeyHash)
+		s.NotContains(key.KeyHash, plaintext)
+	})
+
+	s.Run("Validation", func() {
+		s.SetupTest()
+		account := s.createAccount()
+		past := time.Now().Add(-time.Hour)
+
+		_, _, err := s.useCase.CreateAPIKey(s.ctx, account.Id.Hex(), "deploy", nil, nil)
+		s.ErrorIs(err, domain.ErrValidationFailed, "Keys need at least one scope")
+		_, _, err = s.useCase.CreateAPIKey(s.ctx, account.Id.Hex(), "deploy", []domain.Permission{"tasks:everything"}, nil)
+		s.ErrorIs(err, domain.ErrValidationFailed)
+		_, _, err = s.useCase.CreateAPIKey(s.ctx, account.Id.Hex(), "deploy", []domain.Permission{domain.PermTasksRead}, &past)
+		s.ErrorIs(err, domain.ErrValidationFailed)
+	})
+
+	s.Run("Unknown Account", func() {
+		s.SetupTest()
+		_, _, err := s.useCase.CreateAPIKey(s.ctx, primitive.NewObjectID().Hex(), "deploy", []domain.Permission{domain.PermTasksRead}, nil)
+		s.ErrorIs(err, domain.ErrServiceAccountNotFound)
+	})
+}
+
+func (s *ServiceAccountUseCaseSuite) TestAuthenticateAPIKey() {
+	s.Run("Success", func() {
+		s.SetupTest()
+		account := s.createAccount()
+		plaintext, key, err := s.useCase.CreateAPIKey(s.ctx, account.Id.Hex(), "deploy", []domain.Permission{domain.PermTasksRead, domain.PermTasksCreate}, nil)
+		s.Require().NoError(err)
+
+		claims, err := s.useCase.AuthenticateAPIKey(context.Background(), plaintext)
+
+		s.Require().NoError(err)
+		s.Equal(account.Id.Hex(), claims.UserId)
+		s.Equal("ci", claims.Username)
+		s.Equal(domain.RoleService, claims.Role)
+		s.Equal([]domain.Permission{domain.PermTasksRead, domain.PermTasksCreate}, claims.Permissions)
+		s.Equal(account.OrganizationID.Hex(), claims.OrganizationID, "The key's organization is used without one in the request")
+		s.NotNil(s.keys.Keys[key.Id].LastUsedAt, "Usage should be recorded")
+	})
+
+	s.Run("Revoked", func() {
+		s.SetupTest()
+		account := s.createAccount()
+		plaintext, key, err := s.useCase.CreateAPIKey(s.ctx, account.Id.Hex(), "deploy", []domain.Permission{domain.PermTasksRead}, nil)
+		s.Require().NoError(err)
+		s.Require().NoError(s.useCase.RevokeAPIKey(s.ctx, account.Id.Hex(), key.Id.Hex()))
+
+		_, err = s.useCase.AuthenticateAPIKey(s.ctx, plaintext)
+		s.ErrorIs(err, domain.ErrInvalidAPIKey)
+	})
+
+	s.Run("Expired", func() {
+		s.SetupTest()
+		account := s.createAccount()
+		plaintext, key, err := s.useCase.CreateAPIKey(s.ctx, account.Id.Hex(), "deploy", []domain.Permission{domain.PermTasksRead}, nil)
+		s.Require().NoError(err)
+		expired := time.Now().Add(-time.Minute)
+		s.keys.Keys[key.Id].ExpiresAt = &expired
+
+		_, err = s.useCase.AuthenticateAPIKey(s.ctx, plaintext)
+		s.ErrorIs(err, domain.ErrInvalidAPIKey)
+	})
+
+	s.Run("Unknown", func() {
+		s.SetupTest()
+		_, err := s.useCase.AuthenticateAPIKey(s.ctx, "tmk_doesnotexist")
+		s.ErrorIs(err, domain.ErrInvalidAPIKey)
+		_, err = s.useCase.AuthenticateAPIKey(s.ctx, "Bearer something")
+		s.ErrorIs(err, domain.ErrInvalidAPIKey)
+	})
+}
+
+func (s *ServiceAccountUseCaseSuite) TestRevokeAPIKey_WrongAccount() {
+	owner := s.createAccount()
+	other := s.createAccount()
+	_, key, err := s.useCase.CreateAPIKey(s.ctx, owner.Id.Hex(), "deploy", []domain.Permission{domain.PermTasksRead}, nil)
+	s.Require().NoError(err)
+
+	err = s.useCase.RevokeAPIKey(s.ctx, other.Id.Hex(), key.Id.Hex())
+
+	s.ErrorIs(err, domain.ErrAPIKeyNotFound, "A key can only be revoked through its own account")
+	s.Nil(s.keys.Keys[key.Id].RevokedAt)
+}
+
+func (s *ServiceAccountUseCaseSuite) TestDeleteServiceAccountsCreatedBy() {
+	owned := s.createAccount()
+	plaintext, key, err := s.useCase.CreateAPIKey(s.ctx, owned.Id.Hex(), "deploy", []domain.Permission{domain.PermTasksRead}, nil)
+	s.Require().NoError(err)
+	other, err := s.useCase.CreateServiceAccount(s.ctx, "backup", "nightly", "someone-else")
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.useCase.DeleteServiceAccountsCreatedBy(s.ctx, &domain.User{Username: "admin"}))
+
+	s.NotContains(s.accounts.Accounts, owned.Id)
+	s.Contains(s.accounts.Accounts, other.Id, "Accounts created by others are kept")
+	s.NotNil(s.keys.Keys[key.Id].RevokedAt, "Keys are revoked and kept for auditing")
+	_, err = s.useCase.AuthenticateAPIKey(s.ctx, plaintext)
+	s.ErrorIs(err, domain.ErrInvalidAPIKey)
+}
@@ -0,0 +1,238 @@
+package usecases
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxBulkOperations caps the number of tasks a single bulk request may touch.
+const MaxBulkOperations = 100
+
+type BulkAction string
+
+const (
+	BulkCreate BulkAction = "create"
+	BulkUpdate BulkAction = "update"
+	BulkDelete BulkAction = "delete"
+)
+
+// BulkOperation is one item of a bulk request. TaskID is required for updates and
+// deletes; the field pointers follow the same rules as CreateTask and UpdateTask.
+type BulkOperation struct {
+	Action      BulkAction
+	TaskID      string
+	Title       *string
+	Description *string
+	DueDate     *time.Time
+	Status      *domain.TaskStatus
+}
+
+type BulkItemStatus string
+
+const (
+	BulkSucceeded  BulkItemStatus = "succeeded"
+	BulkFailed     BulkItemStatus = "failed"
+	BulkRolledBack BulkItemStatus = "rolled_back" // succeeded, but undone because another item failed
+	BulkSkipped    BulkItemStatus = "skipped"     // not attempted because an earlier item failed
+)
+
+type BulkItemResult struct {
+	Index  int            `json:"index"`
+	Action BulkAction     `json:"action"`
+	TaskID string         `json:"id,omitempty"`
+	Status BulkItemStatus `json:"status"`
+	Error  string         `json:"error,omitempty"`
+	Task   *domain.Task   `json:"task,omitempty"`
+	err    error
+}
+
+type BulkResult struct {
+	Atomic    bool              `json:"atomic"`
+	Committed bool              `json:"committed"`
+	Results   []*BulkItemResult `json:"results"`
+}
+
+// Err returns the error of a failed item. Error holds its text, which may include
+// internal details; Err lets the caller decide what to show.
+func (r *BulkItemResult) Err() error {
+	return r.err
+}
+
+// FirstFailure returns the first failed item, or nil if none failed.
+func (r *BulkResult) FirstFailure() *BulkItemResult {
+	for _, item := range r.Results {
+		if item.err != nil {
+			return item
+		}
+	}
+	return nil
+}
+
+type TaskBulkUseCase struct {
+	tasks      *TaskUseCase
+	transactor domain.Transactor
+}
+
+func NewTaskBulkUseCase(tasks *TaskUseCase, transactor domain.Transactor) *TaskBulkUseCase {
+	return &TaskBulkUseCase{
+		tasks:      tasks,
+		transactor: transactor,
+	}
+}
+
+// Execute runs the operations in order through the TaskUseCase. Without atomic, every
+// operation is independent and the result reports each outcome. With atomic, the
+// operations run in one transaction that is rolled back if any of them fails.
+func (uc *TaskBulkUseCase) Execute(c context.Context, operations []BulkOperation, atomic bool) (*BulkResult, error) {
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("%w: a bulk request needs at least one operation", domain.ErrValidationFailed)
+	}
+	if len(operations) > MaxBulkOperations {
+		return nil, fmt.Errorf("%w: a bulk request may contain at most %d operations, got %d", domain.ErrValidationFailed, MaxBulkOperations, len(operations))
+	}
+	for i, op := range operations {
+		if err := checkBulkPermission(c, op.Action); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	if !atomic {
+		result := &BulkResult{Committed: true}
+		for i, op := range operations {
+			result.Results = append(result.Results, uc.apply(c, i, op))
+		}
+		return result, nil
+	}
+	return uc.executeAtomic(c, operations)
+}
+
+// UpdateWhere applies the same update to every task matching the query that the caller can see.
+func (uc *TaskBulkUseCase) UpdateWhere(c context.Context, query TaskQuery, title, description *string, dueDate *time.Time, status *domain.TaskStatus, atomic bool) (*BulkResult, error) {
+	if title == nil && description == nil && dueDate == nil && status == nil {
+		return nil, fmt.Errorf("%w: a filter-based update needs at least one field to set", domain.ErrValidationFailed)
+	}
+	taskFilter, err := uc.tasks.queryFilter(c, query)
+	if err != nil {
+		return nil, err
+	}
+
+	matched, err := uc.tasks.taskRepo.GetAllTasks(c, taskFilter)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to find tasks for bulk update: %w", err)
+	}
+	if len(matched) > MaxBulkOperations {
+		return nil, fmt.Errorf("%w: the filter matches %d tasks, more than the limit of %d", domain.ErrValidationFailed, len(matched), MaxBulkOperations)
+	}
+
+	operations := make([]BulkOperation, 0, len(matched))
+	for _, task := range matched {
+		operations = append(operations, BulkOperation{
+			Action: BulkUpdate, TaskID: task.Id.Hex(),
+			Title: title, Description: description, DueDate: dueDate, Status: status,
+		})
+	}
+	if len(operations) == 0 {
+		return &BulkResult{Atomic: atomic, Committed: true, Results: []*BulkItemResult{}}, nil
+	}
+	return uc.Execute(c, operations, atomic)
+}
+
+func (uc *TaskBulkUseCase) executeAtomic(c context.Context, operations []BulkOperation) (*BulkResult, error) {
+	if uc.transactor == nil {
+		return nil, domain.ErrTransactionsUnsupported
+	}
+	var result *BulkResult
+	errItemFailed := errors.New("bulk item failed")
+
+	// Task events, and with them other side effects of the changes, only run once
+	// the transaction has committed.
+	err := runUnitOfWork(c, uc.transactor, func(txCtx context.Context) error {
+		// The transaction may be retried, so start from scratch on every attempt.
+		result = &BulkResult{Atomic: true}
+
+		for i, op := range operations {
+			item := uc.apply(txCtx, i, op)
+			result.Results = append(result.Results, item)
+			if item.Status == BulkFailed {
+				return errItemFailed
+			}
+		}
+		return nil
+	})
+
+	if err == nil {
+		result.Committed = true
+		return result, nil
+	}
+	if !errors.Is(err, errItemFailed) {
+		return nil, fmt.Errorf("usecase: bulk transaction failed: %w", err)
+	}
+
+	for _, item := range result.Results {
+		if item.Status == BulkSucceeded {
+			item.Status = BulkRolledBack
+			item.Task = nil
+		}
+	}
+	for i := len(result.Results); i < len(operations); i++ {
+		result.Results = append(result.Results, &BulkItemResult{Index: i, Action: operations[i].Action, TaskID: operations[i].TaskID, Status: BulkSkipped})
+	}
+	return result, nil
+}
+
+func (uc *TaskBulkUseCase) apply(c context.Context, index int, op BulkOperation) *BulkItemResult {
+	item := &BulkItemResult{Index: index, Action: op.Action, TaskID: op.TaskID}
+	var err error
+	switch op.Action {
+	case BulkCreate:
+		if op.Title == nil || op.DueDate == nil || op.Status == nil {
+			err = fmt.Errorf("%w: create requires title, duedate and status", domain.ErrValidationFailed)
+			break
+		}
+		description := ""
+		if op.Description != nil {
+			description = *op.Description
+		}
+		item.Task, err = uc.tasks.CreateTask(c, *op.Title, description, *op.DueDate, *op.Status)
+		if err == nil {
+			item.TaskID = item.Task.Id.Hex()
+		}
+	case BulkUpdate:
+		item.Task, err = uc.tasks.UpdateTask(c, op.TaskID, op.Title, op.Description, op.DueDate, op.Status)
+	case BulkDelete:
+		err = uc.tasks.DeleteTask(c, op.TaskID)
+	default:
+		err = fmt.Errorf("%w: unknown bulk action %q", domain.ErrValidationFailed, op.Action)
+	}
+
+	if err != nil {
+		item.Status = BulkFailed
+		item.Error = err.Error()
+		item.err = err
+		item.Task = nil
+		return item
+	}
+	item.Status = BulkSucceeded
+	return item
+}
+
+// checkBulkPermission applies the route permissions of the single-task endpoints to
+// each bulk operation.
+func checkBulkPermission(c context.Context, action BulkAction) error {
+	actor, ok := domain.ActorFromContext(c)
+	if !ok {
+		return errNoActor
+	}
+	required := map[BulkAction]domain.Permission{
+		BulkCreate: domain.PermTasksCreate,
+		BulkUpdate: domain.PermTasksUpdate,
+		BulkDelete: domain.PermTasksDelete,
+	}[action]
+	if required != "" && !actor.Has(required) {
+		return fmt.Errorf("%w: missing permission %s", domain.ErrForbidden, required)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package usecases_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FakeTransactor runs fn directly; it cannot undo writes, but lets tests observe
+// how the use case behaves inside and after a transaction.
+type FakeTransactor struct {
+	Calls int
+	Err   error
+}
+
+func (f *FakeTransactor) WithinTransaction(c context.Context, fn func(c context.Context) error) error {
+	f.Calls++
+	if f.Err != nil {
+		return f.Err
+	}
+	return fn(c)
+}
+
+//===========================================================================
+// TaskBulkUseCase Test Suite
+//===========================================================================
+
+type TaskBulkUseCaseSuite struct {
+	suite.Suite
+	pending, done *domain.Task
+	created       []*domain.Task
+	transactor    *FakeTransactor
+	events        []domain.TaskEventType
+	useCase       *usecases.TaskBulkUseCase
+	ctx           context.Context
+}
+
+func TestTaskBulkUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(TaskBulkUseCaseSuite))
+}
+
+func (s *TaskBulkUseCaseSuite) SetupTest() {
+	s.pending = &domain.Task{Id: primitive.NewObjectID(), Title: "Pending", Status: domain.Pending, DueDate: time.Now().Add(48 * time.Hour)}
+	s.done = &domain.Task{Id: primitive.NewObjectID(), Title: "Done", Status: domain.Done, DueDate: time.Now().Add(48 * time.Hour)}
+	s.created = nil
+	taskRepo := newInMemoryTaskRepository(s.pending, s.done)
+	taskRepo.CreateTaskFunc = func(c context.Context, task *domain.Task) (*domain.Task, error) {
+		task.Id = primitive.NewObjectID()
+		s.created = append(s.created, task)
+		return task, nil
+	}
+	taskRepo.DeleteTaskFunc = func(c context.Context, id primitive.ObjectID) error { return nil }
+	taskRepo.RemoveBlockerFromAllFunc = func(c context.Context, blockerID primitive.ObjectID) error { return nil }
+
+	s.transactor = &FakeTransactor{}
+	s.events = nil
+	taskUC := usecases.NewTaskUseCase(taskRepo, NewMockProjectRepository())
+	taskUC.Subscribe(func(c context.Context, event domain.TaskEvent) {
+		s.events = append(s.events, event.Type)
+	})
+	s.useCase = usecases.NewTaskBulkUseCase(taskUC, s.transactor)
+	s.ctx = domain.ContextWithActor(context.Background(), domain.SystemActor)
+}
+
+func (s *TaskBulkUseCaseSuite) operations() []usecases.BulkOperation {
+	title, empty := "New", ""
+	dueDate := time.Now().Add(24 * time.Hour)
+	pending := domain.Pending
+	return []usecases.BulkOperation{
+		{Action: usecases.BulkCreate, Title: &title, DueDate: &dueDate, Status: &pending},
+		{Action: usecases.BulkUpdate, TaskID: s.done.Id.Hex(), Status: &pending}, // completed tasks cannot be reopened
+		{Action: usecases.BulkUpdate, TaskID: s.pending.Id.Hex(), Title: &empty},
+	}
+}
+
+func (s *TaskBulkUseCaseSuite) TestExecute() {
+	s.Run("Independent Operations", func() {
+		s.SetupTest()
+		result, err := s.useCase.Execute(s.ctx, s.operations(), false)
+		s.Require().NoError(err)
+		s.True(result.Committed)
+		s.Require().Len(result.Results, 3)
+		s.Equal(usecases.BulkSucceeded, result.Results[0].Status)
+		s.Equal(s.created[0].Id.Hex(), result.Results[0].TaskID)
+		s.Equal(usecases.BulkFailed, result.Results[1].Status)
+		s.NotEmpty(result.Results[1].Error)
+		s.Equal(usecases.BulkFailed, result.Results[2].Status)
+		s.Zero(s.transactor.Calls)
+	})
+
+	s.Run("Atomic Rolls Back On Failure", func() {
+		s.SetupTest()
+		result, err := s.useCase.Execute(s.ctx, s.operations(), true)
+		s.Require().NoError(err)
+		s.False(result.Committed)
+		s.Equal(1, s.transactor.Calls)
+		s.Equal(usecases.BulkRolledBack, result.Results[0].Status)
+		s.Nil(result.Results[0].Task)
+		s.Equal(usecases.BulkFailed, result.Results[1].Status)
+		s.Equal(usecases.BulkSkipped, result.Results[2].Status)
+		s.Require().NotNil(result.FirstFailure())
+		s.Equal(1, result.FirstFailure().Index)
+		s.ErrorIs(result.FirstFailure().Err(), domain.ErrValidationFailed)
+		s.Empty(s.events, "events of rolled back changes are never published")
+	})
+
+	s.Run("Atomic Publishes Events After Commit", func() {
+		s.SetupTest()
+		result, err := s.useCase.Execute(s.ctx, s.operations()[:1], true)
+		s.Require().NoError(err)
+		s.True(result.Committed)
+		s.Equal([]domain.TaskEventType{domain.TaskCreated}, s.events)
+	})
+
+	s.Run("Atomic Delete Joins The Bulk Transaction", func() {
+		s.SetupTest()
+		result, err := s.useCase.Execute(s.ctx, []usecases.BulkOperation{
+			{Action: usecases.BulkDelete, TaskID: s.pending.Id.Hex()},
+		}, true)
+		s.Require().NoError(err)
+		s.True(result.Committed)
+		s.Equal(1, s.transactor.Calls, "the deletion runs in the bulk transaction, not its own")
+		s.Equal([]domain.TaskEventType{domain.TaskDeleted}, s.events)
+	})
+
+	s.Run("Transactions Unsupported", func() {
+		s.SetupTest()
+		s.transactor.Err = domain.ErrTransactionsUnsupported
+		_, err := s.useCase.Execute(s.ctx, s.operations(), true)
+		s.ErrorIs(err, domain.ErrTransactionsUnsupported)
+		s.Empty(s.created)
+	})
+
+	s.Run("Batch Size Cap", func() {
+		s.SetupTest()
+		operations := make([]usecases.BulkOperation, usecases.MaxBulkOperations+1)
+		_, err := s.useCase.Execute(s.ctx, operations, false)
+		s.ErrorIs(err, domain.ErrValidationFailed)
+
+		_, err = s.useCase.Execute(s.ctx, nil, false)
+		s.ErrorIs(err, domain.ErrValidationFailed)
+	})
+
+	s.Run("Per Operation Permissions", func() {
+		s.SetupTest()
+		ctx := actorContext("editor-1", domain.PermTasksRead, domain.PermTasksUpdate)
+		_, err := s.useCase.Execute(ctx, s.operations(), false)
+		s.ErrorIs(err, domain.ErrForbidden, "creating tasks needs tasks:create")
+		s.Empty(s.created)
+	})
+}
+
+func (s *TaskBulkUseCaseSuite) TestUpdateWhere() {
+	s.Run("Needs A Field To Set", func() {
+		s.SetupTest()
+		_, err := s.useCase.UpdateWhere(s.ctx, usecases.TaskQuery{}, nil, nil, nil, nil, false)
+		s.ErrorIs(err, domain.ErrValidationFailed)
+	})
+
+	s.Run("Updates Every Match", func() {
+		s.SetupTest()
+		description := "bulk edited"
+		result, err := s.useCase.UpdateWhere(s.ctx, usecases.TaskQuery{}, nil, &description, nil, nil, false)
+		s.Require().NoError(err)
+		s.Len(result.Results, 2)
+		s.Equal("bulk edited", s.pending.Description)
+		s.Equal("bulk edited", s.done.Description)
+	})
+
+	s.Run("Invalid Project ID", func() {
+		s.SetupTest()
+		description := "bulk edited"
+		_, err := s.useCase.UpdateWhere(s.ctx, usecases.TaskQuery{ProjectID: "nope"}, nil, &description, nil, nil, false)
+		s.ErrorIs(err, domain.ErrValidationFailed)
+	})
+}
@@ -0,0 +1,207 @@
+package usecases
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AddDependency marks a task as blocked by another task. Links that would create a
+// cycle are rejected.
+func (uc *TaskUseCase) AddDependency(c context.Context, taskID, blockerID string) (*domain.Task, error) {
+	task, err := uc.GetTaskForEdit(c, taskID)
+	if err != nil {
+		return nil, err
+	}
+	blocker, err := uc.GetTaskByID(c, blockerID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = runUnitOfWork(c, uc.transactor, func(c context.Context) error {
+		if err := uc.ensureNoCycle(c, task.Id, blocker.Id); err != nil {
+			return err
+		}
+		if err := uc.taskRepo.AddBlocker(c, task.Id, blocker.Id); err != nil {
+			if errors.Is(err, domain.ErrTaskNotFound) {
+				return domain.ErrTaskNotFound
+			}
+			return fmt.Errorf("usecase: failed to add dependency: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Two requests linking the tasks in opposite directions write different documents,
+	// so neither a transaction nor the check above stops both from committing. Check
+	// again now that the link is visible and take it back if a cycle was closed.
+	if err := uc.ensureNoCycle(c, task.Id, blocker.Id); err != nil {
+		if removeErr := uc.taskRepo.RemoveBlocker(c, task.Id, blocker.Id); removeErr != nil {
+			return nil, fmt.Errorf("usecase: failed to undo dependency: %w", removeErr)
+		}
+		return nil, err
+	}
+	return uc.GetTaskByID(c, taskID)
+}
+
+// RemoveDependency removes a blocked-by link.
+func (uc *TaskUseCase) RemoveDependency(c context.Context, taskID, blockerID string) (*domain.Task, error) {
+	task, err := uc.GetTaskForEdit(c, taskID)
+	if err != nil {
+		return nil, err
+	}
+	blockerObjectID, err := primitive.ObjectIDFromHex(blockerID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid blocker ID format", domain.ErrValidationFailed)
+	}
+
+	if err := uc.taskRepo.RemoveBlocker(c, task.Id, blockerObjectID); err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			return nil, domain.ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("usecase: failed to remove dependency: %w", err)
+	}
+	return uc.GetTaskByID(c, taskID)
+}
+
+// GetDependencyGraph returns the task together with everything it transitively depends
+// on and everything that transitively depends on it, ordered so that blockers come
+// before the tasks they block. Tasks the caller cannot see are left out.
+func (uc *TaskUseCase) GetDependencyGraph(c context.Context, taskID string) ([]*domain.Task, error) {
+	task, err := uc.GetTaskByID(c, taskID)
+	if err != nil {
+		return nil, err
+	}
+	visibility, err := uc.visibilityFilter(c)
+	if err != nil {
+		return nil, err
+	}
+
+	blockers, err := uc.collectBlockers(c, visibility, []*domain.Task{task})
+	if err != nil {
+		return nil, err
+	}
+	dependents, err := uc.collectDependents(c, visibility, task)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []*domain.Task{}
+	seen := map[primitive.ObjectID]bool{}
+	for _, group := range [][]*domain.Task{blockers, dependents} {
+		for _, t := range group {
+			if !seen[t.Id] {
+				seen[t.Id] = true
+				tasks = append(tasks, t)
+			}
+		}
+	}
+	return domain.TopologicalOrder(tasks)
+}
+
+// ensureNoCycle fails with ErrDependencyCycle if blockerID depends on taskID, reading
+// the current links of blockerID rather than a copy loaded earlier.
+func (uc *TaskUseCase) ensureNoCycle(c context.Context, taskID, blockerID primitive.ObjectID) error {
+	blocker, err := uc.taskRepo.GetTaskById(c, blockerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			return domain.ErrTaskNotFound
+		}
+		return fmt.Errorf("usecase: failed to retrieve blocking task: %w", err)
+	}
+	// Cycle detection must see every link, not just the ones visible to the caller.
+	upstream, err := uc.collectBlockers(c, domain.TaskFilter{}, []*domain.Task{blocker})
+	if err != nil {
+		return err
+	}
+	if domain.NewDependencyGraph(upstream).WouldCreateCycle(taskID, blockerID) {
+		return fmt.Errorf("%w: task %s already depends on task %s", domain.ErrDependencyCycle, blockerID.Hex(), taskID.Hex())
+	}
+	return nil
+}
+
+// ensureUnblocked fails with ErrTaskBlocked unless every blocker of the task is Done.
+// Blockers that no longer exist do not block.
+func (uc *TaskUseCase) ensureUnblocked(c context.Context, task *domain.Task) error {
+	if len(task.BlockedBy) == 0 {
+		return nil
+	}
+	blockers, err := uc.taskRepo.GetAllTasks(c, domain.TaskFilter{IDs: task.BlockedBy})
+	if err != nil {
+		return fmt.Errorf("usecase: failed to load blocking tasks: %w", err)
+	}
+	unfinished := 0
+	for _, blocker := range blockers {
+		if blocker.Status != domain.Done {
+			unfinished++
+		}
+	}
+	if unfinished > 0 {
+		return fmt.Errorf("%w: %d blocking task(s) not done", domain.ErrTaskBlocked, unfinished)
+	}
+	return nil
+}
+
+// collectBlockers walks blocked-by links from the start tasks, one query per level.
+func (uc *TaskUseCase) collectBlockers(c context.Context, base domain.TaskFilter, start []*domain.Task) ([]*domain.Task, error) {
+	result := []*domain.Task{}
+	seen := map[primitive.ObjectID]bool{}
+	frontier := start
+	for len(frontier) > 0 {
+		next := []primitive.ObjectID{}
+		for _, t := range frontier {
+			if seen[t.Id] {
+				continue
+			}
+			seen[t.Id] = true
+			result = append(result, t)
+			for _, blockerID := range t.BlockedBy {
+				if !seen[blockerID] {
+					next = append(next, blockerID)
+				}
+			}
+		}
+		if len(next) == 0 {
+			break
+		}
+		filter := base
+		filter.IDs = next
+		loaded, err := uc.taskRepo.GetAllTasks(c, filter)
+		if err != nil {
+			return nil, fmt.Errorf("usecase: failed to load blocking tasks: %w", err)
+		}
+		frontier = loaded
+	}
+	return result, nil
+}
+
+// collectDependents walks links in the other direction, finding every task that
+// transitively waits on the given one.
+func (uc *TaskUseCase) collectDependents(c context.Context, base domain.TaskFilter, task *domain.Task) ([]*domain.Task, error) {
+	dependents := []*domain.Task{}
+	seen := map[primitive.ObjectID]bool{task.Id: true}
+	frontier := []primitive.ObjectID{task.Id}
+	for len(frontier) > 0 {
+		filter := base
+		filter.BlockedByAny = frontier
+		loaded, err := uc.taskRepo.GetAllTasks(c, filter)
+		if err != nil {
+			return nil, fmt.Errorf("usecase: failed to load dependent tasks: %w", err)
+		}
+		frontier = nil
+		for _, t := range loaded {
+			if seen[t.Id] {
+				continue
+			}
+			seen[t.Id] = true
+			dependents = append(dependents, t)
+			frontier = append(frontier, t.Id)
+		}
+	}
+	return dependents, nil
+}
@@ -0,0 +1,182 @@
+package usecases_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newInMemoryTaskRepository wires a MockTaskRepository to a map so that dependency
+// walks can be exercised end to end.
+func newInMemoryTaskRepository(tasks ...*domain.Task) *MockTaskRepository {
+	store := map[primitive.ObjectID]*domain.Task{}
+	for _, task := range tasks {
+		store[task.Id] = task
+	}
+	return &MockTaskRepository{
+		GetTaskByIdFunc: func(c context.Context, id primitive.ObjectID) (*domain.Task, error) {
+			task, ok := store[id]
+			if !ok {
+				return nil, domain.ErrTaskNotFound
+			}
+			return task, nil
+		},
+		GetAllTasksFunc: func(c context.Context, filter domain.TaskFilter) ([]*domain.Task, error) {
+			result := []*domain.Task{}
+			for _, task := range tasks {
+				if _, ok := store[task.Id]; !ok {
+					continue
+				}
+				if len(filter.IDs) > 0 && !slices.Contains(filter.IDs, task.Id) {
+					continue
+				}
+				if len(filter.BlockedByAny) > 0 && !slices.ContainsFunc(task.BlockedBy, func(id primitive.ObjectID) bool {
+					return slices.Contains(filter.BlockedByAny, id)
+				}) {
+					continue
+				}
+				result = append(result, task)
+			}
+			return result, nil
+		},
+		UpdateTaskFunc: func(c context.Context, id primitive.ObjectID, task *domain.Task) (*domain.Task, error) {
+			return task, nil
+		},
+		AddBlockerFunc: func(c context.Context, taskID, blockerID primitive.ObjectID) error {
+			store[taskID].BlockedBy = append(store[taskID].BlockedBy, blockerID)
+			return nil
+		},
+		RemoveBlockerFunc: func(c context.Context, taskID, blockerID primitive.ObjectID) error {
+			store[taskID].BlockedBy = slices.DeleteFunc(store[taskID].BlockedBy, func(id primitive.ObjectID) bool { return id == blockerID })
+			return nil
+		},
+		AddWatcherFunc: func(c context.Context, taskID primitive.ObjectID, userID string) error {
+			if !store[taskID].IsWatchedBy(userID) {
+				store[taskID].Watchers = append(store[taskID].Watchers, userID)
+			}
+			return nil
+		},
+		RemoveWatcherFunc: func(c context.Context, taskID primitive.ObjectID, userID string) error {
+			store[taskID].Watchers = slices.DeleteFunc(store[taskID].Watchers, func(id string) bool { return id == userID })
+			return nil
+		},
+	}
+}
+
+//===========================================================================
+// Task Dependency Test Suite
+//===========================================================================
+
+type TaskDependencySuite struct {
+	suite.Suite
+	design, build, release *domain.Task
+	useCase                *usecases.TaskUseCase
+	ctx                    context.Context
+}
+
+func TestTaskDependencySuite(t *testing.T) {
+	suite.Run(t, new(TaskDependencySuite))
+}
+
+// SetupTest creates a chain: release is blocked by build, which is blocked by design.
+func (s *TaskDependencySuite) SetupTest() {
+	s.design = &domain.Task{Id: primitive.NewObjectID(), Title: "Design", Status: domain.Pending}
+	s.build = &domain.Task{Id: primitive.NewObjectID(), Title: "Build", Status: domain.Pending, BlockedBy: []primitive.ObjectID{s.design.Id}}
+	s.release = &domain.Task{Id: primitive.NewObjectID(), Title: "Release", Status: domain.Pending, BlockedBy: []primitive.ObjectID{s.build.Id}}
+	repo := newInMemoryTaskRepository(s.release, s.build, s.design)
+	s.useCase = usecases.NewTaskUseCase(repo, NewMockProjectRepository())
+	s.ctx = domain.ContextWithActor(context.Background(), domain.SystemActor)
+}
+
+func (s *TaskDependencySuite) TestAddDependency() {
+	s.Run("Success", func() {
+		s.SetupTest()
+		task, err := s.useCase.AddDependency(s.ctx, s.release.Id.Hex(), s.design.Id.Hex())
+		s.Require().NoError(err)
+		s.Contains(task.BlockedBy, s.design.Id)
+	})
+
+	s.Run("Rejects Cycle", func() {
+		s.SetupTest()
+		_, err := s.useCase.AddDependency(s.ctx, s.design.Id.Hex(), s.release.Id.Hex())
+		s.ErrorIs(err, domain.ErrDependencyCycle)
+	})
+
+	s.Run("Rejects Self Link", func() {
+		s.SetupTest()
+		_, err := s.useCase.AddDependency(s.ctx, s.design.Id.Hex(), s.design.Id.Hex())
+		s.ErrorIs(err, domain.ErrDependencyCycle)
+	})
+
+	s.Run("Undoes Link When A Concurrent Request Closes A Cycle", func() {
+		frontend := &domain.Task{Id: primitive.NewObjectID(), Title: "Frontend", Status: domain.Pending}
+		backend := &domain.Task{Id: primitive.NewObjectID(), Title: "Backend", Status: domain.Pending}
+		repo := newInMemoryTaskRepository(frontend, backend)
+		addBlocker := repo.AddBlockerFunc
+		repo.AddBlockerFunc = func(c context.Context, taskID, blockerID primitive.ObjectID) error {
+			// Another request links the tasks the other way round while this one writes.
+			if err := addBlocker(c, backend.Id, frontend.Id); err != nil {
+				return err
+			}
+			return addBlocker(c, taskID, blockerID)
+		}
+		transactor := &FakeTransactor{}
+		useCase := usecases.NewTaskUseCase(repo, NewMockProjectRepository())
+		useCase.SetTransactor(transactor)
+
+		_, err := useCase.AddDependency(s.ctx, frontend.Id.Hex(), backend.Id.Hex())
+		s.ErrorIs(err, domain.ErrDependencyCycle)
+		s.Equal(1, transactor.Calls)
+		s.Empty(frontend.BlockedBy)
+		s.Equal([]primitive.ObjectID{frontend.Id}, backend.BlockedBy)
+	})
+
+	s.Run("Unknown Blocker", func() {
+		s.SetupTest()
+		_, err := s.useCase.AddDependency(s.ctx, s.design.Id.Hex(), primitive.NewObjectID().Hex())
+		s.ErrorIs(err, domain.ErrTaskNotFound)
+	})
+}
+
+func (s *TaskDependencySuite) TestRemoveDependency() {
+	task, err := s.useCase.RemoveDependency(s.ctx, s.build.Id.Hex(), s.design.Id.Hex())
+	s.Require().NoError(err)
+	s.Empty(task.BlockedBy)
+}
+
+func (s *TaskDependencySuite) TestGetDependencyGraph() {
+	tasks, err := s.useCase.GetDependencyGraph(s.ctx, s.build.Id.Hex())
+	s.Require().NoError(err)
+	s.Require().Len(tasks, 3)
+	s.Equal([]string{"Design", "Build", "Release"}, []string{tasks[0].Title, tasks[1].Title, tasks[2].Title})
+}
+
+func (s *TaskDependencySuite) TestBlockedStatusChange() {
+	inProgress := domain.InProgress
+
+	s.Run("Blocked Task Cannot Start", func() {
+		s.SetupTest()
+		_, err := s.useCase.UpdateTask(s.ctx, s.build.Id.Hex(), nil, nil, nil, &inProgress)
+		s.ErrorIs(err, domain.ErrTaskBlocked)
+	})
+
+	s.Run("Task Can Start Once Blockers Are Done", func() {
+		s.SetupTest()
+		s.design.Status = domain.Done
+		task, err := s.useCase.UpdateTask(s.ctx, s.build.Id.Hex(), nil, nil, nil, &inProgress)
+		s.Require().NoError(err)
+		s.Equal(domain.InProgress, task.Status)
+	})
+
+	s.Run("Other Fields Can Change While Blocked", func() {
+		s.SetupTest()
+		title := "Build v2"
+		_, err := s.useCase.UpdateTask(s.ctx, s.build.Id.Hex(), &title, nil, nil, nil)
+		s.NoError(err)
+	})
+}
@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaskHistoryUseCase struct {
+	revisionRepo domain.TaskRevisionRepository
+	tasks        *TaskUseCase
+}
+
+// NewTaskHistoryUseCase creates the use case and hooks it into the TaskUseCase, so that
+// every create and update records a revision in the same unit of work; a change whose
+// revision cannot be saved fails. Revisions are kept after their task is deleted.
+func NewTaskHistoryUseCase(revisionRepo domain.TaskRevisionRepository, tasks *TaskUseCase) *TaskHistoryUseCase {
+	uc := &TaskHistoryUseCase{
+		revisionRepo: revisionRepo,
+		tasks:        tasks,
+	}
+	tasks.OnChange(uc.recordRevision)
+	return uc
+}
+
+// GetHistory returns the revisions of a task, oldest first.
+func (uc *TaskHistoryUseCase) GetHistory(c context.Context, taskID string) ([]*domain.TaskRevision, error) {
+	task, err := uc.tasks.GetTaskByID(c, taskID)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := uc.revisionRepo.GetRevisionsByTask(c, task.Id)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to get task history: %w", err)
+	}
+	return revisions, nil
+}
+
+// RevertTask restores the task to the state recorded in a revision. The change goes
+// through TaskUseCase.UpdateTask, so the usual rules apply (e.g. a due date in the past
+// or reopening a completed task is rejected), and it is itself recorded as a revision.
+func (uc *TaskHistoryUseCase) RevertTask(c context.Context, taskID, revisionID string) (*domain.Task, error) {
+	task, err := uc.tasks.GetTaskForEdit(c, taskID)
+	if err != nil {
+		return nil, err
+	}
+	revisionObjectID, err := primitive.ObjectIDFromHex(revisionID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid revision ID format", domain.ErrValidationFailed)
+	}
+	revision, err := uc.revisionRepo.GetRevisionById(c, revisionObjectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrRevisionNotFound) {
+			return nil, domain.ErrRevisionNotFound
+		}
+		return nil, fmt.Errorf("usecase: failed to get revision: %w", err)
+	}
+	if revision.TaskID != task.Id {
+		return nil, domain.ErrRevisionNotFound
+	}
+
+	// Only pass the fields that differ, so unchanged fields are not re-validated.
+	target := revision.Snapshot
+	var title, description *string
+	var dueDate *time.Time
+	var status *domain.TaskStatus
+	if target.Title != task.Title {
+		title = &target.Title
+	}
+	if target.Description != task.Description {
+		description = &target.Description
+	}
+	if !target.DueDate.Equal(task.DueDate) {
+		dueDate = &target.DueDate
+	}
+	if target.Status != task.Status {
+		status = &target.Status
+	}
+	if title == nil && description == nil && dueDate == nil && status == nil {
+		return task, nil
+	}
+	return uc.tasks.UpdateTask(c, taskID, title, description, dueDate, status)
+}
+
+func (uc *TaskHistoryUseCase) recordRevision(c context.Context, event domain.TaskEvent) error {
+	if event.Type != domain.TaskCreated && event.Type != domain.TaskUpdated {
+		return nil
+	}
+	changes := domain.DiffTasks(event.Previous, event.Task)
+	if len(changes) == 0 {
+		return nil
+	}
+	revision := &domain.TaskRevision{
+		TaskID:    event.Task.Id,
+		Changes:   changes,
+		Snapshot:  domain.SnapshotOf(event.Task),
+		CreatedAt: event.OccurredAt,
+	}
+	if actor, ok := domain.ActorFromContext(c); ok {
+		revision.EditedBy = actor.UserID
+	}
+	if _, err := uc.revisionRepo.CreateRevision(c, revision); err != nil {
+		return fmt.Errorf("usecase: failed to record revision of task %s: %w", event.Task.Id.Hex(), err)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package usecases_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// --- In-memory mock for revisions ---
+type MockRevisionRepository struct {
+	Revisions []*domain.TaskRevision
+	CreateErr error
+}
+
+func (m *MockRevisionRepository) CreateRevision(c context.Context, revision *domain.TaskRevision) (*domain.TaskRevision, error) {
+	if m.CreateErr != nil {
+		return nil, m.CreateErr
+	}
+	revision.Id = primitive.NewObjectID()
+	m.Revisions = append(m.Revisions, revision)
+	return revision, nil
+}
+func (m *MockRevisionRepository) GetRevisionById(c context.Context, id primitive.ObjectID) (*domain.TaskRevision, error) {
+	for _, revision := range m.Revisions {
+		if revision.Id == id {
+			return revision, nil
+		}
+	}
+	return nil, domain.ErrRevisionNotFound
+}
+func (m *MockRevisionRepository) GetRevisionsByTask(c context.Context, taskID primitive.ObjectID) ([]*domain.TaskRevision, error) {
+	revisions := []*domain.TaskRevision{}
+	for _, revision := range m.Revisions {
+		if revision.TaskID == taskID {
+			revisions = append(revisions, revision)
+		}
+	}
+	return revisions, nil
+}
+
+//===========================================================================
+// TaskHistoryUseCase Test Suite
+//===========================================================================
+
+type TaskHistoryUseCaseSuite struct {
+	suite.Suite
+	task      *domain.Task
+	revisions *MockRevisionRepository
+	taskUC    *usecases.TaskUseCase
+	useCase   *usecases.TaskHistoryUseCase
+	ctx       context.Context
+}
+
+func TestTaskHistoryUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(TaskHistoryUseCaseSuite))
+}
+
+func (s *TaskHistoryUseCaseSuite) SetupTest() {
+	s.task = &domain.Task{Id: primitive.NewObjectID(), Title: "Original", Status: domain.Pending, DueDate: time.Now().Add(48 * time.Hour)}
+	taskRepo := newInMemoryTaskRepository(s.task)
+	taskRepo.UpdateTaskFunc = func(c context.Context, id primitive.ObjectID, task *domain.Task) (*domain.Task, error) {
+		*s.task = *task
+		return task, nil
+	}
+	s.revisions = &MockRevisionRepository{}
+	s.taskUC = usecases.NewTaskUseCase(taskRepo, NewMockProjectRepository())
+	s.useCase = usecases.NewTaskHistoryUseCase(s.revisions, s.taskUC)
+	s.ctx = domain.ContextWithActor(context.Background(), &domain.Actor{UserID: "editor-1"})
+}
+
+func (s *TaskHistoryUseCaseSuite) TestUpdatesAreRecorded() {
+	title := "Renamed"
+	_, err := s.taskUC.UpdateTask(s.ctx, s.task.Id.Hex(), &title, nil, nil, nil)
+	s.Require().NoError(err)
+
+	history, err := s.useCase.GetHistory(s.ctx, s.task.Id.Hex())
+	s.Require().NoError(err)
+	s.Require().Len(history, 1)
+	s.Equal([]domain.FieldChange{{Field: "title", Old: "Original", New: "Renamed"}}, history[0].Changes)
+	s.Equal("editor-1", history[0].EditedBy)
+	s.Equal("Renamed", history[0].Snapshot.Title)
+
+	// An update that changes nothing does not add a revision
+	_, err = s.taskUC.UpdateTask(s.ctx, s.task.Id.Hex(), &title, nil, nil, nil)
+	s.Require().NoError(err)
+	history, _ = s.useCase.GetHistory(s.ctx, s.task.Id.Hex())
+	s.Len(history, 1)
+}
+
+func (s *TaskHistoryUseCaseSuite) TestRevisionIsPartOfTheUpdate() {
+	var published []domain.TaskEvent
+	s.taskUC.Subscribe(func(c context.Context, event domain.TaskEvent) {
+		published = append(published, event)
+	})
+	s.revisions.CreateErr = errors.New("db down")
+
+	title := "Renamed"
+	_, err := s.taskUC.UpdateTask(s.ctx, s.task.Id.Hex(), &title, nil, nil, nil)
+	s.Error(err, "an update whose revision cannot be saved fails")
+	s.Empty(published, "the failed update is not published")
+}
+
+func (s *TaskHistoryUseCaseSuite) TestHistoryOutlivesTask() {
+	taskRepo := newInMemoryTaskRepository(s.task)
+	taskRepo.DeleteTaskFunc = func(c context.Context, id primitive.ObjectID) error { return nil }
+	taskRepo.RemoveBlockerFromAllFunc = func(c context.Context, blockerID primitive.ObjectID) error { return nil }
+	s.taskUC = usecases.NewTaskUseCase(taskRepo, NewMockProjectRepository())
+	s.useCase = usecases.NewTaskHistoryUseCase(s.revisions, s.taskUC)
+	title := "Renamed"
+	_, err := s.taskUC.UpdateTask(s.ctx, s.task.Id.Hex(), &title, nil, nil, nil)
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.taskUC.DeleteTask(s.ctx, s.task.Id.Hex()))
+	revisions, err := s.revisions.GetRevisionsByTask(s.ctx, s.task.Id)
+	s.Require().NoError(err)
+	s.Len(revisions, 1)
+}
+
+func (s *TaskHistoryUseCaseSuite) TestRevertTask() {
+	s.Run("Restores Snapshot", func() {
+		s.SetupTest()
+		first, second := "First", "Second"
+		_, err := s.taskUC.UpdateTask(s.ctx, s.task.Id.Hex(), &first, nil, nil, nil)
+		s.Require().NoError(err)
+		_, err = s.taskUC.UpdateTask(s.ctx, s.task.Id.Hex(), &second, nil, nil, nil)
+		s.Require().NoError(err)
+
+		reverted, err := s.useCase.RevertTask(s.ctx, s.task.Id.Hex(), s.revisions.Revisions[0].Id.Hex())
+		s.Require().NoError(err)
+		s.Equal("First", reverted.Title)
+		s.Len(s.revisions.Revisions, 3, "the revert is recorded as a revision")
+	})
+
+	s.Run("Validation Rules Apply", func() {
+		s.SetupTest()
+		inProgress, done := domain.InProgress, domain.Done
+		_, err := s.taskUC.UpdateTask(s.ctx, s.task.Id.Hex(), nil, nil, nil, &inProgress)
+		s.Require().NoError(err)
+		_, err = s.taskUC.UpdateTask(s.ctx, s.task.Id.Hex(), nil, nil, nil, &done)
+		s.Require().NoError(err)
+
+		_, err = s.useCase.RevertTask(s.ctx, s.task.Id.Hex(), s.revisions.Revisions[0].Id.Hex())
+		s.ErrorIs(err, domain.ErrValidationFailed, "a completed task cannot be reopened by a revert")
+	})
+
+	s.Run("Revision Of Another Task", func() {
+		s.SetupTest()
+		other, _ := s.revisions.CreateRevision(s.ctx, &domain.TaskRevision{TaskID: primitive.NewObjectID()})
+		_, err := s.useCase.RevertTask(s.ctx, s.task.Id.Hex(), other.Id.Hex())
+		s.ErrorIs(err, domain.ErrRevisionNotFound)
+	})
+}
@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// defaultStatsDays is the length of the completion trend when no range is given.
+	defaultStatsDays = 30
+	// maxStatsDays bounds the completion trend, which has one entry per day.
+	maxStatsDays = 366
+)
+
+// TaskStatsQuery narrows a statistics request. From and To are calendar days in
+// Location; both are inclusive.
+type TaskStatsQuery struct {
+	OwnerID   string
+	Tag       string
+	ProjectID string
+	From      *time.Time
+	To        *time.Time
+	Location  *time.Location
+}
+
+// GetTaskStats reports statistics over the tasks the caller can see. Weeks start on
+// Monday in the query's location.
+func (uc *TaskUseCase) GetTaskStats(c context.Context, query TaskStatsQuery) (*domain.TaskStats, error) {
+	filter, err := uc.queryFilter(c, TaskQuery{ProjectID: query.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+	filter.OwnerID = query.OwnerID
+	if query.Tag != "" {
+		tags, err := domain.NormalizeTags([]string{query.Tag})
+		if err != nil {
+			return nil, err
+		}
+		if len(tags) == 1 {
+			filter.Tag = tags[0]
+		}
+	}
+
+	if query.Location == nil {
+		// Default to the caller's time zone, which is UTC unless they have set one.
+		query.Location, _ = domain.LocationFromContext(c)
+	}
+	params, err := statsParams(uc.clock.Now(), query)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := uc.taskRepo.GetTaskStats(c, filter, params)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to compute task stats: %w", err)
+	}
+	return stats, nil
+}
+
+func statsParams(now time.Time, query TaskStatsQuery) (domain.TaskStatsParams, error) {
+	location := query.Location
+	if location == nil {
+		location = time.UTC
+	}
+	now = now.In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	// time.Weekday counts from Sunday; shift it so that Monday is day 0.
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+
+	to := today.AddDate(0, 0, 1)
+	if query.To != nil {
+		to = startOfDay(*query.To, location).AddDate(0, 0, 1)
+	}
+	from := to.AddDate(0, 0, -defaultStatsDays)
+	if query.From != nil {
+		from = startOfDay(*query.From, location)
+	}
+	if !from.Before(to) {
+		return domain.TaskStatsParams{}, fmt.Errorf("%w: from must not be after to", domain.ErrValidationFailed)
+	}
+	if from.AddDate(0, 0, maxStatsDays).Before(to) {
+		return domain.TaskStatsParams{}, fmt.Errorf("%w: the date range cannot exceed %d days", domain.ErrValidationFailed, maxStatsDays)
+	}
+
+	return domain.TaskStatsParams{
+		Now:       now,
+		WeekStart: weekStart,
+		WeekEnd:   weekStart.AddDate(0, 0, 7),
+		From:      from,
+		To:        to,
+		Location:  location,
+	}, nil
+}
+
+// startOfDay returns midnight in location of the calendar date of t.
+func startOfDay(t time.Time, location *time.Location) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, location)
+}
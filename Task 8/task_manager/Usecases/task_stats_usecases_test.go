@@ -0,0 +1,104 @@
+package usecases_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	repositories "A2SV_ProjectPhase/Task8/TaskManager/Repositories"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//===========================================================================
+// Task Statistics Test Suite
+//===========================================================================
+
+// TaskStatsUseCaseSuite runs against the in-memory task repository, so that owners,
+// tags and completion times go through a real store.
+type TaskStatsUseCaseSuite struct {
+	suite.Suite
+	useCase *usecases.TaskUseCase
+	alice   context.Context
+	bob     context.Context
+}
+
+func TestTaskStatsUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(TaskStatsUseCaseSuite))
+}
+
+func (s *TaskStatsUseCaseSuite) SetupTest() {
+	s.useCase = usecases.NewTaskUseCase(repositories.NewInMemoryTaskRepository(), NewMockProjectRepository())
+	organizationID := primitive.NewObjectID()
+	s.alice = domain.ContextWithOrganization(actorContext("alice", domain.PermTasksRead, domain.PermTasksCreate, domain.PermTasksUpdate), organizationID)
+	s.bob = domain.ContextWithOrganization(actorContext("bob", domain.PermTasksRead, domain.PermTasksCreate, domain.PermTasksUpdate), organizationID)
+}
+
+func (s *TaskStatsUseCaseSuite) create(c context.Context, title string, due time.Time) *domain.Task {
+	task, err := s.useCase.CreateTask(c, title, "", due, domain.Pending)
+	s.Require().NoError(err)
+	return task
+}
+
+func (s *TaskStatsUseCaseSuite) TestOwnerTagsAndCompletion() {
+	task := s.create(s.alice, "Mine", time.Now().Add(24*time.Hour))
+	s.Equal("alice", task.OwnerID)
+
+	tagged, err := s.useCase.SetTags(s.alice, task.Id.Hex(), []string{"UI", "ui", " release "})
+	s.Require().NoError(err)
+	s.Equal([]string{"ui", "release"}, tagged.Tags)
+
+	done := domain.Done
+	updated, err := s.useCase.UpdateTask(s.alice, task.Id.Hex(), nil, nil, nil, &done)
+	s.Require().NoError(err)
+	s.NotNil(updated.CompletedAt)
+	s.Equal([]string{"ui", "release"}, updated.Tags, "status updates keep the tags")
+}
+
+func (s *TaskStatsUseCaseSuite) TestGetTaskStats() {
+	today := time.Now().UTC()
+	mine := s.create(s.alice, "Mine", today.Add(24*time.Hour))
+	s.create(s.alice, "Also mine", today.Add(60*24*time.Hour))
+	s.create(s.bob, "Bob's", today.Add(24*time.Hour))
+	_, err := s.useCase.SetTags(s.alice, mine.Id.Hex(), []string{"ui"})
+	s.Require().NoError(err)
+	done := domain.Done
+	_, err = s.useCase.UpdateTask(s.alice, mine.Id.Hex(), nil, nil, nil, &done)
+	s.Require().NoError(err)
+
+	s.Run("All Tasks", func() {
+		stats, err := s.useCase.GetTaskStats(s.alice, usecases.TaskStatsQuery{})
+		s.Require().NoError(err)
+		s.Equal(3, stats.Total)
+		s.Equal(1, stats.ByStatus[domain.Done])
+		s.Len(stats.CompletionsPerDay, 30)
+		s.Equal(today.Format("2006-01-02"), stats.CompletionsPerDay[29].Date)
+		s.Equal(1, stats.CompletionsPerDay[29].Count)
+	})
+
+	s.Run("By Owner And Tag", func() {
+		stats, err := s.useCase.GetTaskStats(s.alice, usecases.TaskStatsQuery{OwnerID: "alice"})
+		s.Require().NoError(err)
+		s.Equal(2, stats.Total)
+
+		stats, err = s.useCase.GetTaskStats(s.alice, usecases.TaskStatsQuery{Tag: "UI"})
+		s.Require().NoError(err)
+		s.Equal(1, stats.Total)
+	})
+
+	s.Run("Date Range", func() {
+		from := today.AddDate(0, 0, -6)
+		stats, err := s.useCase.GetTaskStats(s.alice, usecases.TaskStatsQuery{From: &from, To: &today})
+		s.Require().NoError(err)
+		s.Len(stats.CompletionsPerDay, 7)
+
+		_, err = s.useCase.GetTaskStats(s.alice, usecases.TaskStatsQuery{From: &today, To: &from})
+		s.ErrorIs(err, domain.ErrValidationFailed)
+
+		longAgo := today.AddDate(-2, 0, 0)
+		_, err = s.useCase.GetTaskStats(s.alice, usecases.TaskStatsQuery{From: &longAgo})
+		s.ErrorIs(err, domain.ErrValidationFailed)
+	})
+}
@@ -0,0 +1,174 @@
+package usecases
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ExportTasks streams every task matching the query that the caller can see to emit.
+// Errors returned before the first call to emit leave nothing written, so callers can
+// still report them normally.
+func (uc *TaskUseCase) ExportTasks(c context.Context, query TaskQuery, emit func(task *domain.Task) error) error {
+	filter, err := uc.queryFilter(c, query)
+	if err != nil {
+		return err
+	}
+	if err := uc.taskRepo.StreamTasks(c, filter, emit); err != nil {
+		return fmt.Errorf("usecase: failed to export tasks: %w", err)
+	}
+	return nil
+}
+
+// TaskImportRow is one task read from an import file. Row is its 1-based position
+// among the data rows. Plain due dates are placed in the caller's time zone.
+type TaskImportRow struct {
+	Row         int
+	ExternalID  string
+	Title       string
+	Description string
+	DueDate     domain.DueDate
+	Status      domain.TaskStatus
+}
+
+// MalformedRowError is returned by a TaskImportSource for a row it could not parse.
+// The import records it and moves on to the next row.
+type MalformedRowError struct {
+	Row int
+	Err error
+}
+
+func (e *MalformedRowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *MalformedRowError) Unwrap() error {
+	return e.Err
+}
+
+// TaskImportSource yields the rows of an import file one at a time and returns io.EOF
+// after the last one. Errors other than *MalformedRowError abort the import.
+type TaskImportSource func() (*TaskImportRow, error)
+
+type TaskImportOptions struct {
+	// DryRun validates every row and reports what would happen without writing anything.
+	DryRun bool
+	// Upsert updates the task with the same external ID instead of rejecting the row.
+	Upsert bool
+}
+
+type ImportRowError struct {
+	Row        int    `json:"row"`
+	ExternalID string `json:"external_id,omitempty"`
+	Error      string `json:"error"`
+}
+
+type ImportReport struct {
+	DryRun  bool             `json:"dry_run"`
+	Total   int              `json:"total"`
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Failed  int              `json:"failed"`
+	Errors  []ImportRowError `json:"errors"`
+}
+
+// ImportTasks reads rows from next and creates a task for each valid one. Every row is
+// validated by domain.NewTask. Rows with an external ID that already exists are
+// rejected, or update the existing task when opts.Upsert is set. If the source fails,
+// the rows imported so far are kept and the partial report is returned with the error.
+func (uc *TaskUseCase) ImportTasks(c context.Context, next TaskImportSource, opts TaskImportOptions) (*ImportReport, error) {
+	report := &ImportReport{DryRun: opts.DryRun, Errors: []ImportRowError{}}
+	// External IDs seen in this file, so that a file cannot create the same task twice.
+	seen := map[string]int{}
+
+	for {
+		row, err := next()
+		if errors.Is(err, io.EOF) {
+			return report, nil
+		}
+		var malformed *MalformedRowError
+		if errors.As(err, &malformed) {
+			report.Total++
+			report.Failed++
+			report.Errors = append(report.Errors, ImportRowError{Row: malformed.Row, Error: malformed.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return report, fmt.Errorf("%w: failed to read import file: %w", domain.ErrValidationFailed, err)
+		}
+
+		report.Total++
+		updated, err := uc.importRow(c, row, opts, seen)
+		if err != nil {
+			report.Failed++
+			report.Errors = append(report.Errors, ImportRowError{Row: row.Row, ExternalID: row.ExternalID, Error: err.Error()})
+			continue
+		}
+		if updated {
+			report.Updated++
+		} else {
+			report.Created++
+		}
+	}
+}
+
+// importRow imports a single row and reports whether it updated an existing task.
+func (uc *TaskUseCase) importRow(c context.Context, row *TaskImportRow, opts TaskImportOptions, seen map[string]int) (bool, error) {
+	location, _ := domain.LocationFromContext(c)
+	task, err := domain.NewTask(row.Title, row.Description, row.DueDate.In(location), row.Status, uc.now(c))
+	if err != nil {
+		return false, err
+	}
+	task.ExternalID = row.ExternalID
+	task.OwnerID = actorUserID(c)
+
+	if row.ExternalID == "" {
+		return false, uc.importCreate(c, task, opts.DryRun)
+	}
+	if firstRow, ok := seen[row.ExternalID]; ok {
+		return false, fmt.Errorf("external ID %q already used in row %d", row.ExternalID, firstRow)
+	}
+	seen[row.ExternalID] = row.Row
+
+	existing, err := uc.taskRepo.GetTaskByExternalID(c, row.ExternalID)
+	if errors.Is(err, domain.ErrTaskNotFound) {
+		return false, uc.importCreate(c, task, opts.DryRun)
+	}
+	if err != nil {
+		return false, fmt.Errorf("usecase: failed to look up external ID: %w", err)
+	}
+	if !opts.Upsert {
+		return false, fmt.Errorf("a task with external ID %q already exists", row.ExternalID)
+	}
+	if opts.DryRun {
+		// A dry run still reports rows the caller may not change.
+		if _, err := uc.GetTaskForEdit(c, existing.Id.Hex()); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	// Updates go through PatchTask, so that they follow the same rules as edits made
+	// through the API: completed tasks stay completed, blockers are respected, and
+	// history and mentions are recorded.
+	patch := domain.TaskPatch{
+		Title:       domain.Present(task.Title),
+		Description: domain.Present(task.Description),
+		DueDate:     domain.Present(row.DueDate),
+		Status:      domain.Present(task.Status),
+	}
+	if _, err := uc.PatchTask(c, existing.Id.Hex(), patch); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (uc *TaskUseCase) importCreate(c context.Context, task *domain.Task, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+	_, err := uc.createTask(c, task)
+	return err
+}
@@ -0,0 +1,169 @@
+package usecases_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sliceImportSource yields the given rows and errors in order, then io.EOF.
+func sliceImportSource(items ...interface{}) usecases.TaskImportSource {
+	return func() (*usecases.TaskImportRow, error) {
+		if len(items) == 0 {
+			return nil, io.EOF
+		}
+		item := items[0]
+		items = items[1:]
+		if err, ok := item.(error); ok {
+			return nil, err
+		}
+		return item.(*usecases.TaskImportRow), nil
+	}
+}
+
+//===========================================================================
+// Task Import/Export Test Suite
+//===========================================================================
+
+type TaskTransferSuite struct {
+	suite.Suite
+	existing *domain.Task
+	created  []*domain.Task
+	useCase  *usecases.TaskUseCase
+	ctx      context.Context
+	dueDate  time.Time
+}
+
+func TestTaskTransferSuite(t *testing.T) {
+	suite.Run(t, new(TaskTransferSuite))
+}
+
+func (s *TaskTransferSuite) SetupTest() {
+	s.dueDate = time.Now().Add(72 * time.Hour)
+	s.existing = &domain.Task{Id: primitive.NewObjectID(), Title: "Existing", Status: domain.Pending, DueDate: s.dueDate, ExternalID: "JIRA-1"}
+	s.created = nil
+	taskRepo := newInMemoryTaskRepository(s.existing)
+	taskRepo.CreateTaskFunc = func(c context.Context, task *domain.Task) (*domain.Task, error) {
+		task.Id = primitive.NewObjectID()
+		s.created = append(s.created, task)
+		return task, nil
+	}
+	taskRepo.GetTaskByExternalIDFunc = func(c context.Context, externalID string) (*domain.Task, error) {
+		if externalID == s.existing.ExternalID {
+			return s.existing, nil
+		}
+		return nil, domain.ErrTaskNotFound
+	}
+	s.useCase = usecases.NewTaskUseCase(taskRepo, NewMockProjectRepository())
+	s.ctx = domain.ContextWithActor(context.Background(), domain.SystemActor)
+}
+
+func (s *TaskTransferSuite) rows() usecases.TaskImportSource {
+	return sliceImportSource(
+		&usecases.TaskImportRow{Row: 1, Title: "New", DueDate: domain.DueDate{Time: s.dueDate}, Status: domain.Pending},
+		&usecases.TaskImportRow{Row: 2, ExternalID: "JIRA-1", Title: "Renamed", DueDate: domain.DueDate{Time: s.dueDate}, Status: domain.InProgress},
+		&usecases.TaskImportRow{Row: 3, Title: "", DueDate: domain.DueDate{Time: s.dueDate}, Status: domain.Pending},
+		&usecases.MalformedRowError{Row: 4, Err: errors.New("bad date")},
+		&usecases.TaskImportRow{Row: 5, ExternalID: "JIRA-2", Title: "Other", DueDate: domain.DueDate{Time: s.dueDate}, Status: domain.Done},
+		&usecases.TaskImportRow{Row: 6, ExternalID: "JIRA-2", Title: "Again", DueDate: domain.DueDate{Time: s.dueDate}, Status: domain.Done},
+	)
+}
+
+func (s *TaskTransferSuite) TestImportTasks() {
+	s.Run("Reports Row Errors", func() {
+		s.SetupTest()
+		report, err := s.useCase.ImportTasks(s.ctx, s.rows(), usecases.TaskImportOptions{})
+		s.Require().NoError(err)
+		s.Equal(6, report.Total)
+		s.Equal(2, report.Created)
+		s.Zero(report.Updated)
+		s.Equal(4, report.Failed)
+		s.Require().Len(report.Errors, 4)
+		s.Equal(2, report.Errors[0].Row, "existing external IDs are rejected without upsert")
+		s.Equal(3, report.Errors[1].Row)
+		s.Equal(4, report.Errors[2].Row)
+		s.Equal(6, report.Errors[3].Row, "external IDs must be unique within a file")
+		s.Equal("JIRA-2", s.created[1].ExternalID)
+		s.Equal("Existing", s.existing.Title)
+	})
+
+	s.Run("Upsert", func() {
+		s.SetupTest()
+		report, err := s.useCase.ImportTasks(s.ctx, s.rows(), usecases.TaskImportOptions{Upsert: true})
+		s.Require().NoError(err)
+		s.Equal(2, report.Created)
+		s.Equal(1, report.Updated)
+		s.Equal("Renamed", s.existing.Title)
+		s.Equal(domain.InProgress, s.existing.Status)
+	})
+
+	s.Run("Upsert Follows The Edit Rules", func() {
+		s.SetupTest()
+		s.existing.Status = domain.Done
+		report, err := s.useCase.ImportTasks(s.ctx, s.rows(), usecases.TaskImportOptions{Upsert: true})
+		s.Require().NoError(err)
+		s.Zero(report.Updated)
+		s.Equal(2, report.Errors[0].Row, "completed tasks cannot be reopened by an import")
+		s.Equal("Existing", s.existing.Title)
+	})
+
+	s.Run("Dry Run Writes Nothing", func() {
+		s.SetupTest()
+		report, err := s.useCase.ImportTasks(s.ctx, s.rows(), usecases.TaskImportOptions{DryRun: true, Upsert: true})
+		s.Require().NoError(err)
+		s.True(report.DryRun)
+		s.Equal(2, report.Created)
+		s.Equal(1, report.Updated)
+		s.Empty(s.created)
+		s.Equal("Existing", s.existing.Title)
+	})
+
+	s.Run("Plain Dates Use The Caller's Time Zone", func() {
+		s.SetupTest()
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		s.Require().NoError(err)
+		day := time.Now().AddDate(0, 0, 3)
+		dueDate := domain.DueDate{Time: time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC), DateOnly: true}
+		source := sliceImportSource(
+			&usecases.TaskImportRow{Row: 1, Title: "New", DueDate: dueDate, Status: domain.Pending},
+			&usecases.TaskImportRow{Row: 2, ExternalID: "JIRA-1", Title: "Renamed", DueDate: dueDate, Status: domain.Pending},
+		)
+		report, err := s.useCase.ImportTasks(domain.ContextWithLocation(s.ctx, tokyo), source, usecases.TaskImportOptions{Upsert: true})
+		s.Require().NoError(err)
+		s.Require().Empty(report.Errors)
+		want := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, tokyo)
+		s.True(want.Equal(s.created[0].DueDate), "created: got %v, want %v", s.created[0].DueDate, want)
+		s.True(want.Equal(s.existing.DueDate), "updated: got %v, want %v", s.existing.DueDate, want)
+	})
+
+	s.Run("Unreadable File", func() {
+		s.SetupTest()
+		source := sliceImportSource(
+			&usecases.TaskImportRow{Row: 1, Title: "New", DueDate: domain.DueDate{Time: s.dueDate}, Status: domain.Pending},
+			errors.New("unexpected end of JSON input"),
+		)
+		report, err := s.useCase.ImportTasks(s.ctx, source, usecases.TaskImportOptions{})
+		s.ErrorIs(err, domain.ErrValidationFailed)
+		s.Equal(1, report.Created, "rows before the failure are reported")
+	})
+}
+
+func (s *TaskTransferSuite) TestExportTasks() {
+	var exported []*domain.Task
+	err := s.useCase.ExportTasks(s.ctx, usecases.TaskQuery{}, func(task *domain.Task) error {
+		exported = append(exported, task)
+		return nil
+	})
+	s.Require().NoError(err)
+	s.Equal([]*domain.Task{s.existing}, exported)
+
+	err = s.useCase.ExportTasks(s.ctx, usecases.TaskQuery{ProjectID: "invalid"}, func(task *domain.Task) error { return nil })
+	s.ErrorIs(err, domain.ErrValidationFailed)
+}
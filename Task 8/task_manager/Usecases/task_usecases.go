@@ -11,30 +11,152 @@ import (
 )
 
 type TaskUseCase struct {
-	taskRepo domain.TaskRepository
+	taskRepo    domain.TaskRepository
+	projectRepo domain.ProjectRepository
+	userRepo    domain.UserRepository
+	handlers    []domain.TaskEventHandler
+	changeHooks []TaskChangeHook
+	deleteHooks []TaskDeleteHook
+	clock       domain.Clock
+	transactor  domain.Transactor
 }
 
-func NewTaskUseCase(taskRepo domain.TaskRepository) *TaskUseCase {
+// TaskChangeHook records data derived from a task change, such as its history. It runs
+// in the same unit of work as the change, and an error rolls the change back.
+type TaskChangeHook func(c context.Context, event domain.TaskEvent) error
+
+// TaskDeleteHook removes data that belongs to a task being deleted. It runs in the same
+// unit of work as the deletion, and an error rolls the deletion back.
+type TaskDeleteHook func(c context.Context, task *domain.Task) error
+
+func NewTaskUseCase(taskRepo domain.TaskRepository, projectRepo domain.ProjectRepository) *TaskUseCase {
 	return &TaskUseCase{
-		taskRepo: taskRepo,
+		taskRepo:    taskRepo,
+		projectRepo: projectRepo,
+		clock:       domain.SystemClock{},
+		transactor:  domain.NoopTransactor{},
 	}
 }
 
+// SetClock replaces the system clock, e.g. with a fixed time in tests.
+func (uc *TaskUseCase) SetClock(clock domain.Clock) {
+	uc.clock = clock
+}
+
+// SetUserRepository lets @username mentions in descriptions be resolved to users, who
+// then watch the task. Without one, mentions are plain text.
+func (uc *TaskUseCase) SetUserRepository(userRepo domain.UserRepository) {
+	uc.userRepo = userRepo
+}
+
+// SetTransactor makes operations that write several documents atomic. Without one,
+// they run without a transaction.
+func (uc *TaskUseCase) SetTransactor(transactor domain.Transactor) {
+	uc.transactor = transactor
+}
+
+// now returns the current time in the caller's time zone, so that due dates are
+// compared against the caller's "today".
+func (uc *TaskUseCase) now(c context.Context) time.Time {
+	location, _ := domain.LocationFromContext(c)
+	return uc.clock.Now().In(location)
+}
+
 func (uc *TaskUseCase) CreateTask(c context.Context, title, description string, dueDate time.Time, status domain.TaskStatus) (*domain.Task, error) {
-	newTask, err := domain.NewTask(title, description, dueDate, status)
+	newTask, err := domain.NewTask(title, description, dueDate, status, uc.now(c))
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed to create task entity: %s", domain.ErrValidationFailed, err.Error())
+		return nil, err
+	}
+	newTask.OwnerID = actorUserID(c)
+	if err := uc.addInitialWatchers(c, newTask); err != nil {
+		return nil, err
 	}
 
 	// 2. Persist the task via repository
-	savedTask, err := uc.taskRepo.CreateTask(c, newTask)
+	return uc.createTask(c, newTask)
+}
+
+// createTask saves a new task and publishes its creation in one unit of work.
+func (uc *TaskUseCase) createTask(c context.Context, task *domain.Task) (*domain.Task, error) {
+	var savedTask *domain.Task
+	err := runUnitOfWork(c, uc.transactor, func(c context.Context) error {
+		var err error
+		savedTask, err = uc.taskRepo.CreateTask(c, task)
+		if err != nil {
+			return fmt.Errorf("usecase: failed to save task: %w", err)
+		}
+		return uc.publish(c, domain.TaskCreated, savedTask, nil)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("usecase: failed to save task: %w", err)
+		return nil, err
 	}
-
 	return savedTask, nil
 }
 
+// Subscribe registers a handler that is called after tasks change.
+func (uc *TaskUseCase) Subscribe(handler domain.TaskEventHandler) {
+	uc.handlers = append(uc.handlers, handler)
+}
+
+// OnChange registers a hook that runs whenever a task is created, updated or deleted.
+func (uc *TaskUseCase) OnChange(hook TaskChangeHook) {
+	uc.changeHooks = append(uc.changeHooks, hook)
+}
+
+// OnDelete registers a hook that removes data belonging to deleted tasks.
+func (uc *TaskUseCase) OnDelete(hook TaskDeleteHook) {
+	uc.deleteHooks = append(uc.deleteHooks, hook)
+}
+
+// publish runs the change hooks and notifies the handlers of a change. Inside a unit of
+// work, the hooks write as part of it and the handlers are only notified once it
+// commits; an error from a hook must fail the unit of work.
+func (uc *TaskUseCase) publish(c context.Context, eventType domain.TaskEventType, task, previous *domain.Task) error {
+	event := domain.TaskEvent{Type: eventType, Task: task, Previous: previous, OccurredAt: uc.clock.Now()}
+	for _, hook := range uc.changeHooks {
+		if err := hook(c, event); err != nil {
+			return err
+		}
+	}
+	afterCommit(c, func(c context.Context) {
+		uc.dispatch(c, event)
+	})
+	return nil
+}
+
+func (uc *TaskUseCase) dispatch(c context.Context, event domain.TaskEvent) {
+	for _, handler := range uc.handlers {
+		handler(c, event)
+	}
+}
+
+// CreateProjectTask creates a task inside a project. Only owners and editors may add tasks.
+func (uc *TaskUseCase) CreateProjectTask(c context.Context, projectID, title, description string, dueDate time.Time, status domain.TaskStatus) (*domain.Task, error) {
+	project, err := uc.getProject(c, projectID)
+	if err != nil {
+		return nil, err
+	}
+	role, err := callerProjectRole(c, project)
+	if err != nil {
+		return nil, err
+	}
+	if !role.CanEditTasks() {
+		return nil, fmt.Errorf("%w: project role %q cannot create tasks", domain.ErrForbidden, role)
+	}
+
+	newTask, err := domain.NewTask(title, description, dueDate, status, uc.now(c))
+	if err != nil {
+		return nil, err
+	}
+	newTask.OwnerID = actorUserID(c)
+	newTask.ProjectID = project.Id
+	if err := uc.addInitialWatchers(c, newTask); err != nil {
+		return nil, err
+	}
+
+	return uc.createTask(c, newTask)
+}
+
 // GetTaskByID handles fetching a single task by its ID.
 func (uc *TaskUseCase) GetTaskByID(c context.Context, taskID string) (*domain.Task, error) {
 	objectID, err := primitive.ObjectIDFromHex(taskID)
@@ -49,25 +171,112 @@ func (uc *TaskUseCase) GetTaskByID(c context.Context, taskID string) (*domain.Ta
 		}
 		return nil, fmt.Errorf("usecase: failed to get task by ID: %w", err) // Unexpected repo error
 	}
+	if err := uc.authorizeTask(c, task, false); err != nil {
+		return nil, err
+	}
 	return task, nil
 }
 
-// GetAllTasks handles fetching all tasks.
+// GetAllTasks handles fetching all tasks visible to the caller: tasks that belong to no
+// project plus tasks of the projects the caller is a member of.
 func (uc *TaskUseCase) GetAllTasks(c context.Context) ([]*domain.Task, error) {
-	tasks, err := uc.taskRepo.GetAllTasks(c)
+	filter, err := uc.visibilityFilter(c)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := uc.taskRepo.GetAllTasks(c, filter)
 	if err != nil {
 		return nil, fmt.Errorf("usecase: failed to get all tasks: %w", err)
 	}
 	return tasks, nil
 }
 
-// It takes optional fields using pointers, allowing partial updates.
+// GetProjectTasks lists the tasks of a single project. Any member may list them.
+func (uc *TaskUseCase) GetProjectTasks(c context.Context, projectID string) ([]*domain.Task, error) {
+	project, err := uc.getProject(c, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := callerProjectRole(c, project); err != nil {
+		return nil, err
+	}
+	tasks, err := uc.taskRepo.GetAllTasks(c, domain.TaskFilter{ProjectID: project.Id})
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to get project tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// UpdateTask takes optional fields using pointers, allowing partial updates. A nil
+// pointer leaves the field unchanged, so fields cannot be cleared; use PatchTask for that.
 func (uc *TaskUseCase) UpdateTask(c context.Context, taskID string, title, description *string, dueDate *time.Time, status *domain.TaskStatus) (*domain.Task, error) {
+	var patch domain.TaskPatch
+	if title != nil {
+		patch.Title = domain.Present(*title)
+	}
+	if description != nil {
+		patch.Description = domain.Present(*description)
+	}
+	if dueDate != nil {
+		patch.DueDate = domain.Present(domain.DueDate{Time: *dueDate})
+	}
+	if status != nil {
+		patch.Status = domain.Present(*status)
+	}
+	return uc.PatchTask(c, taskID, patch)
+}
+
+// ReplaceTask replaces every editable field of a task, as PUT does. The description
+// and tags are cleared when they are empty.
+func (uc *TaskUseCase) ReplaceTask(c context.Context, taskID string, title, description string, dueDate domain.DueDate, status domain.TaskStatus, tags []string) (*domain.Task, error) {
+	return uc.PatchTask(c, taskID, domain.TaskPatch{
+		Title:       domain.Present(title),
+		Description: domain.Present(description),
+		DueDate:     domain.Present(dueDate),
+		Status:      domain.Present(status),
+		Tags:        domain.Present(tags),
+	})
+}
+
+// JSONPatchTask applies a JSON Patch (RFC 6902) to the editable fields of a task. The
+// operations, including "test", are evaluated against the version of the task that the
+// result overwrites.
+func (uc *TaskUseCase) JSONPatchTask(c context.Context, taskID string, operations []domain.PatchOperation) (*domain.Task, error) {
+	return uc.patchTask(c, taskID, func(task *domain.Task) (domain.TaskPatch, error) {
+		return task.JSONPatch(operations)
+	})
+}
+
+// PatchTask changes the fields that are set in patch and clears those set to null.
+// Only the description and tags can be cleared.
+func (uc *TaskUseCase) PatchTask(c context.Context, taskID string, patch domain.TaskPatch) (*domain.Task, error) {
+	return uc.patchTask(c, taskID, func(*domain.Task) (domain.TaskPatch, error) {
+		return patch, nil
+	})
+}
+
+// patchTask loads a task, builds the patch from it and writes the result in one unit of
+// work. In a transaction, a concurrent write to the task aborts the unit of work, which
+// is then retried with the new version, so build never sees a stale task.
+func (uc *TaskUseCase) patchTask(c context.Context, taskID string, build func(task *domain.Task) (domain.TaskPatch, error)) (*domain.Task, error) {
 	objectID, err := primitive.ObjectIDFromHex(taskID)
 	if err != nil {
 		return nil, fmt.Errorf("%w: invalid task ID format", domain.ErrValidationFailed)
 	}
 
+	var updatedTaskResult *domain.Task
+	err = runUnitOfWork(c, uc.transactor, func(c context.Context) error {
+		var err error
+		updatedTaskResult, err = uc.applyPatch(c, objectID, build)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updatedTaskResult, nil
+}
+
+func (uc *TaskUseCase) applyPatch(c context.Context, objectID primitive.ObjectID, build func(task *domain.Task) (domain.TaskPatch, error)) (*domain.Task, error) {
 	// 1. Fetch existing task to ensure it exists
 	existingTask, err := uc.taskRepo.GetTaskById(c, objectID)
 	if err != nil {
@@ -76,56 +285,310 @@ func (uc *TaskUseCase) UpdateTask(c context.Context, taskID string, title, descr
 		}
 		return nil, fmt.Errorf("usecase: failed to retrieve existing task for update: %w", err)
 	}
+	if err := uc.authorizeTask(c, existingTask, true); err != nil {
+		return nil, err
+	}
 
-	// 2. Apply updates to the existing domain entity based on provided non-nil pointers
-	if title != nil {
-		if *title == "" { // title cannot be empty on update
-			return nil, fmt.Errorf("%w: task title cannot be empty on update", domain.ErrValidationFailed)
+	previous := *existingTask
+	patch, err := build(&previous)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Validate every provided field first, so that all violations are reported together
+	now := uc.now(c)
+	location, _ := domain.LocationFromContext(c)
+	dueDate := patch.DueDate.Value.In(location)
+	violations := &domain.ValidationError{}
+	if patch.Title.Set && (patch.Title.Null || patch.Title.Value == "") {
+		violations.Add("title", domain.RuleRequired, "task title cannot be empty on update")
+	}
+	if patch.DueDate.Set {
+		if patch.DueDate.Null || dueDate.IsZero() {
+			violations.Add("duedate", domain.RuleRequired, "task due date cannot be empty")
+		} else if !dueDate.Equal(existingTask.DueDate) && dueDate.Before(domain.StartOfDay(now)) {
+			// Keeping an overdue task's due date, as a full replacement does, is allowed.
+			violations.Add("duedate", domain.RuleNotPast, "updated due date cannot be in the past")
 		}
-		existingTask.Title = *title
 	}
-	if description != nil {
-		existingTask.Description = *description
+	if patch.Status.Set {
+		if patch.Status.Null || !patch.Status.Value.IsValid() {
+			violations.Add("status", domain.RuleOneOf, "invalid task status for update")
+		} else if existingTask.Status == domain.Done && patch.Status.Value != domain.Done {
+			// Cannot change status from Done to anything else
+			violations.Add("status", domain.RuleImmutable, "cannot change status of a completed task")
+		}
 	}
-	if dueDate != nil {
-		if dueDate.Before(time.Now().Truncate(24 * time.Hour)) { // updated due date not in past
-			return nil, fmt.Errorf("%w: updated due date cannot be in the past", domain.ErrValidationFailed)
+	var tags []string
+	if patch.Tags.Set && !patch.Tags.Null {
+		tags, err = domain.NormalizeTags(patch.Tags.Value)
+		var tagViolations *domain.ValidationError
+		if errors.As(err, &tagViolations) {
+			violations.Violations = append(violations.Violations, tagViolations.Violations...)
 		}
-		existingTask.DueDate = *dueDate
 	}
-	if status != nil {
-		if !status.IsValid() {
-			return nil, fmt.Errorf("%w: invalid task status for update", domain.ErrValidationFailed)
+	if err := violations.Err(); err != nil {
+		return nil, err
+	}
+
+	// 3. Apply the changes to the existing domain entity
+	if patch.Title.Set {
+		existingTask.Title = patch.Title.Value
+	}
+	if patch.Description.Set {
+		// Value is empty for null.
+		existingTask.Description = patch.Description.Value
+	}
+	if patch.DueDate.Set {
+		existingTask.DueDate = dueDate
+	}
+	if patch.Tags.Set {
+		// tags is nil for null.
+		existingTask.Tags = tags
+	}
+	if patch.Status.Set {
+		status := patch.Status.Value
+		// A task cannot be started or finished while its blockers are unfinished
+		if status != domain.Pending && status != existingTask.Status {
+			if err := uc.ensureUnblocked(c, existingTask); err != nil {
+				return nil, err
+			}
 		}
-		// Cannot change status from Done to anything else
-		if existingTask.Status == domain.Done && *status != domain.Done {
-			return nil, fmt.Errorf("%w: cannot change status of a completed task", domain.ErrValidationFailed)
+		existingTask.SetStatus(status, now)
+	}
+
+	// Users mentioned for the first time start watching the task.
+	var mentioned []string
+	if patch.Description.Set {
+		mentioned, err = uc.mentionedUsers(c, existingTask, newMentions(previous.Description, existingTask.Description))
+		if err != nil {
+			return nil, err
 		}
-		existingTask.Status = *status
 	}
 
-	// 3. Persist the updated task
+	// 4. Persist the updated task
 	updatedTaskResult, err := uc.taskRepo.UpdateTask(c, objectID, existingTask)
 	if err != nil {
 		return nil, fmt.Errorf("usecase: failed to update task: %w", err)
 	}
-
+	for _, userID := range mentioned {
+		if err := uc.taskRepo.AddWatcher(c, objectID, userID); err != nil {
+			return nil, fmt.Errorf("usecase: failed to add mentioned watcher: %w", err)
+		}
+	}
+	updatedTaskResult.Watch(mentioned...)
+	if err := uc.publish(c, domain.TaskUpdated, updatedTaskResult, &previous); err != nil {
+		return nil, err
+	}
 	return updatedTaskResult, nil
 }
 
-// DeleteTask handles deleting a task by its ID.
+// DeleteTask deletes the task, unlinks it from the tasks it blocks and runs the delete
+// hooks, all in one unit of work.
 func (uc *TaskUseCase) DeleteTask(c context.Context, taskID string) error {
 	objectID, err := primitive.ObjectIDFromHex(taskID)
 	if err != nil {
 		return fmt.Errorf("%w: invalid task ID format", domain.ErrValidationFailed)
 	}
 
-	err = uc.taskRepo.DeleteTask(c, objectID)
+	return runUnitOfWork(c, uc.transactor, func(c context.Context) error {
+		// The task is loaded first so that project membership can be checked.
+		task, err := uc.taskRepo.GetTaskById(c, objectID)
+		if err != nil {
+			if errors.Is(err, domain.ErrTaskNotFound) {
+				return domain.ErrTaskNotFound
+			}
+			return fmt.Errorf("usecase: failed to retrieve task for deletion: %w", err)
+		}
+		if err := uc.authorizeTask(c, task, true); err != nil {
+			return err
+		}
+
+		err = uc.taskRepo.DeleteTask(c, objectID)
+		if err != nil {
+			if errors.Is(err, domain.ErrTaskNotFound) {
+				return domain.ErrTaskNotFound // Propagate task not found
+			}
+			return fmt.Errorf("usecase: failed to delete task: %w", err)
+		}
+		if err := uc.taskRepo.RemoveBlockerFromAll(c, objectID); err != nil {
+			return fmt.Errorf("usecase: failed to unlink deleted task: %w", err)
+		}
+		for _, hook := range uc.deleteHooks {
+			if err := hook(c, task); err != nil {
+				return err
+			}
+		}
+		return uc.publish(c, domain.TaskDeleted, task, nil)
+	})
+}
+
+// SetTags replaces the tags of a task.
+func (uc *TaskUseCase) SetTags(c context.Context, taskID string, tags []string) (*domain.Task, error) {
+	normalized, err := domain.NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	task, err := uc.GetTaskForEdit(c, taskID)
+	if err != nil {
+		return nil, err
+	}
+	previous := *task
+	task.Tags = normalized
+	var updatedTask *domain.Task
+	err = runUnitOfWork(c, uc.transactor, func(c context.Context) error {
+		updatedTask, err = uc.taskRepo.UpdateTask(c, task.Id, task)
+		if err != nil {
+			if errors.Is(err, domain.ErrTaskNotFound) {
+				return domain.ErrTaskNotFound
+			}
+			return fmt.Errorf("usecase: failed to update task tags: %w", err)
+		}
+		return uc.publish(c, domain.TaskUpdated, updatedTask, &previous)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updatedTask, nil
+}
+
+// GetTaskForEdit fetches a task the caller is allowed to modify. Other use cases that
+// change data hanging off a task use it for authorization.
+func (uc *TaskUseCase) GetTaskForEdit(c context.Context, taskID string) (*domain.Task, error) {
+	task, err := uc.GetTaskByID(c, taskID)
 	if err != nil {
-		if errors.Is(err, domain.ErrTaskNotFound) {
-			return domain.ErrTaskNotFound // Propagate task not found
+		return nil, err
+	}
+	if err := uc.authorizeTask(c, task, true); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// EnsureTaskInProject checks that the task belongs to the given project, so that
+// project-scoped routes cannot be used to reach tasks of other projects.
+func (uc *TaskUseCase) EnsureTaskInProject(c context.Context, projectID, taskID string) error {
+	task, err := uc.GetTaskByID(c, taskID)
+	if err != nil {
+		return err
+	}
+	if task.ProjectID.Hex() != projectID {
+		return domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+// authorizeTask checks the caller's project membership for tasks that belong to a
+// project. Tasks without a project are governed by route permissions alone.
+// Non-members get ErrTaskNotFound so that project contents are not revealed.
+func (uc *TaskUseCase) authorizeTask(c context.Context, task *domain.Task, edit bool) error {
+	if task.ProjectID.IsZero() {
+		return nil
+	}
+	role, err := uc.projectRole(c, task.ProjectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrProjectNotFound) {
+			return domain.ErrTaskNotFound
 		}
-		return fmt.Errorf("usecase: failed to delete task: %w", err)
+		return err
+	}
+	if edit && !role.CanEditTasks() {
+		return fmt.Errorf("%w: project role %q cannot modify tasks", domain.ErrForbidden, role)
 	}
 	return nil
 }
+
+// projectRole resolves the caller's role in a project. Privileged callers are
+// resolved without loading the project; see callerProjectRole.
+func (uc *TaskUseCase) projectRole(c context.Context, projectID primitive.ObjectID) (domain.ProjectRole, error) {
+	actor, ok := domain.ActorFromContext(c)
+	if !ok {
+		return "", errNoActor
+	}
+	if actor.Has(domain.PermProjectsManage) {
+		return domain.ProjectOwner, nil
+	}
+	project, err := uc.projectRepo.GetProjectById(c, projectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrProjectNotFound) {
+			return "", domain.ErrProjectNotFound
+		}
+		return "", fmt.Errorf("usecase: failed to get project: %w", err)
+	}
+	return callerProjectRole(c, project)
+}
+
+func (uc *TaskUseCase) getProject(c context.Context, projectID string) (*domain.Project, error) {
+	objectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid project ID format", domain.ErrValidationFailed)
+	}
+	project, err := uc.projectRepo.GetProjectById(c, objectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrProjectNotFound) {
+			return nil, domain.ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("usecase: failed to get project: %w", err)
+	}
+	return project, nil
+}
+
+// TaskQuery selects tasks for bulk updates and exports. The zero value matches every
+// task the caller can see.
+type TaskQuery struct {
+	Statuses  []domain.TaskStatus
+	ProjectID string
+	DueBefore *time.Time
+	DueAfter  *time.Time
+	// AfterID, if set, skips the tasks up to and including the one with that ID.
+	AfterID primitive.ObjectID
+}
+
+// queryFilter turns a TaskQuery into a repository filter limited to what the caller can see.
+func (uc *TaskUseCase) queryFilter(c context.Context, query TaskQuery) (domain.TaskFilter, error) {
+	filter, err := uc.visibilityFilter(c)
+	if err != nil {
+		return domain.TaskFilter{}, err
+	}
+	filter.Statuses = query.Statuses
+	filter.DueBefore = query.DueBefore
+	filter.DueAfter = query.DueAfter
+	filter.AfterID = query.AfterID
+	if query.ProjectID != "" {
+		filter.ProjectID, err = primitive.ObjectIDFromHex(query.ProjectID)
+		if err != nil {
+			return domain.TaskFilter{}, fmt.Errorf("%w: invalid project ID format", domain.ErrValidationFailed)
+		}
+	}
+	return filter, nil
+}
+
+// errNoActor is returned by checks that need to know the caller when there is none.
+var errNoActor = fmt.Errorf("%w: no authenticated caller", domain.ErrForbidden)
+
+// actorUserID returns the ID of the calling user, or "" for internal calls.
+func actorUserID(c context.Context) string {
+	if actor, ok := domain.ActorFromContext(c); ok {
+		return actor.UserID
+	}
+	return ""
+}
+
+func (uc *TaskUseCase) visibilityFilter(c context.Context) (domain.TaskFilter, error) {
+	actor, ok := domain.ActorFromContext(c)
+	if !ok {
+		return domain.TaskFilter{}, errNoActor
+	}
+	if actor.Has(domain.PermProjectsManage) {
+		return domain.TaskFilter{}, nil
+	}
+	projects, err := uc.projectRepo.GetProjectsForMember(c, actor.UserID)
+	if err != nil {
+		return domain.TaskFilter{}, fmt.Errorf("usecase: failed to get projects for member: %w", err)
+	}
+	filter := domain.TaskFilter{Restricted: true}
+	for _, project := range projects {
+		filter.VisibleProjects = append(filter.VisibleProjects, project.Id)
+	}
+	return filter, nil
+}
@@ -4,6 +4,7 @@ import (
 	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
 	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
@@ -16,9 +17,19 @@ import (
 type MockTaskRepository struct {
 	CreateTaskFunc  func(c context.Context, task *domain.Task) (*domain.Task, error)
 	GetTaskByIdFunc func(c context.Context, id primitive.ObjectID) (*domain.Task, error)
-	GetAllTasksFunc func(c context.Context) ([]*domain.Task, error)
+	GetAllTasksFunc func(c context.Context, filter domain.TaskFilter) ([]*domain.Task, error)
 	UpdateTaskFunc  func(c context.Context, id primitive.ObjectID, task *domain.Task) (*domain.Task, error)
 	DeleteTaskFunc  func(c context.Context, id primitive.ObjectID) error
+
+	GetTaskByExternalIDFunc func(c context.Context, externalID string) (*domain.Task, error)
+
+	AddBlockerFunc           func(c context.Context, taskID, blockerID primitive.ObjectID) error
+	RemoveBlockerFunc        func(c context.Context, taskID, blockerID primitive.ObjectID) error
+	RemoveBlockerFromAllFunc func(c context.Context, blockerID primitive.ObjectID) error
+
+	AddWatcherFunc           func(c context.Context, taskID primitive.ObjectID, userID string) error
+	RemoveWatcherFunc        func(c context.Context, taskID primitive.ObjectID, userID string) error
+	RemoveWatcherFromAllFunc func(c context.Context, userID string) error
 }
 
 func (m *MockTaskRepository) CreateTask(c context.Context, task *domain.Task) (*domain.Task, error) {
@@ -33,12 +44,41 @@ func (m *MockTaskRepository) GetTaskById(c context.Context, id primitive.ObjectI
 	}
 	return nil, errors.New("GetTaskByIdFunc not implemented")
 }
-func (m *MockTaskRepository) GetAllTasks(c context.Context) ([]*domain.Task, error) {
+func (m *MockTaskRepository) GetAllTasks(c context.Context, filter domain.TaskFilter) ([]*domain.Task, error) {
 	if m.GetAllTasksFunc != nil {
-		return m.GetAllTasksFunc(c)
+		return m.GetAllTasksFunc(c, filter)
 	}
 	return nil, errors.New("GetAllTasksFunc not implemented")
 }
+
+// StreamTasks iterates over the result of GetAllTasks.
+func (m *MockTaskRepository) StreamTasks(c context.Context, filter domain.TaskFilter, fn func(task *domain.Task) error) error {
+	tasks, err := m.GetAllTasks(c, filter)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTaskStats computes the statistics over the result of GetAllTasks.
+func (m *MockTaskRepository) GetTaskStats(c context.Context, filter domain.TaskFilter, params domain.TaskStatsParams) (*domain.TaskStats, error) {
+	tasks, err := m.GetAllTasks(c, filter)
+	if err != nil {
+		return nil, err
+	}
+	return domain.ComputeTaskStats(tasks, params), nil
+}
+func (m *MockTaskRepository) GetTaskByExternalID(c context.Context, externalID string) (*domain.Task, error) {
+	if m.GetTaskByExternalIDFunc != nil {
+		return m.GetTaskByExternalIDFunc(c, externalID)
+	}
+	return nil, domain.ErrTaskNotFound
+}
 func (m *MockTaskRepository) UpdateTask(c context.Context, id primitive.ObjectID, task *domain.Task) (*domain.Task, error) {
 	if m.UpdateTaskFunc != nil {
 		return m.UpdateTaskFunc(c, id, task)
@@ -51,6 +91,42 @@ func (m *MockTaskRepository) DeleteTask(c context.Context, id primitive.ObjectID
 	}
 	return errors.New("DeleteTaskFunc not implemented")
 }
+func (m *MockTaskRepository) AddBlocker(c context.Context, taskID, blockerID primitive.ObjectID) error {
+	if m.AddBlockerFunc != nil {
+		return m.AddBlockerFunc(c, taskID, blockerID)
+	}
+	return errors.New("AddBlockerFunc not implemented")
+}
+func (m *MockTaskRepository) RemoveBlocker(c context.Context, taskID, blockerID primitive.ObjectID) error {
+	if m.RemoveBlockerFunc != nil {
+		return m.RemoveBlockerFunc(c, taskID, blockerID)
+	}
+	return errors.New("RemoveBlockerFunc not implemented")
+}
+func (m *MockTaskRepository) RemoveBlockerFromAll(c context.Context, blockerID primitive.ObjectID) error {
+	if m.RemoveBlockerFromAllFunc != nil {
+		return m.RemoveBlockerFromAllFunc(c, blockerID)
+	}
+	return errors.New("RemoveBlockerFromAllFunc not implemented")
+}
+func (m *MockTaskRepository) AddWatcher(c context.Context, taskID primitive.ObjectID, userID string) error {
+	if m.AddWatcherFunc != nil {
+		return m.AddWatcherFunc(c, taskID, userID)
+	}
+	return errors.New("AddWatcherFunc not implemented")
+}
+func (m *MockTaskRepository) RemoveWatcher(c context.Context, taskID primitive.ObjectID, userID string) error {
+	if m.RemoveWatcherFunc != nil {
+		return m.RemoveWatcherFunc(c, taskID, userID)
+	}
+	return errors.New("RemoveWatcherFunc not implemented")
+}
+func (m *MockTaskRepository) RemoveWatcherFromAll(c context.Context, userID string) error {
+	if m.RemoveWatcherFromAllFunc != nil {
+		return m.RemoveWatcherFromAllFunc(c, userID)
+	}
+	return errors.New("RemoveWatcherFromAllFunc not implemented")
+}
 
 //===========================================================================
 // TaskUseCase Test Suite
@@ -58,9 +134,10 @@ func (m *MockTaskRepository) DeleteTask(c context.Context, id primitive.ObjectID
 
 type TaskUseCaseSuite struct {
 	suite.Suite
-	mockRepo *MockTaskRepository
-	useCase  *usecases.TaskUseCase
-	ctx      context.Context
+	mockRepo        *MockTaskRepository
+	mockProjectRepo *MockProjectRepository
+	useCase         *usecases.TaskUseCase
+	ctx             context.Context
 }
 
 // TestTaskUseCaseSuite is the entry point for the test suite
@@ -70,10 +147,33 @@ func TestTaskUseCaseSuite(t *testing.T) {
 
 // SetupTest runs before each test method in the suite.
 // It's the perfect place to initialize mocks and the system under test.
+// fixedClock is a domain.Clock that always tells the same time.
+type fixedClock time.Time
+
+func (f fixedClock) Now() time.Time {
+	return time.Time(f)
+}
+
+// errWriteConflict stands for a write that lost a race with a concurrent transaction.
+var errWriteConflict = errors.New("write conflict")
+
+// retryingTransactor reruns fn when it fails with errWriteConflict, as a MongoDB
+// transaction is retried after a write conflict.
+type retryingTransactor struct{}
+
+func (retryingTransactor) WithinTransaction(c context.Context, fn func(c context.Context) error) error {
+	for {
+		if err := fn(c); !errors.Is(err, errWriteConflict) {
+			return err
+		}
+	}
+}
+
 func (s *TaskUseCaseSuite) SetupTest() {
 	s.mockRepo = &MockTaskRepository{}
-	s.useCase = usecases.NewTaskUseCase(s.mockRepo)
-	s.ctx = context.Background() // A basic context is fine for these tests
+	s.mockProjectRepo = NewMockProjectRepository()
+	s.useCase = usecases.NewTaskUseCase(s.mockRepo, s.mockProjectRepo)
+	s.ctx = domain.ContextWithActor(context.Background(), domain.SystemActor)
 }
 
 // --- Test Methods for TaskUseCase ---
@@ -161,7 +261,8 @@ func (s *TaskUseCaseSuite) TestGetAllTasks() {
 			{Id: primitive.NewObjectID(), Title: "Task 2"},
 		}
 
-		s.mockRepo.GetAllTasksFunc = func(c context.Context) ([]*domain.Task, error) {
+		s.mockRepo.GetAllTasksFunc = func(c context.Context, filter domain.TaskFilter) ([]*domain.Task, error) {
+			s.False(filter.Restricted, "The system actor should not be restricted")
 			return expectedTasks, nil
 		}
 
@@ -171,6 +272,90 @@ func (s *TaskUseCaseSuite) TestGetAllTasks() {
 		s.Len(tasks, 2)
 		s.Equal(expectedTasks, tasks)
 	})
+
+	s.Run("Restricted To Member Projects", func() {
+		s.SetupTest()
+		project, _ := domain.NewProject("Website", "", "member", time.Now())
+		s.mockProjectRepo.CreateProject(s.ctx, project)
+		s.mockProjectRepo.CreateProject(s.ctx, &domain.Project{Name: "Other", Members: []domain.ProjectMember{{UserID: "someone", Role: domain.ProjectOwner}}})
+
+		s.mockRepo.GetAllTasksFunc = func(c context.Context, filter domain.TaskFilter) ([]*domain.Task, error) {
+			s.True(filter.Restricted)
+			s.Equal([]primitive.ObjectID{project.Id}, filter.VisibleProjects)
+			return []*domain.Task{}, nil
+		}
+
+		_, err := s.useCase.GetAllTasks(actorContext("member"))
+		s.Require().NoError(err)
+	})
+
+	s.Run("Failure - No Actor", func() {
+		s.SetupTest()
+		_, err := s.useCase.GetAllTasks(context.Background())
+		s.ErrorIs(err, domain.ErrForbidden, "Calls without an actor are not trusted")
+	})
+}
+
+func (s *TaskUseCaseSuite) TestProjectTasks() {
+	setup := func() *domain.Project {
+		s.SetupTest()
+		project, _ := domain.NewProject("Website", "", "owner", time.Now())
+		s.Require().NoError(project.SetMember("viewer", domain.ProjectViewer))
+		s.mockProjectRepo.CreateProject(s.ctx, project)
+		s.mockRepo.CreateTaskFunc = func(c context.Context, task *domain.Task) (*domain.Task, error) {
+			task.Id = primitive.NewObjectID()
+			return task, nil
+		}
+		return project
+	}
+	dueDate := time.Now().Add(24 * time.Hour)
+
+	s.Run("Owner Creates Task", func() {
+		project := setup()
+		task, err := s.useCase.CreateProjectTask(actorContext("owner"), project.Id.Hex(), "Task", "", dueDate, domain.Pending)
+		s.Require().NoError(err)
+		s.Equal(project.Id, task.ProjectID)
+	})
+
+	s.Run("Viewer Cannot Create Task", func() {
+		project := setup()
+		_, err := s.useCase.CreateProjectTask(actorContext("viewer"), project.Id.Hex(), "Task", "", dueDate, domain.Pending)
+		s.ErrorIs(err, domain.ErrForbidden)
+	})
+
+	s.Run("Non Member Cannot See Project Tasks", func() {
+		project := setup()
+		_, err := s.useCase.GetProjectTasks(actorContext("stranger"), project.Id.Hex())
+		s.ErrorIs(err, domain.ErrProjectNotFound)
+
+		s.mockRepo.GetTaskByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.Task, error) {
+			return &domain.Task{Id: id, ProjectID: project.Id}, nil
+		}
+		_, err = s.useCase.GetTaskByID(actorContext("stranger"), primitive.NewObjectID().Hex())
+		s.ErrorIs(err, domain.ErrTaskNotFound)
+	})
+
+	s.Run("Viewer Cannot Update Or Delete", func() {
+		project := setup()
+		s.mockRepo.GetTaskByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.Task, error) {
+			return &domain.Task{Id: id, ProjectID: project.Id, Status: domain.Pending}, nil
+		}
+		title := "New"
+		_, err := s.useCase.UpdateTask(actorContext("viewer"), primitive.NewObjectID().Hex(), &title, nil, nil, nil)
+		s.ErrorIs(err, domain.ErrForbidden)
+
+		err = s.useCase.DeleteTask(actorContext("viewer"), primitive.NewObjectID().Hex())
+		s.ErrorIs(err, domain.ErrForbidden)
+	})
+
+	s.Run("Task Outside Project", func() {
+		project := setup()
+		s.mockRepo.GetTaskByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.Task, error) {
+			return &domain.Task{Id: id, ProjectID: primitive.NewObjectID()}, nil
+		}
+		err := s.useCase.EnsureTaskInProject(s.ctx, project.Id.Hex(), primitive.NewObjectID().Hex())
+		s.ErrorIs(err, domain.ErrTaskNotFound)
+	})
 }
 
 func (s *TaskUseCaseSuite) TestUpdateTask() {
@@ -215,16 +400,202 @@ func (s *TaskUseCaseSuite) TestUpdateTask() {
 		s.Require().Error(err)
 		s.ErrorIs(err, domain.ErrValidationFailed)
 	})
+
+	s.Run("Validation Failed - Every Field Is Reported", func() {
+		s.SetupTest()
+		taskID := primitive.NewObjectID()
+		s.mockRepo.GetTaskByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.Task, error) {
+			return &domain.Task{Id: taskID, Title: "Title", Status: domain.Pending}, nil
+		}
+		s.mockRepo.UpdateTaskFunc = func(c context.Context, id primitive.ObjectID, task *domain.Task) (*domain.Task, error) {
+			s.Fail("an invalid update must not be saved")
+			return task, nil
+		}
+		emptyTitle := ""
+		pastDate := time.Now().Add(-72 * time.Hour)
+		badStatus := domain.TaskStatus("Someday")
+
+		_, err := s.useCase.UpdateTask(s.ctx, taskID.Hex(), &emptyTitle, nil, &pastDate, &badStatus)
+
+		var validationErr *domain.ValidationError
+		s.Require().ErrorAs(err, &validationErr)
+		s.Len(validationErr.Violations, 3)
+		s.Equal("title", validationErr.Violations[0].Field)
+		s.Equal("duedate", validationErr.Violations[1].Field)
+		s.Equal(domain.RuleOneOf, validationErr.Violations[2].Rule)
+	})
+}
+
+func (s *TaskUseCaseSuite) TestPatchTask() {
+	dueDate := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	setup := func() (primitive.ObjectID, *[]*domain.Task) {
+		s.SetupTest()
+		taskID := primitive.NewObjectID()
+		s.mockRepo.GetTaskByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.Task, error) {
+			return &domain.Task{
+				Id: taskID, Title: "Title", Description: "Notes", DueDate: dueDate,
+				Status: domain.Pending, Tags: []string{"backend"},
+			}, nil
+		}
+		saved := &[]*domain.Task{}
+		s.mockRepo.UpdateTaskFunc = func(c context.Context, id primitive.ObjectID, task *domain.Task) (*domain.Task, error) {
+			*saved = append(*saved, task)
+			return task, nil
+		}
+		return taskID, saved
+	}
+
+	s.Run("Absent, Null And Set Fields", func() {
+		taskID, _ := setup()
+		task, err := s.useCase.PatchTask(s.ctx, taskID.Hex(), domain.TaskPatch{
+			Title:       domain.Present("New Title"),
+			Description: domain.PatchField[string]{Set: true, Null: true},
+		})
+		s.Require().NoError(err)
+		s.Equal("New Title", task.Title)
+		s.Empty(task.Description, "null clears the description")
+		s.Equal([]string{"backend"}, task.Tags, "absent fields are unchanged")
+		s.Equal(dueDate, task.DueDate)
+	})
+
+	s.Run("Required Fields Cannot Be Cleared", func() {
+		taskID, saved := setup()
+		_, err := s.useCase.PatchTask(s.ctx, taskID.Hex(), domain.TaskPatch{
+			Title:   domain.PatchField[string]{Set: true, Null: true},
+			DueDate: domain.PatchField[domain.DueDate]{Set: true, Null: true},
+			Status:  domain.PatchField[domain.TaskStatus]{Set: true, Null: true},
+		})
+		var validationErr *domain.ValidationError
+		s.Require().ErrorAs(err, &validationErr)
+		s.Len(validationErr.Violations, 3)
+		s.Empty(*saved)
+	})
+
+	s.Run("Replace Clears Omitted Optional Fields", func() {
+		taskID, _ := setup()
+		task, err := s.useCase.ReplaceTask(s.ctx, taskID.Hex(), "Replaced", "", domain.DueDate{Time: dueDate}, domain.InProgress, nil)
+		s.Require().NoError(err)
+		s.Equal("Replaced", task.Title)
+		s.Empty(task.Description)
+		s.Empty(task.Tags)
+		s.Equal(domain.InProgress, task.Status)
+	})
+
+	s.Run("Replace Keeps An Overdue Due Date", func() {
+		s.SetupTest()
+		taskID := primitive.NewObjectID()
+		overdue := time.Now().Add(-72 * time.Hour).UTC().Truncate(time.Second)
+		s.mockRepo.GetTaskByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.Task, error) {
+			return &domain.Task{Id: taskID, Title: "Late", DueDate: overdue, Status: domain.Pending}, nil
+		}
+		s.mockRepo.UpdateTaskFunc = func(c context.Context, id primitive.ObjectID, task *domain.Task) (*domain.Task, error) {
+			return task, nil
+		}
+		_, err := s.useCase.ReplaceTask(s.ctx, taskID.Hex(), "Still late", "", domain.DueDate{Time: overdue}, domain.Pending, nil)
+		s.NoError(err)
+	})
+
+	s.Run("Today In The Caller's Time Zone", func() {
+		taskID, _ := setup()
+		tokyo, err := domain.LoadTimeZone("Asia/Tokyo")
+		s.Require().NoError(err)
+		// 1 AM on March 10 in Tokyo is still March 9 in UTC.
+		s.useCase.SetClock(fixedClock(time.Date(2026, time.March, 9, 16, 0, 0, 0, time.UTC)))
+		today := domain.DueDate{Time: time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC), DateOnly: true}
+		yesterday := domain.DueDate{Time: time.Date(2026, time.March, 9, 0, 0, 0, 0, time.UTC), DateOnly: true}
+
+		ctx := domain.ContextWithLocation(s.ctx, tokyo)
+		task, err := s.useCase.PatchTask(ctx, taskID.Hex(), domain.TaskPatch{DueDate: domain.Present(today)})
+		s.Require().NoError(err)
+		s.Equal("2026-03-10T00:00:00+09:00", task.DueDate.Format(time.RFC3339))
+
+		_, err = s.useCase.PatchTask(ctx, taskID.Hex(), domain.TaskPatch{DueDate: domain.Present(yesterday)})
+		s.ErrorIs(err, domain.ErrValidationFailed)
+		_, err = s.useCase.PatchTask(s.ctx, taskID.Hex(), domain.TaskPatch{DueDate: domain.Present(yesterday)})
+		s.NoError(err, "March 9 is today in UTC")
+	})
+
+	s.Run("JSON Patch", func() {
+		taskID, _ := setup()
+		operations := []domain.PatchOperation{
+			{Op: "test", Path: "/title", Value: json.RawMessage(`"Title"`)},
+			{Op: "add", Path: "/tags/-", Value: json.RawMessage(`"Urgent"`)},
+			{Op: "remove", Path: "/description"},
+			{Op: "copy", From: "/title", Path: "/description"},
+			{Op: "replace", Path: "/title", Value: json.RawMessage(`"Patched"`)},
+		}
+		task, err := s.useCase.JSONPatchTask(s.ctx, taskID.Hex(), operations)
+		s.Require().NoError(err)
+		s.Equal("Patched", task.Title)
+		s.Equal("Title", task.Description)
+		s.Equal([]string{"backend", "urgent"}, task.Tags, "tags added by a patch are normalized")
+	})
+
+	s.Run("JSON Patch Tests The Written Version", func() {
+		taskID, saved := setup()
+		title := "Title"
+		s.mockRepo.GetTaskByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.Task, error) {
+			return &domain.Task{Id: taskID, Title: title, DueDate: dueDate, Status: domain.Pending}, nil
+		}
+		s.mockRepo.UpdateTaskFunc = func(c context.Context, id primitive.ObjectID, task *domain.Task) (*domain.Task, error) {
+			if title == "Title" {
+				// Another client renamed the task after it was read.
+				title = "Renamed"
+				return nil, errWriteConflict
+			}
+			*saved = append(*saved, task)
+			return task, nil
+		}
+		s.useCase.SetTransactor(retryingTransactor{})
+
+		_, err := s.useCase.JSONPatchTask(s.ctx, taskID.Hex(), []domain.PatchOperation{
+			{Op: "test", Path: "/title", Value: json.RawMessage(`"Title"`)},
+			{Op: "replace", Path: "/description", Value: json.RawMessage(`"Checked"`)},
+		})
+		s.ErrorIs(err, domain.ErrPatchTestFailed, "the retry tests the renamed task")
+		s.Empty(*saved)
+	})
+
+	s.Run("JSON Patch Errors", func() {
+		taskID, saved := setup()
+		_, err := s.useCase.JSONPatchTask(s.ctx, taskID.Hex(), []domain.PatchOperation{
+			{Op: "test", Path: "/status", Value: json.RawMessage(`"Done"`)},
+		})
+		s.ErrorIs(err, domain.ErrPatchTestFailed)
+
+		_, err = s.useCase.JSONPatchTask(s.ctx, taskID.Hex(), []domain.PatchOperation{
+			{Op: "replace", Path: "/tags/5", Value: json.RawMessage(`"x"`)},
+		})
+		s.ErrorIs(err, domain.ErrInvalidPatch)
+
+		_, err = s.useCase.JSONPatchTask(s.ctx, taskID.Hex(), []domain.PatchOperation{
+			{Op: "add", Path: "/owner_id", Value: json.RawMessage(`"mallory"`)},
+		})
+		var validationErr *domain.ValidationError
+		s.Require().ErrorAs(err, &validationErr)
+		s.Equal(domain.RuleNotPatchable, validationErr.Violations[0].Rule)
+
+		_, err = s.useCase.JSONPatchTask(s.ctx, taskID.Hex(), []domain.PatchOperation{{Op: "remove", Path: "/title"}})
+		s.ErrorIs(err, domain.ErrValidationFailed, "removing a required field is a validation error")
+		s.Empty(*saved)
+	})
 }
 
 func (s *TaskUseCaseSuite) TestDeleteTask() {
 	s.Run("Success", func() {
 		s.SetupTest()
 		taskID := primitive.NewObjectID()
+		s.mockRepo.GetTaskByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.Task, error) {
+			return &domain.Task{Id: id}, nil
+		}
 		s.mockRepo.DeleteTaskFunc = func(c context.Context, id primitive.ObjectID) error {
 			s.Equal(taskID, id)
 			return nil
 		}
+		s.mockRepo.RemoveBlockerFromAllFunc = func(c context.Context, blockerID primitive.ObjectID) error {
+			s.Equal(taskID, blockerID, "Deleted task should be unlinked from the tasks it blocks")
+			return nil
+		}
 
 		err := s.useCase.DeleteTask(s.ctx, taskID.Hex())
 
@@ -234,8 +605,8 @@ func (s *TaskUseCaseSuite) TestDeleteTask() {
 	s.Run("Not Found", func() {
 		s.SetupTest()
 		taskID := primitive.NewObjectID()
-		s.mockRepo.DeleteTaskFunc = func(c context.Context, id primitive.ObjectID) error {
-			return domain.ErrTaskNotFound
+		s.mockRepo.GetTaskByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.Task, error) {
+			return nil, domain.ErrTaskNotFound
 		}
 
 		err := s.useCase.DeleteTask(s.ctx, taskID.Hex())
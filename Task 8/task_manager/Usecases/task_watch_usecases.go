@@ -0,0 +1,132 @@
+package usecases
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// watchBufferSize is how many events a watcher may have queued before it is dropped
+// with domain.ErrWatchLagged.
+const watchBufferSize = 256
+
+// TaskWatchUseCase streams task changes to long-lived subscribers, such as gRPC
+// clients watching a project.
+type TaskWatchUseCase struct {
+	tasks *TaskUseCase
+
+	mu       sync.Mutex
+	watchers map[*taskWatcher]struct{}
+}
+
+type taskWatcher struct {
+	filter domain.TaskFilter
+	// organizationID is the watcher's organization. Events are published for every
+	// organization, and the filter knows nothing about them.
+	organizationID primitive.ObjectID
+	events         chan domain.TaskEvent
+	// lagged is closed when the watcher's buffer overflowed.
+	lagged chan struct{}
+}
+
+// NewTaskWatchUseCase creates the use case and subscribes it to task events.
+func NewTaskWatchUseCase(tasks *TaskUseCase) *TaskWatchUseCase {
+	uc := &TaskWatchUseCase{
+		tasks:    tasks,
+		watchers: make(map[*taskWatcher]struct{}),
+	}
+	tasks.Subscribe(uc.handleTaskEvent)
+	return uc
+}
+
+// WatchTasks calls emit for every change to a task that matches query and is visible
+// to the caller, until c is done, emit fails or the caller falls behind. An update is
+// delivered if the task matched before or after it, so watchers see tasks leave the
+// selection. Only tasks of the caller's organization are delivered, and project
+// membership is checked again for every event, so a caller who leaves a project stops
+// seeing its tasks and one who joins starts seeing them.
+func (uc *TaskWatchUseCase) WatchTasks(c context.Context, query TaskQuery, emit func(event domain.TaskEvent) error) error {
+	filter, err := uc.tasks.queryFilter(c, query)
+	if err != nil {
+		return err
+	}
+	// The project restriction reflects memberships at the start of the watch; visible
+	// checks them when each event is delivered instead.
+	filter.Restricted, filter.VisibleProjects = false, nil
+	organizationID, ok := domain.OrganizationFromContext(c)
+	if !ok {
+		return fmt.Errorf("%w: watching tasks requires an organization", domain.ErrForbidden)
+	}
+	watcher := &taskWatcher{
+		filter:         filter,
+		organizationID: organizationID,
+		events:         make(chan domain.TaskEvent, watchBufferSize),
+		lagged:         make(chan struct{}),
+	}
+	uc.mu.Lock()
+	uc.watchers[watcher] = struct{}{}
+	uc.mu.Unlock()
+	defer func() {
+		uc.mu.Lock()
+		delete(uc.watchers, watcher)
+		uc.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-c.Done():
+			return c.Err()
+		case <-watcher.lagged:
+			return domain.ErrWatchLagged
+		case event := <-watcher.events:
+			visible, err := uc.visible(c, event)
+			if err != nil {
+				return err
+			}
+			if !visible {
+				continue
+			}
+			if err := emit(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// visible reports whether the caller may currently see the task of the event.
+func (uc *TaskWatchUseCase) visible(c context.Context, event domain.TaskEvent) (bool, error) {
+	err := uc.tasks.authorizeTask(c, event.Task, false)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, domain.ErrTaskNotFound), errors.Is(err, domain.ErrForbidden):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// handleTaskEvent queues the event for every matching watcher. It never blocks the
+// change that caused the event; watchers whose buffer is full are dropped instead.
+func (uc *TaskWatchUseCase) handleTaskEvent(c context.Context, event domain.TaskEvent) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	for watcher := range uc.watchers {
+		if event.Task.OrganizationID != watcher.organizationID {
+			continue
+		}
+		if !watcher.filter.Matches(event.Task) && (event.Previous == nil || !watcher.filter.Matches(event.Previous)) {
+			continue
+		}
+		select {
+		case watcher.events <- event:
+		default:
+			close(watcher.lagged)
+			delete(uc.watchers, watcher)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package usecases_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//===========================================================================
+// TaskWatchUseCase Test Suite
+//===========================================================================
+
+type TaskWatchUseCaseSuite struct {
+	suite.Suite
+	mockRepo *MockTaskRepository
+	projects *MockProjectRepository
+	tasks    *usecases.TaskUseCase
+	useCase  *usecases.TaskWatchUseCase
+	ctx      context.Context
+}
+
+func TestTaskWatchUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(TaskWatchUseCaseSuite))
+}
+
+func (s *TaskWatchUseCaseSuite) SetupTest() {
+	s.mockRepo = &MockTaskRepository{}
+	s.mockRepo.CreateTaskFunc = func(c context.Context, task *domain.Task) (*domain.Task, error) {
+		task.Id = primitive.NewObjectID()
+		task.OrganizationID, _ = domain.OrganizationFromContext(c)
+		return task, nil
+	}
+	s.projects = NewMockProjectRepository()
+	s.tasks = usecases.NewTaskUseCase(s.mockRepo, s.projects)
+	s.useCase = usecases.NewTaskWatchUseCase(s.tasks)
+	s.ctx = domain.ContextWithOrganization(domain.ContextWithActor(context.Background(), domain.SystemActor), primitive.NewObjectID())
+}
+
+// watch starts a watch and waits until it receives events, using tasks titled "probe".
+func (s *TaskWatchUseCaseSuite) watch(ctx context.Context, query usecases.TaskQuery) (<-chan domain.TaskEvent, <-chan error) {
+	events := make(chan domain.TaskEvent, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.useCase.WatchTasks(ctx, query, func(event domain.TaskEvent) error {
+			events <- event
+			return nil
+		})
+	}()
+	s.Require().Eventually(func() bool {
+		s.createTask("probe", domain.Pending)
+		select {
+		case <-events:
+			return true
+		case <-time.After(10 * time.Millisecond):
+			return false
+		}
+	}, time.Second, time.Millisecond)
+	for len(events) > 0 {
+		<-events
+	}
+	return events, done
+}
+
+func (s *TaskWatchUseCaseSuite) createTask(title string, status domain.TaskStatus) {
+	_, err := s.tasks.CreateTask(s.ctx, title, "", time.Now().Add(24*time.Hour), status)
+	s.Require().NoError(err)
+}
+
+func (s *TaskWatchUseCaseSuite) TestWatchTasks() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	events, done := s.watch(ctx, usecases.TaskQuery{Statuses: []domain.TaskStatus{domain.Pending}})
+
+	s.createTask("ignored", domain.Done)
+	s.createTask("matched", domain.Pending)
+	select {
+	case event := <-events:
+		s.Equal(domain.TaskCreated, event.Type)
+		s.Equal("matched", event.Task.Title)
+	case <-time.After(time.Second):
+		s.Fail("no event received")
+	}
+
+	cancel()
+	s.ErrorIs(<-done, context.Canceled)
+
+	err := s.useCase.WatchTasks(context.Background(), usecases.TaskQuery{}, func(event domain.TaskEvent) error { return nil })
+	s.ErrorIs(err, domain.ErrForbidden, "Watches are limited to one organization")
+}
+
+func (s *TaskWatchUseCaseSuite) TestSlowWatcherIsDropped() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	received := make(chan struct{}, 1)
+	go func() {
+		done <- s.useCase.WatchTasks(ctx, usecases.TaskQuery{}, func(event domain.TaskEvent) error {
+			select {
+			case received <- struct{}{}:
+			default:
+			}
+			<-release
+			return nil
+		})
+	}()
+	s.Require().Eventually(func() bool {
+		s.createTask("probe", domain.Pending)
+		return len(received) > 0
+	}, time.Second, time.Millisecond)
+
+	// The watcher is stuck on its first event, so its buffer fills up.
+	for i := 0; i < 300; i++ {
+		s.createTask("flood", domain.Pending)
+	}
+	close(release)
+	s.ErrorIs(<-done, domain.ErrWatchLagged)
+}
+
+func (s *TaskWatchUseCaseSuite) TestProjectMembershipIsCheckedPerEvent() {
+	project, err := s.projects.CreateProject(s.ctx, &domain.Project{Name: "Website", Members: []domain.ProjectMember{{UserID: "owner-1", Role: domain.ProjectOwner}}})
+	s.Require().NoError(err)
+	createProjectTask := func(title string) {
+		_, err := s.tasks.CreateProjectTask(s.ctx, project.Id.Hex(), title, "", time.Now().Add(24*time.Hour), domain.Pending)
+		s.Require().NoError(err)
+	}
+	member := &domain.Actor{UserID: "member-1", Permissions: []domain.Permission{domain.PermTasksRead}}
+	ctx, cancel := context.WithCancel(domain.ContextWithActor(s.ctx, member))
+	defer cancel()
+	events, _ := s.watch(ctx, usecases.TaskQuery{})
+
+	// Visibility is checked when an event is delivered, so each step waits for a task
+	// outside any project to come through before membership changes.
+	createProjectTask("before joining")
+	s.createTask("outside projects", domain.Pending)
+	s.Equal("outside projects", (<-events).Task.Title, "Tasks of a project should not be sent to non-members")
+
+	project.Members = append(project.Members, domain.ProjectMember{UserID: "member-1", Role: domain.ProjectViewer})
+	createProjectTask("member")
+	s.Equal("member", (<-events).Task.Title, "Tasks of a project should be sent once the watcher joins it")
+
+	project.Members = project.Members[:1]
+	createProjectTask("after leaving")
+	s.createTask("outside projects", domain.Pending)
+	s.Equal("outside projects", (<-events).Task.Title, "Tasks of a project should not be sent once the watcher left it")
+}
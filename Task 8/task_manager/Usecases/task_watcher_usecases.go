@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// WatchTask makes the caller a watcher of a task they can see, so that they are
+// notified when it changes.
+func (uc *TaskUseCase) WatchTask(c context.Context, taskID string) (*domain.Task, error) {
+	return uc.setWatching(c, taskID, true)
+}
+
+// UnwatchTask stops the caller watching a task. Owners still hear about their tasks.
+func (uc *TaskUseCase) UnwatchTask(c context.Context, taskID string) (*domain.Task, error) {
+	return uc.setWatching(c, taskID, false)
+}
+
+func (uc *TaskUseCase) setWatching(c context.Context, taskID string, watch bool) (*domain.Task, error) {
+	userID := actorUserID(c)
+	if userID == "" {
+		return nil, fmt.Errorf("%w: only users can watch tasks", domain.ErrForbidden)
+	}
+	task, err := uc.GetTaskByID(c, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if watch {
+		err = uc.taskRepo.AddWatcher(c, task.Id, userID)
+	} else {
+		err = uc.taskRepo.RemoveWatcher(c, task.Id, userID)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			return nil, domain.ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("usecase: failed to change task watchers: %w", err)
+	}
+	return uc.GetTaskByID(c, taskID)
+}
+
+// UnwatchAllTasks is a MemberRemovalHook that stops a removed member watching tasks.
+func (uc *TaskUseCase) UnwatchAllTasks(c context.Context, user *domain.User) error {
+	if err := uc.taskRepo.RemoveWatcherFromAll(c, user.Id.Hex()); err != nil {
+		return fmt.Errorf("usecase: failed to remove watcher: %w", err)
+	}
+	return nil
+}
+
+// addInitialWatchers makes the creator of a new task and the users mentioned in its
+// description watch it.
+func (uc *TaskUseCase) addInitialWatchers(c context.Context, task *domain.Task) error {
+	if task.OwnerID != "" {
+		task.Watch(task.OwnerID)
+	}
+	mentioned, err := uc.mentionedUsers(c, task, domain.Mentions(task.Description))
+	if err != nil {
+		return err
+	}
+	task.Watch(mentioned...)
+	return nil
+}
+
+// mentionedUsers resolves mentioned usernames to user IDs. Unknown usernames are
+// ignored, and so are users outside the task's project, who could not see it.
+func (uc *TaskUseCase) mentionedUsers(c context.Context, task *domain.Task, usernames []string) ([]string, error) {
+	if uc.userRepo == nil || len(usernames) == 0 {
+		return nil, nil
+	}
+	var project *domain.Project
+	if !task.ProjectID.IsZero() {
+		var err error
+		if project, err = uc.getProject(c, task.ProjectID.Hex()); err != nil {
+			return nil, err
+		}
+	}
+
+	userIDs := []string{}
+	for _, username := range usernames {
+		user, err := uc.userRepo.GetUserByUsername(c, username)
+		if err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("usecase: failed to look up mentioned user: %w", err)
+		}
+		userID := user.Id.Hex()
+		if project != nil {
+			if _, ok := project.MemberRole(userID); !ok {
+				continue
+			}
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, nil
+}
+
+// newMentions returns the usernames mentioned in after but not in before, so that
+// editing a description does not make users who stopped watching watch again.
+func newMentions(before, after string) []string {
+	previous := domain.Mentions(before)
+	return slices.DeleteFunc(domain.Mentions(after), func(username string) bool {
+		return slices.Contains(previous, username)
+	})
+}
@@ -0,0 +1,108 @@
+package usecases_test
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//===========================================================================
+// Task Watcher Test Suite
+//===========================================================================
+
+type TaskWatcherSuite struct {
+	suite.Suite
+	alice, bob *domain.User
+	task       *domain.Task
+	projects   *MockProjectRepository
+	useCase    *usecases.TaskUseCase
+	aliceCtx   context.Context
+	bobCtx     context.Context
+}
+
+func TestTaskWatcherSuite(t *testing.T) {
+	suite.Run(t, new(TaskWatcherSuite))
+}
+
+func (s *TaskWatcherSuite) SetupTest() {
+	s.alice = &domain.User{Id: primitive.NewObjectID(), Username: "alice"}
+	s.bob = &domain.User{Id: primitive.NewObjectID(), Username: "bob"}
+	users := map[string]*domain.User{s.alice.Username: s.alice, s.bob.Username: s.bob}
+
+	s.task = &domain.Task{Id: primitive.NewObjectID(), Title: "Write report", Status: domain.Pending, OwnerID: s.alice.Id.Hex(), Watchers: []string{s.alice.Id.Hex()}}
+	taskRepo := newInMemoryTaskRepository(s.task)
+	taskRepo.CreateTaskFunc = func(c context.Context, task *domain.Task) (*domain.Task, error) {
+		task.Id = primitive.NewObjectID()
+		return task, nil
+	}
+	s.projects = NewMockProjectRepository()
+	s.useCase = usecases.NewTaskUseCase(taskRepo, s.projects)
+	s.useCase.SetUserRepository(&MockUserRepository{
+		GetUserByUsernameFunc: func(c context.Context, username string) (*domain.User, error) {
+			user, ok := users[username]
+			if !ok {
+				return nil, domain.ErrUserNotFound
+			}
+			return user, nil
+		},
+	})
+	s.aliceCtx = domain.ContextWithActor(context.Background(), &domain.Actor{UserID: s.alice.Id.Hex()})
+	s.bobCtx = domain.ContextWithActor(context.Background(), &domain.Actor{UserID: s.bob.Id.Hex()})
+}
+
+func (s *TaskWatcherSuite) TestWatchAndUnwatch() {
+	task, err := s.useCase.WatchTask(s.bobCtx, s.task.Id.Hex())
+	s.Require().NoError(err)
+	s.Equal([]string{s.alice.Id.Hex(), s.bob.Id.Hex()}, task.Watchers)
+
+	task, err = s.useCase.WatchTask(s.bobCtx, s.task.Id.Hex())
+	s.Require().NoError(err)
+	s.Len(task.Watchers, 2, "Watching twice is a no-op")
+
+	task, err = s.useCase.UnwatchTask(s.aliceCtx, s.task.Id.Hex())
+	s.Require().NoError(err)
+	s.Equal([]string{s.bob.Id.Hex()}, task.Watchers)
+
+	s.Run("Failure - Unknown Task", func() {
+		_, err := s.useCase.WatchTask(s.bobCtx, primitive.NewObjectID().Hex())
+		s.ErrorIs(err, domain.ErrTaskNotFound)
+	})
+
+	s.Run("Failure - No User", func() {
+		_, err := s.useCase.WatchTask(context.Background(), s.task.Id.Hex())
+		s.ErrorIs(err, domain.ErrForbidden)
+	})
+}
+
+func (s *TaskWatcherSuite) TestCreatorsAndMentionedUsersWatchNewTasks() {
+	task, err := s.useCase.CreateTask(s.aliceCtx, "Review", "@bob please review, and ask @nobody", time.Now().Add(24*time.Hour), domain.Pending)
+	s.Require().NoError(err)
+	s.Equal([]string{s.alice.Id.Hex(), s.bob.Id.Hex()}, task.Watchers, "Unknown usernames are ignored")
+
+	project, err := domain.NewProject("Launch", "", s.alice.Id.Hex(), time.Now())
+	s.Require().NoError(err)
+	project, err = s.projects.CreateProject(context.Background(), project)
+	s.Require().NoError(err)
+	task, err = s.useCase.CreateProjectTask(s.aliceCtx, project.Id.Hex(), "Plan", "@bob should see this", time.Now().Add(24*time.Hour), domain.Pending)
+	s.Require().NoError(err)
+	s.Equal([]string{s.alice.Id.Hex()}, task.Watchers, "Users outside the project are not added")
+}
+
+func (s *TaskWatcherSuite) TestMentionsInUpdatedDescriptions() {
+	description := "Ask @bob"
+	task, err := s.useCase.UpdateTask(s.aliceCtx, s.task.Id.Hex(), nil, &description, nil, nil)
+	s.Require().NoError(err)
+	s.Equal([]string{s.alice.Id.Hex(), s.bob.Id.Hex()}, task.Watchers)
+
+	_, err = s.useCase.UnwatchTask(s.bobCtx, s.task.Id.Hex())
+	s.Require().NoError(err)
+	description = "Ask @bob again"
+	task, err = s.useCase.UpdateTask(s.aliceCtx, s.task.Id.Hex(), nil, &description, nil, nil)
+	s.Require().NoError(err)
+	s.Equal([]string{s.alice.Id.Hex()}, task.Watchers, "Only new mentions add watchers")
+}
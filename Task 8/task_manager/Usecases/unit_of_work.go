@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"context"
+)
+
+// afterCommitKey marks a context inside a unit of work. The value is a
+// *[]func(context.Context) that collects the side effects to run once it commits.
+type afterCommitKey struct{}
+
+// runUnitOfWork runs fn in a transaction of transactor. Side effects that cannot be
+// rolled back, such as task events or deleting blobs, are registered with afterCommit
+// and run only once fn's writes are durable; they are dropped if fn fails. A unit of
+// work started inside another joins it, so use cases can call each other freely.
+func runUnitOfWork(c context.Context, transactor domain.Transactor, fn func(c context.Context) error) error {
+	if _, ok := c.Value(afterCommitKey{}).(*[]func(context.Context)); ok {
+		return fn(c)
+	}
+	var pending []func(context.Context)
+	err := transactor.WithinTransaction(c, func(txCtx context.Context) error {
+		// The transaction may be retried, so start from scratch on every attempt.
+		pending = nil
+		return fn(context.WithValue(txCtx, afterCommitKey{}, &pending))
+	})
+	if err != nil {
+		return err
+	}
+	// The transaction's context is finished; side effects run with the caller's.
+	for _, effect := range pending {
+		effect(c)
+	}
+	return nil
+}
+
+// afterCommit runs effect once the unit of work that c belongs to commits, or right
+// away outside of one.
+func afterCommit(c context.Context, effect func(c context.Context)) {
+	if pending, ok := c.Value(afterCommitKey{}).(*[]func(context.Context)); ok {
+		*pending = append(*pending, effect)
+		return
+	}
+	effect(c)
+}
@@ -3,73 +3,535 @@ package usecases
 import (
 	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
 	"context"
+	"crypto/rand"
+	"encoding/base32"
 	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const recoveryCodeCount = 10
+
+// After maxTwoFactorFailures failed second-factor attempts in a row, a user may only
+// try again once twoFactorLockout has passed since the last failure.
+const (
+	maxTwoFactorFailures = 5
+	twoFactorLockout     = 15 * time.Minute
+)
+
+// Ensure UserUseCase can be used by the authentication middleware
+var _ domain.SessionValidator = (*UserUseCase)(nil)
+
 type UserUseCase struct {
 	userRepo        domain.UserRepository
+	roleRepo        domain.RoleRepository
+	orgRepo         domain.OrganizationRepository
 	jwtService      domain.JwtService
 	passwordService domain.PasswordService
+	totpService     domain.TOTPService
+	secretCipher    domain.SecretCipher
+	transactor      domain.Transactor
+	clock           domain.Clock
+
+	// twoFactorRequiredPermissions lists permissions whose holders may not obtain an
+	// access token without two-factor authentication.
+	twoFactorRequiredPermissions []domain.Permission
 }
 
-func NewUserUseCase(userrepo domain.UserRepository, jwtservice domain.JwtService, passwordservice domain.PasswordService) *UserUseCase {
+func NewUserUseCase(userrepo domain.UserRepository, rolerepo domain.RoleRepository, orgrepo domain.OrganizationRepository, jwtservice domain.JwtService, passwordservice domain.PasswordService, totpservice domain.TOTPService, secretcipher domain.SecretCipher) *UserUseCase {
 	return &UserUseCase{
 		userRepo:        userrepo,
+		roleRepo:        rolerepo,
+		orgRepo:         orgrepo,
 		jwtService:      jwtservice,
 		passwordService: passwordservice,
+		totpService:     totpservice,
+		secretCipher:    secretcipher,
+		transactor:      domain.NoopTransactor{},
+		clock:           domain.SystemClock{},
 	}
 }
 
+// SetTransactor makes operations that read before they write, such as registration,
+// run in a transaction. Without one, they run without a transaction.
+func (uc *UserUseCase) SetTransactor(transactor domain.Transactor) {
+	uc.transactor = transactor
+}
+
+// SetClock replaces the system clock, e.g. with a fixed time in tests.
+func (uc *UserUseCase) SetClock(clock domain.Clock) {
+	uc.clock = clock
+}
+
+// RequireTwoFactorFor makes two-factor authentication mandatory for users whose role
+// grants any of the given permissions, whatever the role is called. Such users who
+// have not enrolled yet only receive an enrollment challenge token at login.
+func (uc *UserUseCase) RequireTwoFactorFor(permissions ...domain.Permission) {
+	uc.twoFactorRequiredPermissions = append(uc.twoFactorRequiredPermissions, permissions...)
+}
+
+// LoginResult is the outcome of the password step of a login. Exactly one of
+// Token and ChallengeToken is set.
+type LoginResult struct {
+	Token              string
+	ChallengeToken     string
+	TwoFactorRequired  bool
+	EnrollmentRequired bool
+}
+
+// TwoFactorEnrollment is returned when a user starts enrolling an authenticator app.
+type TwoFactorEnrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+// RegisterUser creates an account with the default role in the default organization.
+// Joining any other organization takes an invitation.
 func (uc *UserUseCase) RegisterUser(c context.Context, username string, password string) (*domain.User, error) {
-	existingUser, err := uc.userRepo.GetUserByUsername(c, username)
-	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
-		return nil, fmt.Errorf("usecase: failed to check exsisting user: %w", err)
+	organization, err := uc.orgRepo.GetOrganizationBySlug(c, domain.DefaultOrganizationSlug)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to look up default organization: %w", err)
 	}
-	if existingUser != nil {
-		return nil, domain.ErrUsernameTaken
+	return uc.registerUser(c, organization.Id, username, password, "")
+}
+
+// registerUser creates an account in the organization, with role or else the default
+// role. The username check and the insert run in one unit of work; registrations of
+// the same name that race each other are caught by the repository's unique index on
+// usernames.
+func (uc *UserUseCase) registerUser(c context.Context, organizationID primitive.ObjectID, username string, password string, role domain.UserRole) (*domain.User, error) {
+	c = domain.ContextWithOrganization(c, organizationID)
+	var savedUser *domain.User
+	err := runUnitOfWork(c, uc.transactor, func(c context.Context) error {
+		existingUser, err := uc.userRepo.GetUserByUsername(c, username)
+		if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+			return fmt.Errorf("usecase: failed to check exsisting user: %w", err)
+		}
+		if existingUser != nil {
+			return domain.ErrUsernameTaken
+		}
+
+		hashedPassword, err := uc.passwordService.Hash(c, password)
+		if err != nil {
+			return fmt.Errorf("usecase: failed to hash password: %w", err)
+		}
+
+		newuser, err := domain.NewUser(username, hashedPassword)
+		if err != nil {
+			return fmt.Errorf("usecase: failed to create new user: %w", err)
+		}
+		newuser.OrganizationID = organizationID
+		if role != "" {
+			newuser.Role = role
+		}
+
+		savedUser, err = uc.userRepo.CreateUser(c, newuser)
+		if err != nil {
+			return fmt.Errorf("usecase: failed to save user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return savedUser, nil
+}
 
-	hashedPassword, err := uc.passwordService.Hash(c, password)
+// Login checks a user's password in the organization with the given slug. An empty
+// organization means the default one, so clients written before organizations existed
+// keep working.
+func (uc *UserUseCase) Login(c context.Context, organization string, username string, password string) (*LoginResult, error) {
+	if organization == "" {
+		organization = domain.DefaultOrganizationSlug
+	}
+	org, err := uc.orgRepo.GetOrganizationBySlug(c, organization)
 	if err != nil {
-		return nil, fmt.Errorf("usecase: failed to hash password: %w", err)
+		if errors.Is(err, domain.ErrOrganizationNotFound) {
+			return nil, domain.ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("usecase: failed to look up organization: %w", err)
 	}
+	c = domain.ContextWithOrganization(c, org.Id)
 
-	newuser, err := domain.NewUser(username, hashedPassword)
+	existingUser, err := uc.userRepo.GetUserByUsername(c, username)
 	if err != nil {
-		return nil, fmt.Errorf("usecase: failed to create new user: %w", err)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("usecase: failed to check exsisting user: %w", err)
+	}
+
+	if err := uc.passwordService.Compare(c, password, existingUser.PasswordHash); err != nil {
+		if !errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			log.Printf("usecase: failed to verify password for user %q: %v\n", username, err)
+		}
+		return nil, domain.ErrInvalidCredentials
 	}
 
-	savedUser, err := uc.userRepo.CreateUser(c, newuser)
+	// The password is correct, but the second factor is still outstanding.
+	if existingUser.TwoFactorEnabled {
+		challenge, err := uc.jwtService.GetChallengeToken(c, existingUser, domain.PurposeTwoFactorLogin)
+		if err != nil {
+			return nil, fmt.Errorf("usecase: failed to get challenge token: %w", err)
+		}
+		return &LoginResult{ChallengeToken: challenge, TwoFactorRequired: true}, nil
+	}
+	required, err := uc.twoFactorRequired(c, existingUser)
 	if err != nil {
-		return nil, fmt.Errorf("usecase: failed to save user: %w", err)
+		return nil, err
+	}
+	if required {
+		challenge, err := uc.jwtService.GetChallengeToken(c, existingUser, domain.PurposeTwoFactorEnroll)
+		if err != nil {
+			return nil, fmt.Errorf("usecase: failed to get challenge token: %w", err)
+		}
+		return &LoginResult{ChallengeToken: challenge, EnrollmentRequired: true}, nil
 	}
 
-	return savedUser, nil
+	token, err := uc.issueAccessToken(c, existingUser)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{Token: token}, nil
 }
 
-func (uc *UserUseCase) Login(c context.Context, username string, password string) (string, error) {
-	existingUser, err := uc.userRepo.GetUserByUsername(c, username)
+// VerifyTwoFactorLogin completes a login started with Login. The code may be either
+// a current TOTP code or one of the user's unused recovery codes. Each TOTP code is
+// accepted only once, and repeated failures lock the user out for a while with
+// domain.ErrTooManyTwoFactorAttempts.
+func (uc *UserUseCase) VerifyTwoFactorLogin(c context.Context, challengeToken string, code string) (string, error) {
+	claims, err := uc.jwtService.ParseToken(c, challengeToken)
+	if err != nil || claims.Purpose != domain.PurposeTwoFactorLogin {
+		return "", domain.ErrInvalidCredentials
+	}
+	if organizationID, err := primitive.ObjectIDFromHex(claims.OrganizationID); err == nil {
+		c = domain.ContextWithOrganization(c, organizationID)
+	}
+
+	user, err := uc.getUser(c, claims.UserId)
 	if err != nil {
 		if errors.Is(err, domain.ErrUserNotFound) {
 			return "", domain.ErrInvalidCredentials
 		}
-		return "", fmt.Errorf("usecase: failed to check exsisting user: %w", err)
+		return "", err
+	}
+	if !user.TwoFactorEnabled {
+		return "", domain.ErrInvalidCredentials
 	}
 
-	if err := uc.passwordService.Compare(c, password, existingUser.PasswordHash); err != nil {
-		if !errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-			log.Printf("usecase: failed to verify password for user %q: %v\n", username, err)
+	if err := uc.verifySecondFactor(c, user, code); err != nil {
+		return "", err
+	}
+
+	return uc.issueAccessToken(c, user)
+}
+
+// AssignRole changes a user's role. The role must be defined in the role store.
+// The new permissions take effect the next time the user logs in.
+func (uc *UserUseCase) AssignRole(c context.Context, userID string, role domain.UserRole) (*domain.User, error) {
+	if _, err := uc.roleRepo.GetRole(c, role); err != nil {
+		if errors.Is(err, domain.ErrRoleNotFound) {
+			return nil, fmt.Errorf("%w: role %q is not defined", domain.ErrValidationFailed, role)
 		}
-		return "", domain.ErrInvalidCredentials
+		return nil, fmt.Errorf("usecase: failed to look up role: %w", err)
+	}
+
+	user, err := uc.getUser(c, userID)
+	if err != nil {
+		return nil, err
 	}
+	user.Role = role
 
-	token, err := uc.jwtService.GetSignedToken(c, existingUser)
+	updatedUser, err := uc.userRepo.UpdateUser(c, user.Id, user)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to update user role: %w", err)
+	}
+	return updatedUser, nil
+}
+
+// issueAccessToken resolves the permissions granted by the user's role in their
+// organization and embeds them in a freshly signed access token.
+func (uc *UserUseCase) issueAccessToken(c context.Context, user *domain.User) (string, error) {
+	c = domain.ContextWithOrganization(c, user.OrganizationID)
+	var permissions []domain.Permission
+	role, err := uc.roleRepo.GetRole(c, user.Role)
+	switch {
+	case err == nil:
+		permissions = role.Permissions
+	case errors.Is(err, domain.ErrRoleNotFound):
+		log.Printf("usecase: role %q of user %q is not defined; issuing token without permissions\n", user.Role, user.Username)
+	default:
+		return "", fmt.Errorf("usecase: failed to resolve permissions: %w", err)
+	}
+
+	token, err := uc.jwtService.GetSignedToken(c, user, permissions)
 	if err != nil {
 		return "", fmt.Errorf("usecase: failed to get token: %w", err)
 	}
 	return token, nil
 }
+
+// BeginTwoFactorEnrollment generates a new TOTP secret for the user. The secret only
+// becomes active once ConfirmTwoFactorEnrollment receives a valid code for it.
+func (uc *UserUseCase) BeginTwoFactorEnrollment(c context.Context, userID string) (*TwoFactorEnrollment, error) {
+	user, err := uc.getUser(c, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := uc.totpService.GenerateSecret(c)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to generate two-factor secret: %w", err)
+	}
+	encrypted, err := uc.secretCipher.Encrypt(c, secret)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to encrypt two-factor secret: %w", err)
+	}
+
+	user.PendingTwoFactorSecret = encrypted
+	if _, err := uc.userRepo.UpdateUser(c, user.Id, user); err != nil {
+		return nil, fmt.Errorf("usecase: failed to save two-factor enrollment: %w", err)
+	}
+
+	return &TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: uc.totpService.ProvisioningURI(c, user.Username, secret),
+	}, nil
+}
+
+// ConfirmTwoFactorEnrollment verifies the first code from the authenticator app,
+// enables two-factor authentication and returns freshly generated recovery codes.
+// The plaintext recovery codes are only ever available from this call.
+func (uc *UserUseCase) ConfirmTwoFactorEnrollment(c context.Context, userID string, code string) ([]string, error) {
+	user, err := uc.getUser(c, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+	if user.PendingTwoFactorSecret == "" {
+		return nil, domain.ErrTwoFactorEnrollmentMissing
+	}
+
+	secret, err := uc.secretCipher.Decrypt(c, user.PendingTwoFactorSecret)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: failed to decrypt two-factor secret: %w", err)
+	}
+	step, ok := uc.totpService.Validate(c, code, secret)
+	if !ok {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+	if err := uc.useTOTPStep(c, user, step); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, hashes, err := uc.generateRecoveryCodes(c)
+	if err != nil {
+		return nil, err
+	}
+
+	user.TwoFactorEnabled = true
+	user.TwoFactorSecret = user.PendingTwoFactorSecret
+	user.PendingTwoFactorSecret = ""
+	user.RecoveryCodeHashes = hashes
+	if _, err := uc.userRepo.UpdateUser(c, user.Id, user); err != nil {
+		return nil, fmt.Errorf("usecase: failed to enable two-factor authentication: %w", err)
+	}
+	return recoveryCodes, nil
+}
+
+// DisableTwoFactor turns two-factor authentication off after verifying a code.
+// Users whose role requires two-factor authentication cannot disable it.
+func (uc *UserUseCase) DisableTwoFactor(c context.Context, userID string, code string) error {
+	user, err := uc.getUser(c, userID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled {
+		return domain.ErrTwoFactorNotEnabled
+	}
+	required, err := uc.twoFactorRequired(c, user)
+	if err != nil {
+		return err
+	}
+	if required {
+		return domain.ErrTwoFactorRequired
+	}
+	if err := uc.verifySecondFactor(c, user, code); err != nil {
+		return err
+	}
+
+	user.TwoFactorEnabled = false
+	user.TwoFactorSecret = ""
+	user.PendingTwoFactorSecret = ""
+	user.RecoveryCodeHashes = nil
+	if _, err := uc.userRepo.UpdateUser(c, user.Id, user); err != nil {
+		return fmt.Errorf("usecase: failed to disable two-factor authentication: %w", err)
+	}
+	return nil
+}
+
+// SetTimeZone stores the IANA time zone the user's "today" is computed in when requests
+// carry no Time-Zone header. An empty zone resets it to UTC. Tokens issued before the
+// change keep the old zone until the user logs in again.
+func (uc *UserUseCase) SetTimeZone(c context.Context, userID string, timeZone string) error {
+	if timeZone != "" {
+		if _, err := domain.LoadTimeZone(timeZone); err != nil {
+			return err
+		}
+	}
+	user, err := uc.getUser(c, userID)
+	if err != nil {
+		return err
+	}
+	user.TimeZone = timeZone
+	if _, err := uc.userRepo.UpdateUser(c, user.Id, user); err != nil {
+		return fmt.Errorf("usecase: failed to set time zone: %w", err)
+	}
+	return nil
+}
+
+// twoFactorRequired reports whether the user's role grants a permission that requires
+// two-factor authentication. A role that is not defined grants nothing.
+func (uc *UserUseCase) twoFactorRequired(c context.Context, user *domain.User) (bool, error) {
+	if len(uc.twoFactorRequiredPermissions) == 0 {
+		return false, nil
+	}
+	role, err := uc.roleRepo.GetRole(domain.ContextWithOrganization(c, user.OrganizationID), user.Role)
+	if errors.Is(err, domain.ErrRoleNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("usecase: failed to resolve permissions: %w", err)
+	}
+	return slices.ContainsFunc(uc.twoFactorRequiredPermissions, role.Has), nil
+}
+
+// GetUser returns the user with the given ID.
+func (uc *UserUseCase) GetUser(c context.Context, userID string) (*domain.User, error) {
+	return uc.getUser(c, userID)
+}
+
+func (uc *UserUseCase) getUser(c context.Context, userID string) (*domain.User, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid user ID format", domain.ErrValidationFailed)
+	}
+	user, err := uc.userRepo.GetUserById(c, objectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("usecase: failed to get user by ID: %w", err)
+	}
+	return user, nil
+}
+
+// ValidateSession implements domain.SessionValidator. A token is revoked once its
+// user no longer exists in the organization it was issued for.
+func (uc *UserUseCase) ValidateSession(c context.Context, claims *domain.Claims) error {
+	organizationID, err := primitive.ObjectIDFromHex(claims.OrganizationID)
+	if err != nil {
+		return domain.ErrSessionRevoked
+	}
+	_, err = uc.getUser(domain.ContextWithOrganization(c, organizationID), claims.UserId)
+	if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrValidationFailed) {
+		return domain.ErrSessionRevoked
+	}
+	return err
+}
+
+// verifySecondFactor accepts a TOTP code or consumes a matching recovery code. Every
+// attempt counts as a failure until it succeeds, so that concurrent guesses are
+// counted too; after maxTwoFactorFailures of them, attempts are refused until
+// twoFactorLockout has passed since the last one.
+func (uc *UserUseCase) verifySecondFactor(c context.Context, user *domain.User, code string) error {
+	now := uc.clock.Now()
+	failures, lastFailure, err := uc.userRepo.RecordTwoFactorAttempt(c, user.Id, now)
+	if err != nil {
+		return fmt.Errorf("usecase: failed to record two-factor attempt: %w", err)
+	}
+	if failures >= maxTwoFactorFailures && lastFailure != nil && now.Before(lastFailure.Add(twoFactorLockout)) {
+		return domain.ErrTooManyTwoFactorAttempts
+	}
+	if err := uc.checkSecondFactor(c, user, code); err != nil {
+		return err
+	}
+	if err := uc.userRepo.ResetTwoFactorFailures(c, user.Id); err != nil {
+		return fmt.Errorf("usecase: failed to reset two-factor failures: %w", err)
+	}
+	return nil
+}
+
+func (uc *UserUseCase) checkSecondFactor(c context.Context, user *domain.User, code string) error {
+	secret, err := uc.secretCipher.Decrypt(c, user.TwoFactorSecret)
+	if err != nil {
+		return fmt.Errorf("usecase: failed to decrypt two-factor secret: %w", err)
+	}
+	if step, ok := uc.totpService.Validate(c, code, secret); ok {
+		return uc.useTOTPStep(c, user, step)
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	for _, hash := range user.RecoveryCodeHashes {
+		if uc.passwordService.Compare(c, normalized, hash) != nil {
+			continue
+		}
+		// Recovery codes are single use, also when two logins redeem one at once.
+		consumed, err := uc.userRepo.ConsumeRecoveryCode(c, user.Id, hash)
+		if err != nil {
+			return fmt.Errorf("usecase: failed to consume recovery code: %w", err)
+		}
+		if !consumed {
+			return domain.ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+	return domain.ErrInvalidTwoFactorCode
+}
+
+// useTOTPStep accepts a TOTP code of the given time step once. A code seen by someone
+// else, e.g. over the user's shoulder, cannot be used again within its validity.
+func (uc *UserUseCase) useTOTPStep(c context.Context, user *domain.User, step int64) error {
+	recorded, err := uc.userRepo.RecordTwoFactorStep(c, user.Id, step)
+	if err != nil {
+		return fmt.Errorf("usecase: failed to record two-factor code: %w", err)
+	}
+	if !recorded {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func (uc *UserUseCase) generateRecoveryCodes(c context.Context) ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, fmt.Errorf("usecase: failed to generate recovery code: %w", err)
+		}
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(raw))
+		hash, err := uc.passwordService.Hash(c, code)
+		if err != nil {
+			return nil, nil, fmt.Errorf("usecase: failed to hash recovery code: %w", err)
+		}
+		codes = append(codes, code[:4]+"-"+code[4:])
+		hashes = append(hashes, hash)
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
@@ -5,7 +5,9 @@ import (
 	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,15 +16,66 @@ import (
 // --- Mocks (can be kept as is, they are well-defined) ---
 type MockUserRepository struct {
 	GetUserByUsernameFunc func(c context.Context, username string) (*domain.User, error)
+	GetUserByIdFunc       func(c context.Context, id primitive.ObjectID) (*domain.User, error)
 	CreateUserFunc        func(c context.Context, user *domain.User) (*domain.User, error)
+	UpdateUserFunc        func(c context.Context, id primitive.ObjectID, user *domain.User) (*domain.User, error)
+	GetAllUsersFunc       func(c context.Context) ([]*domain.User, error)
+	DeleteUserFunc        func(c context.Context, id primitive.ObjectID) error
+
+	GetUserByCalendarTokenHashFunc func(c context.Context, tokenHash string) (*domain.User, error)
+
+	// The two-factor bookkeeping succeeds unless these are set.
+	RecordTwoFactorStepFunc    func(c context.Context, id primitive.ObjectID, step int64) (bool, error)
+	ConsumeRecoveryCodeFunc    func(c context.Context, id primitive.ObjectID, hash string) (bool, error)
+	RecordTwoFactorAttemptFunc func(c context.Context, id primitive.ObjectID, at time.Time) (int, *time.Time, error)
+	ResetTwoFactorFailuresFunc func(c context.Context, id primitive.ObjectID) error
 }
 
 func (m *MockUserRepository) GetUserByUsername(c context.Context, username string) (*domain.User, error) {
 	return m.GetUserByUsernameFunc(c, username)
 }
+func (m *MockUserRepository) GetUserById(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+	return m.GetUserByIdFunc(c, id)
+}
+func (m *MockUserRepository) GetUserByCalendarTokenHash(c context.Context, tokenHash string) (*domain.User, error) {
+	return m.GetUserByCalendarTokenHashFunc(c, tokenHash)
+}
 func (m *MockUserRepository) CreateUser(c context.Context, user *domain.User) (*domain.User, error) {
 	return m.CreateUserFunc(c, user)
 }
+func (m *MockUserRepository) UpdateUser(c context.Context, id primitive.ObjectID, user *domain.User) (*domain.User, error) {
+	return m.UpdateUserFunc(c, id, user)
+}
+func (m *MockUserRepository) GetAllUsers(c context.Context) ([]*domain.User, error) {
+	return m.GetAllUsersFunc(c)
+}
+func (m *MockUserRepository) DeleteUser(c context.Context, id primitive.ObjectID) error {
+	return m.DeleteUserFunc(c, id)
+}
+func (m *MockUserRepository) RecordTwoFactorStep(c context.Context, id primitive.ObjectID, step int64) (bool, error) {
+	if m.RecordTwoFactorStepFunc == nil {
+		return true, nil
+	}
+	return m.RecordTwoFactorStepFunc(c, id, step)
+}
+func (m *MockUserRepository) ConsumeRecoveryCode(c context.Context, id primitive.ObjectID, hash string) (bool, error) {
+	if m.ConsumeRecoveryCodeFunc == nil {
+		return true, nil
+	}
+	return m.ConsumeRecoveryCodeFunc(c, id, hash)
+}
+func (m *MockUserRepository) RecordTwoFactorAttempt(c context.Context, id primitive.ObjectID, at time.Time) (int, *time.Time, error) {
+	if m.RecordTwoFactorAttemptFunc == nil {
+		return 0, nil, nil
+	}
+	return m.RecordTwoFactorAttemptFunc(c, id, at)
+}
+func (m *MockUserRepository) ResetTwoFactorFailures(c context.Context, id primitive.ObjectID) error {
+	if m.ResetTwoFactorFailuresFunc == nil {
+		return nil
+	}
+	return m.ResetTwoFactorFailuresFunc(c, id)
+}
 
 type MockPasswordService struct {
 	HashFunc    func(c context.Context, password string) (string, error)
@@ -37,17 +90,46 @@ func (m *MockPasswordService) Compare(c context.Context, password string, hash s
 }
 
 type MockJwtService struct {
-	GetSignedTokenFunc func(c context.Context, user *domain.User) (string, error)
-	ParseTokenFunc     func(c context.Context, token string) (*domain.Claims, error)
+	GetSignedTokenFunc    func(c context.Context, user *domain.User, permissions []domain.Permission) (string, error)
+	GetChallengeTokenFunc func(c context.Context, user *domain.User, purpose domain.TokenPurpose) (string, error)
+	ParseTokenFunc        func(c context.Context, token string) (*domain.Claims, error)
 }
 
-func (m *MockJwtService) GetSignedToken(c context.Context, user *domain.User) (string, error) {
-	return m.GetSignedTokenFunc(c, user)
+func (m *MockJwtService) GetSignedToken(c context.Context, user *domain.User, permissions []domain.Permission) (string, error) {
+	return m.GetSignedTokenFunc(c, user, permissions)
+}
+func (m *MockJwtService) GetChallengeToken(c context.Context, user *domain.User, purpose domain.TokenPurpose) (string, error) {
+	return m.GetChallengeTokenFunc(c, user, purpose)
 }
 func (m *MockJwtService) ParseToken(c context.Context, token string) (*domain.Claims, error) {
 	return m.ParseTokenFunc(c, token)
 }
 
+type MockTOTPService struct {
+	GenerateSecretFunc func(c context.Context) (string, error)
+	ValidateFunc       func(c context.Context, code string, secret string) (int64, bool)
+}
+
+func (m *MockTOTPService) GenerateSecret(c context.Context) (string, error) {
+	return m.GenerateSecretFunc(c)
+}
+func (m *MockTOTPService) ProvisioningURI(c context.Context, accountName string, secret string) string {
+	return "otpauth://totp/Test:" + accountName + "?secret=" + secret
+}
+func (m *MockTOTPService) Validate(c context.Context, code string, secret string) (int64, bool) {
+	return m.ValidateFunc(c, code, secret)
+}
+
+// MockSecretCipher "encrypts" by prefixing, which keeps assertions readable.
+type MockSecretCipher struct{}
+
+func (m *MockSecretCipher) Encrypt(c context.Context, plaintext string) (string, error) {
+	return "enc:" + plaintext, nil
+}
+func (m *MockSecretCipher) Decrypt(c context.Context, ciphertext string) (string, error) {
+	return strings.TrimPrefix(ciphertext, "enc:"), nil
+}
+
 //===========================================================================
 // UserUseCase Test Suite
 //===========================================================================
@@ -55,8 +137,12 @@ func (m *MockJwtService) ParseToken(c context.Context, token string) (*domain.Cl
 type UserUseCaseSuite struct {
 	suite.Suite
 	mockUserRepo    *MockUserRepository
+	mockRoleRepo    *MockRoleRepository
+	mockOrgRepo     *MockOrganizationRepository
+	defaultOrg      *domain.Organization
 	mockJwtService  *MockJwtService
 	mockPassService *MockPasswordService
+	mockTOTP        *MockTOTPService
 	useCase         *usecases.UserUseCase
 	ctx             context.Context
 }
@@ -69,9 +155,13 @@ func TestUserUseCaseSuite(t *testing.T) {
 // SetupTest runs before each test method. It's the perfect place for initialization.
 func (s *UserUseCaseSuite) SetupTest() {
 	s.mockUserRepo = &MockUserRepository{}
+	s.mockRoleRepo = NewMockRoleRepository(domain.DefaultRoleDefinitions()...)
+	s.defaultOrg = &domain.Organization{Id: primitive.NewObjectID(), Name: "Default", Slug: domain.DefaultOrganizationSlug}
+	s.mockOrgRepo = NewMockOrganizationRepository(s.defaultOrg)
 	s.mockJwtService = &MockJwtService{}
 	s.mockPassService = &MockPasswordService{}
-	s.useCase = usecases.NewUserUseCase(s.mockUserRepo, s.mockJwtService, s.mockPassService)
+	s.mockTOTP = &MockTOTPService{}
+	s.useCase = usecases.NewUserUseCase(s.mockUserRepo, s.mockRoleRepo, s.mockOrgRepo, s.mockJwtService, s.mockPassService, s.mockTOTP, &MockSecretCipher{})
 	s.ctx = context.Background()
 }
 
@@ -103,6 +193,7 @@ func (s *UserUseCaseSuite) TestRegisterUser() {
 		s.Require().NotNil(registeredUser)
 		s.Equal(testUsername, registeredUser.Username)
 		s.Equal(hashedPassword, registeredUser.PasswordHash)
+		s.Equal(s.defaultOrg.Id, registeredUser.OrganizationID, "Open registration joins the default organization")
 		s.False(registeredUser.Id.IsZero())
 	})
 
@@ -132,6 +223,17 @@ func (s *UserUseCaseSuite) TestRegisterUser() {
 		s.ErrorIs(err, expectedErr)
 	})
 
+	s.Run("Runs In A Transaction", func() {
+		transactor := &FakeTransactor{Err: domain.ErrTransactionsUnsupported}
+		s.useCase.SetTransactor(transactor)
+		defer s.useCase.SetTransactor(domain.NoopTransactor{})
+
+		_, err := s.useCase.RegisterUser(s.ctx, testUsername, testPassword)
+
+		s.ErrorIs(err, domain.ErrTransactionsUnsupported)
+		s.Equal(1, transactor.Calls)
+	})
+
 	s.Run("Failure - Domain Validation Error", func() {
 		s.mockPassService.HashFunc = func(c context.Context, password string) (string, error) {
 			return "any-hashed-password", nil
@@ -149,7 +251,7 @@ func (s *UserUseCaseSuite) TestLogin() {
 	testPassword := "password123"
 	hashedPassword := "hashedpassword"
 	expectedToken := "test.jwt.token"
-	mockUser := &domain.User{Id: primitive.NewObjectID(), Username: testUsername, PasswordHash: hashedPassword}
+	mockUser := &domain.User{Id: primitive.NewObjectID(), Username: testUsername, PasswordHash: hashedPassword, Role: domain.RoleUser}
 
 	s.Run("Success", func() {
 		// --- Mock Configuration ---
@@ -161,17 +263,43 @@ func (s *UserUseCaseSuite) TestLogin() {
 			s.Equal(hashedPassword, hash)
 			return nil // Passwords match
 		}
-		s.mockJwtService.GetSignedTokenFunc = func(c context.Context, user *domain.User) (string, error) {
+		s.mockJwtService.GetSignedTokenFunc = func(c context.Context, user *domain.User, permissions []domain.Permission) (string, error) {
 			s.Equal(mockUser.Id, user.Id)
+			s.Equal([]domain.Permission{domain.PermTasksRead}, permissions, "Permissions should be resolved from the user's role")
 			return expectedToken, nil
 		}
 
 		// --- Execution ---
-		token, err := s.useCase.Login(s.ctx, testUsername, testPassword)
+		result, err := s.useCase.Login(s.ctx, "", testUsername, testPassword)
 
 		// --- Assertion ---
 		s.Require().NoError(err)
-		s.Equal(expectedToken, token)
+		s.Equal(expectedToken, result.Token)
+		s.False(result.TwoFactorRequired)
+	})
+
+	s.Run("Scoped To The Named Organization", func() {
+		acme := &domain.Organization{Id: primitive.NewObjectID(), Name: "Acme", Slug: "acme"}
+		s.mockOrgRepo.Organizations[acme.Slug] = acme
+		s.mockUserRepo.GetUserByUsernameFunc = func(c context.Context, username string) (*domain.User, error) {
+			organizationID, _ := domain.OrganizationFromContext(c)
+			s.Equal(acme.Id, organizationID, "The user should be looked up in the named organization")
+			return mockUser, nil
+		}
+		s.mockPassService.CompareFunc = func(c context.Context, password, hash string) error { return nil }
+		s.mockJwtService.GetSignedTokenFunc = func(c context.Context, user *domain.User, permissions []domain.Permission) (string, error) {
+			return expectedToken, nil
+		}
+
+		_, err := s.useCase.Login(s.ctx, "acme", testUsername, testPassword)
+
+		s.Require().NoError(err)
+	})
+
+	s.Run("Failure - Unknown Organization", func() {
+		_, err := s.useCase.Login(s.ctx, "nowhere", testUsername, testPassword)
+
+		s.ErrorIs(err, domain.ErrInvalidCredentials, "Unknown organizations must look like wrong credentials")
 	})
 
 	s.Run("Failure - User Not Found", func() {
@@ -179,7 +307,7 @@ func (s *UserUseCaseSuite) TestLogin() {
 			return nil, domain.ErrUserNotFound
 		}
 
-		_, err := s.useCase.Login(s.ctx, testUsername, testPassword)
+		_, err := s.useCase.Login(s.ctx, "", testUsername, testPassword)
 
 		s.Require().Error(err)
 		s.ErrorIs(err, domain.ErrInvalidCredentials)
@@ -193,7 +321,7 @@ func (s *UserUseCaseSuite) TestLogin() {
 			return errors.New("password comparison failed") // Simulate mismatch
 		}
 
-		_, err := s.useCase.Login(s.ctx, testUsername, testPassword)
+		_, err := s.useCase.Login(s.ctx, "", testUsername, testPassword)
 
 		s.Require().Error(err)
 		s.ErrorIs(err, domain.ErrInvalidCredentials)
@@ -207,13 +335,416 @@ func (s *UserUseCaseSuite) TestLogin() {
 		s.mockPassService.CompareFunc = func(c context.Context, password, hash string) error {
 			return nil // Passwords match
 		}
-		s.mockJwtService.GetSignedTokenFunc = func(c context.Context, user *domain.User) (string, error) {
+		s.mockJwtService.GetSignedTokenFunc = func(c context.Context, user *domain.User, permissions []domain.Permission) (string, error) {
 			return "", expectedErr
 		}
 
-		_, err := s.useCase.Login(s.ctx, testUsername, testPassword)
+		_, err := s.useCase.Login(s.ctx, "", testUsername, testPassword)
 
 		s.Require().Error(err)
 		s.ErrorIs(err, expectedErr)
 	})
 }
+
+// TestLoginTwoFactor covers the two-step login for accounts with two-factor authentication.
+func (s *UserUseCaseSuite) TestLoginTwoFactor() {
+	userID := primitive.NewObjectID()
+	newUser := func() *domain.User {
+		return &domain.User{
+			Id:                 userID,
+			Username:           "secured",
+			PasswordHash:       "hashedpassword",
+			Role:               domain.RoleAdmin,
+			TwoFactorEnabled:   true,
+			TwoFactorSecret:    "enc:TOTPSECRET",
+			RecoveryCodeHashes: []string{"hash:abcdefgh"},
+		}
+	}
+
+	s.Run("Password Step Returns Challenge", func() {
+		s.SetupTest()
+		user := newUser()
+		s.mockUserRepo.GetUserByUsernameFunc = func(c context.Context, username string) (*domain.User, error) {
+			return user, nil
+		}
+		s.mockPassService.CompareFunc = func(c context.Context, password, hash string) error { return nil }
+		s.mockJwtService.GetChallengeTokenFunc = func(c context.Context, u *domain.User, purpose domain.TokenPurpose) (string, error) {
+			s.Equal(domain.PurposeTwoFactorLogin, purpose)
+			return "challenge.token", nil
+		}
+
+		result, err := s.useCase.Login(s.ctx, "", "secured", "password")
+
+		s.Require().NoError(err)
+		s.True(result.TwoFactorRequired)
+		s.Equal("challenge.token", result.ChallengeToken)
+		s.Empty(result.Token, "No access token may be issued before the second factor")
+	})
+
+	s.Run("Enrollment Required By Policy", func() {
+		s.SetupTest()
+		s.useCase.RequireTwoFactorFor(domain.PermUsersManage)
+		user := newUser()
+		user.TwoFactorEnabled = false
+		s.mockUserRepo.GetUserByUsernameFunc = func(c context.Context, username string) (*domain.User, error) {
+			return user, nil
+		}
+		s.mockPassService.CompareFunc = func(c context.Context, password, hash string) error { return nil }
+		s.mockJwtService.GetChallengeTokenFunc = func(c context.Context, u *domain.User, purpose domain.TokenPurpose) (string, error) {
+			s.Equal(domain.PurposeTwoFactorEnroll, purpose)
+			return "enroll.token", nil
+		}
+
+		result, err := s.useCase.Login(s.ctx, "", "secured", "password")
+
+		s.Require().NoError(err)
+		s.True(result.EnrollmentRequired)
+		s.Equal("enroll.token", result.ChallengeToken)
+		s.Empty(result.Token)
+	})
+
+	s.Run("Enrollment Required For Any Role With The Permission", func() {
+		s.SetupTest()
+		s.useCase.RequireTwoFactorFor(domain.PermUsersManage)
+		s.mockRoleRepo.Roles["Support"] = &domain.RoleDefinition{Name: "Support", Permissions: []domain.Permission{domain.PermTasksRead, domain.PermUsersManage}}
+		user := newUser()
+		user.Role = "Support"
+		user.TwoFactorEnabled = false
+		s.mockUserRepo.GetUserByUsernameFunc = func(c context.Context, username string) (*domain.User, error) {
+			return user, nil
+		}
+		s.mockPassService.CompareFunc = func(c context.Context, password, hash string) error { return nil }
+		s.mockJwtService.GetChallengeTokenFunc = func(c context.Context, u *domain.User, purpose domain.TokenPurpose) (string, error) {
+			return "enroll.token", nil
+		}
+
+		result, err := s.useCase.Login(s.ctx, "", "secured", "password")
+
+		s.Require().NoError(err)
+		s.True(result.EnrollmentRequired, "a renamed admin role is still covered")
+		s.Empty(result.Token)
+	})
+
+	s.Run("Verify With TOTP Code", func() {
+		s.SetupTest()
+		s.mockJwtService.ParseTokenFunc = func(c context.Context, token string) (*domain.Claims, error) {
+			return &domain.Claims{UserId: userID.Hex(), Purpose: domain.PurposeTwoFactorLogin}, nil
+		}
+		s.mockUserRepo.GetUserByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			return newUser(), nil
+		}
+		s.mockTOTP.ValidateFunc = func(c context.Context, code string, secret string) (int64, bool) {
+			s.Equal("TOTPSECRET", secret, "The stored secret should be decrypted before validation")
+			return 1, code == "123456"
+		}
+		s.mockJwtService.GetSignedTokenFunc = func(c context.Context, u *domain.User, permissions []domain.Permission) (string, error) {
+			return "access.token", nil
+		}
+
+		token, err := s.useCase.VerifyTwoFactorLogin(s.ctx, "challenge.token", "123456")
+
+		s.Require().NoError(err)
+		s.Equal("access.token", token)
+	})
+
+	s.Run("Verify With Recovery Code Consumes It", func() {
+		s.SetupTest()
+		s.mockJwtService.ParseTokenFunc = func(c context.Context, token string) (*domain.Claims, error) {
+			return &domain.Claims{UserId: userID.Hex(), Purpose: domain.PurposeTwoFactorLogin}, nil
+		}
+		s.mockUserRepo.GetUserByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			return newUser(), nil
+		}
+		s.mockTOTP.ValidateFunc = func(c context.Context, code string, secret string) (int64, bool) { return 0, false }
+		s.mockPassService.CompareFunc = func(c context.Context, password, hash string) error {
+			if "hash:"+password == hash {
+				return nil
+			}
+			return errors.New("mismatch")
+		}
+		var consumed []string
+		s.mockUserRepo.ConsumeRecoveryCodeFunc = func(c context.Context, id primitive.ObjectID, hash string) (bool, error) {
+			consumed = append(consumed, hash)
+			return len(consumed) == 1, nil
+		}
+		s.mockJwtService.GetSignedTokenFunc = func(c context.Context, u *domain.User, permissions []domain.Permission) (string, error) {
+			return "access.token", nil
+		}
+
+		token, err := s.useCase.VerifyTwoFactorLogin(s.ctx, "challenge.token", "ABCD-EFGH")
+
+		s.Require().NoError(err)
+		s.Equal("access.token", token)
+		s.Equal([]string{"hash:abcdefgh"}, consumed, "A used recovery code must be removed")
+
+		// A second login read the user before the code was removed.
+		_, err = s.useCase.VerifyTwoFactorLogin(s.ctx, "challenge.token", "ABCD-EFGH")
+		s.ErrorIs(err, domain.ErrInvalidTwoFactorCode, "A recovery code must not be redeemed twice")
+	})
+
+	s.Run("Failure - Wrong Code", func() {
+		s.SetupTest()
+		s.mockJwtService.ParseTokenFunc = func(c context.Context, token string) (*domain.Claims, error) {
+			return &domain.Claims{UserId: userID.Hex(), Purpose: domain.PurposeTwoFactorLogin}, nil
+		}
+		s.mockUserRepo.GetUserByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			return newUser(), nil
+		}
+		s.mockTOTP.ValidateFunc = func(c context.Context, code string, secret string) (int64, bool) { return 0, false }
+		s.mockPassService.CompareFunc = func(c context.Context, password, hash string) error { return errors.New("mismatch") }
+
+		_, err := s.useCase.VerifyTwoFactorLogin(s.ctx, "challenge.token", "000000")
+
+		s.ErrorIs(err, domain.ErrInvalidTwoFactorCode)
+	})
+
+	s.Run("Failure - Replayed TOTP Code", func() {
+		s.SetupTest()
+		s.mockJwtService.ParseTokenFunc = func(c context.Context, token string) (*domain.Claims, error) {
+			return &domain.Claims{UserId: userID.Hex(), Purpose: domain.PurposeTwoFactorLogin}, nil
+		}
+		user := newUser()
+		user.LastTwoFactorStep = 41
+		s.mockUserRepo.GetUserByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			return user, nil
+		}
+		s.mockTOTP.ValidateFunc = func(c context.Context, code string, secret string) (int64, bool) { return 42, true }
+		s.mockUserRepo.RecordTwoFactorStepFunc = func(c context.Context, id primitive.ObjectID, step int64) (bool, error) {
+			if step <= user.LastTwoFactorStep {
+				return false, nil
+			}
+			user.LastTwoFactorStep = step
+			return true, nil
+		}
+		s.mockJwtService.GetSignedTokenFunc = func(c context.Context, u *domain.User, permissions []domain.Permission) (string, error) {
+			return "access.token", nil
+		}
+
+		_, err := s.useCase.VerifyTwoFactorLogin(s.ctx, "challenge.token", "123456")
+		s.Require().NoError(err)
+		_, err = s.useCase.VerifyTwoFactorLogin(s.ctx, "challenge.token", "123456")
+		s.ErrorIs(err, domain.ErrInvalidTwoFactorCode, "A TOTP code should only be accepted once")
+	})
+
+	s.Run("Lockout After Repeated Failures", func() {
+		s.SetupTest()
+		now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		s.useCase.SetClock(fixedClock(now))
+		s.mockJwtService.ParseTokenFunc = func(c context.Context, token string) (*domain.Claims, error) {
+			return &domain.Claims{UserId: userID.Hex(), Purpose: domain.PurposeTwoFactorLogin}, nil
+		}
+		s.mockUserRepo.GetUserByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			return newUser(), nil
+		}
+		s.mockTOTP.ValidateFunc = func(c context.Context, code string, secret string) (int64, bool) { return 1, code == "123456" }
+		s.mockPassService.CompareFunc = func(c context.Context, password, hash string) error { return errors.New("mismatch") }
+		s.mockJwtService.GetSignedTokenFunc = func(c context.Context, u *domain.User, permissions []domain.Permission) (string, error) {
+			return "access.token", nil
+		}
+		var failures int
+		var lastFailure *time.Time
+		s.mockUserRepo.RecordTwoFactorAttemptFunc = func(c context.Context, id primitive.ObjectID, at time.Time) (int, *time.Time, error) {
+			previous, previousAt := failures, lastFailure
+			failures, lastFailure = failures+1, &at
+			return previous, previousAt, nil
+		}
+		s.mockUserRepo.ResetTwoFactorFailuresFunc = func(c context.Context, id primitive.ObjectID) error {
+			failures, lastFailure = 0, nil
+			return nil
+		}
+
+		for i := 0; i < 5; i++ {
+			_, err := s.useCase.VerifyTwoFactorLogin(s.ctx, "challenge.token", "000000")
+			s.Require().ErrorIs(err, domain.ErrInvalidTwoFactorCode)
+		}
+		_, err := s.useCase.VerifyTwoFactorLogin(s.ctx, "challenge.token", "123456")
+		s.ErrorIs(err, domain.ErrTooManyTwoFactorAttempts, "Even the right code should be refused while locked out")
+
+		s.useCase.SetClock(fixedClock(now.Add(16 * time.Minute)))
+		token, err := s.useCase.VerifyTwoFactorLogin(s.ctx, "challenge.token", "123456")
+		s.Require().NoError(err, "The lockout should end after a while")
+		s.Equal("access.token", token)
+		s.Zero(failures, "A successful attempt should reset the failures")
+	})
+
+	s.Run("Failure - Access Token Used As Challenge", func() {
+		s.SetupTest()
+		s.mockJwtService.ParseTokenFunc = func(c context.Context, token string) (*domain.Claims, error) {
+			return &domain.Claims{UserId: userID.Hex()}, nil
+		}
+
+		_, err := s.useCase.VerifyTwoFactorLogin(s.ctx, "access.token", "123456")
+
+		s.ErrorIs(err, domain.ErrInvalidCredentials)
+	})
+}
+
+// TestTwoFactorEnrollment covers enrolling, confirming and disabling two-factor authentication.
+func (s *UserUseCaseSuite) TestTwoFactorEnrollment() {
+	userID := primitive.NewObjectID()
+
+	s.Run("Begin And Confirm", func() {
+		s.SetupTest()
+		user := &domain.User{Id: userID, Username: "enrolling", Role: domain.RoleUser}
+		s.mockUserRepo.GetUserByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			return user, nil
+		}
+		s.mockUserRepo.UpdateUserFunc = func(c context.Context, id primitive.ObjectID, u *domain.User) (*domain.User, error) {
+			user = u
+			return u, nil
+		}
+		s.mockTOTP.GenerateSecretFunc = func(c context.Context) (string, error) { return "NEWSECRET", nil }
+		s.mockTOTP.ValidateFunc = func(c context.Context, code string, secret string) (int64, bool) {
+			return 1, code == "654321" && secret == "NEWSECRET"
+		}
+		s.mockPassService.HashFunc = func(c context.Context, password string) (string, error) { return "hash:" + password, nil }
+
+		enrollment, err := s.useCase.BeginTwoFactorEnrollment(s.ctx, userID.Hex())
+		s.Require().NoError(err)
+		s.Equal("NEWSECRET", enrollment.Secret)
+		s.Contains(enrollment.ProvisioningURI, "secret=NEWSECRET")
+		s.Equal("enc:NEWSECRET", user.PendingTwoFactorSecret, "Pending secret must be stored encrypted")
+		s.False(user.TwoFactorEnabled, "Two-factor must not be active before confirmation")
+
+		_, err = s.useCase.ConfirmTwoFactorEnrollment(s.ctx, userID.Hex(), "111111")
+		s.ErrorIs(err, domain.ErrInvalidTwoFactorCode)
+
+		codes, err := s.useCase.ConfirmTwoFactorEnrollment(s.ctx, userID.Hex(), "654321")
+		s.Require().NoError(err)
+		s.Len(codes, 10)
+		s.True(user.TwoFactorEnabled)
+		s.Equal("enc:NEWSECRET", user.TwoFactorSecret)
+		s.Empty(user.PendingTwoFactorSecret)
+		s.Len(user.RecoveryCodeHashes, 10)
+		s.NotContains(user.RecoveryCodeHashes, codes[0], "Recovery codes must be stored hashed")
+	})
+
+	s.Run("Failure - Confirm Without Begin", func() {
+		s.SetupTest()
+		s.mockUserRepo.GetUserByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			return &domain.User{Id: userID}, nil
+		}
+
+		_, err := s.useCase.ConfirmTwoFactorEnrollment(s.ctx, userID.Hex(), "654321")
+
+		s.ErrorIs(err, domain.ErrTwoFactorEnrollmentMissing)
+	})
+
+	s.Run("Failure - Already Enabled", func() {
+		s.SetupTest()
+		s.mockUserRepo.GetUserByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			return &domain.User{Id: userID, TwoFactorEnabled: true}, nil
+		}
+
+		_, err := s.useCase.BeginTwoFactorEnrollment(s.ctx, userID.Hex())
+
+		s.ErrorIs(err, domain.ErrTwoFactorAlreadyEnabled)
+	})
+
+	s.Run("Disable", func() {
+		s.SetupTest()
+		user := &domain.User{Id: userID, Role: domain.RoleUser, TwoFactorEnabled: true, TwoFactorSecret: "enc:S", RecoveryCodeHashes: []string{"h"}}
+		s.mockUserRepo.GetUserByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			return user, nil
+		}
+		s.mockUserRepo.UpdateUserFunc = func(c context.Context, id primitive.ObjectID, u *domain.User) (*domain.User, error) {
+			return u, nil
+		}
+		s.mockTOTP.ValidateFunc = func(c context.Context, code string, secret string) (int64, bool) { return 1, code == "123456" }
+
+		err := s.useCase.DisableTwoFactor(s.ctx, userID.Hex(), "123456")
+
+		s.Require().NoError(err)
+		s.False(user.TwoFactorEnabled)
+		s.Empty(user.TwoFactorSecret)
+		s.Empty(user.RecoveryCodeHashes)
+	})
+
+	s.Run("Failure - Disable Required By Policy", func() {
+		s.SetupTest()
+		s.useCase.RequireTwoFactorFor(domain.PermUsersManage)
+		s.mockUserRepo.GetUserByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			return &domain.User{Id: userID, Role: domain.RoleAdmin, TwoFactorEnabled: true}, nil
+		}
+
+		err := s.useCase.DisableTwoFactor(s.ctx, userID.Hex(), "123456")
+
+		s.ErrorIs(err, domain.ErrTwoFactorRequired)
+	})
+}
+
+// TestAssignRole covers changing a user's role.
+func (s *UserUseCaseSuite) TestAssignRole() {
+	userID := primitive.NewObjectID()
+
+	s.Run("Success", func() {
+		s.SetupTest()
+		s.mockRoleRepo.Roles["Editor"] = &domain.RoleDefinition{Name: "Editor", Permissions: []domain.Permission{domain.PermTasksUpdate}}
+		s.mockUserRepo.GetUserByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			return &domain.User{Id: userID, Username: "promoted", Role: domain.RoleUser}, nil
+		}
+		s.mockUserRepo.UpdateUserFunc = func(c context.Context, id primitive.ObjectID, u *domain.User) (*domain.User, error) {
+			return u, nil
+		}
+
+		user, err := s.useCase.AssignRole(s.ctx, userID.Hex(), "Editor")
+
+		s.Require().NoError(err)
+		s.Equal(domain.UserRole("Editor"), user.Role)
+	})
+
+	s.Run("Failure - Undefined Role", func() {
+		s.SetupTest()
+
+		_, err := s.useCase.AssignRole(s.ctx, userID.Hex(), "Ghost")
+
+		s.ErrorIs(err, domain.ErrValidationFailed)
+	})
+}
+
+func (s *UserUseCaseSuite) TestSetTimeZone() {
+	userID := primitive.NewObjectID()
+
+	s.Run("Success", func() {
+		s.SetupTest()
+		var saved *domain.User
+		s.mockUserRepo.GetUserByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+			return &domain.User{Id: userID, Username: "traveller", TimeZone: "Europe/Berlin"}, nil
+		}
+		s.mockUserRepo.UpdateUserFunc = func(c context.Context, id primitive.ObjectID, u *domain.User) (*domain.User, error) {
+			saved = u
+			return u, nil
+		}
+
+		s.Require().NoError(s.useCase.SetTimeZone(s.ctx, userID.Hex(), "America/New_York"))
+		s.Equal("America/New_York", saved.TimeZone)
+
+		s.Require().NoError(s.useCase.SetTimeZone(s.ctx, userID.Hex(), ""))
+		s.Empty(saved.TimeZone, "an empty zone resets to UTC")
+	})
+
+	s.Run("Failure - Unknown Zone", func() {
+		s.SetupTest()
+
+		err := s.useCase.SetTimeZone(s.ctx, userID.Hex(), "Europe/Atlantis")
+
+		s.ErrorIs(err, domain.ErrValidationFailed)
+	})
+}
+
+func (s *UserUseCaseSuite) TestValidateSession() {
+	userID := primitive.NewObjectID()
+	s.mockUserRepo.GetUserByIdFunc = func(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+		organizationID, _ := domain.OrganizationFromContext(c)
+		if id != userID || organizationID != s.defaultOrg.Id {
+			return nil, domain.ErrUserNotFound
+		}
+		return &domain.User{Id: userID, OrganizationID: s.defaultOrg.Id}, nil
+	}
+
+	s.NoError(s.useCase.ValidateSession(s.ctx, &domain.Claims{UserId: userID.Hex(), OrganizationID: s.defaultOrg.Id.Hex()}))
+	s.ErrorIs(s.useCase.ValidateSession(s.ctx, &domain.Claims{UserId: primitive.NewObjectID().Hex(), OrganizationID: s.defaultOrg.Id.Hex()}),
+		domain.ErrSessionRevoked, "Tokens of removed users are revoked")
+	s.ErrorIs(s.useCase.ValidateSession(s.ctx, &domain.Claims{UserId: userID.Hex(), OrganizationID: primitive.NewObjectID().Hex()}),
+		domain.ErrSessionRevoked, "The user is looked up in the token's organization")
+}
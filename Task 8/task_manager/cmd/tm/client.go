@@ -0,0 +1,107 @@
+package main
+
+import (
+	infrastructure "A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// apiClient calls the task manager REST API.
+type apiClient struct {
+	server   string
+	token    string
+	timeZone string
+	http     *http.Client
+}
+
+func newAPIClient(server, token, timeZone string) *apiClient {
+	return &apiClient{
+		server:   strings.TrimSuffix(server, "/"),
+		token:    token,
+		timeZone: timeZone,
+		http:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// apiError is an error response of the API.
+type apiError struct {
+	status  int
+	problem infrastructure.Problem
+}
+
+func (e *apiError) Error() string {
+	message := e.problem.Detail
+	if message == "" {
+		message = e.problem.Title
+	}
+	if message == "" {
+		message = http.StatusText(e.status)
+	}
+	if e.problem.Code != "" {
+		message += " (" + string(e.problem.Code) + ")"
+	}
+	for _, violation := range e.problem.Errors {
+		message += "\n  " + violation.Field + ": " + violation.Message
+	}
+	if e.status == http.StatusUnauthorized {
+		message += "\nRun \"tm login\" to sign in."
+	}
+	return message
+}
+
+// do sends a request with body encoded as JSON, if it is not nil, and decodes the
+// response into out, if it is not nil.
+func (c *apiClient) do(method, path string, query url.Values, body, out any) error {
+	target := c.server + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, target, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.timeZone != "" {
+		req.Header.Set(infrastructure.TimeZoneHeader, c.timeZone)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach %s: %w", c.server, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		apiErr := &apiError{status: resp.StatusCode}
+		// Responses that are not problems, e.g. from a proxy, fall back to the status text.
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr.problem)
+		return apiErr
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("unexpected response from %s %s: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	controllers "A2SV_ProjectPhase/Task8/TaskManager/Delivery/controllers"
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// stringList is a flag that may be repeated and also accepts comma-separated values.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// prompt reads one line from stdin after printing label to stderr.
+func (e *env) prompt(input *bufio.Reader, label string) (string, error) {
+	fmt.Fprint(e.stderr, label)
+	line, err := input.ReadString('\n')
+	if line = strings.TrimSpace(line); line == "" && err != nil {
+		return "", fmt.Errorf("no %s given", strings.ToLower(strings.TrimSuffix(label, ": ")))
+	}
+	return line, nil
+}
+
+// promptPassword reads a password without echoing it when stdin is a terminal. Input
+// from a pipe or file is read line by line, as by prompt.
+func (e *env) promptPassword(input *bufio.Reader, label string) (string, error) {
+	file, ok := e.stdin.(*os.File)
+	if !ok || !term.IsTerminal(int(file.Fd())) {
+		return e.prompt(input, label)
+	}
+	fmt.Fprint(e.stderr, label)
+	password, err := term.ReadPassword(int(file.Fd()))
+	// The newline typed by the user is not echoed either.
+	fmt.Fprintln(e.stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the password: %w", err)
+	}
+	if len(password) == 0 {
+		return "", errors.New("no password given")
+	}
+	return string(password), nil
+}
+
+// parseDueDate accepts the same formats as the API: YYYY-MM-DD or RFC 3339.
+func parseDueDate(value string) (domain.DueDate, error) {
+	var due domain.DueDate
+	if err := json.Unmarshal([]byte(strconv.Quote(value)), &due); err != nil {
+		return due, fmt.Errorf("invalid date %q: use YYYY-MM-DD or RFC 3339", value)
+	}
+	return due, nil
+}
+
+// --- Account commands ---
+
+func (e *env) login(args []string) error {
+	fs := e.newFlagSet("login")
+	username := fs.String("username", "", "user name (prompted for if omitted)")
+	password := fs.String("password", "", "password (read from stdin if omitted)")
+	code := fs.String("code", "", "two-factor code or recovery code (prompted for if needed)")
+	organization := fs.String("organization", "", "slug of the organization to sign in to (default organization if omitted)")
+	if _, err := e.parse(fs, args, 0, "[--organization SLUG] [--username NAME] [--password PASSWORD] [--code CODE]"); err != nil {
+		return err
+	}
+	input := bufio.NewReader(e.stdin)
+	var err error
+	if *username == "" {
+		if *username, err = e.prompt(input, "Username: "); err != nil {
+			return err
+		}
+	}
+	if *password == "" {
+		if *password, err = e.promptPassword(input, "Password: "); err != nil {
+			return err
+		}
+	}
+
+	client := newAPIClient(e.serverURL(), "", e.timeZone)
+	var result struct {
+		Token                       string `json:"token"`
+		TwoFactorRequired           bool   `json:"two_factor_required"`
+		TwoFactorEnrollmentRequired bool   `json:"two_factor_enrollment_required"`
+		ChallengeToken              string `json:"challenge_token"`
+	}
+	request := controllers.UserRegisterLogin{Username: *username, Password: *password, Organization: *organization}
+	if err := client.do(http.MethodPost, "/user/login", nil, request, &result); err != nil {
+		return err
+	}
+	if result.TwoFactorEnrollmentRequired {
+		return errors.New("your role requires two-factor authentication; enroll through POST /user/2fa/enroll before using tm")
+	}
+	if result.TwoFactorRequired {
+		if *code == "" {
+			if *code, err = e.prompt(input, "Two-factor code: "); err != nil {
+				return err
+			}
+		}
+		request := controllers.TwoFactorLoginRequest{ChallengeToken: result.ChallengeToken, Code: *code}
+		if err := client.do(http.MethodPost, "/user/login/2fa", nil, request, &result); err != nil {
+			return err
+		}
+	}
+
+	e.cfg.Server = e.serverURL()
+	e.cfg.Token = result.Token
+	if err := saveConfig(e.cfgPath, e.cfg); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "Logged in to %s as %s.\n", e.cfg.Server, *username)
+	return nil
+}
+
+func (e *env) logout(args []string) error {
+	if _, err := e.parse(e.newFlagSet("logout"), args, 0, ""); err != nil {
+		return err
+	}
+	e.cfg.Token = ""
+	return saveConfig(e.cfgPath, e.cfg)
+}
+
+func (e *env) register(args []string) error {
+	fs := e.newFlagSet("users register")
+	username := fs.String("username", "", "user name (prompted for if omitted)")
+	password := fs.String("password", "", "password (read from stdin if omitted)")
+	invitation := fs.String("invitation", "", "invitation token, to join the organization that issued it")
+	if _, err := e.parse(fs, args, 0, "[--username NAME] [--password PASSWORD] [--invitation TOKEN]"); err != nil {
+		return err
+	}
+	input := bufio.NewReader(e.stdin)
+	var err error
+	if *username == "" {
+		if *username, err = e.prompt(input, "Username: "); err != nil {
+			return err
+		}
+	}
+	if *password == "" {
+		if *password, err = e.promptPassword(input, "Password: "); err != nil {
+			return err
+		}
+	}
+	var user userSummary
+	request := controllers.UserRegisterLogin{Username: *username, Password: *password, Invitation: *invitation}
+	if err := e.client().do(http.MethodPost, "/user/register", nil, request, &user); err != nil {
+		return err
+	}
+	return e.printUser(&user)
+}
+
+func (e *env) setTimeZone(args []string) error {
+	positional, err := e.parse(e.newFlagSet("users timezone"), args, 1, "<zone>")
+	if err != nil {
+		return err
+	}
+	return e.client().do(http.MethodPut, "/user/timezone", nil, controllers.SetTimeZoneRequest{TimeZone: positional[0]}, nil)
+}
+
+func (e *env) setRole(args []string) error {
+	positional, err := e.parse(e.newFlagSet("users set-role"), args, 2, "<user-id> <role>")
+	if err != nil {
+		return err
+	}
+	var user userSummary
+	request := controllers.AssignRoleRequest{Role: domain.UserRole(positional[1])}
+	if err := e.client().do(http.MethodPut, "/users/"+url.PathEscape(positional[0])+"/role", nil, request, &user); err != nil {
+		return err
+	}
+	return e.printUser(&user)
+}
+
+// --- Task commands ---
+
+// listTasks reads the export endpoint, which takes the filters and returns every
+// matching task in one response.
+func (e *env) listTasks(args []string) error {
+	fs := e.newFlagSet("tasks list")
+	var statuses stringList
+	fs.Var(&statuses, "status", "only tasks with this status; repeat or separate with commas")
+	project := fs.String("project", "", "only tasks of this project")
+	dueBefore := fs.String("due-before", "", "only tasks due before this date")
+	dueAfter := fs.String("due-after", "", "only tasks due after this date")
+	if _, err := e.parse(fs, args, 0, "[--status STATUS] [--project ID] [--due-before DATE] [--due-after DATE]"); err != nil {
+		return err
+	}
+
+	query := url.Values{"format": {"json"}}
+	for _, status := range statuses {
+		query.Add("status", status)
+	}
+	if *project != "" {
+		query.Set("project_id", *project)
+	}
+	for name, value := range map[string]string{"due_before": *dueBefore, "due_after": *dueAfter} {
+		if value == "" {
+			continue
+		}
+		due, err := parseDueDate(value)
+		if err != nil {
+			return err
+		}
+		query.Set(name, due.Time.Format(time.RFC3339))
+	}
+
+	var tasks []domain.Task
+	if err := e.client().do(http.MethodGet, "/tasks/export", query, nil, &tasks); err != nil {
+		return err
+	}
+	return e.printTasks(tasks)
+}
+
+func (e *env) getTask(args []string) error {
+	positional, err := e.parse(e.newFlagSet("tasks get"), args, 1, "<id>")
+	if err != nil {
+		return err
+	}
+	var task domain.Task
+	if err := e.client().do(http.MethodGet, "/tasks/"+url.PathEscape(positional[0]), nil, nil, &task); err != nil {
+		return err
+	}
+	return e.printTask(&task)
+}
+
+func (e *env) createTask(args []string) error {
+	fs := e.newFlagSet("tasks create")
+	title := fs.String("title", "", "title (required)")
+	description := fs.String("description", "", "description")
+	dueValue := fs.String("due", "", "due date, YYYY-MM-DD or RFC 3339 (required)")
+	status := fs.String("status", string(domain.Pending), "status")
+	project := fs.String("project", "", "create the task in this project")
+	if _, err := e.parse(fs, args, 0, "--title TITLE --due DATE [--description TEXT] [--status STATUS] [--project ID]"); err != nil {
+		return err
+	}
+	if *title == "" || *dueValue == "" {
+		fmt.Fprintln(e.stderr, "tm: tasks create needs --title and --due")
+		return errUsage
+	}
+	due, err := parseDueDate(*dueValue)
+	if err != nil {
+		return err
+	}
+
+	path := "/tasks/"
+	if *project != "" {
+		path = "/projects/" + url.PathEscape(*project) + "/tasks"
+	}
+	request := controllers.CreateTaskRequest{Title: *title, Description: *description, DueDate: &due, Status: domain.TaskStatus(*status)}
+	var task domain.Task
+	if err := e.client().do(http.MethodPost, path, nil, request, &task); err != nil {
+		return err
+	}
+	return e.printTask(&task)
+}
+
+// updateTask sends a merge patch with only the fields whose flags were given, so
+// --description "" clears the description while leaving it out keeps it.
+func (e *env) updateTask(args []string) error {
+	fs := e.newFlagSet("tasks update")
+	title := fs.String("title", "", "new title")
+	description := fs.String("description", "", "new description")
+	dueValue := fs.String("due", "", "new due date, YYYY-MM-DD or RFC 3339")
+	status := fs.String("status", "", "new status")
+	var tags stringList
+	fs.Var(&tags, "tags", "replace the tags; repeat or separate with commas, \"\" removes them")
+	positional, err := e.parse(fs, args, 1, "<id> [--title TITLE] [--description TEXT] [--due DATE] [--status STATUS] [--tags TAGS]")
+	if err != nil {
+		return err
+	}
+
+	var patch domain.TaskPatch
+	changed := false
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "title":
+			patch.Title = domain.Present(*title)
+		case "description":
+			patch.Description = domain.Present(*description)
+		case "status":
+			patch.Status = domain.Present(domain.TaskStatus(*status))
+		case "tags":
+			patch.Tags = domain.Present(append([]string{}, tags...))
+		case "due":
+			var due domain.DueDate
+			if due, err = parseDueDate(*dueValue); err == nil {
+				patch.DueDate = domain.Present(due)
+			}
+		default:
+			return
+		}
+		changed = true
+	})
+	if err != nil {
+		return err
+	}
+	if !changed {
+		fmt.Fprintln(e.stderr, "tm: tasks update needs at least one field to change")
+		return errUsage
+	}
+
+	// The API treats a plain JSON body as a merge patch.
+	var task domain.Task
+	if err := e.client().do(http.MethodPatch, "/tasks/"+url.PathEscape(positional[0]), nil, patch, &task); err != nil {
+		return err
+	}
+	return e.printTask(&task)
+}
+
+func (e *env) deleteTask(args []string) error {
+	positional, err := e.parse(e.newFlagSet("tasks delete"), args, 1, "<id>")
+	if err != nil {
+		return err
+	}
+	return e.client().do(http.MethodDelete, "/tasks/"+url.PathEscape(positional[0]), nil, nil, nil)
+}
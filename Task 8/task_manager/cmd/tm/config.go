@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// defaultServer is the address of a locally running API server.
+const defaultServer = "http://localhost:8080"
+
+// config is what tm remembers between runs. The file holds a bearer token, so it is
+// only readable by its owner.
+type config struct {
+	Server string `json:"server,omitempty"`
+	Token  string `json:"token,omitempty"`
+}
+
+// configPath returns the config file to use: the --config flag, then $TM_CONFIG,
+// then tm/config.json in the user's config directory.
+func configPath(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if path := os.Getenv("TM_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot find a config directory, use --config: %w", err)
+	}
+	return filepath.Join(dir, "tm", "config.json"), nil
+}
+
+// loadConfig reads the config file. A missing file is an empty config.
+func loadConfig(path string) (*config, error) {
+	cfg := &config{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("config file %s is not valid JSON: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func saveConfig(path string, cfg *config) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
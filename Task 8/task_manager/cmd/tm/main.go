@@ -0,0 +1,187 @@
+// Command tm is a command-line client for the task manager API.
+//
+// Usage:
+//
+//	tm login --organization acme --username alice
+//	tm tasks list --status Pending,"In progress" --due-before 2025-01-31
+//	tm tasks create --title "Write report" --due 2025-01-31
+//	tm tasks update <id> --status Done
+//	tm users set-role <id> Admin
+//
+// The token from tm login is stored in a config file (see configPath) and sent with
+// every later request.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+const usage = `Usage: tm <command> [flags]
+
+Commands:
+  login                      sign in and store the token (--organization for other organizations)
+  logout                     forget the stored token
+  tasks list                 list tasks (--status, --project, --due-before, --due-after)
+  tasks get <id>             show a task
+  tasks create               create a task (--title, --due, --description, --status, --project)
+  tasks update <id>          change the given fields of a task
+  tasks delete <id>          delete a task
+  users register             create an account (--invitation to join another organization)
+  users timezone <zone>      set your time zone, e.g. Europe/Berlin
+  users set-role <id> <role> change the role of a user (admins only)
+
+Flags accepted by every command:
+  --server URL        API address (default $TM_SERVER, the stored server or ` + defaultServer + `)
+  --config FILE       config file (default $TM_CONFIG or tm/config.json in the user config directory)
+  -o, --output FMT    table or json (default table)
+  --time-zone ZONE    IANA time zone sent with requests
+`
+
+// errUsage reports a command line that cannot be run; the message has already been printed.
+var errUsage = errors.New("usage")
+
+// env holds what every command needs: the streams, the global flags and, once
+// loaded, the config.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	server     string
+	configFile string
+	output     string
+	timeZone   string
+
+	cfgPath string
+	cfg     *config
+}
+
+// run executes tm with args and returns its exit status: 0 on success, 1 when a
+// request fails and 2 for usage errors.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	e := &env{stdin: stdin, stdout: stdout, stderr: stderr}
+	err := e.dispatch(args)
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+		return 2
+	default:
+		fmt.Fprintln(stderr, "tm:", err)
+		return 1
+	}
+}
+
+func (e *env) dispatch(args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(e.stderr, usage)
+		return errUsage
+	}
+	commands := map[string]func([]string) error{
+		"login":  e.login,
+		"logout": e.logout,
+		"tasks":  e.subcommands("tasks", map[string]func([]string) error{"list": e.listTasks, "get": e.getTask, "create": e.createTask, "update": e.updateTask, "delete": e.deleteTask}),
+		"users":  e.subcommands("users", map[string]func([]string) error{"register": e.register, "timezone": e.setTimeZone, "set-role": e.setRole}),
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		return e.usageError("unknown command %q", args[0])
+	}
+	return command(args[1:])
+}
+
+func (e *env) subcommands(group string, commands map[string]func([]string) error) func([]string) error {
+	return func(args []string) error {
+		if len(args) == 0 {
+			return e.usageError("tm %s needs a subcommand", group)
+		}
+		command, ok := commands[args[0]]
+		if !ok {
+			return e.usageError("unknown command \"%s %s\"", group, args[0])
+		}
+		return command(args[1:])
+	}
+}
+
+func (e *env) usageError(format string, args ...any) error {
+	fmt.Fprintf(e.stderr, "tm: "+format+"\n\n", args...)
+	fmt.Fprint(e.stderr, usage)
+	return errUsage
+}
+
+// newFlagSet returns a flag set for a command with the global flags already defined.
+func (e *env) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("tm "+name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&e.server, "server", "", "API address")
+	fs.StringVar(&e.configFile, "config", "", "config file")
+	fs.StringVar(&e.output, "output", "table", "output format: table or json")
+	fs.StringVar(&e.output, "o", "table", "shorthand for --output")
+	fs.StringVar(&e.timeZone, "time-zone", "", "IANA time zone sent with requests")
+	return fs
+}
+
+// parse parses flags that may appear before, after or between the positional
+// arguments and checks that exactly want positional arguments were given.
+func (e *env) parse(fs *flag.FlagSet, args []string, want int, names string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, errUsage
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if len(positional) != want {
+		fmt.Fprintf(e.stderr, "Usage: %s %s\n", fs.Name(), names)
+		return nil, errUsage
+	}
+	if e.output != "table" && e.output != "json" {
+		fmt.Fprintf(e.stderr, "tm: unknown output format %q (expected table or json)\n", e.output)
+		return nil, errUsage
+	}
+	if err := e.loadConfig(); err != nil {
+		return nil, err
+	}
+	return positional, nil
+}
+
+func (e *env) loadConfig() error {
+	path, err := configPath(e.configFile)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	e.cfgPath, e.cfg = path, cfg
+	return nil
+}
+
+// serverURL picks the API address: --server, then $TM_SERVER, then the config.
+func (e *env) serverURL() string {
+	for _, server := range []string{e.server, os.Getenv("TM_SERVER"), e.cfg.Server} {
+		if server != "" {
+			return strings.TrimSuffix(server, "/")
+		}
+	}
+	return defaultServer
+}
+
+func (e *env) client() *apiClient {
+	return newAPIClient(e.serverURL(), e.cfg.Token, e.timeZone)
+}
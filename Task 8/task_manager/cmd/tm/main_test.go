@@ -0,0 +1,405 @@
+package main
+
+import (
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/controllers"
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/routers"
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	infrastructure "A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
+	"A2SV_ProjectPhase/Task8/TaskManager/Repositories"
+	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// --- In-memory repositories ---
+
+type memoryUserRepository struct {
+	mu    sync.Mutex
+	users map[primitive.ObjectID]*domain.User
+}
+
+func (r *memoryUserRepository) CreateUser(c context.Context, user *domain.User) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user.Id = primitive.NewObjectID()
+	r.users[user.Id] = user
+	return user, nil
+}
+
+func (r *memoryUserRepository) GetUserByUsername(c context.Context, username string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *memoryUserRepository) GetUserById(c context.Context, id primitive.ObjectID) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *memoryUserRepository) GetUserByCalendarTokenHash(c context.Context, tokenHash string) (*domain.User, error) {
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *memoryUserRepository) UpdateUser(c context.Context, id primitive.ObjectID, user *domain.User) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[id] = user
+	return user, nil
+}
+
+func (r *memoryUserRepository) GetAllUsers(c context.Context) ([]*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	users := []*domain.User{}
+	for _, user := range r.users {
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func (r *memoryUserRepository) DeleteUser(c context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[id]; !ok {
+		return domain.ErrUserNotFound
+	}
+	delete(r.users, id)
+	return nil
+}
+
+func (r *memoryUserRepository) RecordTwoFactorStep(c context.Context, id primitive.ObjectID, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return false, domain.ErrUserNotFound
+	}
+	if step <= user.LastTwoFactorStep {
+		return false, nil
+	}
+	user.LastTwoFactorStep = step
+	return true, nil
+}
+
+func (r *memoryUserRepository) ConsumeRecoveryCode(c context.Context, id primitive.ObjectID, hash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return false, domain.ErrUserNotFound
+	}
+	i := slices.Index(user.RecoveryCodeHashes, hash)
+	if i < 0 {
+		return false, nil
+	}
+	user.RecoveryCodeHashes = slices.Delete(user.RecoveryCodeHashes, i, i+1)
+	return true, nil
+}
+
+func (r *memoryUserRepository) RecordTwoFactorAttempt(c context.Context, id primitive.ObjectID, at time.Time) (int, *time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return 0, nil, domain.ErrUserNotFound
+	}
+	failures, lastFailure := user.TwoFactorFailures, user.LastTwoFactorFailure
+	user.TwoFactorFailures++
+	user.LastTwoFactorFailure = &at
+	return failures, lastFailure, nil
+}
+
+func (r *memoryUserRepository) ResetTwoFactorFailures(c context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user, ok := r.users[id]; ok {
+		user.TwoFactorFailures, user.LastTwoFactorFailure = 0, nil
+	}
+	return nil
+}
+
+// memoryOrganizationRepository holds the default organization only.
+type memoryOrganizationRepository struct {
+	organization *domain.Organization
+}
+
+func (r memoryOrganizationRepository) CreateOrganization(c context.Context, organization *domain.Organization) (*domain.Organization, error) {
+	return nil, domain.ErrOrganizationSlugTaken
+}
+
+func (r memoryOrganizationRepository) GetOrganizationById(c context.Context, id primitive.ObjectID) (*domain.Organization, error) {
+	if id != r.organization.Id {
+		return nil, domain.ErrOrganizationNotFound
+	}
+	return r.organization, nil
+}
+
+func (r memoryOrganizationRepository) GetOrganizationBySlug(c context.Context, slug string) (*domain.Organization, error) {
+	if slug != r.organization.Slug {
+		return nil, domain.ErrOrganizationNotFound
+	}
+	return r.organization, nil
+}
+
+type memoryRoleRepository struct{}
+
+func (memoryRoleRepository) GetRole(c context.Context, name domain.UserRole) (*domain.RoleDefinition, error) {
+	for _, role := range domain.DefaultRoleDefinitions() {
+		if role.Name == name {
+			return role, nil
+		}
+	}
+	return nil, domain.ErrRoleNotFound
+}
+
+func (memoryRoleRepository) GetAllRoles(c context.Context) ([]*domain.RoleDefinition, error) {
+	return domain.DefaultRoleDefinitions(), nil
+}
+
+func (memoryRoleRepository) UpsertRole(c context.Context, role *domain.RoleDefinition) (*domain.RoleDefinition, error) {
+	return role, nil
+}
+
+//===========================================================================
+// CLI End-to-End Test Suite
+//===========================================================================
+
+// CLISuite runs tm against the real gin routes, controllers and use cases, backed by
+// in-memory repositories.
+type CLISuite struct {
+	suite.Suite
+	server     *httptest.Server
+	users      *memoryUserRepository
+	configFile string
+	dueDate    string
+}
+
+func TestCLISuite(t *testing.T) {
+	suite.Run(t, new(CLISuite))
+}
+
+func (s *CLISuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	passwords := infrastructure.NewBcryptPasswordService(bcrypt.MinCost)
+	jwtService := infrastructure.NewJwtService("cli-test-secret")
+	s.users = &memoryUserRepository{users: make(map[primitive.ObjectID]*domain.User)}
+	organization := &domain.Organization{Id: primitive.NewObjectID(), Name: "Default", Slug: domain.DefaultOrganizationSlug}
+	userUC := usecases.NewUserUseCase(s.users, memoryRoleRepository{}, memoryOrganizationRepository{organization}, jwtService, passwords, nil, nil)
+	taskUC := usecases.NewTaskUseCase(repositories.NewInMemoryTaskRepository(), repositories.NewInMemoryProjectRepository())
+
+	hash, err := passwords.Hash(context.Background(), "admin-password")
+	s.Require().NoError(err)
+	_, err = s.users.CreateUser(context.Background(), &domain.User{Username: "root", PasswordHash: hash, Role: domain.RoleAdmin, OrganizationID: organization.Id})
+	s.Require().NoError(err)
+
+	router := gin.New()
+	auth := infrastructure.NewAuthMiddleware(jwtService, nil, nil)
+	userController := controllers.NewUserController(userUC, nil)
+	routers.SetupCommonMiddleware(router)
+	routers.SetupUserRouters(router, userController, auth)
+	routers.SetupTaskRoutes(router, controllers.NewTaskController(taskUC), auth, nil)
+	routers.SetupRoleRoutes(router, controllers.NewRoleController(usecases.NewRoleUseCase(memoryRoleRepository{})), userController, auth)
+	s.server = httptest.NewServer(router)
+
+	s.configFile = filepath.Join(s.T().TempDir(), "tm", "config.json")
+	s.dueDate = time.Now().AddDate(0, 0, 7).Format(time.DateOnly)
+}
+
+func (s *CLISuite) TearDownTest() {
+	s.server.Close()
+}
+
+// tm runs the CLI with the test server and config file and returns the exit status
+// and both outputs.
+func (s *CLISuite) tm(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	args = append(args, "--server", s.server.URL, "--config", s.configFile)
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// must runs the CLI and fails the test unless it succeeds.
+func (s *CLISuite) must(stdin string, args ...string) string {
+	code, stdout, stderr := s.tm(stdin, args...)
+	s.Require().Equal(0, code, "tm %s: %s", strings.Join(args, " "), stderr)
+	return stdout
+}
+
+func (s *CLISuite) login(username, password string) {
+	s.must(password+"\n", "login", "--username", username)
+}
+
+func (s *CLISuite) createTask(args ...string) domain.Task {
+	var task domain.Task
+	out := s.must("", append([]string{"tasks", "create", "-o", "json", "--due", s.dueDate}, args...)...)
+	s.Require().NoError(json.Unmarshal([]byte(out), &task))
+	return task
+}
+
+func (s *CLISuite) TestLoginStoresTokenInConfig() {
+	s.login("root", "admin-password")
+
+	info, err := os.Stat(s.configFile)
+	s.Require().NoError(err)
+	s.Equal(os.FileMode(0o600), info.Mode().Perm())
+	cfg, err := loadConfig(s.configFile)
+	s.Require().NoError(err)
+	s.Equal(s.server.URL, cfg.Server)
+	s.NotEmpty(cfg.Token)
+
+	s.must("", "logout")
+	cfg, err = loadConfig(s.configFile)
+	s.Require().NoError(err)
+	s.Empty(cfg.Token)
+	s.Equal(s.server.URL, cfg.Server, "logging out keeps the server")
+}
+
+func (s *CLISuite) TestLoginWithWrongPasswordFails() {
+	code, _, stderr := s.tm("wrong\n", "login", "--username", "root")
+
+	s.Equal(1, code)
+	s.Contains(stderr, "invalid_credentials")
+	_, err := os.Stat(s.configFile)
+	s.True(os.IsNotExist(err), "no config is written for a failed login")
+}
+
+func (s *CLISuite) TestLoginNamesOrganization() {
+	s.must("admin-password\n", "login", "--organization", domain.DefaultOrganizationSlug, "--username", "root")
+
+	code, _, stderr := s.tm("admin-password\n", "login", "--organization", "acme", "--username", "root")
+	s.Equal(1, code)
+	s.Contains(stderr, "invalid_credentials", "unknown organizations look like wrong passwords")
+}
+
+func (s *CLISuite) TestTaskLifecycle() {
+	s.login("root", "admin-password")
+	task := s.createTask("--title", "Write report", "--description", "Quarterly numbers")
+	s.Equal("Write report", task.Title)
+	s.Equal(domain.Pending, task.Status)
+
+	out := s.must("", "tasks", "get", task.Id.Hex())
+	s.Contains(out, "ID")
+	s.Contains(out, "TITLE")
+	s.Contains(out, "Write report")
+	s.Contains(out, s.dueDate)
+
+	out = s.must("", "tasks", "update", task.Id.Hex(), "--status", "In progress", "--description", "", "--tags", "reports,q3", "-o", "json")
+	var updated domain.Task
+	s.Require().NoError(json.Unmarshal([]byte(out), &updated))
+	s.Equal(domain.InProgress, updated.Status)
+	s.Empty(updated.Description, "an empty flag clears the field")
+	s.Equal("Write report", updated.Title, "fields without flags are unchanged")
+	s.Equal([]string{"reports", "q3"}, updated.Tags)
+
+	s.must("", "tasks", "delete", task.Id.Hex())
+	code, _, stderr := s.tm("", "tasks", "get", task.Id.Hex())
+	s.Equal(1, code)
+	s.Contains(stderr, "task_not_found")
+}
+
+func (s *CLISuite) TestListFilters() {
+	s.login("root", "admin-password")
+	pending := s.createTask("--title", "Pending task")
+	done := s.createTask("--title", "Done task", "--status", "Done")
+	later := s.createTask("--title", "Later task", "--due", time.Now().AddDate(0, 0, 30).Format(time.DateOnly))
+
+	list := func(args ...string) []string {
+		var tasks []domain.Task
+		s.Require().NoError(json.Unmarshal([]byte(s.must("", append([]string{"tasks", "list", "-o", "json"}, args...)...)), &tasks))
+		var titles []string
+		for _, task := range tasks {
+			titles = append(titles, task.Title)
+		}
+		return titles
+	}
+
+	s.ElementsMatch([]string{pending.Title, done.Title, later.Title}, list())
+	s.ElementsMatch([]string{done.Title}, list("--status", "Done"))
+	s.ElementsMatch([]string{pending.Title, done.Title, later.Title}, list("--status", "Pending", "--status", "Done"))
+	s.ElementsMatch([]string{pending.Title, later.Title}, list("--status", "Pending,In progress"))
+	s.ElementsMatch([]string{later.Title}, list("--due-after", time.Now().AddDate(0, 0, 14).Format(time.DateOnly)))
+	s.ElementsMatch([]string{pending.Title, done.Title}, list("--due-before", time.Now().AddDate(0, 0, 14).Format(time.DateOnly)))
+
+	out := s.must("", "tasks", "list")
+	s.Equal(4, strings.Count(out, "\n"), "a header and one line per task")
+	s.True(strings.HasPrefix(out, "ID "))
+}
+
+func (s *CLISuite) TestValidationErrorsListFields() {
+	s.login("root", "admin-password")
+
+	code, _, stderr := s.tm("", "tasks", "create", "--title", "Bad", "--due", s.dueDate, "--status", "Someday")
+
+	s.Equal(1, code)
+	s.Contains(stderr, "status:")
+}
+
+func (s *CLISuite) TestUsers() {
+	out := s.must("", "users", "register", "--username", "alice", "--password", "alice-password", "-o", "json")
+	var alice userSummary
+	s.Require().NoError(json.Unmarshal([]byte(out), &alice))
+	s.Equal(domain.RoleUser, alice.Role)
+
+	s.login("alice", "alice-password")
+	s.must("", "users", "timezone", "Europe/Berlin")
+	user, err := s.users.GetUserByUsername(context.Background(), "alice")
+	s.Require().NoError(err)
+	s.Equal("Europe/Berlin", user.TimeZone)
+
+	code, _, stderr := s.tm("", "tasks", "create", "--title", "Not allowed", "--due", s.dueDate)
+	s.Equal(1, code, "users may only read tasks")
+	s.Contains(stderr, "forbidden")
+
+	s.login("root", "admin-password")
+	out = s.must("", "users", "set-role", alice.ID, "Admin")
+	s.Contains(out, "alice")
+	s.Contains(out, "Admin")
+
+	s.login("alice", "alice-password")
+	s.createTask("--title", "Allowed now")
+}
+
+func (s *CLISuite) TestRequestsWithoutLoginExplainHowToSignIn() {
+	code, _, stderr := s.tm("", "tasks", "list")
+
+	s.Equal(1, code)
+	s.Contains(stderr, "tm login")
+}
+
+func (s *CLISuite) TestUsageErrors() {
+	for _, args := range [][]string{
+		{},
+		{"projects"},
+		{"tasks"},
+		{"tasks", "get"},
+		{"tasks", "update", primitive.NewObjectID().Hex()},
+		{"tasks", "list", "-o", "yaml"},
+	} {
+		code, _, _ := s.tm("", args...)
+		s.Equal(2, code, "tm %s", strings.Join(args, " "))
+	}
+}
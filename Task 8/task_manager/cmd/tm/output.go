@@ -0,0 +1,77 @@
+package main
+
+import (
+	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// userSummary is the user as returned by the register and role endpoints.
+type userSummary struct {
+	ID       string          `json:"id"`
+	Username string          `json:"username"`
+	Role     domain.UserRole `json:"role"`
+}
+
+func writeJSON(w io.Writer, value any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(value)
+}
+
+func writeTable(w io.Writer, header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func (e *env) printTasks(tasks []domain.Task) error {
+	if e.output == "json" {
+		return writeJSON(e.stdout, tasks)
+	}
+	rows := make([][]string, 0, len(tasks))
+	for _, task := range tasks {
+		project := ""
+		if !task.ProjectID.IsZero() {
+			project = task.ProjectID.Hex()
+		}
+		rows = append(rows, []string{
+			task.Id.Hex(),
+			task.Title,
+			string(task.Status),
+			formatDueDate(task.DueDate),
+			project,
+			strings.Join(task.Tags, ","),
+		})
+	}
+	return writeTable(e.stdout, []string{"ID", "TITLE", "STATUS", "DUE", "PROJECT", "TAGS"}, rows)
+}
+
+func (e *env) printTask(task *domain.Task) error {
+	if e.output == "json" {
+		return writeJSON(e.stdout, task)
+	}
+	return e.printTasks([]domain.Task{*task})
+}
+
+func (e *env) printUser(user *userSummary) error {
+	if e.output == "json" {
+		return writeJSON(e.stdout, user)
+	}
+	return writeTable(e.stdout, []string{"ID", "USERNAME", "ROLE"}, [][]string{{user.ID, user.Username, string(user.Role)}})
+}
+
+// formatDueDate shows date-only due dates without the midnight time.
+func formatDueDate(due time.Time) string {
+	if due.Hour() == 0 && due.Minute() == 0 && due.Second() == 0 && due.Nanosecond() == 0 {
+		return due.Format(time.DateOnly)
+	}
+	return due.Format(time.RFC3339)
+}
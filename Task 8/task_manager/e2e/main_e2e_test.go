@@ -2,6 +2,7 @@ package e2e_test
 
 import (
 	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/controllers"
+	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/graphqlapi"
 	"A2SV_ProjectPhase/Task8/TaskManager/Delivery/routers"
 	domain "A2SV_ProjectPhase/Task8/TaskManager/Domain"
 	infrastructure "A2SV_ProjectPhase/Task8/TaskManager/Infrastructure"
@@ -9,14 +10,19 @@ import (
 	usecases "A2SV_ProjectPhase/Task8/TaskManager/Usecases"
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -35,6 +41,18 @@ var (
 	testDBName      = "test_learning_phase"
 	userCol         = "user8"
 	taskCol         = "task8"
+	roleCol         = "role8"
+	accountCol      = "serviceaccount8"
+	apiKeyCol       = "apikey8"
+	projectCol      = "project8"
+	attachmentCol   = "attachment8"
+	revisionCol     = "revision8"
+	idempotencyCol  = "idempotency8"
+	organizationCol = "organization8"
+	invitationCol   = "invitation8"
+	notificationCol = "notification8"
+	// e2eOperatorToken is the operator token of the server under test.
+	e2eOperatorToken = "e2e-operator-token"
 )
 
 // TestMain controls the entire lifecycle for the e2e test package.
@@ -94,21 +112,108 @@ func setupApplication() *gin.Engine {
 	// Instantiate all layers with real implementations
 	passwordService := infrastructure.NewBcryptPasswordService(bcrypt.DefaultCost)
 	jwtService := infrastructure.NewJwtService(jwtSecret)
+	totpService := infrastructure.NewTOTPService("TaskManagerE2E")
+	secretCipher, err := infrastructure.NewAESSecretCipher(jwtSecret)
+	if err != nil {
+		log.Fatalf("FATAL: Failed to initialize secret cipher: %v", err)
+	}
 	userCollection := db.Collection(userCol)
 	taskCollection := db.Collection(taskCol)
 	userRepo := repositories.NewMongoDBUserRepository(userCollection)
 	taskRepo := repositories.NewMongoDBTaskRepository(taskCollection)
-	userUsecase := usecases.NewUserUseCase(userRepo, jwtService, passwordService)
-	taskUsecase := usecases.NewTaskUseCase(taskRepo)
-	userController := controllers.NewUserController(userUsecase)
+	roleRepo := repositories.NewMongoDBRoleRepository(db.Collection(roleCol))
+	organizationRepo := repositories.NewMongoDBOrganizationRepository(db.Collection(organizationCol))
+	if err := organizationRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("FATAL: Failed to create organization indexes: %v", err)
+	}
+	defaultOrg, err := organizationRepo.GetOrganizationBySlug(context.Background(), domain.DefaultOrganizationSlug)
+	if errors.Is(err, domain.ErrOrganizationNotFound) {
+		defaultOrg, err = organizationRepo.CreateOrganization(context.Background(), &domain.Organization{
+			Name: "Default", Slug: domain.DefaultOrganizationSlug, CreatedAt: time.Now().UTC(),
+		})
+	}
+	if err != nil {
+		log.Fatalf("FATAL: Failed to set up the default organization: %v", err)
+	}
+	roleUsecase := usecases.NewRoleUseCase(roleRepo)
+	if err := roleUsecase.EnsureDefaultRoles(domain.ContextWithOrganization(context.Background(), defaultOrg.Id)); err != nil {
+		log.Fatalf("FATAL: Failed to seed default roles: %v", err)
+	}
+	userUsecase := usecases.NewUserUseCase(userRepo, roleRepo, organizationRepo, jwtService, passwordService, totpService, secretCipher)
+	organizationUsecase := usecases.NewOrganizationUseCase(
+		organizationRepo, repositories.NewMongoDBInvitationRepository(db.Collection(invitationCol)), userUsecase, roleUsecase,
+	)
+	projectRepo := repositories.NewMongoDBProjectRepository(db.Collection(projectCol))
+	taskUsecase := usecases.NewTaskUseCase(taskRepo, projectRepo)
+	taskUsecase.SetUserRepository(userRepo)
+	projectUsecase := usecases.NewProjectUseCase(projectRepo, userRepo)
+	attachmentDir, err := os.MkdirTemp("", "task-manager-e2e-attachments-")
+	if err != nil {
+		log.Fatalf("FATAL: Failed to create attachment directory: %v", err)
+	}
+	blobStore, err := infrastructure.NewLocalBlobStore(attachmentDir)
+	if err != nil {
+		log.Fatalf("FATAL: Failed to initialize blob store: %v", err)
+	}
+	attachmentUsecase := usecases.NewAttachmentUseCase(
+		repositories.NewMongoDBAttachmentRepository(db.Collection(attachmentCol)),
+		blobStore, taskUsecase, domain.DefaultAttachmentPolicy(),
+	)
+	serviceAccountUsecase := usecases.NewServiceAccountUseCase(
+		repositories.NewMongoDBServiceAccountRepository(db.Collection(accountCol)),
+		repositories.NewMongoDBAPIKeyRepository(db.Collection(apiKeyCol)),
+	)
+	organizationUsecase.OnMemberRemoved(projectUsecase.RemoveFromAllProjects)
+	organizationUsecase.OnMemberRemoved(taskUsecase.UnwatchAllTasks)
+	organizationUsecase.OnMemberRemoved(serviceAccountUsecase.DeleteServiceAccountsCreatedBy)
+	userController := controllers.NewUserController(userUsecase, organizationUsecase)
 	taskController := controllers.NewTaskController(taskUsecase)
-	authMiddleware := infrastructure.NewAuthMiddleware(jwtService)
+	roleController := controllers.NewRoleController(roleUsecase)
+	serviceAccountController := controllers.NewServiceAccountController(serviceAccountUsecase)
+	projectController := controllers.NewProjectController(projectUsecase)
+	attachmentController := controllers.NewAttachmentController(attachmentUsecase)
+	historyController := controllers.NewTaskHistoryController(
+		usecases.NewTaskHistoryUseCase(repositories.NewMongoDBRevisionRepository(db.Collection(revisionCol)), taskUsecase),
+	)
+	bulkController := controllers.NewTaskBulkController(
+		usecases.NewTaskBulkUseCase(taskUsecase, repositories.NewMongoTransactor(testMongoClient)),
+	)
+	calendarController := controllers.NewCalendarController(
+		usecases.NewCalendarUseCase(userRepo, roleRepo, taskUsecase, infrastructure.NewICalRenderer("-//A2SV//Task Manager E2E//EN", "taskmanager")),
+	)
+	notificationController := controllers.NewNotificationController(
+		usecases.NewNotificationUseCase(repositories.NewMongoDBNotificationRepository(db.Collection(notificationCol)), userRepo, roleRepo, taskUsecase),
+	)
+	authMiddleware := infrastructure.NewAuthMiddleware(jwtService, serviceAccountUsecase, userUsecase)
+	idempotencyMiddleware := infrastructure.NewIdempotencyMiddleware(
+		repositories.NewMongoDBIdempotencyRepository(db.Collection(idempotencyCol)), time.Hour,
+	)
+	graphQLHandler, err := graphqlapi.NewHandler(taskUsecase, userUsecase, graphqlapi.DefaultLimits)
+	if err != nil {
+		log.Fatalf("FATAL: Failed to build the GraphQL schema: %v", err)
+	}
 
 	// Setup router
 	gin.SetMode(gin.TestMode)
-	router := gin.Default()
-	routers.SetupUserRouters(router, userController)
-	routers.SetupTaskRoutes(router, taskController, authMiddleware)
+	router := gin.New()
+	router.Use(gin.Logger())
+	routers.SetupRoutes(router, routers.Handlers{
+		Users:           userController,
+		Organizations:   controllers.NewOrganizationController(organizationUsecase),
+		Tasks:           taskController,
+		Roles:           roleController,
+		ServiceAccounts: serviceAccountController,
+		Projects:        projectController,
+		Attachments:     attachmentController,
+		History:         historyController,
+		Bulk:            bulkController,
+		Calendar:        calendarController,
+		Notifications:   notificationController,
+		GraphQL:         graphQLHandler,
+		Auth:            authMiddleware,
+		Operator:        infrastructure.NewOperatorMiddleware(e2eOperatorToken),
+		Idempotency:     idempotencyMiddleware,
+	})
 
 	return router
 }
@@ -136,7 +241,7 @@ func (s *E2ETestSuite) TearDownSuite() {
 
 func (s *E2ETestSuite) SetupTest() {
 	// Clean all collections before each test method runs
-	collections := []string{userCol, taskCol}
+	collections := []string{userCol, taskCol, accountCol, apiKeyCol, projectCol, attachmentCol, revisionCol, idempotencyCol, invitationCol, notificationCol}
 	for _, coll := range collections {
 		_, err := s.DB.Collection(coll).DeleteMany(context.Background(), bson.D{})
 		s.Require().NoError(err)
@@ -255,6 +360,8 @@ func (s *TaskE2ETestSuite) TestTaskLifecycleAndAuthorization() {
 	s.Run("Unauthenticated Access Fails", func() {
 		resp := s.makeRequest(http.MethodGet, "/tasks", "", nil)
 		s.Equal(http.StatusUnauthorized, resp.StatusCode)
+		// Failed authentication is reported in the same format as every other error.
+		s.Equal(infrastructure.ProblemContentType, resp.Header.Get("Content-Type"))
 	})
 
 	// --- 2. Regular user cannot create a task ---
@@ -291,15 +398,42 @@ func (s *TaskE2ETestSuite) TestTaskLifecycleAndAuthorization() {
 	// --- 5. Admin can update the task ---
 	s.Run("Admin Updates Task", func() {
 		updateBody := bytes.NewBufferString(`{"title": "updated admin task", "status": "In progress"}`)
-		resp := s.makeRequest(http.MethodPut, "/tasks/"+createdTaskID, s.adminToken, updateBody)
+		resp := s.makeRequest(http.MethodPatch, "/tasks/"+createdTaskID, s.adminToken, updateBody)
 		s.Equal(http.StatusOK, resp.StatusCode)
 
 		var updatedTask domain.Task
 		json.NewDecoder(resp.Body).Decode(&updatedTask)
 		s.Equal("updated admin task", updatedTask.Title)
+		s.Equal("desc", updatedTask.Description, "fields left out of a merge patch are unchanged")
 		s.Equal(domain.InProgress, updatedTask.Status)
 	})
 
+	s.Run("Admin Clears Fields", func() {
+		req, err := http.NewRequest(http.MethodPatch, s.Server.URL+"/tasks/"+createdTaskID,
+			bytes.NewBufferString(`[{"op": "add", "path": "/tags/-", "value": "ops"}, {"op": "remove", "path": "/description"}]`))
+		s.Require().NoError(err)
+		req.Header.Set("Content-Type", "application/json-patch+json")
+		req.Header.Set("Authorization", "Bearer "+s.adminToken)
+		resp, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+		var patchedTask domain.Task
+		json.NewDecoder(resp.Body).Decode(&patchedTask)
+		s.Empty(patchedTask.Description)
+		s.Equal([]string{"ops"}, patchedTask.Tags)
+
+		// PUT replaces the whole task, so the tags it leaves out are cleared.
+		replaceBody := bytes.NewBufferString(`{"title": "replaced", "duedate": "2099-01-01T15:04:05Z", "status": "In progress"}`)
+		resp = s.makeRequest(http.MethodPut, "/tasks/"+createdTaskID, s.adminToken, replaceBody)
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+		json.NewDecoder(resp.Body).Decode(&patchedTask)
+		s.Equal("replaced", patchedTask.Title)
+		s.Empty(patchedTask.Tags)
+
+		resp = s.makeRequest(http.MethodPut, "/tasks/"+createdTaskID, s.adminToken, bytes.NewBufferString(`{"title": "partial"}`))
+		s.Equal(http.StatusBadRequest, resp.StatusCode, "PUT requires every required field")
+	})
+
 	// --- 6. Admin can delete the task ---
 	s.Run("Admin Deletes Task", func() {
 		resp := s.makeRequest(http.MethodDelete, "/tasks/"+createdTaskID, s.adminToken, nil)
@@ -310,5 +444,509 @@ func (s *TaskE2ETestSuite) TestTaskLifecycleAndAuthorization() {
 	s.Run("Deleted Task Is Not Found", func() {
 		resp := s.makeRequest(http.MethodGet, "/tasks/"+createdTaskID, s.adminToken, nil)
 		s.Equal(http.StatusNotFound, resp.StatusCode)
+		s.Equal(infrastructure.ProblemContentType, resp.Header.Get("Content-Type"))
+
+		var problem infrastructure.Problem
+		json.NewDecoder(resp.Body).Decode(&problem)
+		s.Equal(infrastructure.CodeTaskNotFound, problem.Code)
+		s.Equal("task not found", problem.Detail)
+		s.Equal(resp.Header.Get(infrastructure.RequestIDHeader), problem.RequestID)
 	})
 }
+
+// TestValidationErrors checks that both binding and domain validation list every invalid field.
+func (s *TaskE2ETestSuite) TestValidationErrors() {
+	fieldsOf := func(resp *http.Response) []string {
+		s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+		var problem infrastructure.Problem
+		json.NewDecoder(resp.Body).Decode(&problem)
+		s.Equal(infrastructure.CodeValidationFailed, problem.Code)
+		fields := []string{}
+		for _, violation := range problem.Errors {
+			fields = append(fields, violation.Field)
+		}
+		return fields
+	}
+
+	resp := s.makeRequest(http.MethodPost, "/tasks", s.adminToken, bytes.NewBufferString(`{"description": "no required fields"}`))
+	s.ElementsMatch([]string{"title", "duedate", "status"}, fieldsOf(resp))
+
+	resp = s.makeRequest(http.MethodPost, "/tasks", s.adminToken, bytes.NewBufferString(`{"title": "t", "duedate": "2000-01-01T00:00:00Z", "status": "Someday"}`))
+	s.Equal([]string{"status", "duedate"}, fieldsOf(resp))
+}
+
+// TestServiceAccountAPIKey checks that an API key authenticates with its scopes only.
+func (s *TaskE2ETestSuite) TestServiceAccountAPIKey() {
+	// --- 1. Admin creates a service account and a read-only key ---
+	resp := s.makeRequest(http.MethodPost, "/admin/service-accounts", s.adminToken, bytes.NewBufferString(`{"name": "ci"}`))
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var account domain.ServiceAccount
+	json.NewDecoder(resp.Body).Decode(&account)
+
+	resp = s.makeRequest(http.MethodPost, "/admin/service-accounts/"+account.Id.Hex()+"/keys", s.adminToken, bytes.NewBufferString(`{"name": "read", "scopes": ["tasks:read"]}`))
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var created struct {
+		Key    string        `json:"key"`
+		APIKey domain.APIKey `json:"api_key"`
+	}
+	json.NewDecoder(resp.Body).Decode(&created)
+	s.Require().NotEmpty(created.Key)
+
+	withKey := func(method, path string, body io.Reader) *http.Response {
+		req, err := http.NewRequest(method, s.Server.URL+path, body)
+		s.Require().NoError(err)
+		req.Header.Set("X-API-Key", created.Key)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		return resp
+	}
+
+	// --- 2. The key can read but not write ---
+	s.Equal(http.StatusOK, withKey(http.MethodGet, "/tasks", nil).StatusCode)
+	s.Equal(http.StatusForbidden, withKey(http.MethodPost, "/tasks", bytes.NewBufferString(`{"title": "t", "duedate": "2099-01-01T00:00:00Z", "status": "Pending"}`)).StatusCode)
+
+	// --- 3. A revoked key is rejected ---
+	resp = s.makeRequest(http.MethodDelete, "/admin/service-accounts/"+account.Id.Hex()+"/keys/"+created.APIKey.Id.Hex(), s.adminToken, nil)
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode)
+	s.Equal(http.StatusUnauthorized, withKey(http.MethodGet, "/tasks", nil).StatusCode)
+}
+
+// TestProjectMembership checks that project tasks are only visible to project members.
+func (s *TaskE2ETestSuite) TestProjectMembership() {
+	// --- 1. A regular user creates a project and a task in it ---
+	resp := s.makeRequest(http.MethodPost, "/projects", s.userToken, bytes.NewBufferString(`{"name": "Website"}`))
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var project domain.Project
+	json.NewDecoder(resp.Body).Decode(&project)
+
+	taskBody := bytes.NewBufferString(`{"title": "project task", "duedate": "2099-01-01T15:04:05Z", "status": "Pending"}`)
+	resp = s.makeRequest(http.MethodPost, "/projects/"+project.Id.Hex()+"/tasks", s.userToken, taskBody)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var task domain.Task
+	json.NewDecoder(resp.Body).Decode(&task)
+	s.Equal(project.Id, task.ProjectID)
+
+	resp = s.makeRequest(http.MethodGet, "/tasks", s.userToken, nil)
+	var tasks []*domain.Task
+	json.NewDecoder(resp.Body).Decode(&tasks)
+	s.Len(tasks, 1)
+
+	// --- 2. Another user cannot see the project or its tasks ---
+	regBody := bytes.NewBufferString(`{"username": "e2e_outsider", "password": "outsider_pass"}`)
+	s.Require().Equal(http.StatusCreated, s.makeRequest(http.MethodPost, "/user/register", "", regBody).StatusCode)
+	resp = s.makeRequest(http.MethodPost, "/user/login", "", bytes.NewBufferString(`{"username": "e2e_outsider", "password": "outsider_pass"}`))
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var loginResp map[string]string
+	json.NewDecoder(resp.Body).Decode(&loginResp)
+	outsiderToken := loginResp["token"]
+
+	s.Equal(http.StatusNotFound, s.makeRequest(http.MethodGet, "/projects/"+project.Id.Hex()+"/tasks", outsiderToken, nil).StatusCode)
+	s.Equal(http.StatusNotFound, s.makeRequest(http.MethodGet, "/tasks/"+task.Id.Hex(), outsiderToken, nil).StatusCode)
+	resp = s.makeRequest(http.MethodGet, "/tasks", outsiderToken, nil)
+	tasks = nil
+	json.NewDecoder(resp.Body).Decode(&tasks)
+	s.Empty(tasks)
+}
+
+// TestTaskAttachments checks uploading, downloading and cleanup of attachments.
+func (s *TaskE2ETestSuite) TestTaskAttachments() {
+	taskBody := bytes.NewBufferString(`{"title": "with files", "duedate": "2099-01-01T15:04:05Z", "status": "Pending"}`)
+	resp := s.makeRequest(http.MethodPost, "/tasks", s.adminToken, taskBody)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var task domain.Task
+	json.NewDecoder(resp.Body).Decode(&task)
+
+	// --- 1. Upload a file as multipart form data ---
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="notes.txt"`)
+	header.Set("Content-Type", "text/plain")
+	part, err := writer.CreatePart(header)
+	s.Require().NoError(err)
+	part.Write([]byte("release notes"))
+	writer.Close()
+
+	req, err := http.NewRequest(http.MethodPost, s.Server.URL+"/tasks/"+task.Id.Hex()+"/attachments/", &form)
+	s.Require().NoError(err)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+s.adminToken)
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var attachment domain.Attachment
+	json.NewDecoder(resp.Body).Decode(&attachment)
+	s.Equal(int64(len("release notes")), attachment.Size)
+
+	// --- 2. Download it again ---
+	resp = s.makeRequest(http.MethodGet, "/tasks/"+task.Id.Hex()+"/attachments/"+attachment.Id.Hex(), s.userToken, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	content, _ := io.ReadAll(resp.Body)
+	s.Equal("release notes", string(content))
+	s.Equal("nosniff", resp.Header.Get("X-Content-Type-Options"))
+	s.Equal(`attachment; filename=notes.txt`, resp.Header.Get("Content-Disposition"))
+
+	// --- 3. Deleting the task removes its attachments ---
+	s.Require().Equal(http.StatusNoContent, s.makeRequest(http.MethodDelete, "/tasks/"+task.Id.Hex(), s.adminToken, nil).StatusCode)
+	count, err := s.DB.Collection(attachmentCol).CountDocuments(context.Background(), bson.M{"task_id": task.Id})
+	s.Require().NoError(err)
+	s.Zero(count)
+}
+
+// TestTaskHistory checks that updates are recorded and can be reverted.
+func (s *TaskE2ETestSuite) TestTaskHistory() {
+	taskBody := bytes.NewBufferString(`{"title": "original", "duedate": "2099-01-01T15:04:05Z", "status": "Pending"}`)
+	resp := s.makeRequest(http.MethodPost, "/tasks", s.adminToken, taskBody)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var task domain.Task
+	json.NewDecoder(resp.Body).Decode(&task)
+
+	resp = s.makeRequest(http.MethodPatch, "/tasks/"+task.Id.Hex(), s.adminToken, bytes.NewBufferString(`{"title": "renamed"}`))
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp = s.makeRequest(http.MethodGet, "/tasks/"+task.Id.Hex()+"/history", s.userToken, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var revisions []domain.TaskRevision
+	json.NewDecoder(resp.Body).Decode(&revisions)
+	s.Require().Len(revisions, 2)
+	s.Equal([]domain.FieldChange{{Field: "title", Old: "original", New: "renamed"}}, revisions[1].Changes)
+
+	// Regular users lack tasks:update and tasks:revert and cannot revert
+	path := "/tasks/" + task.Id.Hex() + "/history/" + revisions[0].Id.Hex() + "/revert"
+	s.Equal(http.StatusForbidden, s.makeRequest(http.MethodPost, path, s.userToken, nil).StatusCode)
+
+	resp = s.makeRequest(http.MethodPost, path, s.adminToken, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var reverted domain.Task
+	json.NewDecoder(resp.Body).Decode(&reverted)
+	s.Equal("original", reverted.Title)
+}
+
+// TestBulkTaskOperations covers the non-atomic mode, which works without a replica set.
+func (s *TaskE2ETestSuite) TestBulkTaskOperations() {
+	body := bytes.NewBufferString(`{"operations": [
+		{"action": "create", "title": "first", "duedate": "2099-01-01T15:04:05Z", "status": "Pending"},
+		{"action": "create", "title": "", "duedate": "2099-01-01T15:04:05Z", "status": "Pending"},
+		{"action": "create", "title": "second", "duedate": "2099-02-01T15:04:05Z", "status": "Pending"}
+	]}`)
+	resp := s.makeRequest(http.MethodPost, "/tasks/bulk", s.adminToken, body)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var result usecases.BulkResult
+	json.NewDecoder(resp.Body).Decode(&result)
+	s.Require().Len(result.Results, 3)
+	s.Equal(usecases.BulkSucceeded, result.Results[0].Status)
+	s.Equal(usecases.BulkFailed, result.Results[1].Status)
+	s.Equal(usecases.BulkSucceeded, result.Results[2].Status)
+
+	// Filter-based update of everything still pending
+	body = bytes.NewBufferString(`{"filter": {"status": ["Pending"]}, "update": {"status": "Done"}}`)
+	resp = s.makeRequest(http.MethodPost, "/tasks/bulk", s.adminToken, body)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	json.NewDecoder(resp.Body).Decode(&result)
+	s.Len(result.Results, 2)
+	count, err := s.DB.Collection(taskCol).CountDocuments(context.Background(), bson.M{"status": domain.Done})
+	s.Require().NoError(err)
+	s.Equal(int64(2), count)
+
+	// Regular users lack tasks:update
+	body = bytes.NewBufferString(`{"operations": [{"action": "delete", "id": "` + result.Results[0].TaskID + `"}]}`)
+	s.Equal(http.StatusForbidden, s.makeRequest(http.MethodPost, "/tasks/bulk", s.userToken, body).StatusCode)
+}
+
+// TestTaskImportExport imports a CSV file and exports the result in each format.
+func (s *TaskE2ETestSuite) TestTaskImportExport() {
+	csvFile := "external_id,title,description,duedate,status\n" +
+		"JIRA-1,Imported,from csv,2099-01-01,Pending\n" +
+		"JIRA-2,,missing title,2099-01-01,Pending\n" +
+		"JIRA-3,Bad date,,tomorrow,Pending\n" +
+		",Second,=SUM(A1),2099-02-01T10:00:00Z,In progress\n"
+
+	// --- 1. Regular users cannot import ---
+	resp := s.makeRequest(http.MethodPost, "/tasks/import?format=csv", s.userToken, bytes.NewBufferString(csvFile))
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+
+	// --- 2. A dry run writes nothing ---
+	resp = s.makeRequest(http.MethodPost, "/tasks/import?format=csv&dry_run=true", s.adminToken, bytes.NewBufferString(csvFile))
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var report usecases.ImportReport
+	json.NewDecoder(resp.Body).Decode(&report)
+	s.Equal(2, report.Created)
+	s.Equal(2, report.Failed)
+	count, err := s.DB.Collection(taskCol).CountDocuments(context.Background(), bson.M{})
+	s.Require().NoError(err)
+	s.Zero(count)
+
+	// --- 3. The real import ---
+	resp = s.makeRequest(http.MethodPost, "/tasks/import?format=csv", s.adminToken, bytes.NewBufferString(csvFile))
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	json.NewDecoder(resp.Body).Decode(&report)
+	s.Equal(2, report.Created)
+	s.Require().Len(report.Errors, 2)
+	s.Equal(2, report.Errors[0].Row)
+	s.Equal(3, report.Errors[1].Row)
+
+	// --- 4. Upserting by external ID updates instead of duplicating ---
+	resp = s.makeRequest(http.MethodPost, "/tasks/import?format=ndjson&upsert=true", s.adminToken,
+		bytes.NewBufferString(`{"external_id": "JIRA-1", "title": "Renamed", "duedate": "2099-01-01", "status": "Pending"}`+"\n"))
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	json.NewDecoder(resp.Body).Decode(&report)
+	s.Equal(1, report.Updated)
+
+	// --- 5. Export ---
+	resp = s.makeRequest(http.MethodGet, "/tasks/export?format=csv", s.userToken, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Contains(resp.Header.Get("Content-Type"), "text/csv")
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	s.Require().NoError(err)
+	s.Require().Len(records, 3)
+	s.Equal("external_id", records[0][1])
+	s.Equal([]string{"JIRA-1", "Renamed"}, records[1][1:3])
+	s.Equal("'=SUM(A1)", records[2][3], "formulas must not reach a spreadsheet unescaped")
+
+	resp = s.makeRequest(http.MethodGet, "/tasks/export?format=json&status=Pending", s.userToken, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var tasks []domain.Task
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&tasks))
+	s.Require().Len(tasks, 1)
+	s.Equal("JIRA-1", tasks[0].ExternalID)
+
+	resp = s.makeRequest(http.MethodGet, "/tasks/export?format=ndjson", s.userToken, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	lines, _ := io.ReadAll(resp.Body)
+	s.Equal(2, bytes.Count(lines, []byte("\n")))
+
+	s.Equal(http.StatusBadRequest, s.makeRequest(http.MethodGet, "/tasks/export?format=xml", s.userToken, nil).StatusCode)
+}
+
+// TestCalendarFeed checks that the feed works with its token alone and can be revoked.
+func (s *TaskE2ETestSuite) TestCalendarFeed() {
+	taskBody := bytes.NewBufferString(`{"title": "Quarterly report", "duedate": "2099-03-31T17:00:00Z", "status": "Pending"}`)
+	s.Require().Equal(http.StatusCreated, s.makeRequest(http.MethodPost, "/tasks", s.adminToken, taskBody).StatusCode)
+
+	resp := s.makeRequest(http.MethodPost, "/user/calendar-token", s.userToken, nil)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var issued struct {
+		Token    string `json:"token"`
+		FeedPath string `json:"feed_path"`
+	}
+	json.NewDecoder(resp.Body).Decode(&issued)
+	s.Require().NotEmpty(issued.Token)
+
+	// No Authorization header: the token in the URL is the credential
+	resp = s.makeRequest(http.MethodGet, issued.FeedPath+"?type=todo", "", nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Contains(resp.Header.Get("Content-Type"), "text/calendar")
+	calendar, _ := io.ReadAll(resp.Body)
+	s.Contains(string(calendar), "SUMMARY:Quarterly report\r\n")
+	s.Contains(string(calendar), "DUE:20990331T170000Z\r\n")
+
+	s.Equal(http.StatusNoContent, s.makeRequest(http.MethodDelete, "/user/calendar-token", s.userToken, nil).StatusCode)
+	s.Equal(http.StatusNotFound, s.makeRequest(http.MethodGet, issued.FeedPath, "", nil).StatusCode)
+}
+
+// TestTaskStats tags a task, completes it and reads the statistics back.
+func (s *TaskE2ETestSuite) TestTaskStats() {
+	taskBody := bytes.NewBufferString(`{"title": "Release notes", "duedate": "2099-01-01T15:04:05Z", "status": "Pending"}`)
+	resp := s.makeRequest(http.MethodPost, "/tasks", s.adminToken, taskBody)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var task domain.Task
+	json.NewDecoder(resp.Body).Decode(&task)
+	s.NotEmpty(task.OwnerID)
+
+	resp = s.makeRequest(http.MethodPut, "/tasks/"+task.Id.Hex()+"/tags", s.adminToken, bytes.NewBufferString(`{"tags": ["Docs", " docs", "release"]}`))
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	json.NewDecoder(resp.Body).Decode(&task)
+	s.Equal([]string{"docs", "release"}, task.Tags)
+	s.Equal(http.StatusForbidden, s.makeRequest(http.MethodPut, "/tasks/"+task.Id.Hex()+"/tags", s.userToken, bytes.NewBufferString(`{"tags": []}`)).StatusCode)
+
+	resp = s.makeRequest(http.MethodPatch, "/tasks/"+task.Id.Hex(), s.adminToken, bytes.NewBufferString(`{"status": "Done"}`))
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp = s.makeRequest(http.MethodGet, "/tasks/stats?tag=docs&owner=me", s.adminToken, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var stats domain.TaskStats
+	json.NewDecoder(resp.Body).Decode(&stats)
+	s.Equal(1, stats.Total)
+	s.Equal(1, stats.ByStatus[domain.Done])
+	s.Require().Len(stats.CompletionsPerDay, 30)
+	s.Equal(1, stats.CompletionsPerDay[29].Count)
+
+	s.Equal(http.StatusBadRequest, s.makeRequest(http.MethodGet, "/tasks/stats?tz=Mars/Olympus", s.userToken, nil).StatusCode)
+	s.Equal(http.StatusBadRequest, s.makeRequest(http.MethodGet, "/tasks/stats?from=2025-02-01&to=2025-01-01", s.userToken, nil).StatusCode)
+}
+
+// TestIdempotentTaskCreation retries a task creation with the same Idempotency-Key.
+func (s *TaskE2ETestSuite) TestIdempotentTaskCreation() {
+	create := func(token, key, body string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, s.Server.URL+"/tasks", bytes.NewBufferString(body))
+		s.Require().NoError(err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set(infrastructure.IdempotencyKeyHeader, key)
+		resp, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		return resp
+	}
+	taskBody := `{"title": "Retried", "duedate": "2099-01-01T15:04:05Z", "status": "Pending"}`
+
+	first := create(s.adminToken, "retry-1", taskBody)
+	s.Require().Equal(http.StatusCreated, first.StatusCode)
+	var created domain.Task
+	json.NewDecoder(first.Body).Decode(&created)
+
+	retry := create(s.adminToken, "retry-1", taskBody)
+	s.Require().Equal(http.StatusCreated, retry.StatusCode)
+	s.Equal("true", retry.Header.Get(infrastructure.IdempotentReplayedHeader))
+	var replayed domain.Task
+	json.NewDecoder(retry.Body).Decode(&replayed)
+	s.Equal(created.Id, replayed.Id)
+
+	count, err := s.DB.Collection(taskCol).CountDocuments(context.Background(), bson.M{"title": "Retried"})
+	s.Require().NoError(err)
+	s.Equal(int64(1), count)
+
+	resp := create(s.adminToken, "retry-1", `{"title": "Different", "duedate": "2099-01-01T15:04:05Z", "status": "Pending"}`)
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+
+	// The forbidden attempt is not stored, so it does not claim the key.
+	s.Equal(http.StatusForbidden, create(s.userToken, "retry-2", taskBody).StatusCode)
+}
+
+func (s *TaskE2ETestSuite) TestOrganizationIsolation() {
+	taskBody := bytes.NewBufferString(`{"title": "Default org task", "duedate": "2099-01-01T15:04:05Z", "status": "Pending"}`)
+	resp := s.makeRequest(http.MethodPost, "/tasks", s.adminToken, taskBody)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var task domain.Task
+	json.NewDecoder(resp.Body).Decode(&task)
+
+	// A second organization may reuse a username from the first.
+	slug := fmt.Sprintf("acme-%d", time.Now().UnixNano())
+	orgBody := bytes.NewBufferString(fmt.Sprintf(`{"name": "Acme", "slug": "%s", "username": "e2e_admin", "password": "acme_pass"}`, slug))
+	resp = s.makeRequest(http.MethodPost, "/organizations", "", bytes.NewBufferString(orgBody.String()))
+	s.Equal(http.StatusUnauthorized, resp.StatusCode, "only operators create organizations")
+	req, err := http.NewRequest(http.MethodPost, s.Server.URL+"/organizations", orgBody)
+	s.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(infrastructure.OperatorTokenHeader, e2eOperatorToken)
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	loginBody := bytes.NewBufferString(fmt.Sprintf(`{"organization": "%s", "username": "e2e_admin", "password": "acme_pass"}`, slug))
+	resp = s.makeRequest(http.MethodPost, "/user/login", "", loginBody)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var loginResp map[string]string
+	json.NewDecoder(resp.Body).Decode(&loginResp)
+	acmeToken := loginResp["token"]
+
+	resp = s.makeRequest(http.MethodGet, "/tasks/"+task.Id.Hex(), acmeToken, nil)
+	s.Equal(http.StatusNotFound, resp.StatusCode, "tasks of another organization do not exist for the caller")
+	resp = s.makeRequest(http.MethodDelete, "/tasks/"+task.Id.Hex(), acmeToken, nil)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+
+	resp = s.makeRequest(http.MethodGet, "/tasks", acmeToken, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var tasks []domain.Task
+	json.NewDecoder(resp.Body).Decode(&tasks)
+	s.Empty(tasks)
+
+	// Invitations add members to the inviting organization only.
+	resp = s.makeRequest(http.MethodPost, "/organization/invitations", acmeToken, bytes.NewBufferString(`{"role": "User"}`))
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var invitation map[string]any
+	json.NewDecoder(resp.Body).Decode(&invitation)
+	regBody := bytes.NewBufferString(fmt.Sprintf(`{"username": "e2e_user", "password": "acme_pass", "invitation": "%s"}`, invitation["token"]))
+	resp = s.makeRequest(http.MethodPost, "/user/register", "", regBody)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	resp = s.makeRequest(http.MethodGet, "/organization/members", acmeToken, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var members []map[string]any
+	json.NewDecoder(resp.Body).Decode(&members)
+	s.Len(members, 2)
+}
+
+func (s *TaskE2ETestSuite) TestNotifications() {
+	taskBody := bytes.NewBufferString(`{"title": "Watched", "duedate": "2099-01-01T15:04:05Z", "status": "Pending"}`)
+	resp := s.makeRequest(http.MethodPost, "/tasks", s.adminToken, taskBody)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var task domain.Task
+	json.NewDecoder(resp.Body).Decode(&task)
+
+	// Someone other than the owner changes the task: a service account key.
+	resp = s.makeRequest(http.MethodPost, "/admin/service-accounts", s.adminToken, bytes.NewBufferString(`{"name": "bot"}`))
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var account domain.ServiceAccount
+	json.NewDecoder(resp.Body).Decode(&account)
+	resp = s.makeRequest(http.MethodPost, "/admin/service-accounts/"+account.Id.Hex()+"/keys", s.adminToken, bytes.NewBufferString(`{"name": "edit", "scopes": ["tasks:update"]}`))
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var created struct {
+		Key string `json:"key"`
+	}
+	json.NewDecoder(resp.Body).Decode(&created)
+	req, err := http.NewRequest(http.MethodPatch, s.Server.URL+"/tasks/"+task.Id.Hex(), bytes.NewBufferString(`{"status": "In progress"}`))
+	s.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", created.Key)
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp = s.makeRequest(http.MethodGet, "/me/notifications", s.adminToken, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var page domain.NotificationPage
+	json.NewDecoder(resp.Body).Decode(&page)
+	s.Equal(int64(1), page.Unread)
+	s.Require().Len(page.Notifications, 1)
+	s.Equal(domain.NotificationTaskUpdated, page.Notifications[0].Type)
+	s.Equal(task.Id, page.Notifications[0].TaskID)
+
+	resp = s.makeRequest(http.MethodPost, "/me/notifications/"+page.Notifications[0].Id.Hex()+"/read", s.userToken, nil)
+	s.Equal(http.StatusNotFound, resp.StatusCode, "other users cannot mark the notification")
+	resp = s.makeRequest(http.MethodPost, "/me/notifications/read", s.adminToken, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	resp = s.makeRequest(http.MethodGet, "/me/notifications?unread=true", s.adminToken, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	page = domain.NotificationPage{}
+	json.NewDecoder(resp.Body).Decode(&page)
+	s.Zero(page.Total)
+
+	resp = s.makeRequest(http.MethodPut, "/me/notifications/preferences", s.adminToken, bytes.NewBufferString(`{"task.updated": false}`))
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var preferences domain.NotificationPreferences
+	json.NewDecoder(resp.Body).Decode(&preferences)
+	s.False(preferences[domain.NotificationTaskUpdated])
+	s.True(preferences[domain.NotificationTaskOverdue])
+}
+
+func (s *TaskE2ETestSuite) TestWatchersAndMentions() {
+	taskBody := bytes.NewBufferString(`{"title": "Review", "description": "@e2e_user please review", "duedate": "2099-01-01T15:04:05Z", "status": "Pending"}`)
+	resp := s.makeRequest(http.MethodPost, "/tasks", s.adminToken, taskBody)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var task domain.Task
+	json.NewDecoder(resp.Body).Decode(&task)
+	s.Len(task.Watchers, 2, "The creator and the mentioned user watch the task")
+
+	resp = s.makeRequest(http.MethodGet, "/me/notifications", s.userToken, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var page domain.NotificationPage
+	json.NewDecoder(resp.Body).Decode(&page)
+	s.Require().Len(page.Notifications, 1)
+	s.Equal(domain.NotificationTaskMentioned, page.Notifications[0].Type)
+	s.Equal(task.Id, page.Notifications[0].TaskID)
+
+	resp = s.makeRequest(http.MethodDelete, "/tasks/"+task.Id.Hex()+"/watch", s.userToken, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var unwatched domain.Task
+	json.NewDecoder(resp.Body).Decode(&unwatched)
+	s.Equal([]string{task.OwnerID}, unwatched.Watchers)
+
+	resp = s.makeRequest(http.MethodPost, "/tasks/"+task.Id.Hex()+"/watch", s.userToken, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var watched domain.Task
+	json.NewDecoder(resp.Body).Decode(&watched)
+	s.Len(watched.Watchers, 2)
+}